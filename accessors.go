@@ -0,0 +1,100 @@
+package csvee
+
+// Columns returns a copy of the reader's column names.
+func (r *Reader) Columns() []string {
+
+	columnNames := make([]string, len(r.ColumnNames))
+	_ = copy(columnNames, r.ColumnNames)
+	return columnNames
+}
+
+// SetColumns replaces the reader's column names. The names must be unique and non empty and must
+// include every column that has a format; otherwise an error is returned and nothing is changed.
+func (r *Reader) SetColumns(columnNames []string) error {
+
+	if len(columnNames) == 0 {
+		return ErrColumnNamesRequired
+	}
+
+	if err := validateColumnNames(columnNames, r.planConfig.repeated); err != nil {
+		return err
+	}
+
+	if err := validateFormatColumns(r.ColumnFormats, columnNames); err != nil {
+		return err
+	}
+
+	r.ColumnNames = make([]string, len(columnNames))
+	_ = copy(r.ColumnNames, columnNames)
+	r.plans = nil
+	r.columnSettings = nil
+	return nil
+}
+
+// Formats returns a copy of the reader's column formats.
+func (r *Reader) Formats() map[string]string {
+
+	formats := make(map[string]string, len(r.ColumnFormats))
+	for k, v := range r.ColumnFormats {
+		formats[k] = v
+	}
+
+	return formats
+}
+
+// SetFormats replaces the reader's column formats. Each format must be valid and refer to a known
+// column; otherwise an error is returned and nothing is changed.
+func (r *Reader) SetFormats(formats map[string]string) error {
+
+	if err := validateColumnFormats(formats); err != nil {
+		return err
+	}
+
+	if err := validateFormatColumns(formats, r.ColumnNames); err != nil {
+		return err
+	}
+
+	formatsCopy := make(map[string]string, len(formats))
+	for k, v := range formats {
+		formatsCopy[k] = v
+	}
+
+	r.ColumnFormats = formatsCopy
+	r.columnSettings = nil
+	return nil
+}
+
+// SetColumnFormat sets the format of a single column, leaving the others unchanged.
+func (r *Reader) SetColumnFormat(column, format string) error {
+
+	formats := r.Formats()
+	formats[column] = format
+	return r.SetFormats(formats)
+}
+
+// rowCounts accounts for the records a reader has taken from its input.
+type rowCounts struct {
+	read, skipped, errored int64
+}
+
+// RowsRead returns the number of records read from the input so far, not counting headers, whether
+// they were decoded, skipped, or failed. Records tokenized ahead by a pipeline are counted once they
+// are read.
+func (r *Reader) RowsRead() int64 {
+
+	return r.counts.read
+}
+
+// RowsSkipped returns the number of records read so far that were dropped without being decoded,
+// as duplicates within the dedup window.
+func (r *Reader) RowsSkipped() int64 {
+
+	return r.counts.skipped
+}
+
+// RowsErrored returns the number of records read so far that could not be tokenized, decoded, or
+// validated, including those ErrorSkip and ErrorCollect carried on past.
+func (r *Reader) RowsErrored() int64 {
+
+	return r.counts.errored
+}
@@ -0,0 +1,134 @@
+package csvee
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_Accessors verifies configuration accessors copy their values and validate changes
+func TestReader_Accessors(t *testing.T) {
+
+	reader, err := NewReader(
+		strings.NewReader("1613235342"),
+		&ReaderOptions{ColumnNames: []string{"I", "T"}, ColumnFormats: map[string]string{"T": TimeFormatUnix}},
+	)
+	require.NoError(t, err)
+
+	columns := reader.Columns()
+	columns[0] = "X"
+	assert.Equal(t, []string{"I", "T"}, reader.Columns())
+
+	formats := reader.Formats()
+	formats["T"] = "2006"
+	assert.Equal(t, map[string]string{"T": TimeFormatUnix}, reader.Formats())
+
+	assert.EqualError(t, reader.SetColumns([]string{"I", "I"}), `duplicate column name "I"`)
+	assert.EqualError(t, reader.SetColumns([]string{"I"}), `column format provided for unknown column "T"`)
+	assert.EqualError(t, reader.SetColumnFormat("T", "uinx"), `unknown column format "uinx" for column T`)
+	assert.EqualError(t, reader.SetColumnFormat("X", TimeFormatUnix), `column format provided for unknown column "X"`)
+	assert.Equal(t, []string{"I", "T"}, reader.Columns())
+
+	require.NoError(t, reader.SetFormats(nil))
+	require.NoError(t, reader.SetColumns([]string{"Tu"}))
+	require.NoError(t, reader.SetColumnFormat("Tu", TimeFormatUnix))
+
+	var actualData readTo
+	require.NoError(t, reader.Read(&actualData))
+	assert.Equal(t, int64(1613235342), actualData.Tu.Unix())
+}
+
+// TestReader_RowCounts verifies rows read, skipped, and errored are counted on every read path
+func TestReader_RowCounts(t *testing.T) {
+
+	const data = "I,S\n1,a\n1,a\nx,b\n2\n4,c\"d\n5,e\n"
+
+	type row struct {
+		I int
+		S string
+	}
+
+	var testCases = []struct {
+		name       string
+		options    ReaderOptions
+		read       func(r *Reader) error
+		expRead    int64
+		expErrored int64
+	}{
+		{
+			name:       "ReadAll",
+			options:    ReaderOptions{OnError: ErrorSkip},
+			read:       func(r *Reader) error { var rows []row; return r.ReadAll(&rows) },
+			expRead:    6,
+			expErrored: 3,
+		},
+		{
+			name:    "ReadAll pipelined",
+			options: ReaderOptions{OnError: ErrorCollect, PipelineDepth: 2},
+			read: func(r *Reader) error {
+				var rows []row
+				_ = r.ReadAll(&rows)
+				return nil
+			},
+			expRead:    6,
+			expErrored: 3,
+		},
+		{
+			name: "Read",
+			read: func(r *Reader) error {
+				for {
+					var v row
+					if err := r.Read(&v); err == io.EOF {
+						return nil
+					}
+				}
+			},
+			expRead:    6,
+			expErrored: 3,
+		},
+		{
+			name: "Decoder",
+			read: func(r *Reader) error {
+				d := r.Decode()
+				for d.Next() {
+					var v row
+					_ = d.Scan(&v)
+				}
+				return nil
+			},
+			// The Decoder stops at the record with too few fields, which it cannot tokenize.
+			expRead:    4,
+			expErrored: 2,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			options := tt.options
+			options.ReadHeaders = true
+			options.DedupWindow = 4
+
+			reader, err := NewReader(strings.NewReader(data), &options)
+			require.NoError(t, err)
+
+			require.NoError(t, tt.read(reader))
+			assert.Equal(t, tt.expRead, reader.RowsRead())
+			assert.Equal(t, int64(1), reader.RowsSkipped())
+			assert.Equal(t, tt.expErrored, reader.RowsErrored())
+		})
+	}
+
+	// Rows that fail on a worker are counted once they are collected.
+	reader, err := NewReader(strings.NewReader("I,S\n1,a\n2,b\nx,c\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	var rows []row
+	require.Error(t, reader.ReadAllConcurrent(&rows, 2))
+	assert.Equal(t, int64(3), reader.RowsRead())
+	assert.Equal(t, int64(0), reader.RowsSkipped())
+	assert.Equal(t, int64(1), reader.RowsErrored())
+}
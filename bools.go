@@ -0,0 +1,40 @@
+package csvee
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// BoolParsing controls which values are accepted for bool fields.
+type BoolParsing int
+
+const (
+	// BoolStrict accepts only "true" and "false".
+	BoolStrict BoolParsing = iota
+
+	// BoolLenient accepts, case insensitively, true/false, t/f, yes/no, y/n, and 1/0.
+	BoolLenient
+)
+
+// parseBool parses s according to mode.
+func parseBool(s string, mode BoolParsing) (bool, error) {
+
+	if mode == BoolLenient {
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case "true", "t", "yes", "y", "1":
+			return true, nil
+		case "false", "f", "no", "n", "0":
+			return false, nil
+		}
+	} else {
+		switch s {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	}
+
+	return false, errors.Errorf("invalid value %q for bool", s)
+}
@@ -0,0 +1,74 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_BoolParsing verifies bool fields are parsed the same way on every decode path
+func TestReader_BoolParsing(t *testing.T) {
+
+	type flags struct {
+		B  bool
+		BA []bool
+	}
+
+	var testCases = []struct {
+		name       string
+		inData     string
+		inMode     BoolParsing
+		expData    flags
+		expErrText string
+	}{
+		{
+			name:    "strict",
+			inData:  `true,"false,true"`,
+			inMode:  BoolStrict,
+			expData: flags{B: true, BA: []bool{false, true}},
+		},
+		{
+			name:       "strict rejects capitalized",
+			inData:     `True,false`,
+			inMode:     BoolStrict,
+			expErrText: `row 1, column "B": invalid value "True" for bool`,
+		},
+		{
+			name:    "lenient",
+			inData:  `YES,"0,T,n"`,
+			inMode:  BoolLenient,
+			expData: flags{B: true, BA: []bool{false, true, false}},
+		},
+		{
+			name:       "lenient rejects unknown",
+			inData:     `maybe,true`,
+			inMode:     BoolLenient,
+			expErrText: `row 1, column "B": invalid value "maybe" for bool`,
+		},
+	}
+
+	for _, tt := range testCases {
+		for _, fastPath := range []bool{false, true} {
+			t.Run(tt.name, func(t *testing.T) {
+
+				reader, err := NewReader(
+					strings.NewReader(tt.inData),
+					&ReaderOptions{ColumnNames: []string{"B", "BA"}, BoolParsing: tt.inMode, UnsafeFastPath: fastPath},
+				)
+				require.NoError(t, err)
+
+				var actualData flags
+				err = reader.Read(&actualData)
+				if tt.expErrText != "" {
+					assert.EqualError(t, err, tt.expErrText)
+					return
+				}
+
+				require.NoError(t, err)
+				assert.Equal(t, tt.expData, actualData)
+			})
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package csvee
+
+import (
+	"context"
+	"fmt"
+)
+
+// PartialResultError is returned by ReadAll, when the reader was constructed with PartialResults,
+// if it stopped before the end of the input. The rows decoded before it stopped are left in the
+// destination.
+type PartialResultError struct {
+	// Rows is the number of rows added to the destination.
+	Rows int
+
+	// Err is the reason ReadAll stopped, such as context.Canceled or an *ErrorBudgetError.
+	Err error
+}
+
+func (e *PartialResultError) Error() string {
+
+	return fmt.Sprintf("stopped after %d rows: %v", e.Rows, e.Err)
+}
+
+// Unwrap returns the reason ReadAll stopped.
+func (e *PartialResultError) Unwrap() error {
+
+	return e.Err
+}
+
+// ReadAllContext reads the remaining records into v as ReadAll does, stopping with ctx's error if
+// ctx is done before every record has been read. ctx is also passed to the reader's Limiter.
+func (r *Reader) ReadAllContext(ctx context.Context, v interface{}) error {
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.ctx = ctx
+	defer func() { r.ctx = nil }()
+
+	return r.ReadAll(v)
+}
+
+// context returns the context of the read in progress, or the background context if it has none.
+func (r *Reader) context() context.Context {
+
+	if r.ctx == nil {
+		return context.Background()
+	}
+
+	return r.ctx
+}
@@ -0,0 +1,162 @@
+package csvee
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// contextLimiter records the context it is waited on with.
+type contextLimiter struct {
+	ctx context.Context
+}
+
+func (l *contextLimiter) Wait(ctx context.Context) error {
+
+	l.ctx = ctx
+	return ctx.Err()
+}
+
+// TestReader_ReadAllContext verifies a cancelled read stops and keeps the rows decoded before it
+func TestReader_ReadAllContext(t *testing.T) {
+
+	input := "Name,Age\nann,1\nbob,2\ncat,3\ndan,4\n"
+
+	var testCases = []struct {
+		name    string
+		partial bool
+		expErr  string
+	}{
+		{name: "plain", expErr: "context canceled"},
+		{name: "partial", partial: true, expErr: "stopped after 2 rows: context canceled"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			limiter := &contextLimiter{}
+			reader, err := NewReader(strings.NewReader(input), &ReaderOptions{
+				ReadHeaders:    true,
+				Limiter:        limiter,
+				PartialResults: tt.partial,
+				AfterRow: func(n int, v interface{}) error {
+					if n == 2 {
+						cancel()
+					}
+					return nil
+				},
+			})
+			require.NoError(t, err)
+
+			var actual []validatedRow
+			err = reader.ReadAllContext(ctx, &actual)
+			require.Error(t, err)
+			assert.Equal(t, tt.expErr, err.Error())
+			assert.True(t, errors.Is(err, context.Canceled))
+			assert.Equal(t, []validatedRow{{Name: "ann", Age: 1}, {Name: "bob", Age: 2}}, actual)
+			assert.Equal(t, ctx, limiter.ctx)
+
+			var partialErr *PartialResultError
+			if assert.Equal(t, tt.partial, errors.As(err, &partialErr)) && tt.partial {
+				assert.Equal(t, 2, partialErr.Rows)
+			}
+
+			// The context only applies to the read it was given to.
+			require.NoError(t, reader.ReadAll(&actual))
+			assert.Len(t, actual, 4)
+		})
+	}
+}
+
+// TestReader_ReadAllContext_Done verifies nothing is read once the context is done
+func TestReader_ReadAllContext_Done(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	reader, err := NewReader(strings.NewReader("Name,Age\nann,1\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	var actual []validatedRow
+	assert.Equal(t, context.Canceled, reader.ReadAllContext(ctx, &actual))
+
+	require.NoError(t, reader.ReadAll(&actual))
+	assert.Len(t, actual, 1)
+}
+
+// TestReader_ErrorBudget verifies reading stops once more rows fail than the budget allows
+func TestReader_ErrorBudget(t *testing.T) {
+
+	input := "Name,Age\nann,1\nbob,x\ncat,3\ndan,y\nemu,5\nfay,z\n"
+
+	var testCases = []struct {
+		name     string
+		policy   ErrorPolicy
+		budget   int
+		exp      []validatedRow
+		expErr   string
+		expCount int
+	}{
+		{
+			name:   "within budget",
+			policy: ErrorSkip,
+			budget: 3,
+			exp:    []validatedRow{{Name: "ann", Age: 1}, {Name: "cat", Age: 3}, {Name: "emu", Age: 5}},
+		},
+		{
+			name:   "skip",
+			policy: ErrorSkip,
+			budget: 1,
+			exp:    []validatedRow{{Name: "ann", Age: 1}, {Name: "cat", Age: 3}},
+			expErr: `stopped after 2 rows: more than 1 rows failed: row 4, column "Age": invalid value "y" for int`,
+		},
+		{
+			name:     "collect",
+			policy:   ErrorCollect,
+			budget:   1,
+			exp:      []validatedRow{{Name: "ann", Age: 1}, {Name: "cat", Age: 3}},
+			expErr:   "stopped after 2 rows: more than 1 rows failed: 2 rows failed: ",
+			expCount: 2,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader(input), &ReaderOptions{
+				ReadHeaders:    true,
+				OnError:        tt.policy,
+				ErrorBudget:    tt.budget,
+				PartialResults: true,
+			})
+			require.NoError(t, err)
+
+			var actual []validatedRow
+			err = reader.ReadAll(&actual)
+			assert.Equal(t, tt.exp, actual)
+
+			if tt.expErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expErr)
+
+			var budgetErr *ErrorBudgetError
+			require.True(t, errors.As(err, &budgetErr))
+			assert.Equal(t, tt.budget, budgetErr.Budget)
+
+			var multiErr *MultiError
+			if assert.Equal(t, tt.expCount > 0, errors.As(err, &multiErr)) && tt.expCount > 0 {
+				assert.Len(t, multiErr.Errors, tt.expCount)
+			}
+		})
+	}
+}
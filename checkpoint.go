@@ -0,0 +1,23 @@
+package csvee
+
+// RowsWritten returns the number of rows written so far across every part, not counting headers.
+// Rows that have been written may still be buffered; see Flush.
+func (w *Writer) RowsWritten() int64 {
+
+	return int64(w.rows)
+}
+
+// reachCheckpoint flushes the writer and calls the Checkpoint callback if the row just written
+// completes a checkpoint interval.
+func (w *Writer) reachCheckpoint() error {
+
+	if w.checkpoint == nil || w.rows%w.checkpointEvery != 0 {
+		return nil
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	return w.checkpoint(w.RowsWritten())
+}
@@ -0,0 +1,87 @@
+package csvee
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriter_Checkpoint verifies the checkpoint is called every interval once its rows are flushed
+func TestWriter_Checkpoint(t *testing.T) {
+
+	var (
+		buf         bytes.Buffer
+		checkpoints []int64
+		flushed     []string
+	)
+	writer, err := NewWriter(&buf, &WriterOptions{
+		ColumnNames:     []string{"Name"},
+		WriteHeaders:    true,
+		CheckpointEvery: 2,
+		Checkpoint: func(rows int64) error {
+			checkpoints = append(checkpoints, rows)
+			flushed = append(flushed, buf.String())
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		require.NoError(t, writer.Write(writeFrom{Name: name}))
+	}
+	assert.Equal(t, int64(5), writer.RowsWritten())
+	assert.Equal(t, []int64{2, 4}, checkpoints)
+	assert.Equal(t, []string{"Name\na\nb\n", "Name\na\nb\nc\nd\n"}, flushed)
+
+	require.NoError(t, writer.Flush())
+	assert.Equal(t, "Name\na\nb\nc\nd\ne\n", buf.String())
+}
+
+// TestWriter_Checkpoint_Errors verifies checkpoint options are validated and callback errors are
+// returned
+func TestWriter_Checkpoint_Errors(t *testing.T) {
+
+	var buf bytes.Buffer
+	checkpoint := func(rows int64) error { return nil }
+
+	_, err := NewWriter(&buf, &WriterOptions{ColumnNames: []string{"Name"}, Checkpoint: checkpoint})
+	assert.EqualError(t, err, "a checkpoint requires both a callback and an interval")
+
+	_, err = NewWriter(&buf, &WriterOptions{ColumnNames: []string{"Name"}, CheckpointEvery: 1})
+	assert.EqualError(t, err, "a checkpoint requires both a callback and an interval")
+
+	_, err = NewWriter(&buf, &WriterOptions{ColumnNames: []string{"Name"}, Checkpoint: checkpoint, CheckpointEvery: -1})
+	assert.EqualError(t, err, "checkpoint interval must not be negative, got -1")
+
+	stop := errors.New("stop")
+	writer, err := NewWriter(&buf, &WriterOptions{
+		ColumnNames:     []string{"Name"},
+		CheckpointEvery: 1,
+		Checkpoint:      func(rows int64) error { return stop },
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, stop, writer.WriteRecord([]string{"a"}))
+	assert.Equal(t, int64(1), writer.RowsWritten())
+}
+
+// TestWriter_RowsWritten verifies rows are counted across parts
+func TestWriter_RowsWritten(t *testing.T) {
+
+	var parts []*partBuffer
+	writer, err := NewPartWriter(func(part int) (io.WriteCloser, error) {
+		parts = append(parts, &partBuffer{})
+		return parts[len(parts)-1], nil
+	}, &WriterOptions{ColumnNames: []string{"Name"}, WriteHeaders: true, MaxRowsPerPart: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(0), writer.RowsWritten())
+	require.NoError(t, writer.WriteAll([]writeFrom{{Name: "a"}, {Name: "b"}, {Name: "c"}}))
+	require.NoError(t, writer.Close())
+	assert.Equal(t, int64(3), writer.RowsWritten())
+	assert.Len(t, parts, 2)
+}
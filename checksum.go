@@ -0,0 +1,80 @@
+package csvee
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"path/filepath"
+)
+
+// ChecksumAlgorithm names the hash a Writer computes over its output.
+type ChecksumAlgorithm string
+
+const (
+	ChecksumSHA256 ChecksumAlgorithm = "sha256"
+	ChecksumMD5    ChecksumAlgorithm = "md5"
+)
+
+func (a ChecksumAlgorithm) new() (hash.Hash, bool) {
+
+	switch a {
+	case ChecksumSHA256:
+		return sha256.New(), true
+	case ChecksumMD5:
+		return md5.New(), true
+	}
+
+	return nil, false
+}
+
+// Checksum returns the hex encoded checksum of everything flushed to the current part, or an empty
+// string if WriterOptions.Checksum is not set. Once the part is closed, it covers the whole part,
+// including the trailer.
+func (w *Writer) Checksum() string {
+
+	if w.hash == nil {
+		return ""
+	}
+
+	return hex.EncodeToString(w.hash.Sum(nil))
+}
+
+// writeTrailer writes the checksum of the current part as a final "#<algorithm>=<checksum>" line.
+func (w *Writer) writeTrailer() error {
+
+	if !w.checksumTrailer || w.trailerWritten {
+		return nil
+	}
+
+	if err := w.out.Flush(); err != nil {
+		return err
+	}
+
+	w.trailerWritten = true
+	if _, err := fmt.Fprintf(w.out, "#%s=%s\n", w.checksum, w.Checksum()); err != nil {
+		return err
+	}
+
+	return w.out.Flush()
+}
+
+// writeSidecar writes the checksum of the file at path to a sidecar file named after it with the
+// algorithm as an extension, in the format used by sha256sum and md5sum, and signs it if a Signer
+// was provided.
+func (w *Writer) writeSidecar(path string) error {
+
+	if !w.checksumSidecar {
+		return nil
+	}
+
+	sidecar := path + "." + string(w.checksum)
+	contents := []byte(fmt.Sprintf("%s  %s\n", w.Checksum(), filepath.Base(path)))
+
+	if err := writeFileAtomic(sidecar, contents, w.sync); err != nil {
+		return err
+	}
+
+	return w.writeSignature(sidecar, contents)
+}
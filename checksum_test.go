@@ -0,0 +1,121 @@
+package csvee
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha256Hex(data string) string {
+
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func md5Hex(data string) string {
+
+	sum := md5.Sum([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// TestWriter_Checksum verifies checksums are computed and written as trailers
+func TestWriter_Checksum(t *testing.T) {
+
+	const rows = "Name\na\n"
+	md5Trailed := rows + "#md5=" + md5Hex(rows) + "\n"
+
+	var testCases = []struct {
+		name        string
+		options     WriterOptions
+		expData     string
+		expChecksum string
+		expErr      bool
+	}{
+		{
+			name:    "none",
+			options: WriterOptions{},
+			expData: rows,
+		},
+		{
+			name:        "sha256",
+			options:     WriterOptions{Checksum: ChecksumSHA256},
+			expData:     rows,
+			expChecksum: sha256Hex(rows),
+		},
+		{
+			name:        "md5 trailer",
+			options:     WriterOptions{Checksum: ChecksumMD5, ChecksumTrailer: true},
+			expData:     md5Trailed,
+			expChecksum: md5Hex(md5Trailed),
+		},
+		{
+			name:    "unknown algorithm",
+			options: WriterOptions{Checksum: "crc"},
+			expErr:  true,
+		},
+		{
+			name:    "trailer without algorithm",
+			options: WriterOptions{ChecksumTrailer: true},
+			expErr:  true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.options.ColumnNames = []string{"Name"}
+			tt.options.WriteHeaders = true
+
+			var buf bytes.Buffer
+			writer, err := NewWriter(&buf, &tt.options)
+			require.Equal(t, tt.expErr, err != nil, err)
+			if tt.expErr {
+				return
+			}
+
+			require.NoError(t, writer.Write(writeFrom{Name: "a"}))
+			require.NoError(t, writer.Close())
+			require.NoError(t, writer.Close())
+
+			assert.Equal(t, tt.expData, buf.String())
+			assert.Equal(t, tt.expChecksum, writer.Checksum())
+		})
+	}
+}
+
+// TestWriter_ChecksumSidecar verifies sidecars are written beside files
+func TestWriter_ChecksumSidecar(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "csvee-checksum")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	options := &WriterOptions{ColumnNames: []string{"Name"}, Checksum: ChecksumSHA256, ChecksumSidecar: true, MaxRowsPerPart: 1}
+
+	writer, err := NewPartWriter(PartFiles(filepath.Join(dir, "part-%d.csv")), options)
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(writeFrom{Name: "a"}))
+	require.NoError(t, writer.Write(writeFrom{Name: "b"}))
+	require.NoError(t, writer.Close())
+
+	options.MaxRowsPerPart = 0
+	require.NoError(t, WriteFile(filepath.Join(dir, "out.csv"), writeFrom{Name: "c"}, options))
+
+	for name, expData := range map[string]string{"part-1.csv": "a\n", "part-2.csv": "b\n", "out.csv": "c\n"} {
+		sidecar, err := ioutil.ReadFile(filepath.Join(dir, name+".sha256"))
+		require.NoError(t, err)
+		assert.Equal(t, sha256Hex(expData)+"  "+name+"\n", string(sidecar))
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 6)
+}
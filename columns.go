@@ -0,0 +1,163 @@
+package csvee
+
+import (
+	"io"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// ReadColumns reads the remaining records column by column, appending the cells of each column in
+// dest to the slice its value points to, such as a *[]float64 or *[]time.Time. Columns that are not
+// in dest are not decoded. Slice elements may be of any type a struct field can have other than a
+// slice, and cells are parsed as ParseInto parses them. The reader's formats, number parsers,
+// converters, and whitespace policies apply, but Rules and the AfterRow hook do not, as there are
+// no rows to pass them. If an error occurs, every slice is left holding the rows decoded before it.
+func (r *Reader) ReadColumns(dest map[string]interface{}) error {
+
+	if dest == nil {
+		return ErrReadTargetNil
+	}
+
+	settings := r.settings()
+	columns := make([]columnVector, 0, len(dest))
+	for i, name := range r.ColumnNames {
+
+		target, exists := dest[name]
+		if !exists {
+			continue
+		}
+
+		vector, err := r.newColumnVector(i, name, target)
+		if err != nil {
+			return err
+		}
+		columns = append(columns, vector)
+	}
+
+	if len(columns) != len(dest) {
+		for name := range dest {
+			if _, exists := r.columnIndex(name); !exists {
+				return errors.Errorf("column %q not found", name)
+			}
+		}
+	}
+
+	return r.recordRun(func() (int, error) {
+		r.startPipeline()
+		defer r.stopPipeline()
+
+		var rowsDecoded int
+		for {
+			record, err := r.readRecord()
+			if err == io.EOF {
+				return rowsDecoded, nil
+			}
+			if err == nil && len(record) != len(r.ColumnNames) {
+				err = ErrColumnNamesMismatch
+			}
+
+			for i := 0; err == nil && i < len(columns); i++ {
+				vector := columns[i]
+				field, skip := applyWhitespacePolicy(settings[vector.column].whitespace, record[vector.column])
+				if r.isNull(field) {
+					field, skip = "", true
+				}
+				if err = vector.append(field, skip); err != nil {
+					err = &FieldError{Row: r.rowsRead, Column: r.ColumnNames[vector.column], Value: field, Err: err}
+				}
+			}
+
+			if err != nil {
+				for _, vector := range columns {
+					vector.slice.SetLen(vector.start + rowsDecoded)
+				}
+				return rowsDecoded, err
+			}
+			rowsDecoded++
+		}
+	})
+}
+
+// columnIndex returns the index of the named column.
+func (r *Reader) columnIndex(name string) (int, bool) {
+
+	for i, column := range r.ColumnNames {
+		if column == name {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// columnVector appends the cells of a single column to a slice.
+type columnVector struct {
+	column int
+	slice  reflect.Value
+	start  int
+	append func(field string, skip bool) error
+}
+
+// newColumnVector returns the vector for the column at index i, appending to target, which must be
+// a pointer to a slice. Strings are appended without reflection; other cells are converted as the
+// fields of structs are.
+func (r *Reader) newColumnVector(i int, name string, target interface{}) (columnVector, error) {
+
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Slice {
+		return columnVector{}, errors.Errorf("destination for column %q must be a pointer to a slice, got %T", name, target)
+	}
+
+	slice := value.Elem()
+	elemType := slice.Type().Elem()
+	vector := columnVector{column: i, slice: slice, start: slice.Len()}
+
+	if converter, exists := r.converters[name]; exists {
+		vector.append = func(field string, skip bool) error {
+			elem := reflect.New(elemType).Elem()
+			if !skip {
+				result, err := converter(field)
+				if err != nil {
+					return err
+				}
+				if result != nil {
+					if err := assignConverted(elem, reflect.ValueOf(result)); err != nil {
+						return err
+					}
+				}
+			}
+			slice.Set(reflect.Append(slice, elem))
+			return nil
+		}
+		return vector, nil
+	}
+
+	if baseType := getBaseType(elemType); !isUnmarshaler(baseType) {
+		if _, _, valid := getFieldTypeInfo(elemType); !valid || baseType.Kind() == reflect.Slice {
+			return columnVector{}, errors.Wrapf(ErrInvalidFieldType, "column %q", name)
+		}
+	}
+
+	switch s := target.(type) {
+	case *[]string:
+		vector.append = func(field string, skip bool) error {
+			*s = append(*s, field)
+			return nil
+		}
+	default:
+		vector.append = func(field string, skip bool) error {
+			if skip {
+				field = ""
+			}
+			elem, err := parseValue(field, elemType, r.cellParsing(i, name))
+			if err != nil {
+				return err
+			}
+			slice.Set(reflect.Append(slice, elem))
+			return nil
+		}
+	}
+
+	return vector, nil
+}
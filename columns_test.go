@@ -0,0 +1,139 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_ReadColumns verifies selected columns are decoded into slices
+func TestReader_ReadColumns(t *testing.T) {
+
+	const data = "Name,Price,Qty,Count,Shipped,Paid,Rating\na,1.5,1,10,2020-01-02,true,\nb, 2 ,2,20,2020-01-03,false,4\n"
+
+	reader, err := NewReader(strings.NewReader(data), &ReaderOptions{
+		ReadHeaders:   true,
+		ColumnFormats: map[string]string{"Shipped": "2006-01-02"},
+	})
+	require.NoError(t, err)
+
+	names := []string{"existing"}
+	var (
+		prices  []float64
+		qty     []int
+		counts  []int64
+		shipped []time.Time
+		paid    []bool
+		ratings []*uint8
+	)
+
+	err = reader.ReadColumns(map[string]interface{}{
+		"Name":    &names,
+		"Price":   &prices,
+		"Qty":     &qty,
+		"Count":   &counts,
+		"Shipped": &shipped,
+		"Paid":    &paid,
+		"Rating":  &ratings,
+	})
+	require.NoError(t, err)
+
+	four := uint8(4)
+	assert.Equal(t, []string{"existing", "a", "b"}, names)
+	assert.Equal(t, []float64{1.5, 2}, prices)
+	assert.Equal(t, []int{1, 2}, qty)
+	assert.Equal(t, []int64{10, 20}, counts)
+	assert.Equal(t, []time.Time{time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)}, shipped)
+	assert.Equal(t, []bool{true, false}, paid)
+	assert.Equal(t, []*uint8{nil, &four}, ratings)
+
+	reader, err = NewReader(strings.NewReader(data), &ReaderOptions{
+		ReadHeaders:   true,
+		ColumnFormats: map[string]string{"Shipped": "2006-01-02"},
+	})
+	require.NoError(t, err)
+
+	var days []int64
+	require.NoError(t, reader.ReadColumns(map[string]interface{}{"Shipped": &days}))
+	assert.Equal(t, []int64{1577923200, 1578009600}, days)
+}
+
+// TestReader_ReadColumnsErrors verifies invalid destinations and cells are reported
+func TestReader_ReadColumnsErrors(t *testing.T) {
+
+	const data = "Name,Qty\na,1\nb,x\n"
+
+	var testCases = []struct {
+		name   string
+		dest   func(names *[]string, qty *[]int) map[string]interface{}
+		expErr string
+		expLen int
+	}{
+		{
+			name:   "unknown column",
+			dest:   func(names *[]string, qty *[]int) map[string]interface{} { return map[string]interface{}{"Cost": qty} },
+			expErr: `column "Cost" not found`,
+		},
+		{
+			name:   "not a pointer to a slice",
+			dest:   func(names *[]string, qty *[]int) map[string]interface{} { return map[string]interface{}{"Qty": *qty} },
+			expErr: `destination for column "Qty" must be a pointer to a slice, got []int`,
+		},
+		{
+			name: "unsupported element type",
+			dest: func(names *[]string, qty *[]int) map[string]interface{} {
+				return map[string]interface{}{"Qty": &[][]int{}}
+			},
+			expErr: `column "Qty": ` + ErrInvalidFieldType.Error(),
+		},
+		{
+			name: "invalid cell",
+			dest: func(names *[]string, qty *[]int) map[string]interface{} {
+				return map[string]interface{}{"Name": names, "Qty": qty}
+			},
+			expErr: `row 2, column "Qty": invalid value "x" for int`,
+			expLen: 1,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader(data), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+
+			var (
+				names []string
+				qty   []int
+			)
+			err = reader.ReadColumns(tt.dest(&names, &qty))
+			assert.EqualError(t, err, tt.expErr)
+			assert.Len(t, names, tt.expLen)
+			assert.Len(t, qty, tt.expLen)
+		})
+	}
+}
+
+// BenchmarkReader_ReadColumns measures decoding two columns of a wide file into slices, for
+// comparison with BenchmarkReader_WideRead.
+func BenchmarkReader_ReadColumns(b *testing.B) {
+
+	inData := wideData(5000, 100)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		reader, err := NewReader(strings.NewReader(inData), &ReaderOptions{ReadHeaders: true})
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		var first []int
+		var last []float64
+		if err := reader.ReadColumns(map[string]interface{}{"C0": &first, "C4999": &last}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
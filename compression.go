@@ -0,0 +1,78 @@
+package csvee
+
+import (
+	"compress/gzip"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Compression names the compression applied to a Writer's output. Only gzip is built in; other
+// compressions, such as zstd, which the standard library has no encoder for, must be registered by
+// the caller with RegisterCompressor, e.g. by wrapping github.com/klauspost/compress/zstd.
+type Compression string
+
+const CompressionGzip Compression = "gzip"
+
+// Compressor returns a writer that compresses to w at the given level. A level of 0 selects the
+// compressor's default. If the returned writer has a Flush() error method, Writer.Flush calls it so
+// flushed rows can be decompressed by the reader on the other end.
+type Compressor func(w io.Writer, level int) (io.WriteCloser, error)
+
+var (
+	compressorRegistryMu sync.RWMutex
+	compressorRegistry   = map[Compression]Compressor{
+		CompressionGzip: newGzipWriter,
+	}
+)
+
+// RegisterCompressor registers compress as the Compressor for the named compression so it can be
+// used in WriterOptions.Compression. Registering a name that already exists replaces it.
+func RegisterCompressor(name Compression, compress Compressor) {
+
+	compressorRegistryMu.Lock()
+	defer compressorRegistryMu.Unlock()
+
+	compressorRegistry[name] = compress
+}
+
+// Extension returns the file extension conventionally used for c, e.g. ".gz", and for registered
+// compressions, c's name after a dot.
+func (c Compression) Extension() string {
+
+	switch c {
+	case "":
+		return ""
+	case CompressionGzip:
+		return ".gz"
+	}
+
+	return "." + string(c)
+}
+
+func (c Compression) compressor() (Compressor, error) {
+
+	compressorRegistryMu.RLock()
+	defer compressorRegistryMu.RUnlock()
+
+	compress, registered := compressorRegistry[c]
+	if !registered {
+		return nil, errors.Errorf("compression %q is not registered", c)
+	}
+
+	return compress, nil
+}
+
+func newGzipWriter(w io.Writer, level int) (io.WriteCloser, error) {
+
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	return gzip.NewWriterLevel(w, level)
+}
+
+type flusher interface {
+	Flush() error
+}
@@ -0,0 +1,87 @@
+package csvee
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+
+	return nil
+}
+
+// TestWriter_Compression verifies output is compressed and flushed rows can be decompressed
+func TestWriter_Compression(t *testing.T) {
+
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, &WriterOptions{
+		ColumnNames:      []string{"Name"},
+		WriteHeaders:     true,
+		Compression:      CompressionGzip,
+		CompressionLevel: gzip.BestCompression,
+		Checksum:         ChecksumSHA256,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Write(writeFrom{Name: "a"}))
+	require.NoError(t, writer.Flush())
+
+	// A flushed but unterminated stream yields the rows written so far.
+	gzipReader, err := gzip.NewReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	flushed := make([]byte, 64)
+	n, _ := io.ReadFull(gzipReader, flushed)
+	assert.Equal(t, "Name\na\n", string(flushed[:n]))
+
+	require.NoError(t, writer.Write(writeFrom{Name: "b"}))
+	require.NoError(t, writer.Close())
+
+	gzipReader, err = gzip.NewReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(gzipReader)
+	require.NoError(t, err)
+	assert.Equal(t, "Name\na\nb\n", string(data))
+	assert.Equal(t, sha256Hex(buf.String()), writer.Checksum())
+}
+
+// TestWriter_CompressionOptions verifies compression options are validated and compressors can be registered
+func TestWriter_CompressionOptions(t *testing.T) {
+
+	var testCases = []struct {
+		name    string
+		options WriterOptions
+		expErr  bool
+	}{
+		{name: "unregistered", options: WriterOptions{Compression: "zstd"}, expErr: true},
+		{name: "invalid level", options: WriterOptions{Compression: CompressionGzip, CompressionLevel: 42}, expErr: true},
+		{name: "trailer", options: WriterOptions{Compression: CompressionGzip, Checksum: ChecksumMD5, ChecksumTrailer: true}, expErr: true},
+		{name: "registered", options: WriterOptions{Compression: "identity"}},
+	}
+
+	RegisterCompressor("identity", func(w io.Writer, level int) (io.WriteCloser, error) {
+		return nopWriteCloser{w}, nil
+	})
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.options.ColumnNames = []string{"Name"}
+			_, err := NewWriter(&bytes.Buffer{}, &tt.options)
+			assert.Equal(t, tt.expErr, err != nil, err)
+		})
+	}
+
+	assert.Equal(t, ".gz", CompressionGzip.Extension())
+	assert.Equal(t, ".identity", Compression("identity").Extension())
+	assert.Equal(t, "", Compression("").Extension())
+}
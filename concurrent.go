@@ -0,0 +1,217 @@
+package csvee
+
+import (
+	"io"
+	"reflect"
+	"sync"
+)
+
+// concurrentBatchSize is the number of rows ReadAllConcurrent hands to a worker at a time.
+const concurrentBatchSize = 64
+
+// decodeBatch is a run of consecutive rows decoded by a single worker.
+type decodeBatch struct {
+	seq     int
+	rows    []row
+	raw     [][]string
+	rowNums []int
+	lines   []int
+	spans   []recordSpan
+
+	// values holds the decoded rows. If err is set, it occurred decoding the row after them.
+	values []reflect.Value
+	err    error
+}
+
+// ReadAllConcurrent reads all the remaining records into v, a pointer to a slice of structs, as
+// ReadAll does, but decodes them on up to workers goroutines. Records are still read, and the
+// BeforeRow and AfterRow hooks still called, in order on the calling goroutine, and the slice holds
+// the rows in the order they were read. Converters, Rule checks, and the Validate hook must be safe
+// for concurrent use. If a row cannot be decoded, some of the rows after it may already have been
+// read.
+//
+// If workers is one or less, the reader detects leading zeros, which it warns of in row order,
+// traces rows, or its OnError policy is not ErrorFail, ReadAllConcurrent is the same as ReadAll.
+func (r *Reader) ReadAllConcurrent(v interface{}, workers int) error {
+
+	if _, isFrame := v.(*Frame); isFrame || workers <= 1 || r.detectLeadingZeros || r.onTrace != nil || r.traceWriter != nil || r.onError != ErrorFail {
+		return r.ReadAll(v)
+	}
+
+	direct, base, isPtr, err := readAllTarget(v)
+	if err != nil {
+		return err
+	}
+
+	return r.recordRun(func() (int, error) {
+		return r.readAllConcurrent(direct, base, isPtr, workers)
+	})
+}
+
+// readAllConcurrent reads batches of rows and passes them to the workers, appending the decoded
+// rows to direct in order as their batches complete. At most two batches per worker are in flight.
+func (r *Reader) readAllConcurrent(direct reflect.Value, base reflect.Type, isPtr bool, workers int) (int, error) {
+
+	if r.expectedRows > 0 {
+		growSlice(direct, direct.Len()+r.expectedRows)
+	}
+
+	// Compute the lazily built settings and column index now, so the workers only read them.
+	r.settings()
+	r.LastRecord()
+
+	allocSize := 1
+	if isPtr {
+		allocSize = r.slabSize
+	}
+
+	maxInFlight := 2 * workers
+	jobs := make(chan *decodeBatch)
+	results := make(chan *decodeBatch, maxInFlight)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+
+		// Each worker decodes with its own copy of the reader, on which the row being decoded is
+		// set, since errors and conditional formats refer to it.
+		worker := *r
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			alloc := newSlabAllocator(base, allocSize)
+			for batch := range jobs {
+				worker.decodeBatch(batch, alloc)
+				results <- batch
+			}
+		}()
+	}
+	defer func() {
+		close(jobs)
+		wg.Wait()
+	}()
+
+	// The pipeline is only started once the workers have copied the reader, as it updates it.
+	r.startPipeline()
+	defer r.stopPipeline()
+
+	pending := make(map[int]*decodeBatch)
+	var sent, next, inFlight, rowsDecoded int
+	var readErr error
+	for {
+		for readErr == nil && inFlight < maxInFlight {
+			var batch *decodeBatch
+			batch, readErr = r.readBatch(base)
+			if len(batch.rows) > 0 {
+				batch.seq = sent
+				sent++
+				inFlight++
+				jobs <- batch
+			}
+		}
+
+		if inFlight == 0 {
+			break
+		}
+
+		done := <-results
+		inFlight--
+		pending[done.seq] = done
+
+		for batch, exists := pending[next]; exists; batch, exists = pending[next] {
+			delete(pending, next)
+			next++
+
+			n, err := r.collectBatch(direct, batch, isPtr)
+			rowsDecoded += n
+			if err != nil {
+				return rowsDecoded, err
+			}
+		}
+	}
+
+	if readErr == io.EOF {
+		return rowsDecoded, nil
+	}
+
+	return rowsDecoded, readErr
+}
+
+// readBatch reads up to concurrentBatchSize rows, returning the error that stopped it early along
+// with the rows read before it.
+func (r *Reader) readBatch(base reflect.Type) (*decodeBatch, error) {
+
+	batch := &decodeBatch{}
+	for len(batch.rows) < concurrentBatchSize {
+
+		nextRow, err := r.read(base)
+		if err != nil {
+			return batch, err
+		}
+
+		// The batch is decoded after later records are read, which may reuse these.
+		raw := r.lastRecord
+		if r.CSVReader.ReuseRecord {
+			raw = append([]string(nil), raw...)
+			nextRow.record = append([]string(nil), nextRow.record...)
+		}
+
+		batch.rows = append(batch.rows, nextRow)
+		batch.raw = append(batch.raw, raw)
+		batch.rowNums = append(batch.rowNums, r.rowsRead)
+		batch.lines = append(batch.lines, r.lastLine)
+		batch.spans = append(batch.spans, r.lastSpan.detached(r))
+	}
+
+	return batch, nil
+}
+
+// decodeBatch decodes the rows of batch, stopping at the first that cannot be decoded.
+func (r *Reader) decodeBatch(batch *decodeBatch, alloc *slabAllocator) {
+
+	batch.values = make([]reflect.Value, 0, len(batch.rows))
+	for i, row := range batch.rows {
+
+		r.rowsRead, r.lastRecord, r.lastLine = batch.rowNums[i], batch.raw[i], batch.lines[i]
+		r.lastSpan = batch.spans[i]
+
+		value := alloc.new()
+		if batch.err = r.decode(row, value.Interface()); batch.err != nil {
+			return
+		}
+		batch.values = append(batch.values, value)
+	}
+}
+
+// collectBatch passes the decoded rows of batch to the AfterRow hook and appends them to direct,
+// returning the number appended.
+func (r *Reader) collectBatch(direct reflect.Value, batch *decodeBatch, isPtr bool) (int, error) {
+
+	if length := direct.Len() + len(batch.values); length > direct.Cap() {
+		growSlice(direct, 2*direct.Cap()+readAllMinGrowth)
+	}
+
+	for i, value := range batch.values {
+
+		if r.afterRow != nil {
+			if err := r.afterRow(batch.rowNums[i], value.Interface()); err != nil {
+				return i, err
+			}
+		}
+
+		direct.SetLen(direct.Len() + 1)
+		if isPtr {
+			direct.Index(direct.Len() - 1).Set(value)
+		} else {
+			direct.Index(direct.Len() - 1).Set(value.Elem())
+		}
+	}
+
+	// The worker that decoded the batch counted its error on its own copy of the reader.
+	if batch.err != nil && isRowError(batch.err) {
+		r.counts.errored++
+	}
+
+	return len(batch.values), batch.err
+}
@@ -0,0 +1,145 @@
+package csvee
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type concurrentRow struct {
+	ID    int
+	Name  string
+	Score float64
+	Tags  []string
+}
+
+func concurrentInput(rows int) string {
+
+	var b strings.Builder
+	b.WriteString("ID,Name,Score,Tags\n")
+	for i := 1; i <= rows; i++ {
+		fmt.Fprintf(&b, "%d,name %d,%d.5,\"a,b%d\"\n", i, i, i, i)
+	}
+
+	return b.String()
+}
+
+// TestReader_ReadAllConcurrent verifies rows decoded concurrently match those decoded by ReadAll, in order
+func TestReader_ReadAllConcurrent(t *testing.T) {
+
+	input := concurrentInput(1000)
+
+	reader, err := NewReader(strings.NewReader(input), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	var expected []concurrentRow
+	require.NoError(t, reader.ReadAll(&expected))
+
+	tests := []struct {
+		name    string
+		workers int
+		options ReaderOptions
+	}{
+		{name: "sequential", workers: 1},
+		{name: "workers", workers: 4},
+		{name: "pipelined", workers: 8, options: ReaderOptions{PipelineDepth: 16}},
+		{name: "slabs", workers: 3, options: ReaderOptions{SlabSize: 10, ExpectedRows: 1000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.options.ReadHeaders = true
+			var hooked []int
+			tt.options.AfterRow = func(n int, v interface{}) error {
+				hooked = append(hooked, n)
+				return nil
+			}
+
+			reader, err := NewReader(strings.NewReader(input), &tt.options)
+			require.NoError(t, err)
+
+			var rows []concurrentRow
+			require.NoError(t, reader.ReadAllConcurrent(&rows, tt.workers))
+			assert.Equal(t, expected, rows)
+
+			require.Len(t, hooked, 1000)
+			for i, n := range hooked {
+				assert.Equal(t, i+1, n)
+			}
+
+			reader, err = NewReader(strings.NewReader(input), &tt.options)
+			require.NoError(t, err)
+
+			var ptrs []*concurrentRow
+			require.NoError(t, reader.ReadAllConcurrent(&ptrs, tt.workers))
+			require.Len(t, ptrs, 1000)
+			for i, row := range ptrs {
+				assert.Equal(t, expected[i], *row)
+			}
+		})
+	}
+}
+
+// TestReader_ReadAllConcurrentErrors verifies the first error in row order is returned with the rows before it
+func TestReader_ReadAllConcurrentErrors(t *testing.T) {
+
+	input := concurrentInput(300)
+	input = strings.Replace(input, "\n150,name 150,150.5", "\n150,name 150,bad", 1)
+	input = strings.Replace(input, "\n250,name 250,250.5", "\n250,name 250,bad", 1)
+
+	reader, err := NewReader(strings.NewReader(input), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	var rows []concurrentRow
+	err = reader.ReadAllConcurrent(&rows, 4)
+	var fieldErr *FieldError
+	require.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, 150, fieldErr.Row)
+	assert.Equal(t, "Score", fieldErr.Column)
+	assert.Len(t, rows, 149)
+
+	stop := errors.New("stop")
+	reader, err = NewReader(strings.NewReader(concurrentInput(300)), &ReaderOptions{
+		ReadHeaders: true,
+		AfterRow: func(n int, v interface{}) error {
+			if n == 70 {
+				return stop
+			}
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	rows = nil
+	assert.Equal(t, stop, reader.ReadAllConcurrent(&rows, 4))
+	assert.Len(t, rows, 69)
+
+	reader, err = NewReader(strings.NewReader("ID,Name\n1,a\n2\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	reader.CSVReader.FieldsPerRecord = -1
+
+	rows = nil
+	assert.Equal(t, ErrColumnNamesMismatch, reader.ReadAllConcurrent(&rows, 2))
+	assert.Len(t, rows, 1)
+}
+
+func BenchmarkReader_ReadAllConcurrent(b *testing.B) {
+
+	input := concurrentInput(20000)
+	b.SetBytes(int64(len(input)))
+
+	for i := 0; i < b.N; i++ {
+		reader, err := NewReader(strings.NewReader(input), &ReaderOptions{ReadHeaders: true})
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		var rows []concurrentRow
+		if err := reader.ReadAllConcurrent(&rows, 4); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
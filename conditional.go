@@ -0,0 +1,54 @@
+package csvee
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ConditionalFormat chooses the format of a column from the value of another column in the same
+// row, so that a per-row indicator such as a locale can determine how the column is parsed.
+type ConditionalFormat struct {
+	// Column is the column whose value selects the format.
+	Column string
+
+	// Formats maps values of Column, with surrounding whitespace trimmed, to formats. Rows whose
+	// value is not in Formats use the column's entry in ColumnFormats, if any.
+	Formats map[string]string
+}
+
+// columnFormat returns the format for the named column in the record most recently read, which is
+// the reader's default time format if the column has none.
+func (r *Reader) columnFormat(column string) Format {
+
+	if conditional, exists := r.conditionalFormats[column]; exists {
+		value, _ := r.LastRecord().Column(conditional.Column)
+		if format, exists := conditional.Formats[strings.TrimSpace(value)]; exists {
+			return Format(format)
+		}
+	}
+
+	if format, exists := r.ColumnFormats[column]; exists && format != "" {
+		return Format(format)
+	}
+
+	return r.defaultTimeFormat
+}
+
+func validateConditionalFormats(formats map[string]ConditionalFormat) error {
+
+	for _, column := range sortedKeys(formats) {
+		conditional := formats[column]
+		if conditional.Column == column {
+			return errors.Errorf("conditional format for column %q cannot depend on itself", column)
+		}
+		for _, value := range sortedKeys(conditional.Formats) {
+			format := conditional.Formats[value]
+			if !Format(format).Valid() {
+				return errors.Wrapf(&FormatError{Column: column, Format: format}, "when %s is %q", conditional.Column, value)
+			}
+		}
+	}
+
+	return nil
+}
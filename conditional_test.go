@@ -0,0 +1,84 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type localeRow struct {
+	Locale  string
+	Shipped time.Time
+}
+
+// TestReader_ConditionalFormats verifies formats can be chosen by another column of the row
+func TestReader_ConditionalFormats(t *testing.T) {
+
+	options := &ReaderOptions{
+		ReadHeaders:   true,
+		ColumnFormats: map[string]string{"Shipped": "2006-01-02"},
+		ConditionalFormats: map[string]ConditionalFormat{
+			"Shipped": {Column: "Locale", Formats: map[string]string{"en-US": "01/02/2006", "en-GB": "02/01/2006", "epoch": TimeFormatUnix}},
+		},
+	}
+
+	reader, err := NewReader(strings.NewReader("Locale,Shipped\nen-US,03/04/2020\n en-GB ,03/04/2020\nde-DE,2020-04-03\nepoch,0\n"), options)
+	require.NoError(t, err)
+
+	var rows []localeRow
+	require.NoError(t, reader.ReadAll(&rows))
+	require.Len(t, rows, 4)
+
+	assert.Equal(t, time.Date(2020, 3, 4, 0, 0, 0, 0, time.UTC), rows[0].Shipped)
+	assert.Equal(t, time.Date(2020, 4, 3, 0, 0, 0, 0, time.UTC), rows[1].Shipped)
+	assert.Equal(t, time.Date(2020, 4, 3, 0, 0, 0, 0, time.UTC), rows[2].Shipped)
+	assert.True(t, time.Unix(0, 0).Equal(rows[3].Shipped))
+
+	reader, err = NewReader(strings.NewReader("Locale,Shipped\nen-GB,2020-04-03\n"), options)
+	require.NoError(t, err)
+
+	var row localeRow
+	assert.Error(t, reader.Read(&row))
+}
+
+// TestReader_ConditionalFormatsInvalid verifies conditional formats are validated
+func TestReader_ConditionalFormatsInvalid(t *testing.T) {
+
+	var testCases = []struct {
+		name    string
+		formats map[string]ConditionalFormat
+		expErr  string
+	}{
+		{
+			name:    "unknown column",
+			formats: map[string]ConditionalFormat{"Received": {Column: "Locale"}},
+			expErr:  `conditional format provided for unknown column "Received"`,
+		},
+		{
+			name:    "unknown selecting column",
+			formats: map[string]ConditionalFormat{"Shipped": {Column: "Country"}},
+			expErr:  `conditional format for column "Shipped" depends on unknown column "Country"`,
+		},
+		{
+			name:    "self reference",
+			formats: map[string]ConditionalFormat{"Shipped": {Column: "Shipped"}},
+			expErr:  `conditional format for column "Shipped" cannot depend on itself`,
+		},
+		{
+			name:    "invalid format",
+			formats: map[string]ConditionalFormat{"Shipped": {Column: "Locale", Formats: map[string]string{"en-US": "mdy"}}},
+			expErr:  `when Locale is "en-US": unknown column format "mdy" for column Shipped`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			_, err := NewReader(strings.NewReader("Locale,Shipped\n"), &ReaderOptions{ReadHeaders: true, ConditionalFormats: tt.formats})
+			assert.EqualError(t, err, tt.expErr)
+		})
+	}
+}
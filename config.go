@@ -0,0 +1,169 @@
+package csvee
+
+import (
+	"sort"
+	"time"
+)
+
+// ReaderConfig is a snapshot of a Reader's effective configuration, with defaults applied and the
+// settings of each column resolved, for logging and debugging. Changing it does not change the
+// Reader.
+type ReaderConfig struct {
+	// Dialect is the syntax the input is read with. Its Delimiter is never zero.
+	Dialect Dialect
+
+	// Encoding is the character encoding the input is read in, EncodingDetect if it is detected.
+	Encoding Encoding
+
+	// ReadHeaders reports whether the column names were read from the input, and Headers holds them
+	// as they were read, before the HeaderNormalizer or any saved mapping renamed them.
+	// HeaderNormalizer reports whether the reader has one.
+	ReadHeaders      bool
+	Headers          []string
+	HeaderNormalizer bool
+
+	// Columns describes each column, in the order they appear in each record.
+	Columns []ColumnConfig
+
+	// Bindings maps the name of each struct type the reader has decoded into, such as "main.Row", to
+	// how its columns are matched to the type's fields.
+	Bindings map[string][]ColumnMatch
+
+	// NullValues are the cell values read as null, sorted, or nil if none are.
+	NullValues []string
+
+	// Location is the location times without zone information are parsed in, or nil for UTC.
+	Location *time.Location
+
+	// LeapSeconds, ZeroDates, and MaxDates are the policies placeholder times are decoded with.
+	LeapSeconds DatePolicy
+	ZeroDates   DatePolicy
+	MaxDates    DatePolicy
+
+	BoolParsing         BoolParsing
+	NestedSeparator     string
+	FuzzyMatchThreshold float64
+	Merge               bool
+	AllowRaggedRows     bool
+	RepeatedColumns     bool
+	MaxGroupElements    int
+	UnsafeFastPath      bool
+	PipelineDepth       int
+	BufferSize          int
+	RowTimeout          time.Duration
+	OnError             ErrorPolicy
+	ErrorBudget         int
+	PartialResults      bool
+	Rules               int
+}
+
+// ColumnConfig is the resolved configuration of a single column.
+type ColumnConfig struct {
+	Name string
+
+	// Format is the format its times are parsed in, or empty for RFC 3339. ConditionalFormat, if
+	// set, takes precedence over it.
+	Format            string
+	ConditionalFormat *ConditionalFormat
+
+	Whitespace     WhitespacePolicy
+	SliceDelimiter string
+
+	// IntegerBase is the base integers are parsed in, 0 if it is detected from their prefix.
+	// NumberParser reports whether a NumberParser parses its numbers instead.
+	IntegerBase  int
+	NumberParser bool
+
+	// Converter reports whether a converter decodes its cells in place of the usual parsing.
+	Converter bool
+
+	// Kind is the kind its cells are decoded as when reading into a map[string]interface{}.
+	Kind ColumnKind
+}
+
+// Config returns a snapshot of the reader's effective configuration.
+func (r *Reader) Config() ReaderConfig {
+
+	config := ReaderConfig{
+		Dialect: Dialect{
+			Delimiter:        r.CSVReader.Comma,
+			Comment:          r.CSVReader.Comment,
+			LazyQuotes:       r.CSVReader.LazyQuotes,
+			TrimLeadingSpace: r.CSVReader.TrimLeadingSpace,
+		},
+		Encoding:            r.encoding,
+		ReadHeaders:         r.readHeaders,
+		HeaderNormalizer:    r.headerNormalizer != nil,
+		Headers:             append([]string(nil), r.headers...),
+		Columns:             make([]ColumnConfig, len(r.ColumnNames)),
+		Bindings:            make(map[string][]ColumnMatch, len(r.plans)),
+		Location:            r.location,
+		LeapSeconds:         r.dates.leapSeconds,
+		ZeroDates:           r.dates.zeroDates,
+		MaxDates:            r.dates.maxDates,
+		BoolParsing:         r.boolParsing,
+		NestedSeparator:     r.planConfig.separator,
+		FuzzyMatchThreshold: r.planConfig.fuzzyThreshold,
+		Merge:               r.merge,
+		AllowRaggedRows:     r.allowRaggedRows,
+		RepeatedColumns:     r.planConfig.repeated,
+		MaxGroupElements:    r.planConfig.maxElements,
+		UnsafeFastPath:      r.fastPath,
+		PipelineDepth:       r.pipelineDepth,
+		BufferSize:          r.bufferSize,
+		RowTimeout:          r.rowTimeout,
+		OnError:             r.onError,
+		ErrorBudget:         r.errorBudget,
+		PartialResults:      r.partialResults,
+		Rules:               len(r.rules),
+	}
+
+	settings := r.settings()
+	for i, name := range r.ColumnNames {
+
+		column := ColumnConfig{
+			Name:           name,
+			Format:         r.ColumnFormats[name],
+			Whitespace:     settings[i].whitespace,
+			SliceDelimiter: settings[i].sliceDelimiter,
+			IntegerBase:    10,
+			Kind:           r.columnTypes[name],
+		}
+
+		if column.Format == "" {
+			column.Format = string(r.defaultTimeFormat)
+		}
+
+		if conditional, exists := r.conditionalFormats[name]; exists {
+			formats := make(map[string]string, len(conditional.Formats))
+			for k, v := range conditional.Formats {
+				formats[k] = v
+			}
+			column.ConditionalFormat = &ConditionalFormat{Column: conditional.Column, Formats: formats}
+		}
+
+		if base, exists := r.integerBases[name]; exists {
+			column.IntegerBase = base
+		} else if _, exists := r.numberParsers[name]; exists {
+			column.NumberParser = true
+		}
+
+		_, column.Converter = r.converters[name]
+
+		config.Columns[i] = column
+	}
+
+	for vType, plan := range r.plans {
+		config.Bindings[vType.String()] = append([]ColumnMatch(nil), plan.matches...)
+	}
+
+	if r.nullValues != nil {
+		config.NullValues = make([]string, 0, len(r.nullValues))
+		for value := range r.nullValues {
+			config.NullValues = append(config.NullValues, value)
+		}
+		sort.Strings(config.NullValues)
+	}
+
+	return config
+}
@@ -0,0 +1,79 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type configRow struct {
+	Name  string
+	Count int
+	When  string `csvee:"when"`
+}
+
+// TestReader_Config verifies the snapshot reports the reader's resolved configuration
+func TestReader_Config(t *testing.T) {
+
+	input := "Name;Count;when;Extra\nann;0x1F;2021-02-13;x\n"
+	reader, err := NewReader(strings.NewReader(input), &ReaderOptions{
+		ReadHeaders:        true,
+		Dialect:            &DialectSemicolonEU,
+		LazyQuotes:         true,
+		ColumnFormats:      map[string]string{"when": "2006-01-02"},
+		IntegerBases:       map[string]int{"Count": 0},
+		WhitespacePolicy:   WhitespaceEmpty,
+		WhitespacePolicies: map[string]WhitespacePolicy{"Name": WhitespaceNull},
+		SliceDelimiters:    map[string]string{"Extra": "|"},
+		ColumnTypes:        map[string]ColumnKind{"Count": KindInt},
+		NullValues:         []string{"NULL", " N/A "},
+		ColumnConverters:   map[string]Converter{"Extra": func(s string) (interface{}, error) { return s, nil }},
+	})
+	require.NoError(t, err)
+
+	var row configRow
+	require.NoError(t, reader.Read(&row))
+
+	config := reader.Config()
+	assert.Equal(t, Dialect{Delimiter: ';', LazyQuotes: true}, config.Dialect)
+	assert.True(t, config.ReadHeaders)
+	assert.Equal(t, []string{"Name", "Count", "when", "Extra"}, config.Headers)
+	assert.Equal(t, []string{"N/A", "NULL"}, config.NullValues)
+	assert.Equal(t, ".", config.NestedSeparator)
+	assert.Equal(t, []ColumnConfig{
+		{Name: "Name", Whitespace: WhitespaceNull, SliceDelimiter: ",", IntegerBase: 10},
+		{Name: "Count", Whitespace: WhitespaceEmpty, SliceDelimiter: ",", IntegerBase: 0, Kind: KindInt},
+		{Name: "when", Format: "2006-01-02", Whitespace: WhitespaceEmpty, SliceDelimiter: ",", IntegerBase: 10},
+		{Name: "Extra", Whitespace: WhitespaceEmpty, SliceDelimiter: "|", IntegerBase: 10, Converter: true},
+	}, config.Columns)
+
+	require.Contains(t, config.Bindings, "csvee.configRow")
+	matches := config.Bindings["csvee.configRow"]
+	require.Len(t, matches, 4)
+	assert.Equal(t, ColumnMatch{Column: "when", Field: "When", Method: MatchExact, Similarity: 1}, matches[2])
+	assert.Equal(t, MatchNone, matches[3].Method)
+
+	// The snapshot is a copy.
+	config.Columns[0].Name = "changed"
+	config.Headers[0] = "changed"
+	assert.Equal(t, "Name", reader.Config().Columns[0].Name)
+	assert.Equal(t, "Name", reader.Config().Headers[0])
+}
+
+// TestReader_Config_Defaults verifies the snapshot of a reader with no options set reports the
+// defaults
+func TestReader_Config_Defaults(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader(""), &ReaderOptions{ColumnNames: []string{"A"}})
+	require.NoError(t, err)
+
+	config := reader.Config()
+	assert.Equal(t, Dialect{Delimiter: ','}, config.Dialect)
+	assert.False(t, config.ReadHeaders)
+	assert.Nil(t, config.Headers)
+	assert.Nil(t, config.NullValues)
+	assert.Empty(t, config.Bindings)
+	assert.Equal(t, []ColumnConfig{{Name: "A", SliceDelimiter: ",", IntegerBase: 10}}, config.Columns)
+}
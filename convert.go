@@ -0,0 +1,191 @@
+package csvee
+
+import (
+	"io"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// HeaderMode controls the header row ToCSV writes.
+type HeaderMode int
+
+const (
+	// HeaderDefault writes the Writer's column names if its WriteHeaders option is set.
+	HeaderDefault HeaderMode = iota
+
+	// HeaderRenamed writes the Writer's column names, which are the reader's column names after any
+	// HeaderNormalizer or saved mapping renamed them, whether or not WriteHeaders is set.
+	HeaderRenamed
+
+	// HeaderOriginal writes, for each of the Writer's columns filled from one of the reader's, the
+	// header that column had in the input, as Reader.Headers holds it, and the Writer's column name
+	// for the others, whether or not WriteHeaders is set.
+	HeaderOriginal
+
+	// HeaderSuppress writes no header row, even if WriteHeaders is set.
+	HeaderSuppress
+)
+
+// CSVOptions configures ToCSV.
+type CSVOptions struct {
+	// ExplodeColumns flattens the JSON objects held in columns into columns of their own. A
+	// column's flattened keys fill the Writer's columns of the same name.
+	ExplodeColumns []ExplodedColumn
+
+	// ColumnKinds maps the reader's column names to the kind their cells are coerced to, so that
+	// they are checked and normalized without decoding into a struct. Cells are parsed in the
+	// reader's formats and number parsers, as JSONOptions.ColumnKinds describes, and written as the
+	// Writer writes fields of that kind, with times in the Writer's format for the column. Cells that
+	// cannot be parsed are an error.
+	ColumnKinds map[string]ColumnKind
+
+	// IgnoreUnknownColumns drops cells, including flattened values, whose columns the Writer does
+	// not have, which are otherwise an error.
+	IgnoreUnknownColumns bool
+
+	// Header controls the header row written. Defaults to HeaderDefault.
+	Header HeaderMode
+}
+
+// ToCSV copies the reader's remaining records to w, filling each of w's columns from the reader's
+// column of the same name and leaving those the reader does not have empty. Cells are copied as
+// they are unless opts gives their columns a kind. w is flushed once every record has been written;
+// opts may be nil.
+func ToCSV(reader *Reader, w *Writer, opts *CSVOptions) error {
+
+	if w == nil {
+		return ErrWriterNil
+	}
+
+	if opts == nil {
+		opts = &CSVOptions{}
+	}
+
+	exploded, err := reader.explodedColumns(opts.ExplodeColumns)
+	if err != nil {
+		return err
+	}
+
+	kinds, err := reader.cellKinds(opts.ColumnKinds, false)
+	if err != nil {
+		return err
+	}
+
+	columns := make(map[string]int, len(w.ColumnNames))
+	for i, name := range w.ColumnNames {
+		columns[name] = i
+	}
+
+	// targets maps the reader's columns to the Writer's, or -1 for those that are dropped.
+	targets := make([]int, len(reader.ColumnNames))
+	for j, name := range reader.ColumnNames {
+		targets[j] = -1
+		if exploded[j] != nil && !exploded[j].KeepColumn {
+			continue
+		}
+		if i, exists := columns[name]; exists {
+			targets[j] = i
+		} else if !opts.IgnoreUnknownColumns {
+			return errors.Errorf("column %q is not a column of the writer", name)
+		}
+	}
+
+	restore := w.overrideHeader(opts.Header, reader.headerNames(w, targets))
+	defer restore()
+
+	err = reader.recordRun(func() (int, error) {
+		reader.startPipeline()
+		defer reader.stopPipeline()
+
+		var rowsWritten int
+		for {
+			record, err := reader.readRecord()
+			if err == io.EOF {
+				return rowsWritten, nil
+			}
+			if err == nil && len(record) != len(reader.ColumnNames) {
+				err = ErrColumnNamesMismatch
+			}
+			if err != nil {
+				return rowsWritten, err
+			}
+
+			out := make([]string, len(w.ColumnNames))
+			filled := make([]bool, len(w.ColumnNames))
+			for j, cell := range record {
+
+				if i := targets[j]; i >= 0 {
+					coerced, err := reader.coerceCell(j, cell, kinds, w, Format(w.ColumnFormats[w.ColumnNames[i]]))
+					if err != nil {
+						return rowsWritten, &FieldError{Row: reader.rowsRead, Column: reader.ColumnNames[j], Value: cell, Err: err}
+					}
+					out[i], filled[i] = coerced, true
+				}
+
+				if exploded[j] == nil {
+					continue
+				}
+
+				err := reader.explode(exploded[j], cell, func(key string, value interface{}) error {
+					i, exists := columns[key]
+					if !exists {
+						if opts.IgnoreUnknownColumns {
+							return nil
+						}
+						return errors.Errorf("key %q is not a column of the writer", key)
+					}
+					if filled[i] {
+						return errors.Errorf("more than one value for column %q", key)
+					}
+					cell, err := flattenedCell(value)
+					out[i], filled[i] = cell, true
+					return err
+				})
+				if err != nil {
+					return rowsWritten, &FieldError{Row: reader.rowsRead, Column: reader.ColumnNames[j], Value: cell, Err: err}
+				}
+			}
+
+			if err := w.WriteRecord(out); err != nil {
+				return rowsWritten, err
+			}
+			rowsWritten++
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+// headerNames returns the header HeaderOriginal writes for w, whose columns the reader's fill as
+// targets maps them.
+func (r *Reader) headerNames(w *Writer, targets []int) []string {
+
+	names := append([]string(nil), w.ColumnNames...)
+	for j, i := range targets {
+		if i >= 0 && j < len(r.headers) {
+			names[i] = r.headers[j]
+		}
+	}
+
+	return names
+}
+
+// coerceCell parses cell, from the column at index j, as the kind kinds gives it and formats it as
+// w would, writing times in format. Cells of columns without a kind are returned as they are.
+func (r *Reader) coerceCell(j int, cell string, kinds cellKinds, w *Writer, format Format) (string, error) {
+
+	if !kinds.typed[j] {
+		return cell, nil
+	}
+
+	value, err := r.typedCell(j, cell, kinds)
+	if err != nil || value == nil {
+		return "", err
+	}
+
+	return w.formatValue(reflect.ValueOf(value), format, defaultSliceDelimiter, nil)
+}
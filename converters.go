@@ -0,0 +1,100 @@
+package csvee
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Converter converts a cell to the value assigned to its field, for cells that need custom parsing
+// such as currency amounts, percentages, or enums. The value must be assignable to the field's
+// type, or to the type it points to, or be a number or string convertible to it. A nil value sets
+// the field to its zero value.
+type Converter func(field string) (interface{}, error)
+
+var (
+	converterRegistryMu sync.RWMutex
+	converterRegistry   = map[string]Converter{}
+)
+
+// RegisterConverter registers converter under name so it can be used in
+// ReaderOptions.ColumnConverterNames. Registering a name that already exists replaces its converter.
+func RegisterConverter(name string, converter Converter) {
+
+	converterRegistryMu.Lock()
+	defer converterRegistryMu.Unlock()
+
+	converterRegistry[name] = converter
+}
+
+// registeredConverter returns the converter registered under name.
+func registeredConverter(name string) (Converter, bool) {
+
+	converterRegistryMu.RLock()
+	defer converterRegistryMu.RUnlock()
+
+	converter, registered := converterRegistry[name]
+	return converter, registered
+}
+
+// convertedColumns returns the names of the columns with converters as a single key.
+func convertedColumns(converters map[string]Converter) string {
+
+	columns := make([]string, 0, len(converters))
+	for column := range converters {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	return strings.Join(columns, "\x00")
+}
+
+// setConverted sets the column's field on the struct pointed to by structPtr to the result of its
+// converter.
+func (r *Reader) setConverted(structPtr reflect.Value, col columnPlan, field string) error {
+
+	result, err := r.converters[col.name](field)
+	if err != nil {
+		return &FieldError{Row: r.rowsRead, Column: col.name, Value: field, Err: err}
+	}
+
+	v := fieldAt(structPtr.Elem(), col.field.Index)
+	if result == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	if err := assignConverted(v, reflect.ValueOf(result)); err != nil {
+		return &FieldError{Row: r.rowsRead, Column: col.name, Value: field, Err: err}
+	}
+
+	return nil
+}
+
+// assignConverted sets v to value, allocating v if it is a nil pointer and value is of the type it
+// points to.
+func assignConverted(v, value reflect.Value) error {
+
+	for {
+		t := value.Type()
+		switch {
+		case t.AssignableTo(v.Type()):
+			v.Set(value)
+			return nil
+		case t.Kind() == v.Kind() && t.ConvertibleTo(v.Type()),
+			isNumberKind(t.Kind()) && isNumberKind(v.Kind()):
+			v.Set(value.Convert(v.Type()))
+			return nil
+		}
+
+		if v.Kind() != reflect.Ptr {
+			break
+		}
+		v = allocate(v)
+	}
+
+	return errors.Errorf("converter returned a %s, which cannot be assigned to a %s", value.Type(), v.Type())
+}
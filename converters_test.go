@@ -0,0 +1,103 @@
+package csvee
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cents int64
+
+type priority struct {
+	Level int
+}
+
+type convertedRow struct {
+	Price    cents
+	Discount float64
+	Rating   *float32
+	Priority priority
+	Label    *string
+}
+
+func parseCurrency(field string) (interface{}, error) {
+
+	amount, err := strconv.ParseFloat(strings.NewReplacer("$", "", ",", "").Replace(field), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return int64(amount*100 + 0.5), nil
+}
+
+func parsePercentage(field string) (interface{}, error) {
+
+	percentage, err := strconv.ParseFloat(strings.TrimSuffix(field, "%"), 64)
+	return percentage / 100, err
+}
+
+// TestReader_ColumnConverters verifies converter results are assigned to their fields
+func TestReader_ColumnConverters(t *testing.T) {
+
+	converters := map[string]Converter{
+		"Price":    parseCurrency,
+		"Discount": parsePercentage,
+		"Rating":   func(field string) (interface{}, error) { return strconv.ParseFloat(field, 64) },
+		"Priority": func(field string) (interface{}, error) {
+			levels := map[string]int{"low": 1, "high": 3}
+			level, exists := levels[field]
+			if !exists {
+				return nil, errors.New("unknown priority")
+			}
+			return priority{Level: level}, nil
+		},
+		"Label": func(field string) (interface{}, error) {
+			if field == "" {
+				return nil, nil
+			}
+			return strings.ToUpper(field), nil
+		},
+	}
+
+	reader, err := NewReader(strings.NewReader("Price,Discount,Rating,Priority,Label\n\"$1,234.56\",15%,4.5,high,new\n$0.99,0%,1,low,\n$1,0%,1,urgent,\n"), &ReaderOptions{
+		ReadHeaders:      true,
+		ColumnConverters: converters,
+	})
+	require.NoError(t, err)
+
+	var row convertedRow
+	require.NoError(t, reader.Read(&row))
+	label := "NEW"
+	rating := float32(4.5)
+	assert.Equal(t, convertedRow{Price: 123456, Discount: 0.15, Rating: &rating, Priority: priority{Level: 3}, Label: &label}, row)
+
+	require.NoError(t, reader.Read(&row))
+	rating = 1
+	assert.Equal(t, convertedRow{Price: 99, Discount: 0, Rating: &rating, Priority: priority{Level: 1}}, row)
+
+	err = reader.Read(&row)
+	assert.EqualError(t, err, `row 3, column "Priority": unknown priority`)
+}
+
+// TestReader_ColumnConvertersInvalid verifies converters are validated and their results checked
+func TestReader_ColumnConvertersInvalid(t *testing.T) {
+
+	_, err := NewReader(strings.NewReader("Price\n"), &ReaderOptions{ReadHeaders: true, ColumnConverters: map[string]Converter{"Cost": parseCurrency}})
+	assert.EqualError(t, err, `converter provided for unknown column "Cost"`)
+
+	_, err = NewReader(strings.NewReader("Price\n"), &ReaderOptions{ReadHeaders: true, ColumnConverters: map[string]Converter{"Price": nil}})
+	assert.EqualError(t, err, `converter for column "Price" must be non nil`)
+
+	reader, err := NewReader(strings.NewReader("Priority\nhigh\n"), &ReaderOptions{
+		ReadHeaders:      true,
+		ColumnConverters: map[string]Converter{"Priority": func(field string) (interface{}, error) { return field, nil }},
+	})
+	require.NoError(t, err)
+
+	var row convertedRow
+	assert.EqualError(t, reader.Read(&row), `row 1, column "Priority": converter returned a string, which cannot be assigned to a csvee.priority`)
+}
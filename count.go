@@ -0,0 +1,60 @@
+package csvee
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// CountRows counts the data rows in the reader's input, excluding headers, and uses the count as the
+// expected number of rows so that ReadAll can size its destination once. The input must implement
+// io.Seeker; it is rewound to count the rows and then returned to its previous position, so reading
+// continues where it left off.
+func (r *Reader) CountRows() (rows int, err error) {
+
+	seeker, ok := r.source.(io.ReadSeeker)
+	if !ok {
+		return 0, ErrNotSeekable
+	}
+
+	offset, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+
+	// The position is restored however counting ends, so that a failed count leaves reading where it was.
+	defer func() {
+		if _, seekErr := seeker.Seek(offset, io.SeekStart); seekErr != nil && err == nil {
+			rows, err = 0, seekErr
+		}
+	}()
+
+	if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	counter := csv.NewReader(newDecodingReader(seeker, r.encoding))
+	counter.Comma = r.CSVReader.Comma
+	counter.Comment = r.CSVReader.Comment
+	counter.LazyQuotes = r.CSVReader.LazyQuotes
+	counter.TrimLeadingSpace = r.CSVReader.TrimLeadingSpace
+	counter.FieldsPerRecord = -1
+	counter.ReuseRecord = true
+
+	for {
+		_, err := counter.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		rows++
+	}
+
+	if r.readHeaders && rows > 0 {
+		rows--
+	}
+
+	r.expectedRows = rows
+	return rows, nil
+}
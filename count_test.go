@@ -0,0 +1,66 @@
+package csvee
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_CountRows verifies rows are counted without disturbing the read position
+func TestReader_CountRows(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader("I\n1\n2\n3\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	rows, err := reader.CountRows()
+	require.NoError(t, err)
+	assert.Equal(t, 3, rows)
+
+	var actualData []readTo
+	require.NoError(t, reader.ReadAll(&actualData))
+	require.Len(t, actualData, 3)
+	assert.Equal(t, 1, actualData[0].I)
+	assert.Equal(t, 3, cap(actualData))
+
+	reader, err = NewReader(bytes.NewBufferString("I\n1\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	_, err = reader.CountRows()
+	assert.Equal(t, ErrNotSeekable, err)
+}
+
+// TestReader_CountRowsMalformed verifies the read position is restored when a row cannot be counted
+func TestReader_CountRowsMalformed(t *testing.T) {
+
+	source := strings.NewReader("I\n1\n2\n\"3\n")
+	reader, err := NewReader(source, &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	before, err := source.Seek(0, io.SeekCurrent)
+	require.NoError(t, err)
+
+	rows, err := reader.CountRows()
+	assert.Error(t, err)
+	assert.Zero(t, rows)
+
+	after, err := source.Seek(0, io.SeekCurrent)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+// TestReader_ReadAllExpectedRows verifies the destination is sized from ExpectedRows
+func TestReader_ReadAllExpectedRows(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader("I\n1\n2\n"), &ReaderOptions{ReadHeaders: true, ExpectedRows: 10})
+	require.NoError(t, err)
+
+	actualData := []readTo{{I: 9}}
+	require.NoError(t, reader.ReadAll(&actualData))
+	require.Len(t, actualData, 3)
+	assert.Equal(t, 9, actualData[0].I)
+	assert.Equal(t, 2, actualData[2].I)
+	assert.Equal(t, 11, cap(actualData))
+}
@@ -1,13 +1,65 @@
 package csvee
 
-import "errors"
+import (
+	"fmt"
+)
 
-const TimeFormatUnix string = "unix"
+const (
+	TimeFormatUnix      string = "unix"
+	TimeFormatUnixMilli string = "unixmilli"
+	TimeFormatUnixNano  string = "unixnano"
+)
 
+// The package's errors are in the message catalog; see Code. New errors take the next unused code.
 var (
-	ErrColumnNamesMismatch    = errors.New("The number of column names does not match the number of fieldsin the record.")
-	ErrUnsupportedTargetType  = errors.New("Target interface must be of type struct or map.")
-	ErrInvalidFieldType       = errors.New("Struct field type must be int*, float*, bool, string, time, or a slice.")
-	ErrReadAllNotSlicePointer = errors.New("The argument to ReadAll must be a pointer to a slice of structs.")
-	ErrReadTargetNil          = errors.New("The argument to Reader.Read[All] must be non nil.")
+	ErrColumnNamesMismatch       = newError("CSVEE-001", "The number of column names does not match the number of fields in the record.")
+	ErrUnsupportedTargetType     = newError("CSVEE-002", "Target interface must be of type struct or map.")
+	ErrInvalidFieldType          = newError("CSVEE-003", "Struct field type must be int*, float*, bool, string, time, or a slice.")
+	ErrReadAllNotSlicePointer    = newError("CSVEE-004", "The argument to ReadAll must be a pointer to a slice of structs.")
+	ErrReadTargetNil             = newError("CSVEE-005", "The argument to Reader.Read[All] must be non nil.")
+	ErrReaderNil                 = newError("CSVEE-006", "The io.Reader provided to NewReader must be non nil.")
+	ErrReaderOptionsRequired     = newError("CSVEE-007", "ReaderOptions must be provided to NewReader.")
+	ErrColumnNamesRequired       = newError("CSVEE-008", "Column names must be provided when ReadHeaders is false.")
+	ErrNotSeekable               = newError("CSVEE-009", "The io.Reader provided to NewReader must implement io.Seeker.")
+	ErrMappingStoreNil           = newError("CSVEE-010", "The reader must be constructed with a MappingStore to save mappings.")
+	ErrPumpSinkNil               = newError("CSVEE-011", "The sink provided to Reader.Pump must be non nil.")
+	ErrWriterNil                 = newError("CSVEE-012", "The io.Writer provided to NewWriter must be non nil.")
+	ErrWriterOptionsRequired     = newError("CSVEE-013", "WriterOptions must be provided to NewWriter.")
+	ErrWriterColumnNamesRequired = newError("CSVEE-014", "Column names must be provided to NewWriter.")
+	ErrPartOpenerNil             = newError("CSVEE-015", "The PartOpener provided to NewPartWriter must be non nil.")
+	ErrPartLimitsRequireOpener   = newError("CSVEE-016", "Part limits can only be used with NewPartWriter.")
+	ErrWriteSourceNil            = newError("CSVEE-017", "The argument to Writer.Write must be non nil.")
+	ErrUnsupportedSourceType     = newError("CSVEE-018", "The argument to Writer.Write must be a struct or a pointer to one.")
+	ErrWatcherDirsRequired       = newError("CSVEE-019", "The watcher's Dir, DoneDir, and FailedDir must all be provided.")
+	ErrWatcherProcessNil         = newError("CSVEE-020", "The watcher's Process function must be non nil.")
+	ErrDecoderNoRecord           = newError("CSVEE-021", "Decoder.Scan must follow a call to Decoder.Next that returned true.")
+	ErrRuleFailed                = newError("CSVEE-022", "The row does not satisfy the validation rule.")
+	ErrStopReading               = newError("CSVEE-023", "The callback stopped reading.")
+	ErrReadEachFuncNil           = newError("CSVEE-024", "The function provided to Reader.ReadEach must be non nil.")
+	ErrRowTimeout                = newError("CSVEE-025", "Decoding the row took longer than the row timeout.")
+	ErrLeapSecond                = newError("CSVEE-026", "The time is a leap second, 60 seconds past the minute.")
+	ErrZeroDate                  = newError("CSVEE-027", "The date is the zero date, 0000-00-00.")
+	ErrMaxDate                   = newError("CSVEE-028", "The date is the maximum date, 9999-12-31.")
+	ErrCSVReaderNil              = newError("CSVEE-029", "The csv.Reader provided to NewCSVReader must be non nil.")
+	ErrRecordSourceNil           = newError("CSVEE-030", "The RecordSource provided to NewRecordReader must be non nil.")
+	ErrTooManyAbandonedRows      = newError("CSVEE-031", "Too many rows that took longer than the row timeout are still decoding.")
 )
+
+// FieldError records an error converting a single field, identifying the row and column it came from.
+type FieldError struct {
+	Row    int
+	Column string
+	Value  string
+	Err    error
+}
+
+func (e *FieldError) Error() string {
+
+	return fmt.Sprintf("row %d, column %q: %v", e.Row, e.Column, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+
+	return e.Err
+}
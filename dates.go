@@ -0,0 +1,106 @@
+package csvee
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DatePolicy controls how times that databases export as placeholders, such as the zero date
+// "0000-00-00", are decoded.
+type DatePolicy int
+
+const (
+	// DateParse decodes the time as its format parses it, which fails for leap seconds and zero
+	// dates and keeps maximum dates.
+	DateParse DatePolicy = iota
+
+	// DateReject fails with ErrLeapSecond, ErrZeroDate, or ErrMaxDate, rather than a parse error.
+	DateReject
+
+	// DateNull decodes the time as null, which zeroes its field and leaves pointers nil.
+	DateNull
+
+	// DateClamp decodes the time as the nearest one that exists: a leap second as the second before
+	// it, a zero date as the zero time.Time, and a maximum date as it is.
+	DateClamp
+)
+
+// errNullDate is returned parsing a time that a DateNull policy decodes as null.
+var errNullDate = errors.New("csvee: null date")
+
+// datePolicies holds the reader's DatePolicy for each kind of placeholder time.
+type datePolicies struct {
+	leapSeconds, zeroDates, maxDates DatePolicy
+}
+
+// apply returns t and err, the result of parsing value with parse, as the policies decode them.
+// Leap seconds are found by parsing value again at the second before.
+func (d datePolicies) apply(value string, t time.Time, err error, parse func(string) (time.Time, error)) (time.Time, error) {
+
+	if err == nil {
+		if d.maxDates != DateParse && isMaxDate(t) {
+			return d.maxDates.resolve(t, ErrMaxDate)
+		}
+		return t, nil
+	}
+
+	if d.zeroDates != DateParse && isZeroDate(value) {
+		return d.zeroDates.resolve(time.Time{}, ErrZeroDate)
+	}
+
+	if d.leapSeconds != DateParse {
+		if i := strings.LastIndex(value, ":60"); i >= 0 {
+			if clamped, clampErr := parse(value[:i] + ":59" + value[i+3:]); clampErr == nil {
+				return d.leapSeconds.resolve(clamped, ErrLeapSecond)
+			}
+		}
+	}
+
+	return t, err
+}
+
+// resolve returns clamped, the time a placeholder clamps to, or the null or sentinel error the
+// policy decodes it as.
+func (p DatePolicy) resolve(clamped time.Time, sentinel error) (time.Time, error) {
+
+	switch p {
+	case DateNull:
+		return time.Time{}, errNullDate
+	case DateClamp:
+		return clamped, nil
+	}
+
+	return time.Time{}, sentinel
+}
+
+// isZeroDate reports whether every digit of value is zero, as in "0000-00-00" and
+// "0000-00-00 00:00:00".
+func isZeroDate(value string) bool {
+
+	digits := 0
+	for _, c := range value {
+		if c >= '1' && c <= '9' {
+			return false
+		}
+		if c == '0' {
+			digits++
+		}
+	}
+
+	return digits > 0
+}
+
+// isMaxDate reports whether t falls on 9999-12-31.
+func isMaxDate(t time.Time) bool {
+
+	year, month, day := t.Date()
+	return year == 9999 && month == time.December && day == 31
+}
+
+// isDatePolicyError reports whether err is the error a DateReject policy fails with.
+func isDatePolicyError(err error) bool {
+
+	return err == ErrLeapSecond || err == ErrZeroDate || err == ErrMaxDate
+}
@@ -0,0 +1,186 @@
+package csvee
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// setField parses field and sets the column's field on the struct pointed to by structPtr,
+// allocating any nil pointers on the way to it. Blank cells leave scalar fields other than strings
+// untouched.
+func (r *Reader) setField(structPtr reflect.Value, col columnPlan, field string) error {
+
+	if blankLeavesField(col, field) {
+		return nil
+	}
+
+	v := settableField(structPtr.Elem(), col.field.Index)
+
+	if col.sliceType != nil {
+		return r.setSlice(v, col, field)
+	}
+
+	err := r.setValue(v, col, field, false)
+	if err == errNullDate {
+		zeroField(structPtr, col)
+		return nil
+	}
+
+	return err
+}
+
+// setSlice sets v, a slice or array, from the elements of field, separated by the column's slice
+// delimiter and quoted as splitSlice describes. A blank field empties slices of anything but strings
+// and times, and blank elements leave pointers nil.
+func (r *Reader) setSlice(v reflect.Value, col columnPlan, field string) error {
+
+	delimiter := r.settings()[col.column].sliceDelimiter
+
+	// Fields without quotes are walked in place rather than split, which would allocate.
+	var elements []string
+	n := 0
+	if col.sliceType.Kind() == reflect.String && !col.unmarshal || isTimeType(col.sliceType) || strings.TrimSpace(field) != "" {
+		if strings.Contains(field, `"`) {
+			var err error
+			if elements, err = splitSlice(field, delimiter); err != nil {
+				return &FieldError{Row: r.rowsRead, Column: col.name, Value: field, Err: err}
+			}
+			n = len(elements)
+		} else {
+			n = strings.Count(field, delimiter) + 1
+		}
+	}
+
+	if v.Kind() == reflect.Slice {
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+	} else {
+		v.Set(reflect.Zero(v.Type()))
+	}
+
+	rest := field
+	for i := 0; i < n && i < v.Len(); i++ {
+		var element string
+		if elements != nil {
+			element = elements[i]
+		} else if end := strings.Index(rest, delimiter); end >= 0 {
+			element, rest = rest[:end], rest[end+len(delimiter):]
+		} else {
+			element = rest
+		}
+
+		// Blank elements leave pointers nil and unmarshaled values zero, as blank cells do.
+		elem := v.Index(i)
+		if (elem.Kind() == reflect.Ptr && col.sliceType.Kind() != reflect.String || col.unmarshal) && strings.TrimSpace(element) == "" {
+			continue
+		}
+
+		err := r.setValue(allocate(elem), col, element, true)
+		if err == errNullDate {
+			elem.Set(reflect.Zero(elem.Type()))
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setValue parses field and sets v, which must be of the column's scalar type, converting it as
+// setScalar does. Numbers and bools within slices are trimmed of surrounding whitespace. It returns
+// errNullDate, leaving v untouched, for times a DatePolicy decodes as null.
+func (r *Reader) setValue(v reflect.Value, col columnPlan, field string, inSlice bool) error {
+
+	t := v.Type()
+
+	if col.unmarshal {
+		if err := unmarshalCell(v, field); err != nil {
+			return &FieldError{Row: r.rowsRead, Column: col.name, Value: field, Err: err}
+		}
+		return nil
+	}
+
+	parsing := r.cellParsing(col.column, col.name)
+	_, epoch := parsing.epochUnit(t)
+
+	value := field
+	if !isTimeType(t) && t.Kind() != reflect.String && !epoch {
+		if t.Kind() != reflect.Bool {
+			r.checkLeadingZeros(col.name, field, t)
+		}
+		if inSlice {
+			value = strings.TrimSpace(field)
+		}
+	}
+
+	err := parsing.setScalar(v, value)
+	switch {
+	case err == nil, err == errNullDate, err == ErrInvalidFieldType:
+		return err
+	case isTimeType(t) || epoch:
+		var overflow *epochOverflowError
+		if errors.As(err, &overflow) {
+			return &FieldError{Row: r.rowsRead, Column: col.name, Value: field, Err: err}
+		}
+		return &FieldError{Row: r.rowsRead, Column: col.name, Value: field, Err: r.suggestTimeRepair(col.name, field, err)}
+	case t.Kind() == reflect.Bool:
+		return &FieldError{Row: r.rowsRead, Column: col.name, Value: field, Err: err}
+	}
+
+	return r.numberError(col.name, field, t, err)
+}
+
+// cellParsing returns how the cells of the column at index j, named name, are converted. Columns
+// without a format of their own are parsed in the default time format.
+func (r *Reader) cellParsing(j int, name string) cellParsing {
+
+	setting := r.settings()[j]
+	parsing := cellParsing{
+		format:         r.defaultTimeFormat,
+		numbers:        setting.numbers,
+		boolParsing:    r.boolParsing,
+		sliceDelimiter: setting.sliceDelimiter,
+		location:       r.location,
+		zones:          r.zones,
+		dates:          r.dates,
+		epochs:         setting.formatted,
+	}
+	if setting.formatted {
+		parsing.format = r.columnFormat(name)
+	}
+
+	return parsing
+}
+
+// setFast sets a fast path column through its offset.
+func (r *Reader) setFast(structPtr reflect.Value, col columnPlan, field string) error {
+
+	if col.fieldType.Kind() != reflect.String && strings.TrimSpace(field) == "" {
+		return nil
+	}
+
+	if r.settings()[col.column].formatted && isIntegerKind(col.fieldType.Kind()) {
+		return r.setField(structPtr, col, field)
+	}
+
+	r.checkLeadingZeros(col.name, field, col.fieldType)
+	if err := setPrimitive(structPtr, col, field, r.settings()[col.column].numbers, r.boolParsing); err != nil {
+		if col.fieldType.Kind() == reflect.Bool {
+			return &FieldError{Row: r.rowsRead, Column: col.name, Value: field, Err: err}
+		}
+		return r.numberError(col.name, field, col.fieldType, err)
+	}
+
+	return nil
+}
+
+// parseTime parses field in the format configured for the named column, or as RFC 3339 if there is
+// none.
+func (r *Reader) parseTime(column, field string) (time.Time, error) {
+
+	return cellParsing{format: r.columnFormat(column), location: r.location, zones: r.zones, dates: r.dates}.parseTime(field)
+}
@@ -0,0 +1,152 @@
+package csvee
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type unexportedEmbed struct {
+	Hidden string
+}
+
+type decodeReadTo struct {
+	*unexportedEmbed
+	S      string
+	secret string
+	PS     *string
+	IPA    []*int
+	Arr    [2]int
+	When   time.Time
+	WhenP  *time.Time
+	Floats []float32
+}
+
+// TestReader_DecodeFields verifies fields are set directly, including values that could not survive a JSON round trip
+func TestReader_DecodeFields(t *testing.T) {
+
+	one, two := 1, 2
+	empty := ""
+	when := time.Date(2021, time.February, 13, 16, 55, 42, 123456789, time.FixedZone("", -5*60*60))
+
+	var testCases = []struct {
+		name    string
+		inData  string
+		expData decodeReadTo
+		expErr  string
+	}{
+		{
+			name:   "special characters",
+			inData: "S,PS\n\"back\\slash \"\"quoted\"\"\nline\ttab\",\n",
+			expData: decodeReadTo{
+				S:  "back\\slash \"quoted\"\nline\ttab",
+				PS: &empty,
+			},
+		},
+		{
+			name:    "pointers, arrays, and times",
+			inData:  "IPA,Arr,When,WhenP,Floats\n\"1, 2\",\"3,4,5\",2021-02-13T16:55:42.123456789-05:00,2021-02-13T16:55:42.123456789-05:00,\"1.5, 2\"\n",
+			expData: decodeReadTo{IPA: []*int{&one, &two}, Arr: [2]int{3, 4}, When: when, WhenP: &when, Floats: []float32{1.5, 2}},
+		},
+		{
+			name:    "unsettable fields ignored",
+			inData:  "secret,Hidden\nx,y\n",
+			expData: decodeReadTo{},
+		},
+		{
+			name:   "invalid time",
+			inData: "When\nyesterday\n",
+			expErr: `row 1, column "When": parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`,
+		},
+		{
+			name:   "invalid slice element",
+			inData: "Floats\n\"1,x\"\n",
+			expErr: `row 1, column "Floats": invalid value "x" for float32`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader(tt.inData), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+
+			var actual decodeReadTo
+			err = reader.Read(&actual)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expData, actual)
+		})
+	}
+}
+
+func benchmarkDecodeData() string {
+
+	var sb strings.Builder
+	sb.WriteString("F,I,B,S,IA,SA,T\n")
+	for i := 0; i < 1000; i++ {
+		sb.WriteString("29.4,3,true,hello,\"1,2,3\",\"a,b\",1991-04-05T11:11:11Z\n")
+	}
+
+	return sb.String()
+}
+
+// BenchmarkReader_Decode measures decoding records by setting fields directly.
+func BenchmarkReader_Decode(b *testing.B) {
+
+	inData := benchmarkDecodeData()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		reader, err := NewReader(strings.NewReader(inData), &ReaderOptions{ReadHeaders: true})
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		var actualData []readTo
+		if err := reader.ReadAll(&actualData); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkReader_DecodeJSONRoundTrip measures the approach the reader used to take, building a JSON
+// object per record and unmarshaling it, as a baseline for BenchmarkReader_Decode.
+func BenchmarkReader_DecodeJSONRoundTrip(b *testing.B) {
+
+	inData := benchmarkDecodeData()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		reader, err := NewReader(strings.NewReader(inData), &ReaderOptions{ReadHeaders: true})
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		var actualData []readTo
+		for {
+			record, err := reader.ReadRaw()
+			if err != nil {
+				break
+			}
+
+			object := `{"F":` + record.Field(0) + `,"I":` + record.Field(1) + `,"B":` + record.Field(2) +
+				`,"S":"` + record.Field(3) + `","IA":[` + record.Field(4) + `],"SA":["` +
+				strings.Join(strings.Split(record.Field(5), ","), `","`) + `"],"T":"` + record.Field(6) + `"}`
+
+			var v readTo
+			if err := json.Unmarshal([]byte(object), &v); err != nil {
+				b.Fatal(err)
+			}
+			actualData = append(actualData, v)
+		}
+	}
+}
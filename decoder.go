@@ -0,0 +1,188 @@
+package csvee
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ParseError describes a row that could not be decoded, locating it in the input.
+type ParseError struct {
+	// Row is the 1-based number of the record, excluding headers.
+	Row int
+
+	// Line is the 1-based line of the input the offending cell, or the record if the error is not
+	// specific to a cell, starts on.
+	Line int
+
+	// Column and Value are the name and raw contents of the offending cell, if there is one.
+	Column string
+	Value  string
+
+	// Position is the 1-based byte column within Line that the offending cell starts at, or that the
+	// error occurred at if the record could not be tokenized. Zero if it is unknown or the error is
+	// not specific to a cell.
+	Position int
+
+	// Offset and End are the byte offsets in the input between which the record was read. Offset
+	// precedes any blank lines skipped before the record. Both are zero if they are unknown, such as
+	// for records that could not be tokenized.
+	Offset int64
+	End    int64
+
+	// Err is the underlying cause.
+	Err error
+
+	// endLine is the line the record ends on, which Excerpt counts back from End.
+	endLine int
+}
+
+func (e *ParseError) Error() string {
+
+	if e.Column == "" {
+		return fmt.Sprintf("row %d, line %d: %v", e.Row, e.Line, e.Err)
+	}
+
+	return fmt.Sprintf("row %d, line %d, column %q, value %q: %v", e.Row, e.Line, e.Column, e.Value, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+
+	return e.Err
+}
+
+// Decoder reads records one at a time so that they can be decoded, and their errors handled,
+// individually:
+//
+//	dec := reader.Decode()
+//	for dec.Next() {
+//		var row Row
+//		if err := dec.Scan(&row); err != nil {
+//			// err is a *ParseError; the next row can still be read.
+//		}
+//	}
+//	if err := dec.Err(); err != nil {
+//		// the input could not be read.
+//	}
+type Decoder struct {
+	reader *Reader
+	record []string
+	err    error
+}
+
+// Decode returns a Decoder that reads the reader's remaining records.
+func (r *Reader) Decode() *Decoder {
+
+	return &Decoder{reader: r}
+}
+
+// Next reads the next record, reporting whether there is one to Scan. It returns false at the end
+// of the input or if the input cannot be read, in which case Err returns the error.
+func (d *Decoder) Next() bool {
+
+	d.record = nil
+	if d.err != nil {
+		return false
+	}
+
+	record, err := d.reader.readRecord()
+	if err == io.EOF {
+		return false
+	}
+	if err != nil {
+		d.err = d.reader.parseError(err)
+		return false
+	}
+
+	d.record = record
+	return true
+}
+
+// Scan decodes the record read by the last call to Next into v, which must be a pointer to a
+// struct, and calls the AfterRow hook. Errors decoding the record are returned as a *ParseError and
+// do not stop the Decoder.
+func (d *Decoder) Scan(v interface{}) error {
+
+	if v == nil {
+		return ErrReadTargetNil
+	}
+	if d.record == nil {
+		return ErrDecoderNoRecord
+	}
+
+	r := d.reader
+	if len(d.record) != len(r.ColumnNames) {
+		r.counts.errored++
+		return r.parseError(ErrColumnNamesMismatch)
+	}
+
+	vType := getBaseType(reflect.TypeOf(v))
+	var plan *decodePlan
+	switch {
+	case isMapTarget(vType):
+	case vType.Kind() == reflect.Struct:
+		var err error
+		if plan, err = r.planFor(vType); err != nil {
+			return err
+		}
+	default:
+		return ErrUnsupportedTargetType
+	}
+
+	if err := r.decode(row{record: d.record, plan: plan}, v); err != nil {
+		return r.parseError(err)
+	}
+
+	if r.afterRow != nil {
+		if err := r.afterRow(r.rowsRead, v); err != nil {
+			return r.parseError(err)
+		}
+	}
+
+	return nil
+}
+
+// Err returns the error that stopped Next, or nil if it stopped at the end of the input.
+func (d *Decoder) Err() error {
+
+	return d.err
+}
+
+// parseError describes err, which occurred reading or decoding the record most recently read, as a
+// *ParseError. The causes of FieldErrors are unwrapped, since the ParseError holds their context.
+func (r *Reader) parseError(err error) error {
+
+	var csvErr *csv.ParseError
+	if errors.As(err, &csvErr) {
+		parseErr := &ParseError{Row: r.unreadableRow, Line: csvErr.StartLine, Err: err}
+		if csvErr.Line == csvErr.StartLine {
+			parseErr.Position = csvErr.Column
+		}
+		return parseErr
+	}
+
+	parseErr := &ParseError{Row: r.rowsRead, Line: r.lastLine, Err: err}
+	cell := -1
+
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		parseErr.Column, parseErr.Value, parseErr.Err = fieldErr.Column, fieldErr.Value, fieldErr.Err
+
+		// Quoted cells may span lines, so count the lines taken by the cells before this one.
+		for i, name := range r.ColumnNames {
+			if name == fieldErr.Column || i >= len(r.lastRecord) {
+				cell = i
+				break
+			}
+			parseErr.Line += strings.Count(r.lastRecord[i], "\n")
+		}
+	}
+	r.locate(parseErr, cell)
+
+	return parseErr
+}
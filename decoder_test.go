@@ -0,0 +1,166 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type decoderRow struct {
+	Name  string
+	Qty   int
+	Valid bool
+}
+
+// TestDecoder verifies rows are decoded one at a time and that bad rows do not stop decoding
+func TestDecoder(t *testing.T) {
+
+	const data = "Name,Qty,Valid\na,1,true\n\"multi\nline\",x,true\nc,3,maybe\nd,4\ne,5,false\n"
+
+	reader, err := NewReader(strings.NewReader(data), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	reader.CSVReader.FieldsPerRecord = -1
+
+	var (
+		rows []decoderRow
+		errs []error
+	)
+
+	dec := reader.Decode()
+	assert.Equal(t, ErrDecoderNoRecord, dec.Scan(&decoderRow{}))
+
+	for dec.Next() {
+		var row decoderRow
+		if err := dec.Scan(&row); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	require.NoError(t, dec.Err())
+	assert.False(t, dec.Next())
+	assert.Equal(t, ErrDecoderNoRecord, dec.Scan(&decoderRow{}))
+
+	assert.Equal(t, []decoderRow{{Name: "a", Qty: 1, Valid: true}, {Name: "e", Qty: 5}}, rows)
+	require.Len(t, errs, 3)
+
+	assert.EqualError(t, errs[0], `row 2, line 4, column "Qty", value "x": invalid value "x" for int`)
+	assert.EqualError(t, errs[1], `row 3, line 5, column "Valid", value "maybe": invalid value "maybe" for bool`)
+	assert.Equal(t, &ParseError{Row: 4, Line: 6, Offset: 54, End: 58, Err: ErrColumnNamesMismatch, endLine: 6}, errs[2])
+}
+
+// TestDecoder_ReadError verifies input errors stop the decoder
+func TestDecoder_ReadError(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader("Name,Qty,Valid\na,1,true\nb,\"2,true\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	dec := reader.Decode()
+	require.True(t, dec.Next())
+	require.False(t, dec.Next())
+
+	var parseErr *ParseError
+	require.ErrorAs(t, dec.Err(), &parseErr)
+	assert.Equal(t, 2, parseErr.Row)
+	assert.Equal(t, 3, parseErr.Line)
+	assert.False(t, dec.Next())
+}
+
+// TestParseError_Excerpt verifies errors locate their cells in the input and point at them
+func TestParseError_Excerpt(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		data     string
+		position int
+		excerpt  string
+	}{
+		{
+			name:     "cell",
+			data:     "Name,Qty,Valid\na,1,true\nbb,x,true\n",
+			position: 4,
+			excerpt:  "3 | bb,x,true\n  |    ^",
+		},
+		{
+			name:     "quoted cell after a multi-line cell",
+			data:     "Name,Qty,Valid\n\"multi\nline\",\"x\",true\n",
+			position: 7,
+			excerpt:  "3 | line\",\"x\",true\n  |       ^",
+		},
+		{
+			name:     "blank lines and carriage returns",
+			data:     "Name,Qty,Valid\r\n\r\n\r\na,1,maybe\r\n",
+			position: 5,
+			excerpt:  "4 | a,1,maybe\n  |     ^",
+		},
+		{
+			name:     "tabs",
+			data:     "Name,Qty,Valid\n\ta,x,true",
+			position: 4,
+			excerpt:  "2 | \ta,x,true\n  | \t  ^",
+		},
+		{
+			name:    "record",
+			data:    "Name,Qty,Valid\na,1\n",
+			excerpt: "2 | a,1",
+		},
+		{
+			name:     "malformed record",
+			data:     "Name,Qty,Valid\na,1,true\nb,2\"x,true\n",
+			position: 4,
+			excerpt:  "3 | b,2\"x,true\n  |    ^",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader(tt.data), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+			reader.CSVReader.FieldsPerRecord = -1
+
+			var parseErr *ParseError
+			dec := reader.Decode()
+			for dec.Next() && parseErr == nil {
+				if err := dec.Scan(&decoderRow{}); err != nil {
+					require.ErrorAs(t, err, &parseErr)
+				}
+			}
+			if parseErr == nil {
+				require.ErrorAs(t, dec.Err(), &parseErr)
+			}
+
+			assert.Equal(t, tt.position, parseErr.Position)
+			assert.Equal(t, tt.excerpt, parseErr.Excerpt(strings.NewReader(tt.data)))
+		})
+	}
+}
+
+// TestParseError_Excerpt_Pipeline verifies errors are located in records tokenized ahead of decoding
+func TestParseError_Excerpt_Pipeline(t *testing.T) {
+
+	const data = "Name,Qty,Valid\na,1,true\nb,2,true\nc,3,maybe\nd,x,true\ne,5,false\n"
+
+	reader, err := NewReader(strings.NewReader(data), &ReaderOptions{
+		ReadHeaders: true, PipelineDepth: 4, OnError: ErrorCollect,
+	})
+	require.NoError(t, err)
+
+	var rows []decoderRow
+	err = reader.ReadAll(&rows)
+
+	var multiErr *MultiError
+	require.ErrorAs(t, err, &multiErr)
+	require.Len(t, multiErr.Errors, 2)
+
+	var excerpts []string
+	for _, parseErr := range multiErr.Errors {
+		excerpts = append(excerpts, parseErr.Excerpt(strings.NewReader(data)))
+	}
+
+	assert.Equal(t, []string{"4 | c,3,maybe\n  |     ^", "5 | d,x,true\n  |   ^"}, excerpts)
+	assert.Len(t, rows, 3)
+}
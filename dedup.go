@@ -0,0 +1,67 @@
+package csvee
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// recordDigest identifies a record within the dedup window. It is a SHA-256 hash of the record's
+// fields and their lengths, so distinct records are not mistaken for one another in practice.
+type recordDigest [sha256.Size]byte
+
+// dedupWindow remembers the digests of the most recently read records so re-delivered rows can be
+// suppressed.
+type dedupWindow struct {
+	size  int
+	seen  map[recordDigest]int
+	ring  []recordDigest
+	index int
+}
+
+func newDedupWindow(size int) *dedupWindow {
+
+	return &dedupWindow{
+		size: size,
+		seen: make(map[recordDigest]int, size),
+		ring: make([]recordDigest, 0, size),
+	}
+}
+
+// duplicate reports whether record has been seen within the window. Records that have not been
+// seen are added to the window, evicting the oldest record if the window is full.
+func (d *dedupWindow) duplicate(record []string) bool {
+
+	h := digestRecord(record)
+	if d.seen[h] > 0 {
+		return true
+	}
+
+	if len(d.ring) < d.size {
+		d.ring = append(d.ring, h)
+	} else {
+		evicted := d.ring[d.index]
+		if d.seen[evicted]--; d.seen[evicted] <= 0 {
+			delete(d.seen, evicted)
+		}
+		d.ring[d.index] = h
+		d.index = (d.index + 1) % d.size
+	}
+
+	d.seen[h]++
+	return false
+}
+
+func digestRecord(record []string) recordDigest {
+
+	h := sha256.New()
+	var length [binary.MaxVarintLen64]byte
+	for _, field := range record {
+		// Include the field length so that field boundaries affect the hash.
+		_, _ = h.Write(length[:binary.PutUvarint(length[:], uint64(len(field)))])
+		_, _ = h.Write([]byte(field))
+	}
+
+	var digest recordDigest
+	h.Sum(digest[:0])
+	return digest
+}
@@ -0,0 +1,74 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_DedupWindow verifies rows repeated within the window are skipped
+func TestReader_DedupWindow(t *testing.T) {
+
+	var testCases = []struct {
+		name     string
+		inData   string
+		inWindow int
+		expRows  []string
+	}{
+		{
+			name:     "no window",
+			inData:   "S\na\na\nb\n",
+			inWindow: 0,
+			expRows:  []string{"a", "a", "b"},
+		},
+		{
+			name:     "duplicates suppressed",
+			inData:   "S\na\nb\na\nc\nb\n",
+			inWindow: 3,
+			expRows:  []string{"a", "b", "c"},
+		},
+		{
+			name:     "duplicate outside window",
+			inData:   "S\na\nb\nc\na\n",
+			inWindow: 2,
+			expRows:  []string{"a", "b", "c", "a"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(
+				strings.NewReader(tt.inData),
+				&ReaderOptions{ReadHeaders: true, DedupWindow: tt.inWindow},
+			)
+			require.NoError(t, err)
+
+			var actualData []readTo
+			require.NoError(t, reader.ReadAll(&actualData))
+
+			actualRows := make([]string, len(actualData))
+			for i, row := range actualData {
+				actualRows[i] = row.S
+			}
+			assert.Equal(t, tt.expRows, actualRows)
+		})
+	}
+}
+
+// TestDedupWindow_FieldLengths verifies records whose fields only differ in where long fields are
+// split are not treated as duplicates
+func TestDedupWindow_FieldLengths(t *testing.T) {
+
+	// With lengths truncated to two bytes, both records would encode to the same bytes.
+	tail := strings.Repeat("c", 0xfffe)
+	long := []string{"ab\xfe\xff" + tail}
+	split := []string{"ab", tail}
+
+	window := newDedupWindow(2)
+	assert.False(t, window.duplicate(long))
+	assert.False(t, window.duplicate(split))
+	assert.True(t, window.duplicate(long))
+}
@@ -0,0 +1,160 @@
+package csvee
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// MakeDelta compares two full extracts and writes the rows of a patch that turns old into new, in a
+// form ApplyPatch accepts. w's first column is the operation column, which holds "insert",
+// "update", or "delete", and its remaining columns must be new's columns. Inserted and updated rows
+// are written in new's order with all of their cells, followed by deleted rows in old's order with
+// only their key cells. Rows are matched on the key columns, which both readers must have; old
+// columns that new does not have are ignored. The old extract is held in memory. MakeDelta flushes w
+// but does not close it.
+func MakeDelta(old, new *Reader, key Columns, w *Writer) (PatchResult, error) {
+
+	var result PatchResult
+
+	if len(key) == 0 {
+		return result, errors.New("at least one key column is required")
+	}
+
+	if len(w.ColumnNames) != len(new.ColumnNames)+1 || !sameColumns(w.ColumnNames[1:], new.ColumnNames) {
+		return result, errors.New("the writer's columns must be the operation column followed by the new extract's columns")
+	}
+
+	oldKey, err := columnIndexes(old.ColumnNames, key)
+	if err != nil {
+		return result, errors.Wrap(err, "old")
+	}
+
+	newKey, err := columnIndexes(new.ColumnNames, key)
+	if err != nil {
+		return result, errors.Wrap(err, "new")
+	}
+
+	// oldColumns maps each new column to its old column, or -1 if old does not have it.
+	oldColumns := make([]int, len(new.ColumnNames))
+	for i, name := range new.ColumnNames {
+		oldColumns[i] = -1
+		for j, oldName := range old.ColumnNames {
+			if oldName == name {
+				oldColumns[i] = j
+			}
+		}
+	}
+
+	var oldRows [][]string
+	index := make(map[string]int)
+	for {
+		record, err := old.ReadRaw()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+
+		fields := copyFields(record)
+		index[recordKey(fields, oldKey)] = len(oldRows)
+		oldRows = append(oldRows, fields)
+	}
+
+	seen := make([]bool, len(oldRows))
+	out := make([]string, len(w.ColumnNames))
+
+	for {
+		record, err := new.ReadRaw()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+
+		i, exists := index[recordKey(copyFields(record), newKey)]
+		switch {
+		case !exists:
+			out[0] = "insert"
+			result.Inserted++
+		case rowChanged(oldRows[i], record, oldColumns):
+			seen[i] = true
+			out[0] = "update"
+			result.Updated++
+		default:
+			seen[i] = true
+			continue
+		}
+
+		for j := 0; j < record.Len(); j++ {
+			out[j+1] = record.Field(j)
+		}
+		if err := w.WriteRecord(out); err != nil {
+			return result, err
+		}
+	}
+
+	for i, row := range oldRows {
+		if seen[i] {
+			continue
+		}
+
+		for j := range out {
+			out[j] = ""
+		}
+		out[0] = "delete"
+		for k, column := range newKey {
+			out[column+1] = row[oldKey[k]]
+		}
+
+		if err := w.WriteRecord(out); err != nil {
+			return result, err
+		}
+		result.Deleted++
+	}
+
+	return result, w.Flush()
+}
+
+// rowChanged reports whether any cell of record differs from the matching cell of the old row.
+func rowChanged(old []string, record RawRecord, oldColumns []int) bool {
+
+	for i, j := range oldColumns {
+		oldField := ""
+		if j >= 0 {
+			oldField = old[j]
+		}
+		if record.Field(i) != oldField {
+			return true
+		}
+	}
+
+	return false
+}
+
+func copyFields(record RawRecord) []string {
+
+	fields := make([]string, record.Len())
+	for i := range fields {
+		fields[i] = record.Field(i)
+	}
+
+	return fields
+}
+
+func sameColumns(a, b []string) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
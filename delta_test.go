@@ -0,0 +1,87 @@
+package csvee
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMakeDelta verifies deltas contain only changed rows and apply back onto the old extract
+func TestMakeDelta(t *testing.T) {
+
+	const oldData = "ID,Region,Name,Qty\n1,eu,a,1\n2,eu,b,2\n2,us,c,3\n"
+
+	var testCases = []struct {
+		name      string
+		newData   string
+		key       Columns
+		expDelta  string
+		expResult PatchResult
+		expErr    string
+	}{
+		{
+			name:      "adds updates and deletes",
+			newData:   "ID,Region,Name,Qty\n2,us,c,30\n2,eu,b,2\n4,eu,d,4\n",
+			key:       Columns{"ID", "Region"},
+			expDelta:  "op,ID,Region,Name,Qty\nupdate,2,us,c,30\ninsert,4,eu,d,4\ndelete,1,eu,,\n",
+			expResult: PatchResult{Inserted: 1, Updated: 1, Deleted: 1},
+		},
+		{
+			name:     "unchanged",
+			newData:  oldData,
+			key:      Columns{"ID", "Region"},
+			expDelta: "op,ID,Region,Name,Qty\n",
+		},
+		{
+			name:    "missing key",
+			newData: "ID,Name,Qty\n1,a,1\n",
+			key:     Columns{"ID", "Region"},
+			expErr:  `new: column "Region" not found`,
+		},
+		{
+			name:    "no key",
+			newData: oldData,
+			expErr:  "at least one key column is required",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			old, err := NewReader(strings.NewReader(oldData), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+			new, err := NewReader(strings.NewReader(tt.newData), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+
+			var delta bytes.Buffer
+			writer, err := NewWriter(&delta, &WriterOptions{ColumnNames: append([]string{"op"}, new.Columns()...), WriteHeaders: true})
+			require.NoError(t, err)
+
+			result, err := MakeDelta(old, new, tt.key, writer)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expResult, result)
+			assert.Equal(t, tt.expDelta, delta.String())
+
+			base, err := NewReader(strings.NewReader(oldData), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+			patch, err := NewReader(&delta, &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+
+			var patched bytes.Buffer
+			writer, err = NewWriter(&patched, &WriterOptions{ColumnNames: base.Columns(), WriteHeaders: true})
+			require.NoError(t, err)
+
+			_, err = ApplyPatch(base, patch, tt.key, writer, nil)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, strings.Split(tt.newData, "\n"), strings.Split(patched.String(), "\n"))
+		})
+	}
+}
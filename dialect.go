@@ -0,0 +1,108 @@
+package csvee
+
+import (
+	"encoding/csv"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// Dialect describes the syntax of a CSV file. Its fields correspond to those of csv.Reader.
+type Dialect struct {
+	// Delimiter separates fields. Zero means a comma.
+	Delimiter rune
+
+	// Comment, if not zero, starts lines that are ignored.
+	Comment rune
+
+	// LazyQuotes allows quotes to appear in unquoted fields and unescaped quotes in quoted fields.
+	LazyQuotes bool
+
+	// TrimLeadingSpace ignores leading whitespace in fields.
+	TrimLeadingSpace bool
+}
+
+var (
+	// DialectExcel reads comma separated files as Excel writes them, quoted as RFC 4180 describes.
+	DialectExcel = Dialect{Delimiter: ','}
+
+	// DialectTSV reads tab separated files, which rarely quote fields, so stray quotes are allowed.
+	DialectTSV = Dialect{Delimiter: '\t', LazyQuotes: true}
+
+	// DialectSemicolonEU reads semicolon separated files, as written in locales that use a comma as
+	// the decimal separator.
+	DialectSemicolonEU = Dialect{Delimiter: ';'}
+)
+
+// dialect returns the dialect described by the options: Dialect, if set, overridden by the
+// individual settings that are not zero.
+func (o *ReaderOptions) dialect() Dialect {
+
+	var d Dialect
+	if o.Dialect != nil {
+		d = *o.Dialect
+	}
+
+	if o.Delimiter != 0 {
+		d.Delimiter = o.Delimiter
+	}
+	if o.Comment != 0 {
+		d.Comment = o.Comment
+	}
+	d.LazyQuotes = d.LazyQuotes || o.LazyQuotes
+	d.TrimLeadingSpace = d.TrimLeadingSpace || o.TrimLeadingSpace
+
+	return d
+}
+
+// validate checks that csv.Reader accepts the dialect.
+func (d Dialect) validate() error {
+
+	if d.Delimiter != 0 && !validDelimiter(d.Delimiter) {
+		return errors.Errorf("invalid delimiter %q", d.Delimiter)
+	}
+
+	if d.Comment != 0 && !validDelimiter(d.Comment) {
+		return errors.Errorf("invalid comment character %q", d.Comment)
+	}
+
+	delimiter := d.Delimiter
+	if delimiter == 0 {
+		delimiter = ','
+	}
+	if d.Comment == delimiter {
+		return errors.Errorf("comment character and delimiter must differ, both are %q", delimiter)
+	}
+
+	return nil
+}
+
+// apply configures reader to read the dialect.
+func (d Dialect) apply(reader *csv.Reader) {
+
+	if d.Delimiter != 0 {
+		reader.Comma = d.Delimiter
+	}
+	reader.Comment = d.Comment
+	reader.LazyQuotes = d.LazyQuotes
+	reader.TrimLeadingSpace = d.TrimLeadingSpace
+}
+
+// overlay sets the parts of the dialect that are not zero on reader, keeping the rest of its
+// settings.
+func (d Dialect) overlay(reader *csv.Reader) {
+
+	if d.Delimiter != 0 {
+		reader.Comma = d.Delimiter
+	}
+	if d.Comment != 0 {
+		reader.Comment = d.Comment
+	}
+	reader.LazyQuotes = reader.LazyQuotes || d.LazyQuotes
+	reader.TrimLeadingSpace = reader.TrimLeadingSpace || d.TrimLeadingSpace
+}
+
+func validDelimiter(r rune) bool {
+
+	return r != '"' && r != '\r' && r != '\n' && utf8.ValidRune(r) && r != utf8.RuneError
+}
@@ -0,0 +1,84 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dialectRow struct {
+	Name  string
+	Count int
+}
+
+// TestReader_Dialect verifies dialect presets and options configure the CSV syntax
+func TestReader_Dialect(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		options  ReaderOptions
+		expected []dialectRow
+		err      string
+	}{
+		{
+			name:     "tsv",
+			input:    "Name\tCount\nsix \"inch\" pipe\t3\n",
+			options:  ReaderOptions{Dialect: &DialectTSV},
+			expected: []dialectRow{{Name: `six "inch" pipe`, Count: 3}},
+		},
+		{
+			name:     "semicolon",
+			input:    "Name;Count\n\"a;b\";1\n",
+			options:  ReaderOptions{Dialect: &DialectSemicolonEU},
+			expected: []dialectRow{{Name: "a;b", Count: 1}},
+		},
+		{
+			name:     "excel",
+			input:    "Name,Count\n\"x,\"\"y\"\"\",2\n",
+			options:  ReaderOptions{Dialect: &DialectExcel},
+			expected: []dialectRow{{Name: `x,"y"`, Count: 2}},
+		},
+		{
+			name:     "options",
+			input:    "Name|Count\n# skipped\n  padded|4\n",
+			options:  ReaderOptions{Delimiter: '|', Comment: '#', TrimLeadingSpace: true},
+			expected: []dialectRow{{Name: "padded", Count: 4}},
+		},
+		{
+			name:     "override",
+			input:    "Name|Count\nz|5\n",
+			options:  ReaderOptions{Dialect: &DialectTSV, Delimiter: '|'},
+			expected: []dialectRow{{Name: "z", Count: 5}},
+		},
+		{
+			name:    "invalid delimiter",
+			options: ReaderOptions{Delimiter: '"'},
+			err:     `invalid delimiter '"'`,
+		},
+		{
+			name:    "comment is delimiter",
+			options: ReaderOptions{Dialect: &DialectSemicolonEU, Comment: ';'},
+			err:     "comment character and delimiter must differ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.options.ReadHeaders = true
+			reader, err := NewReader(strings.NewReader(tt.input), &tt.options)
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+
+			var rows []dialectRow
+			require.NoError(t, reader.ReadAll(&rows))
+			assert.Equal(t, tt.expected, rows)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package csvee
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dynamicSampleRows is the number of records ReadDynamic reads ahead to infer its struct's types.
+const dynamicSampleRows = 100
+
+// ReadDynamic reads the next record into a struct and returns a pointer to it, for callers that
+// have no struct of their own. The struct's type is built with reflect.StructOf on the first call
+// from the reader's columns and the next 100 records, which are read ahead and then returned in
+// order. Each column becomes an exported field named as GoIdentifiers names it and tagged with the
+// column's name, of the type ReaderOptions.ColumnTypes gives the column, or time.Time if it has a
+// format its cells all parse with, or otherwise the type InferSchema infers from its cells, whose
+// time layout becomes the column's format. A column with blank or null cells among those records
+// has a pointer field, which null cells leave nil, as do blank cells of columns other than strings.
+// Columns whose names contain a comma are not decoded, since a tag cannot name them. DynamicType
+// returns the type.
+func (r *Reader) ReadDynamic() (interface{}, error) {
+
+	if r.dynamicType == nil {
+		dynamicType, err := r.buildDynamicType()
+		if err != nil {
+			return nil, err
+		}
+		r.dynamicType = dynamicType
+	}
+
+	v := reflect.New(r.dynamicType).Interface()
+	if err := r.Read(v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// DynamicType returns the struct type ReadDynamic decodes records into, or nil if it has not been
+// called.
+func (r *Reader) DynamicType() reflect.Type {
+
+	return r.dynamicType
+}
+
+// buildDynamicType infers the type of each of the reader's columns from the records read ahead
+// with sampleRecords and returns the struct type with a field for each.
+func (r *Reader) buildDynamicType() (reflect.Type, error) {
+
+	samples := make([][]string, len(r.ColumnNames))
+	nullable := make([]bool, len(r.ColumnNames))
+	for _, record := range r.sampleRecords(dynamicSampleRows) {
+		for j := 0; j < len(record) && j < len(samples); j++ {
+			if cell := strings.TrimSpace(record[j]); cell != "" && !r.isNull(cell) {
+				samples[j] = append(samples[j], cell)
+			} else {
+				nullable[j] = true
+			}
+		}
+	}
+
+	formats := r.Formats()
+	identifiers := GoIdentifiers(r.ColumnNames).Identifiers()
+	fields := make([]reflect.StructField, len(r.ColumnNames))
+	for j, name := range r.ColumnNames {
+
+		fieldType := r.dynamicFieldType(name, samples[j], formats)
+		if nullable[j] {
+			fieldType = reflect.PtrTo(fieldType)
+		}
+
+		fields[j] = reflect.StructField{
+			Name: identifiers[j],
+			Type: fieldType,
+			Tag:  reflect.StructTag(tagName + ":" + strconv.Quote(name)),
+		}
+	}
+
+	if len(formats) != len(r.ColumnFormats) {
+		if err := r.SetFormats(formats); err != nil {
+			return nil, err
+		}
+	}
+
+	return reflect.StructOf(fields), nil
+}
+
+// dynamicFieldType returns the type of the named column's field given its non-blank cells, adding
+// the layout of inferred times to formats.
+func (r *Reader) dynamicFieldType(name string, cells []string, formats map[string]string) reflect.Type {
+
+	if kind, typed := r.columnTypes[name]; typed {
+		return columnKindTypes[kind]
+	}
+
+	if _, formatted := formats[name]; formatted {
+		if allCells(cells, func(cell string) bool {
+			_, err := r.parseTime(name, cell)
+			return err == nil
+		}) {
+			return columnKindTypes[KindTime]
+		}
+		return columnKindTypes[KindString]
+	}
+
+	inferred, layout := inferType(cells)
+	switch inferred {
+	case inferredInt:
+		return columnKindTypes[KindInt]
+	case inferredFloat:
+		return columnKindTypes[KindFloat]
+	case inferredBool:
+		return columnKindTypes[KindBool]
+	case inferredTime:
+		formats[name] = layout
+		return columnKindTypes[KindTime]
+	}
+
+	return columnKindTypes[KindString]
+}
+
+// columnKindTypes are the field types ReadDynamic gives columns of each kind.
+var columnKindTypes = map[ColumnKind]reflect.Type{
+	KindString: reflect.TypeOf(""),
+	KindInt:    reflect.TypeOf(int64(0)),
+	KindFloat:  reflect.TypeOf(float64(0)),
+	KindBool:   reflect.TypeOf(false),
+	KindTime:   reflect.TypeOf(time.Time{}),
+}
+
+// sampleRecords tokenizes up to n records ahead of the reader and returns them, keeping them, and
+// the error that stopped it if any, to be read in order.
+func (r *Reader) sampleRecords(n int) [][]string {
+
+	var (
+		items   []recordItem
+		records [][]string
+	)
+	for len(records) < n {
+
+		item := r.nextItem()
+		if item.record != nil {
+			if item.fields == nil {
+				item.fields = r.fieldPositions(len(item.record))
+			}
+			if r.CSVReader.ReuseRecord {
+				item.record = append([]string(nil), item.record...)
+			}
+			records = append(records, item.record)
+		}
+
+		items = append(items, item)
+		if item.err != nil {
+			break
+		}
+	}
+
+	r.pending = append(items, r.pending...)
+	return records
+}
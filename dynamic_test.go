@@ -0,0 +1,127 @@
+package csvee
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_ReadDynamic verifies records are decoded into a struct built from the header and the
+// types inferred from the records read ahead
+func TestReader_ReadDynamic(t *testing.T) {
+
+	input := "id,unit price,active,joined,2nd name,notes\n" +
+		"1,1.5,true,2021-02-13,Ann,\n" +
+		"2,3,false,2021-03-01,,x\n"
+
+	reader, err := NewReader(strings.NewReader(input), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	assert.Nil(t, reader.DynamicType())
+
+	first, err := reader.ReadDynamic()
+	require.NoError(t, err)
+
+	dynamicType := reader.DynamicType()
+	require.NotNil(t, dynamicType)
+	assert.Equal(t, reflect.PtrTo(dynamicType), reflect.TypeOf(first))
+
+	var expFields = []struct {
+		name  string
+		typ   interface{}
+		value interface{}
+	}{
+		{name: "Id", typ: int64(0), value: int64(1)},
+		{name: "UnitPrice", typ: float64(0), value: 1.5},
+		{name: "Active", typ: false, value: true},
+		{name: "Joined", typ: time.Time{}, value: time.Date(2021, time.February, 13, 0, 0, 0, 0, time.UTC)},
+		{name: "X2ndName", typ: (*string)(nil), value: "Ann"},
+		{name: "Notes", typ: (*string)(nil), value: ""},
+	}
+
+	value := reflect.ValueOf(first).Elem()
+	require.Equal(t, len(expFields), dynamicType.NumField())
+	for i, exp := range expFields {
+		field := dynamicType.Field(i)
+		assert.Equal(t, exp.name, field.Name)
+		assert.Equal(t, reflect.TypeOf(exp.typ), field.Type, exp.name)
+
+		actual := reflect.Indirect(value.Field(i))
+		assert.Equal(t, exp.value, actual.Interface(), exp.name)
+	}
+	assert.Equal(t, "2006-01-02", reader.Formats()["joined"])
+
+	second, err := reader.ReadDynamic()
+	require.NoError(t, err)
+	assert.Equal(t, reflect.TypeOf(first), reflect.TypeOf(second))
+	assert.Equal(t, int64(2), reflect.ValueOf(second).Elem().Field(0).Int())
+	assert.Equal(t, "", reflect.ValueOf(second).Elem().Field(4).Elem().String())
+	assert.Equal(t, "x", reflect.ValueOf(second).Elem().Field(5).Elem().String())
+
+	_, err = reader.ReadDynamic()
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, int64(2), reader.RowsRead())
+
+	reader, err = NewReader(strings.NewReader("n\nNULL\n4\n"), &ReaderOptions{ReadHeaders: true, NullValues: []string{"NULL"}})
+	require.NoError(t, err)
+	null, err := reader.ReadDynamic()
+	require.NoError(t, err)
+	assert.True(t, reflect.ValueOf(null).Elem().Field(0).IsNil())
+	assert.Equal(t, reflect.TypeOf((*int64)(nil)), reader.DynamicType().Field(0).Type)
+}
+
+// TestReader_ReadDynamic_Options verifies configured column types and formats take precedence over
+// inferred types, and that read ahead records are not lost by a pipeline
+func TestReader_ReadDynamic_Options(t *testing.T) {
+
+	input := "code,when\n007,13/02/2021\n008,01/03/2021\n"
+
+	var testCases = []struct {
+		name    string
+		options ReaderOptions
+		expCode interface{}
+		expWhen interface{}
+	}{
+		{
+			name:    "inferred",
+			options: ReaderOptions{ReadHeaders: true},
+			expCode: "007",
+			expWhen: "13/02/2021",
+		},
+		{
+			name:    "configured",
+			options: ReaderOptions{ReadHeaders: true, ColumnTypes: map[string]ColumnKind{"code": KindInt}, ColumnFormats: map[string]string{"when": "02/01/2006"}},
+			expCode: int64(7),
+			expWhen: time.Date(2021, time.February, 13, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "pipelined",
+			options: ReaderOptions{ReadHeaders: true, PipelineDepth: 1},
+			expCode: "007",
+			expWhen: "13/02/2021",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader(input), &tt.options)
+			require.NoError(t, err)
+
+			v, err := reader.ReadDynamic()
+			require.NoError(t, err)
+			value := reflect.ValueOf(v).Elem()
+			assert.Equal(t, tt.expCode, value.Field(0).Interface())
+			assert.Equal(t, tt.expWhen, value.Field(1).Interface())
+
+			_, err = reader.ReadDynamic()
+			require.NoError(t, err)
+			_, err = reader.ReadDynamic()
+			assert.Equal(t, io.EOF, err)
+		})
+	}
+}
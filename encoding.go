@@ -0,0 +1,266 @@
+package csvee
+
+import (
+	"bytes"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// Encoding is the character encoding of a Reader's input, which is transcoded to UTF-8 before it is
+// parsed.
+type Encoding string
+
+const (
+	// EncodingDetect, the default, reads UTF-16 input that starts with a byte order mark as UTF-16
+	// and everything else as UTF-8, dropping the byte order mark either way.
+	EncodingDetect Encoding = ""
+
+	// EncodingUTF8 reads the input as UTF-8, dropping a leading byte order mark.
+	EncodingUTF8 Encoding = "utf-8"
+
+	// EncodingUTF16LE and EncodingUTF16BE read the input as little or big endian UTF-16, dropping a
+	// leading byte order mark.
+	EncodingUTF16LE Encoding = "utf-16le"
+	EncodingUTF16BE Encoding = "utf-16be"
+
+	// EncodingWindows1252 reads the input as Windows-1252, the default code page of Excel on
+	// western Windows systems.
+	EncodingWindows1252 Encoding = "windows-1252"
+
+	// EncodingLatin1 reads the input as ISO-8859-1.
+	EncodingLatin1 Encoding = "iso-8859-1"
+)
+
+var (
+	bomUTF8    = []byte{0xef, 0xbb, 0xbf}
+	bomUTF16LE = []byte{0xff, 0xfe}
+	bomUTF16BE = []byte{0xfe, 0xff}
+)
+
+// windows1252 maps the bytes from 0x80 to 0x9f, where Windows-1252 differs from ISO-8859-1, to
+// their runes. Bytes it leaves undefined map to the control characters of the same value.
+var windows1252 = [32]rune{
+	'€', '\u0081', '‚', 'ƒ', '„', '…', '†', '‡',
+	'ˆ', '‰', 'Š', '‹', 'Œ', '\u008d', 'Ž', '\u008f',
+	'\u0090', '‘', '’', '“', '”', '•', '–', '—',
+	'˜', '™', 'š', '›', 'œ', '\u009d', 'ž', 'Ÿ',
+}
+
+// windows1252Bytes maps the runes of windows1252 back to their bytes.
+var windows1252Bytes = func() map[rune]byte {
+
+	m := make(map[rune]byte, len(windows1252))
+	for i, r := range windows1252 {
+		m[r] = byte(0x80 + i)
+	}
+	return m
+}()
+
+// Valid reports whether e is a supported encoding.
+func (e Encoding) Valid() bool {
+
+	switch e {
+	case EncodingDetect, EncodingUTF8, EncodingUTF16LE, EncodingUTF16BE, EncodingWindows1252, EncodingLatin1:
+		return true
+	}
+
+	return false
+}
+
+// decodingReader transcodes its source from an Encoding to UTF-8.
+type decodingReader struct {
+	src      io.Reader
+	encoding Encoding
+	sniffed  bool
+
+	// carry holds bytes read from src that have not been decoded, and out decoded bytes that have
+	// not been returned.
+	carry []byte
+	out   []byte
+	buf   []byte
+	err   error
+}
+
+// newDecodingReader returns a reader of src transcoded from encoding to UTF-8.
+func newDecodingReader(src io.Reader, encoding Encoding) io.Reader {
+
+	return &decodingReader{src: src, encoding: encoding}
+}
+
+func (d *decodingReader) Read(p []byte) (int, error) {
+
+	if !d.sniffed {
+		d.sniffed = true
+		if err := d.sniff(); err != nil {
+			return 0, err
+		}
+	}
+
+	if d.encoding == EncodingUTF8 {
+		if len(d.carry) > 0 {
+			n := copy(p, d.carry)
+			d.carry = d.carry[n:]
+			return n, nil
+		}
+		return d.src.Read(p)
+	}
+
+	for len(d.out) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		d.fill()
+	}
+
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
+
+// sniff reads the start of the input to drop its byte order mark, which decides the encoding if it
+// is to be detected.
+func (d *decodingReader) sniff() error {
+
+	head := make([]byte, 3)
+	n, err := io.ReadFull(d.src, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	head = head[:n]
+
+	switch {
+	case bytes.HasPrefix(head, bomUTF8) && (d.encoding == EncodingDetect || d.encoding == EncodingUTF8):
+		d.encoding, head = EncodingUTF8, head[len(bomUTF8):]
+	case bytes.HasPrefix(head, bomUTF16LE) && (d.encoding == EncodingDetect || d.encoding == EncodingUTF16LE):
+		d.encoding, head = EncodingUTF16LE, head[len(bomUTF16LE):]
+	case bytes.HasPrefix(head, bomUTF16BE) && (d.encoding == EncodingDetect || d.encoding == EncodingUTF16BE):
+		d.encoding, head = EncodingUTF16BE, head[len(bomUTF16BE):]
+	case d.encoding == EncodingDetect:
+		d.encoding = EncodingUTF8
+	}
+
+	d.carry = append(d.carry, head...)
+	return nil
+}
+
+// fill reads from the source and decodes what it can into out, setting err once the source is
+// exhausted.
+func (d *decodingReader) fill() {
+
+	if d.buf == nil {
+		d.buf = make([]byte, 32*1024)
+	}
+
+	n, err := d.src.Read(d.buf)
+	d.carry = append(d.carry, d.buf[:n]...)
+	if err != nil {
+		d.err = err
+	}
+
+	switch d.encoding {
+	case EncodingUTF16LE, EncodingUTF16BE:
+		d.decodeUTF16(d.err != nil)
+	default:
+		for _, b := range d.carry {
+			switch {
+			case b < 0x80 || d.encoding == EncodingLatin1 || b >= 0xa0:
+				d.out = utf8.AppendRune(d.out, rune(b))
+			default:
+				d.out = utf8.AppendRune(d.out, windows1252[b-0x80])
+			}
+		}
+		d.carry = d.carry[:0]
+	}
+}
+
+// decodeUTF16 decodes the complete code units in carry, keeping a trailing partial unit or high
+// surrogate unless the input has ended, in which case they decode to the replacement character.
+func (d *decodingReader) decodeUTF16(final bool) {
+
+	unit := func(i int) rune {
+		if d.encoding == EncodingUTF16LE {
+			return rune(d.carry[i]) | rune(d.carry[i+1])<<8
+		}
+		return rune(d.carry[i])<<8 | rune(d.carry[i+1])
+	}
+
+	i := 0
+	for ; i+1 < len(d.carry); i += 2 {
+		r := unit(i)
+		if utf16.IsSurrogate(r) {
+			if i+3 >= len(d.carry) {
+				if !final {
+					break
+				}
+				r = utf8.RuneError
+			} else if pair := utf16.DecodeRune(r, unit(i+2)); pair != utf8.RuneError {
+				r = pair
+				i += 2
+			} else {
+				r = utf8.RuneError
+			}
+		}
+		d.out = utf8.AppendRune(d.out, r)
+	}
+
+	if final && i < len(d.carry) {
+		d.out = utf8.AppendRune(d.out, utf8.RuneError)
+		i = len(d.carry)
+	}
+	d.carry = append(d.carry[:0], d.carry[i:]...)
+}
+
+// bom returns the byte order mark of e, or nil if it has none. EncodingDetect writes UTF-8.
+func (e Encoding) bom() []byte {
+
+	switch e {
+	case EncodingDetect, EncodingUTF8:
+		return bomUTF8
+	case EncodingUTF16LE:
+		return bomUTF16LE
+	case EncodingUTF16BE:
+		return bomUTF16BE
+	}
+
+	return nil
+}
+
+// appendEncoded appends src, which is UTF-8, to dst transcoded to encoding. It fails on runes that
+// encoding cannot represent.
+func appendEncoded(dst, src []byte, encoding Encoding) ([]byte, error) {
+
+	for len(src) > 0 {
+		r, size := utf8.DecodeRune(src)
+		src = src[size:]
+
+		switch encoding {
+		case EncodingUTF16LE, EncodingUTF16BE:
+			units := []uint16{uint16(r)}
+			if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+				units = []uint16{uint16(r1), uint16(r2)}
+			}
+			for _, unit := range units {
+				if encoding == EncodingUTF16LE {
+					dst = append(dst, byte(unit), byte(unit>>8))
+				} else {
+					dst = append(dst, byte(unit>>8), byte(unit))
+				}
+			}
+		default:
+			b, exists := windows1252Bytes[r]
+			switch {
+			case r < 0x80 || r >= 0xa0 && r <= 0xff || encoding == EncodingLatin1 && r <= 0xff:
+				dst = append(dst, byte(r))
+			case encoding == EncodingWindows1252 && exists:
+				dst = append(dst, b)
+			default:
+				return dst, errors.Errorf("%q cannot be encoded in %s", r, encoding)
+			}
+		}
+	}
+
+	return dst, nil
+}
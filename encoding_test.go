@@ -0,0 +1,244 @@
+package csvee
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type encodedRow struct {
+	Name string
+	City string
+}
+
+// utf16Bytes encodes s as UTF-16 in the given byte order.
+func utf16Bytes(s string, littleEndian bool) []byte {
+
+	var b []byte
+	for _, u := range utf16.Encode([]rune(s)) {
+		if littleEndian {
+			b = append(b, byte(u), byte(u>>8))
+		} else {
+			b = append(b, byte(u>>8), byte(u))
+		}
+	}
+
+	return b
+}
+
+// TestReader_Encoding verifies input is transcoded to UTF-8 and byte order marks are dropped
+func TestReader_Encoding(t *testing.T) {
+
+	text := "Name,City\nZoë,Kraków\n😀 Ann,“Zürich”\n"
+	expected := []encodedRow{{Name: "Zoë", City: "Kraków"}, {Name: "😀 Ann", City: "“Zürich”"}}
+
+	var testCases = []struct {
+		name     string
+		encoding Encoding
+		input    []byte
+		exp      []encodedRow
+	}{
+		{name: "utf-8", input: []byte(text), exp: expected},
+		{name: "utf-8 bom", input: append([]byte{0xef, 0xbb, 0xbf}, text...), exp: expected},
+		{name: "utf-8 bom explicit", encoding: EncodingUTF8, input: append([]byte{0xef, 0xbb, 0xbf}, text...), exp: expected},
+		{name: "utf-16le bom", input: append([]byte{0xff, 0xfe}, utf16Bytes(text, true)...), exp: expected},
+		{name: "utf-16be bom", input: append([]byte{0xfe, 0xff}, utf16Bytes(text, false)...), exp: expected},
+		{name: "utf-16le", encoding: EncodingUTF16LE, input: utf16Bytes(text, true), exp: expected},
+		{name: "utf-16be", encoding: EncodingUTF16BE, input: utf16Bytes(text, false), exp: expected},
+		{
+			name:     "windows-1252",
+			encoding: EncodingWindows1252,
+			input:    []byte("Name,City\nZo\xeb,\x93Z\xfcrich\x94 \x80\n"),
+			exp:      []encodedRow{{Name: "Zoë", City: "“Zürich” €"}},
+		},
+		{
+			name:     "latin-1",
+			encoding: EncodingLatin1,
+			input:    []byte("Name,City\nZo\xeb,Z\xfcrich\x93\n"),
+			exp:      []encodedRow{{Name: "Zoë", City: "Zürich\u0093"}},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			for _, input := range []io.Reader{bytes.NewReader(tt.input), iotest.OneByteReader(bytes.NewReader(tt.input))} {
+				reader, err := NewReader(input, &ReaderOptions{ReadHeaders: true, Encoding: tt.encoding})
+				require.NoError(t, err)
+
+				var actual []encodedRow
+				require.NoError(t, reader.ReadAll(&actual))
+				assert.Equal(t, tt.exp, actual)
+				assert.Equal(t, []string{"Name", "City"}, reader.Headers())
+			}
+		})
+	}
+}
+
+// TestReader_Unicode verifies emoji, CJK, and combining characters are decoded intact by every
+// decode path and written back unchanged
+func TestReader_Unicode(t *testing.T) {
+
+	type unicodeRow struct {
+		Name string
+		Tags []string
+		Note *string
+	}
+
+	// A family emoji joined with zero width joiners, a flag, CJK, and a decomposed accent.
+	const family, flag, cjk, accent = "👨‍👩‍👧", "🇯🇵", "東京", "e\u0301"
+	text := "Name；Tags；Note\n" + family + "；" + flag + "|" + cjk + "；" + accent + "\n"
+	note := accent
+	expected := []unicodeRow{{Name: family, Tags: []string{flag, cjk}, Note: &note}}
+
+	for _, input := range [][]byte{[]byte(text), append([]byte{0xff, 0xfe}, utf16Bytes(text, true)...)} {
+		for _, fastPath := range []bool{false, true} {
+
+			options := &ReaderOptions{ReadHeaders: true, Delimiter: '；', SliceDelimiter: "|", UnsafeFastPath: fastPath}
+			reader, err := NewReader(iotest.OneByteReader(bytes.NewReader(input)), options)
+			require.NoError(t, err)
+
+			var actual []unicodeRow
+			require.NoError(t, reader.ReadAll(&actual))
+			assert.Equal(t, expected, actual)
+
+			var buf bytes.Buffer
+			writer, err := NewWriter(&buf, &WriterOptions{ColumnNames: []string{"Name", "Tags", "Note"}, WriteHeaders: true, SliceDelimiter: "|"})
+			require.NoError(t, err)
+			require.NoError(t, writer.Write(actual[0]))
+			require.NoError(t, writer.Flush())
+			assert.Equal(t, strings.ReplaceAll(text, "；", ","), buf.String())
+		}
+	}
+
+	reader, err := NewReader(strings.NewReader(text), &ReaderOptions{ReadHeaders: true, Delimiter: '；'})
+	require.NoError(t, err)
+
+	row := map[string]string{}
+	require.NoError(t, reader.Read(&row))
+	assert.Equal(t, map[string]string{"Name": family, "Tags": flag + "|" + cjk, "Note": accent}, row)
+}
+
+// TestReader_Encoding_CountRows verifies rows of transcoded input are counted as they are read
+func TestReader_Encoding_CountRows(t *testing.T) {
+
+	input := append([]byte{0xff, 0xfe}, utf16Bytes("Name,City\nann,\"a\nb\"\nbob,c\n", true)...)
+	reader, err := NewReader(bytes.NewReader(input), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	rows, err := reader.CountRows()
+	require.NoError(t, err)
+	assert.Equal(t, 2, rows)
+
+	var actual []encodedRow
+	require.NoError(t, reader.ReadAll(&actual))
+	assert.Equal(t, []encodedRow{{Name: "ann", City: "a\nb"}, {Name: "bob", City: "c"}}, actual)
+}
+
+// TestReader_Encoding_Invalid verifies unknown encodings and truncated UTF-16 are handled
+func TestReader_Encoding_Invalid(t *testing.T) {
+
+	_, err := NewReader(strings.NewReader(""), &ReaderOptions{ReadHeaders: true, Encoding: "ebcdic"})
+	assert.EqualError(t, err, `unknown encoding "ebcdic"`)
+
+	input := append(utf16Bytes("Name\nab", true), 0x3d, 0xd8, 0x41)
+	reader, err := NewReader(bytes.NewReader(input), &ReaderOptions{ReadHeaders: true, Encoding: EncodingUTF16LE})
+	require.NoError(t, err)
+
+	var actual []encodedRow
+	require.NoError(t, reader.ReadAll(&actual))
+	assert.Equal(t, []encodedRow{{Name: "ab��"}}, actual)
+}
+
+// TestWriter_Encoding verifies output is transcoded and starts with the encoding's byte order mark
+func TestWriter_Encoding(t *testing.T) {
+
+	rows := []encodedRow{{Name: "Zoë", City: "“Zürich” €"}, {Name: "😀 Ann"}}
+
+	var testCases = []struct {
+		name     string
+		encoding Encoding
+		bom      bool
+		exp      []byte
+		expRows  []encodedRow
+		expErr   string
+	}{
+		{
+			name:    "utf-8 bom",
+			bom:     true,
+			exp:     []byte("\ufeffName,City\nZoë,“Zürich” €\n😀 Ann,\n"),
+			expRows: rows,
+		},
+		{
+			name:     "utf-16le bom",
+			encoding: EncodingUTF16LE,
+			bom:      true,
+			exp:      append([]byte{0xff, 0xfe}, utf16Bytes("Name,City\nZoë,“Zürich” €\n😀 Ann,\n", true)...),
+			expRows:  rows,
+		},
+		{
+			name:     "utf-16be",
+			encoding: EncodingUTF16BE,
+			exp:      utf16Bytes("Name,City\nZoë,“Zürich” €\n😀 Ann,\n", false),
+		},
+		{
+			name:     "windows-1252",
+			encoding: EncodingWindows1252,
+			exp:      []byte("Name,City\nZo\xeb,\x93Z\xfcrich\x94 \x80\n"),
+			expErr:   `'😀' cannot be encoded in windows-1252`,
+		},
+		{
+			name:     "latin-1",
+			encoding: EncodingLatin1,
+			exp:      []byte("Name,City\n"),
+			expErr:   `'“' cannot be encoded in iso-8859-1`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var buf bytes.Buffer
+			writer, err := NewWriter(&buf, &WriterOptions{
+				ColumnNames:  []string{"Name", "City"},
+				WriteHeaders: true,
+				Encoding:     tt.encoding,
+				WriteBOM:     tt.bom,
+			})
+			require.NoError(t, err)
+
+			err = writer.WriteAll(rows)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.NoError(t, writer.Flush())
+			assert.Equal(t, tt.exp, buf.Bytes())
+
+			if tt.expRows != nil {
+				reader, err := NewReader(&buf, &ReaderOptions{ReadHeaders: true})
+				require.NoError(t, err)
+
+				var actual []encodedRow
+				require.NoError(t, reader.ReadAll(&actual))
+				assert.Equal(t, tt.expRows, actual)
+			}
+		})
+	}
+
+	_, err := NewWriter(&bytes.Buffer{}, &WriterOptions{ColumnNames: []string{"Name"}, Encoding: "ebcdic"})
+	assert.EqualError(t, err, `unknown encoding "ebcdic"`)
+
+	_, err = NewWriter(&bytes.Buffer{}, &WriterOptions{ColumnNames: []string{"Name"}, Encoding: EncodingWindows1252, WriteBOM: true})
+	assert.EqualError(t, err, "windows-1252 has no byte order mark")
+
+	_, err = NewWriter(&bytes.Buffer{}, &WriterOptions{ColumnNames: []string{"Name"}, Encoding: EncodingUTF16LE, Checksum: ChecksumSHA256, ChecksumTrailer: true})
+	assert.EqualError(t, err, "a checksum trailer cannot be combined with utf-16le output")
+}
@@ -0,0 +1,21 @@
+package csvee
+
+import "io"
+
+// Encrypter wraps a Writer's output stream so it is encrypted before it reaches its destination,
+// e.g. with filippo.io/age or golang.org/x/crypto/openpgp. Output is compressed before it is
+// encrypted.
+type Encrypter interface {
+	// Encrypt returns a writer that encrypts to w. Closing it must finish the encrypted stream
+	// without closing w.
+	Encrypt(w io.Writer) (io.WriteCloser, error)
+}
+
+// EncrypterFunc is an adapter to allow the use of ordinary functions as an Encrypter.
+type EncrypterFunc func(w io.Writer) (io.WriteCloser, error)
+
+// Encrypt calls f(w).
+func (f EncrypterFunc) Encrypt(w io.Writer) (io.WriteCloser, error) {
+
+	return f(w)
+}
@@ -0,0 +1,85 @@
+package csvee
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// xorWriter is a stand-in cipher that marks the stream it starts and finishes.
+type xorWriter struct {
+	w io.Writer
+}
+
+func (x *xorWriter) Write(p []byte) (int, error) {
+
+	out := make([]byte, len(p))
+	for i, b := range p {
+		out[i] = b ^ 0x5a
+	}
+	return x.w.Write(out)
+}
+
+func (x *xorWriter) Close() error {
+
+	_, err := x.w.Write([]byte("END"))
+	return err
+}
+
+func xorDecrypt(data []byte) []byte {
+
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[i] = b ^ 0x5a
+	}
+	return out
+}
+
+// TestWriter_Encrypter verifies output is compressed, then encrypted, and each part is finished separately
+func TestWriter_Encrypter(t *testing.T) {
+
+	encrypter := EncrypterFunc(func(w io.Writer) (io.WriteCloser, error) {
+		_, err := w.Write([]byte("BEGIN"))
+		return &xorWriter{w: w}, err
+	})
+
+	var parts []*partBuffer
+	writer, err := NewPartWriter(func(part int) (io.WriteCloser, error) {
+		parts = append(parts, &partBuffer{})
+		return parts[len(parts)-1], nil
+	}, &WriterOptions{
+		ColumnNames:    []string{"Name"},
+		MaxRowsPerPart: 1,
+		Compression:    CompressionGzip,
+		Encrypter:      encrypter,
+		Checksum:       ChecksumSHA256,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Write(writeFrom{Name: "a"}))
+	require.NoError(t, writer.Write(writeFrom{Name: "b"}))
+	require.NoError(t, writer.Close())
+
+	require.Len(t, parts, 2)
+	for i, expData := range []string{"a\n", "b\n"} {
+
+		data := parts[i].Bytes()
+		require.True(t, bytes.HasPrefix(data, []byte("BEGIN")))
+		require.True(t, bytes.HasSuffix(data, []byte("END")))
+
+		gzipReader, err := gzip.NewReader(bytes.NewReader(xorDecrypt(data[len("BEGIN") : len(data)-len("END")])))
+		require.NoError(t, err)
+		plain, err := ioutil.ReadAll(gzipReader)
+		require.NoError(t, err)
+		assert.Equal(t, expData, string(plain))
+	}
+	assert.Equal(t, sha256Hex(parts[1].String()), writer.Checksum())
+
+	_, err = NewWriter(&bytes.Buffer{}, &WriterOptions{ColumnNames: []string{"Name"}, Encrypter: encrypter, Checksum: ChecksumMD5, ChecksumTrailer: true})
+	assert.Error(t, err)
+}
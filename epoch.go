@@ -0,0 +1,91 @@
+package csvee
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// epochUnitSeparator separates a column format from the epoch unit times in it are stored in when
+// they are decoded into integer fields, as in "2006-01-02|unixmilli".
+const epochUnitSeparator = "|"
+
+// epochUnit splits f into the format times are parsed in and the epoch unit they are stored in when
+// decoded into integer fields, which defaults to FormatUnix.
+func (f Format) epochUnit() (Format, Format) {
+
+	if i := strings.LastIndex(string(f), epochUnitSeparator); i >= 0 {
+		switch unit := f[i+len(epochUnitSeparator):]; unit {
+		case FormatUnix, FormatUnixMilli, FormatUnixNano:
+			return f[:i], unit
+		}
+	}
+
+	return f, FormatUnix
+}
+
+// isEpoch reports whether f is one of the epoch formats, whose cells are already integers.
+func (f Format) isEpoch() bool {
+
+	return f == FormatUnix || f == FormatUnixMilli || f == FormatUnixNano
+}
+
+// epochUnit returns the unit that integers of type t hold times in when they are parsed with p, and
+// false if they hold plain numbers. Integers hold epoch times in columns whose format is a time
+// layout rather than one of the epoch formats.
+func (p cellParsing) epochUnit(t reflect.Type) (Format, bool) {
+
+	if !p.epochs || !isIntegerKind(t.Kind()) {
+		return "", false
+	}
+
+	format, unit := p.format.epochUnit()
+	if format == "" || format.isEpoch() {
+		return "", false
+	}
+
+	return unit, true
+}
+
+// setEpoch sets v, an integer, to tm as an epoch time in unit.
+func setEpoch(v reflect.Value, tm time.Time, unit Format) error {
+
+	var epoch int64
+	switch unit {
+	case FormatUnixMilli:
+		epoch = tm.UnixMilli()
+	case FormatUnixNano:
+		epoch = tm.UnixNano()
+	default:
+		epoch = tm.Unix()
+	}
+
+	if isUnsignedKind(v.Kind()) {
+		if epoch < 0 || v.OverflowUint(uint64(epoch)) {
+			return &epochOverflowError{unit: unit, epoch: epoch, t: v.Type()}
+		}
+		v.SetUint(uint64(epoch))
+		return nil
+	}
+
+	if v.OverflowInt(epoch) {
+		return &epochOverflowError{unit: unit, epoch: epoch, t: v.Type()}
+	}
+	v.SetInt(epoch)
+
+	return nil
+}
+
+// epochOverflowError reports a time whose epoch value does not fit in the integer it is decoded
+// into. Unlike errors parsing the time, it is not a sign of a wrong format.
+type epochOverflowError struct {
+	unit  Format
+	epoch int64
+	t     reflect.Type
+}
+
+func (e *epochOverflowError) Error() string {
+
+	return fmt.Sprintf("%s time %d overflows %s", e.unit, e.epoch, e.t)
+}
@@ -0,0 +1,158 @@
+package csvee
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// fieldPosition is the 1-based line and byte column a field starts at, as csv.Reader.FieldPos
+// reports it.
+type fieldPosition struct {
+	line, column int
+}
+
+// recordSpan locates the record most recently read in the input, which it was read from between the
+// byte offsets offset and end. fields locates each of its fields, unless live is set, in which case
+// the csv.Reader still does.
+type recordSpan struct {
+	offset, end int64
+	fields      []fieldPosition
+	live        bool
+}
+
+// fieldPositions returns the positions of the first n fields of the record the csv.Reader read last.
+func (r *Reader) fieldPositions(n int) []fieldPosition {
+
+	fields := make([]fieldPosition, n)
+	for j := range fields {
+		fields[j].line, fields[j].column = r.CSVReader.FieldPos(j)
+	}
+
+	return fields
+}
+
+// detached returns the span with the positions of its fields copied from the csv.Reader, so that
+// they remain known after it reads further records.
+func (s recordSpan) detached(r *Reader) recordSpan {
+
+	if s.live {
+		s.fields, s.live = r.fieldPositions(len(r.lastRecord)), false
+	}
+
+	return s
+}
+
+// fieldPosition returns the position of the j-th field of the record most recently read, or the zero
+// position if it is unknown.
+func (r *Reader) fieldPosition(j int) fieldPosition {
+
+	if j < 0 || j >= len(r.lastRecord) {
+		return fieldPosition{}
+	}
+
+	if r.lastSpan.live {
+		var pos fieldPosition
+		pos.line, pos.column = r.CSVReader.FieldPos(j)
+		return pos
+	}
+
+	if j < len(r.lastSpan.fields) {
+		return r.lastSpan.fields[j]
+	}
+
+	return fieldPosition{}
+}
+
+// locate sets the position of the record most recently read, and of its j-th cell if j is not
+// negative, on e.
+func (r *Reader) locate(e *ParseError, j int) {
+
+	last := len(r.lastRecord) - 1
+	end := r.fieldPosition(last)
+	if end.line == 0 {
+		return
+	}
+
+	e.Offset, e.End = r.lastSpan.offset, r.lastSpan.end
+	e.endLine = end.line + strings.Count(r.lastRecord[last], "\n")
+
+	if j >= 0 {
+		e.Position = r.fieldPosition(j).column
+	}
+}
+
+// Excerpt returns the line of source that e occurred on, beneath a line number, with a caret under
+// the offending cell, such as:
+//
+//	4 | c,3,maybe
+//	  |     ^
+//
+// source must hold the input e was read from, as the Reader read it: after any decompression or
+// transcoding, since offsets and positions count bytes of the CSV text. The line is found between
+// Offset and End when they are known, and otherwise by scanning source from its start. The caret is
+// omitted if Position is unknown, and an empty string is returned if the line cannot be read.
+func (e *ParseError) Excerpt(source io.ReaderAt) string {
+
+	if e.Line <= 0 {
+		return ""
+	}
+
+	text, ok := e.sourceLine(source)
+	if !ok {
+		return ""
+	}
+
+	gutter := strings.Repeat(" ", len(strconv.Itoa(e.Line)))
+	excerpt := fmt.Sprintf("%d | %s", e.Line, text)
+	if e.Position <= 0 || e.Position-1 > len(text) {
+		return excerpt
+	}
+
+	// Tabs are kept so that the caret lines up however wide they are displayed.
+	var indent strings.Builder
+	for _, c := range text[:e.Position-1] {
+		if c == '\t' {
+			indent.WriteRune('\t')
+		} else {
+			indent.WriteRune(' ')
+		}
+	}
+
+	return fmt.Sprintf("%s\n%s | %s^", excerpt, gutter, indent.String())
+}
+
+// sourceLine returns the text of line e.Line of source, without its line ending.
+func (e *ParseError) sourceLine(source io.ReaderAt) (string, bool) {
+
+	if e.End > e.Offset && e.endLine >= e.Line {
+
+		chunk := make([]byte, e.End-e.Offset)
+		if n, _ := source.ReadAt(chunk, e.Offset); n < len(chunk) {
+			return "", false
+		}
+
+		// The record ends on the last line read, and any lines skipped before it come first.
+		lines := strings.Split(strings.TrimSuffix(string(chunk), "\n"), "\n")
+		i := len(lines) - 1 - (e.endLine - e.Line)
+		if i < 0 {
+			return "", false
+		}
+
+		return strings.TrimSuffix(lines[i], "\r"), true
+	}
+
+	lines := bufio.NewReader(io.NewSectionReader(source, 0, math.MaxInt64))
+	for line := 1; ; line++ {
+		text, err := lines.ReadString('\n')
+		if line == e.Line && (err == nil || text != "") {
+			return strings.TrimSuffix(strings.TrimSuffix(text, "\n"), "\r"), true
+		}
+		if err != nil {
+			return "", false
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package csvee
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ExplainPlan describes how records with the columns of options are decoded into v's struct type,
+// one line per column, so that a mapping can be reviewed, for instance in a pull request, and a
+// column bound to the wrong field or to none caught before any data is read. Each line gives the
+// field and type the column is decoded into and how the column matched it, followed by its format,
+// converter, and the rules that check it, where it has them, such as:
+//
+//	"joined" -> Joined (time.Time), exact match; format "2006-01-02"; rules "joined required"
+//
+// Options are validated as NewReader validates them, and must name their columns, since there is
+// no header to read them from. The plan is built as a Reader builds it, through the cache readers
+// share, so explaining a type also warms the cache for readers of the same columns and options.
+func ExplainPlan(v interface{}, options *ReaderOptions) (string, error) {
+
+	if v == nil {
+		return "", ErrReadTargetNil
+	}
+
+	vType := getBaseType(reflect.TypeOf(v))
+	if vType.Kind() != reflect.Struct {
+		return "", ErrUnsupportedTargetType
+	}
+
+	if options == nil {
+		return "", ErrReaderOptionsRequired
+	}
+	if len(options.ColumnNames) == 0 {
+		return "", errors.New("column names are required to explain a plan, since there are no headers to read")
+	}
+
+	explained := *options
+	explained.ReadHeaders, explained.Manifest = false, nil
+	r, err := NewReader(strings.NewReader(""), &explained)
+	if err != nil {
+		return "", err
+	}
+
+	plan, err := r.planFor(vType)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "plan for %s:", vType)
+	for i, match := range plan.matches {
+		b.WriteString("\n  ")
+		b.WriteString(r.explainColumn(plan, i, match, options))
+	}
+	if options.Validate != nil {
+		b.WriteString("\n  rows are checked by Validate")
+	}
+
+	return b.String(), nil
+}
+
+// explainColumn describes how the i-th column is decoded by plan.
+func (r *Reader) explainColumn(plan *decodePlan, i int, match ColumnMatch, options *ReaderOptions) string {
+
+	name := match.Column
+	k, planned := plan.byName[name]
+	if !planned {
+		return fmt.Sprintf("%q: unmapped", name)
+	}
+
+	column := plan.columns[k]
+	if column.column != i {
+		return fmt.Sprintf("%q: collected with column %d", name, column.column+1)
+	}
+
+	var method string
+	switch match.Method {
+	case MatchFuzzy:
+		method = fmt.Sprintf("fuzzy match (%.2f)", match.Similarity)
+	default:
+		method = fmt.Sprintf("%s match", match.Method)
+	}
+	details := []string{fmt.Sprintf("%q -> %s (%s), %s", name, match.Field, column.field.Type, method)}
+
+	if len(column.repeats) > 1 {
+		details = append(details, fmt.Sprintf("collects %d repeated columns", len(column.repeats)))
+	}
+
+	if format, exists := r.ColumnFormats[name]; exists {
+		details = append(details, fmt.Sprintf("format %q", format))
+	} else if conditional, exists := r.conditionalFormats[name]; exists {
+		details = append(details, fmt.Sprintf("format chosen by %q", conditional.Column))
+	}
+
+	switch {
+	case options.ColumnConverterNames[name] != "":
+		details = append(details, fmt.Sprintf("converter %q", options.ColumnConverterNames[name]))
+	case column.converted:
+		details = append(details, "converter func")
+	case column.unmarshal:
+		details = append(details, "unmarshaled")
+	case column.nullable:
+		details = append(details, fmt.Sprintf("nullable %s", column.fieldType))
+	}
+
+	var rules []string
+	for _, rule := range r.rules {
+		for _, ruleColumn := range rule.Columns {
+			if ruleColumn == name {
+				rules = append(rules, fmt.Sprintf("%q", rule.Name))
+				break
+			}
+		}
+	}
+	if len(rules) > 0 {
+		details = append(details, "rules "+strings.Join(rules, ", "))
+	}
+
+	return strings.Join(details, "; ")
+}
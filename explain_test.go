@@ -0,0 +1,108 @@
+package csvee
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type explainRow struct {
+	ID        int `csvee:"id"`
+	FirstName string
+	Joined    time.Time      `csvee:"joined"`
+	Tags      []string       `csvee:"tag"`
+	Note      sql.NullString `csvee:"note"`
+	Code      string         `csvee:"code"`
+	Total     float64        `csvee:"total"`
+}
+
+// TestExplainPlan verifies each column's field, match, format, converter, and rules are described
+func TestExplainPlan(t *testing.T) {
+
+	RegisterConverter("explain-upper", func(s string) (interface{}, error) { return strings.ToUpper(s), nil })
+
+	options := &ReaderOptions{
+		ColumnNames:          []string{"id", "first_name", "joined", "tag", "tag", "note", "code", "total", "extra"},
+		HeaderNormalizer:     CamelCaseHeader,
+		RepeatedColumns:      true,
+		ColumnFormats:        map[string]string{"joined": "2006-01-02"},
+		ColumnConverterNames: map[string]string{"code": "explain-upper"},
+		ColumnConverters:     map[string]Converter{"total": func(s string) (interface{}, error) { return 0.0, nil }},
+		Rules:                []Rule{{Expr: "id > 0"}, {Name: "joined set", Expr: "joined required"}},
+		Validate:             func(v interface{}, line int) error { return nil },
+	}
+
+	exp := `plan for csvee.explainRow:
+  "id" -> ID (int), exact match; rules "id > 0"
+  "first_name" -> FirstName (string), normalized match
+  "joined" -> Joined (time.Time), exact match; format "2006-01-02"; rules "joined set"
+  "tag" -> Tags ([]string), exact match; collects 2 repeated columns
+  "tag": collected with column 4
+  "note" -> Note (sql.NullString), exact match; nullable string
+  "code" -> Code (string), exact match; converter "explain-upper"
+  "total" -> Total (float64), exact match; converter func
+  "extra": unmapped
+  rows are checked by Validate`
+
+	actual, err := ExplainPlan(&explainRow{}, options)
+	require.NoError(t, err)
+	assert.Equal(t, exp, actual)
+
+	actual, err = ExplainPlan(reflect.New(reflect.TypeOf(explainRow{})).Interface(), options)
+	require.NoError(t, err)
+	assert.Equal(t, exp, actual)
+}
+
+// TestExplainPlan_WarmsCache verifies a plan explained for columns without a normalizer is the one
+// readers of the same columns use
+func TestExplainPlan_WarmsCache(t *testing.T) {
+
+	ResetPlanCache()
+	defer ResetPlanCache()
+
+	options := &ReaderOptions{ColumnNames: []string{"id", "joined"}, ColumnFormats: map[string]string{"joined": "2006-01-02"}}
+	_, err := ExplainPlan(explainRow{}, options)
+	require.NoError(t, err)
+	assert.Equal(t, CacheStats{Misses: 1, Entries: 1}, PlanCacheStats())
+
+	reader, err := NewReader(strings.NewReader("1,2021-02-13\n"), options)
+	require.NoError(t, err)
+
+	var actual explainRow
+	require.NoError(t, reader.Read(&actual))
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 1, Entries: 1}, PlanCacheStats())
+}
+
+// TestExplainPlan_Errors verifies invalid targets and options are rejected
+func TestExplainPlan_Errors(t *testing.T) {
+
+	var testCases = []struct {
+		name    string
+		v       interface{}
+		options *ReaderOptions
+		expErr  string
+	}{
+		{name: "nil target", options: &ReaderOptions{ColumnNames: []string{"id"}}, expErr: ErrReadTargetNil.Error()},
+		{name: "not a struct", v: map[string]string{}, options: &ReaderOptions{ColumnNames: []string{"id"}}, expErr: ErrUnsupportedTargetType.Error()},
+		{name: "no options", v: explainRow{}, expErr: ErrReaderOptionsRequired.Error()},
+		{name: "headers only", v: explainRow{}, options: &ReaderOptions{ReadHeaders: true}, expErr: "column names are required to explain a plan, since there are no headers to read"},
+		{name: "unknown format column", v: explainRow{}, options: &ReaderOptions{ColumnNames: []string{"id"}, ColumnFormats: map[string]string{"when": "2006"}}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			_, err := ExplainPlan(tt.v, tt.options)
+			if tt.expErr == "" {
+				assert.Error(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expErr)
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package csvee
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ColumnKind is the type of values a column holds when it is exported.
+type ColumnKind int
+
+const (
+	KindString ColumnKind = iota
+	KindInt
+	KindFloat
+	KindBool
+	KindTime
+)
+
+func (k ColumnKind) String() string {
+
+	switch k {
+	case KindInt:
+		return "int"
+	case KindFloat:
+		return "float"
+	case KindBool:
+		return "bool"
+	case KindTime:
+		return "time"
+	}
+
+	return "string"
+}
+
+// cellKinds holds the kinds record cells are converted to for output: kinds[j] applies to column
+// j if typed[j] is set, and otherwise the kind is inferred from each cell if infer is set.
+type cellKinds struct {
+	kinds []ColumnKind
+	typed []bool
+	infer bool
+}
+
+// cellKinds returns the cellKinds for the given kinds by column name.
+func (r *Reader) cellKinds(columnKinds map[string]ColumnKind, infer bool) (cellKinds, error) {
+
+	kinds := cellKinds{
+		kinds: make([]ColumnKind, len(r.ColumnNames)),
+		typed: make([]bool, len(r.ColumnNames)),
+		infer: infer,
+	}
+
+	for name, kind := range columnKinds {
+		j, exists := r.columnIndex(name)
+		if !exists {
+			return kinds, errors.Errorf("column %q not found", name)
+		}
+		kinds.kinds[j], kinds.typed[j] = kind, true
+	}
+
+	return kinds, nil
+}
+
+// typedCell converts cell, from the column at index j, to the kind kinds gives it: an int64,
+// float64, bool, or time.Time, or nil if it is null or blank. Cells that are not typed are
+// returned as they are, as are those of columns without a kind whose kind cannot be inferred. An
+// inferred kind is float for finite numbers and bool for true or false, ignoring case.
+func (r *Reader) typedCell(j int, cell string, kinds cellKinds) (interface{}, error) {
+
+	if r.isNull(cell) {
+		return nil, nil
+	}
+
+	numbers := r.settings()[j].numbers
+	value := strings.TrimSpace(cell)
+
+	kind := kinds.kinds[j]
+	if !kinds.typed[j] {
+		if !kinds.infer || value == "" {
+			return cell, nil
+		}
+		if f, err := numbers.parseFloat(value, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			return f, nil
+		}
+		if strings.EqualFold(value, "true") || strings.EqualFold(value, "false") {
+			return strings.EqualFold(value, "true"), nil
+		}
+		return cell, nil
+	}
+
+	if value == "" {
+		return nil, nil
+	}
+
+	var v reflect.Value
+	switch kind {
+	case KindInt:
+		v = reflect.New(reflect.TypeOf(int64(0))).Elem()
+	case KindFloat:
+		v = reflect.New(reflect.TypeOf(float64(0))).Elem()
+	case KindBool:
+		v = reflect.New(reflect.TypeOf(false)).Elem()
+	case KindTime:
+		v = reflect.New(reflect.TypeOf(time.Time{})).Elem()
+	default:
+		return cell, nil
+	}
+
+	if err := r.cellParsing(j, r.ColumnNames[j]).setScalar(v, value); err != nil {
+		if err == errNullDate {
+			return nil, nil
+		}
+		if kind == KindTime {
+			return nil, err
+		}
+		return nil, describeNumberError(value, v.Type(), err)
+	}
+
+	return v.Interface(), nil
+}
+
+// ColumnSink receives typed columns from ExportFrame and ExportStructs, one call per column in
+// order. It is the shim between csvee and dataframe libraries such as gota or Arrow, whose series
+// and array builders can be filled directly from each call. For example, with gota:
+//
+//	type gotaSink struct{ series []series.Series }
+//
+//	func (s *gotaSink) AddInts(name string, values []int64) error {
+//		ints := make([]int, len(values))
+//		for i, v := range values {
+//			ints[i] = int(v)
+//		}
+//		s.series = append(s.series, series.New(ints, series.Int, name))
+//		return nil
+//	}
+//
+// and so on for the other kinds, after which dataframe.New(s.series...) builds the dataframe.
+type ColumnSink interface {
+	AddStrings(name string, values []string) error
+	AddInts(name string, values []int64) error
+	AddFloats(name string, values []float64) error
+	AddBools(name string, values []bool) error
+	AddTimes(name string, values []time.Time) error
+}
+
+// Kind infers the kind of the named column from its non-blank cells: int if they are all integers,
+// float if they are all numbers, bool if they are all bools, time if the column has a format and
+// they all parse in it, and otherwise string. A column with no non-blank cells is a string column.
+func (f *Frame) Kind(name string) (ColumnKind, error) {
+
+	j, err := f.columnIndex(name)
+	if err != nil {
+		return KindString, err
+	}
+
+	parsing := f.parsing[j]
+	isInt, isFloat, isBool, isTime := true, true, true, parsing.format != ""
+	blank := true
+
+	for _, cell := range f.columns[j] {
+
+		cell = strings.TrimSpace(cell)
+		if cell == "" {
+			continue
+		}
+		blank = false
+
+		if isInt {
+			_, err := parsing.numbers.parseInt(cell, 64)
+			isInt = err == nil
+		}
+		if isFloat {
+			_, err := parsing.numbers.parseFloat(cell, 64)
+			isFloat = err == nil
+		}
+		if isBool {
+			_, err := parseBool(cell, parsing.boolParsing)
+			isBool = err == nil
+		}
+		if isTime {
+			_, err := parsing.parseTime(cell)
+			isTime = err == nil || err == errNullDate
+		}
+
+		if !isInt && !isFloat && !isBool && !isTime {
+			return KindString, nil
+		}
+	}
+
+	switch {
+	case blank:
+		return KindString, nil
+	case isTime:
+		return KindTime, nil
+	case isInt:
+		return KindInt, nil
+	case isFloat:
+		return KindFloat, nil
+	case isBool:
+		return KindBool, nil
+	}
+
+	return KindString, nil
+}
+
+// ExportFrame passes each column of frame to sink as the kind Frame.Kind infers for it. Blank
+// cells in typed columns are exported as zero values.
+func ExportFrame(frame *Frame, sink ColumnSink) error {
+
+	for _, name := range frame.names {
+
+		kind, err := frame.Kind(name)
+		if err != nil {
+			return err
+		}
+
+		switch kind {
+		case KindInt:
+			var values []int64
+			if values, err = frame.Ints(name); err == nil {
+				err = sink.AddInts(name, values)
+			}
+		case KindFloat:
+			var values []float64
+			if values, err = frame.Floats(name); err == nil {
+				err = sink.AddFloats(name, values)
+			}
+		case KindBool:
+			var values []bool
+			if values, err = frame.Bools(name); err == nil {
+				err = sink.AddBools(name, values)
+			}
+		case KindTime:
+			var values []time.Time
+			if values, err = frame.Times(name); err == nil {
+				err = sink.AddTimes(name, values)
+			}
+		default:
+			var values []string
+			if values, err = frame.Strings(name); err == nil {
+				err = sink.AddStrings(name, values)
+			}
+		}
+
+		if err != nil {
+			return errors.Wrapf(err, "column %q", name)
+		}
+	}
+
+	return nil
+}
+
+// ExportStructs passes each exported field of the structs in v, a slice of structs or of pointers
+// to structs, to sink as a column, named by its csvee tag or else its Go name. Integer, float,
+// bool, and time fields, and pointers to them, are exported as those kinds, with nil pointers as
+// zero values; other fields, such as slices, are exported as strings formatted as a Writer would
+// format them.
+func ExportStructs(v interface{}, sink ColumnSink) error {
+
+	slice := reflect.Indirect(reflect.ValueOf(v))
+	if slice.Kind() != reflect.Slice {
+		return ErrUnsupportedSourceType
+	}
+
+	vType := getBaseType(slice.Type().Elem())
+	if vType.Kind() != reflect.Struct {
+		return ErrUnsupportedSourceType
+	}
+
+	for _, field := range reflect.VisibleFields(vType) {
+
+		if field.PkgPath != "" || field.Anonymous || fieldSkipped(vType, field.Index) {
+			continue
+		}
+
+		name := field.Name
+		if tag := parseFieldTag(field); tag.name != "" {
+			name = tag.name
+		}
+
+		if err := exportField(slice, field, name, sink); err != nil {
+			return errors.Wrapf(err, "column %q", name)
+		}
+	}
+
+	return nil
+}
+
+// exportField passes the values of field across the structs in slice to sink.
+func exportField(slice reflect.Value, field reflect.StructField, name string, sink ColumnSink) error {
+
+	// values holds the field of each struct, dereferenced, or an invalid value if it is nil.
+	values := make([]reflect.Value, slice.Len())
+	for i := range values {
+		elem := reflect.Indirect(slice.Index(i))
+		if !elem.IsValid() {
+			continue
+		}
+		if value, exists := fieldByIndex(elem, field.Index); exists {
+			values[i] = reflect.Indirect(value)
+		}
+	}
+
+	t := getBaseType(field.Type)
+	switch {
+	case isTimeType(t):
+		times := make([]time.Time, len(values))
+		for i, value := range values {
+			if value.IsValid() {
+				times[i] = value.Interface().(time.Time)
+			}
+		}
+		return sink.AddTimes(name, times)
+	case isIntKind(t.Kind()) || t.Kind() >= reflect.Uint && t.Kind() <= reflect.Uint64:
+		ints := make([]int64, len(values))
+		for i, value := range values {
+			if value.IsValid() {
+				if isIntKind(t.Kind()) {
+					ints[i] = value.Int()
+				} else {
+					ints[i] = int64(value.Uint())
+				}
+			}
+		}
+		return sink.AddInts(name, ints)
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		floats := make([]float64, len(values))
+		for i, value := range values {
+			if value.IsValid() {
+				floats[i] = value.Float()
+			}
+		}
+		return sink.AddFloats(name, floats)
+	case t.Kind() == reflect.Bool:
+		bools := make([]bool, len(values))
+		for i, value := range values {
+			if value.IsValid() {
+				bools[i] = value.Bool()
+			}
+		}
+		return sink.AddBools(name, bools)
+	}
+
+	var w Writer
+	strs := make([]string, len(values))
+	for i, value := range values {
+		if !value.IsValid() {
+			continue
+		}
+		cell, err := w.formatValue(value, "", defaultSliceDelimiter, nil)
+		if err != nil {
+			return err
+		}
+		strs[i] = cell
+	}
+
+	return sink.AddStrings(name, strs)
+}
@@ -0,0 +1,114 @@
+package csvee
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink records the columns it is given as a dataframe adapter would receive them.
+type recordingSink struct {
+	names   []string
+	columns map[string]interface{}
+	failOn  string
+}
+
+func (s *recordingSink) add(name string, values interface{}) error {
+
+	if name == s.failOn {
+		return errors.New("rejected")
+	}
+	if s.columns == nil {
+		s.columns = make(map[string]interface{})
+	}
+	s.names = append(s.names, name)
+	s.columns[name] = values
+	return nil
+}
+
+func (s *recordingSink) AddStrings(name string, values []string) error  { return s.add(name, values) }
+func (s *recordingSink) AddInts(name string, values []int64) error      { return s.add(name, values) }
+func (s *recordingSink) AddFloats(name string, values []float64) error  { return s.add(name, values) }
+func (s *recordingSink) AddBools(name string, values []bool) error      { return s.add(name, values) }
+func (s *recordingSink) AddTimes(name string, values []time.Time) error { return s.add(name, values) }
+
+// TestExportFrame verifies frame columns are exported as their inferred kinds
+func TestExportFrame(t *testing.T) {
+
+	const data = "Name,Qty,Price,Paid,Shipped,Code,Empty\na,1,1.5,true,2020-01-02,1,\nb,,2,false,,x,\n"
+
+	reader, err := NewReader(strings.NewReader(data), &ReaderOptions{
+		ReadHeaders:   true,
+		ColumnFormats: map[string]string{"Shipped": "2006-01-02"},
+	})
+	require.NoError(t, err)
+
+	var frame Frame
+	require.NoError(t, reader.ReadAll(&frame))
+
+	kinds := make([]ColumnKind, 0, len(frame.Columns()))
+	for _, name := range frame.Columns() {
+		kind, err := frame.Kind(name)
+		require.NoError(t, err)
+		kinds = append(kinds, kind)
+	}
+	assert.Equal(t, []ColumnKind{KindString, KindInt, KindFloat, KindBool, KindTime, KindString, KindString}, kinds)
+	assert.Equal(t, "float", KindFloat.String())
+
+	var sink recordingSink
+	require.NoError(t, ExportFrame(&frame, &sink))
+	assert.Equal(t, frame.Columns(), sink.names)
+	assert.Equal(t, []int64{1, 0}, sink.columns["Qty"])
+	assert.Equal(t, []float64{1.5, 2}, sink.columns["Price"])
+	assert.Equal(t, []bool{true, false}, sink.columns["Paid"])
+	assert.Equal(t, []time.Time{time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), {}}, sink.columns["Shipped"])
+	assert.Equal(t, []string{"1", "x"}, sink.columns["Code"])
+
+	assert.EqualError(t, ExportFrame(&frame, &recordingSink{failOn: "Paid"}), `column "Paid": rejected`)
+}
+
+type exportEmbedded struct {
+	Region string
+}
+
+type exportRow struct {
+	exportEmbedded
+	Name    string `csvee:"name"`
+	Qty     *int
+	Weight  float32
+	Count   uint16
+	Active  bool
+	Created time.Time
+	Tags    []string
+	Secret  string `csvee:"-"`
+	hidden  int
+}
+
+// TestExportStructs verifies struct fields are exported as typed columns
+func TestExportStructs(t *testing.T) {
+
+	qty := 3
+	when := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	rows := []*exportRow{
+		{exportEmbedded: exportEmbedded{Region: "eu"}, Name: "a", Qty: &qty, Weight: 1.5, Count: 7, Active: true, Created: when, Tags: []string{"x", "y"}, hidden: 1},
+		{Name: "b"},
+	}
+
+	var sink recordingSink
+	require.NoError(t, ExportStructs(rows, &sink))
+	assert.Equal(t, []string{"Region", "name", "Qty", "Weight", "Count", "Active", "Created", "Tags"}, sink.names)
+	assert.Equal(t, []string{"eu", ""}, sink.columns["Region"])
+	assert.Equal(t, []int64{3, 0}, sink.columns["Qty"])
+	assert.Equal(t, []float64{1.5, 0}, sink.columns["Weight"])
+	assert.Equal(t, []int64{7, 0}, sink.columns["Count"])
+	assert.Equal(t, []bool{true, false}, sink.columns["Active"])
+	assert.Equal(t, []time.Time{when, {}}, sink.columns["Created"])
+	assert.Equal(t, []string{"x,y", ""}, sink.columns["Tags"])
+
+	assert.Equal(t, ErrUnsupportedSourceType, ExportStructs([]int{1}, &sink))
+	assert.Equal(t, ErrUnsupportedSourceType, ExportStructs(exportRow{}, &sink))
+}
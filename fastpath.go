@@ -0,0 +1,76 @@
+//go:build !csvee_nounsafe
+// +build !csvee_nounsafe
+
+package csvee
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// setPrimitive parses field and writes it directly into the struct pointed to by structPtr at the
+// column's precomputed offset.
+func setPrimitive(structPtr reflect.Value, col columnPlan, field string, parser *NumberParser, boolParsing BoolParsing) error {
+
+	base := unsafe.Pointer(structPtr.Pointer())
+	p := unsafe.Pointer(uintptr(base) + col.offset)
+
+	switch kind := col.fieldType.Kind(); kind {
+	case reflect.String:
+		*(*string)(p) = field
+	case reflect.Bool:
+		b, err := parseBool(field, boolParsing)
+		if err != nil {
+			return err
+		}
+		*(*bool)(p) = b
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := parser.parseInt(field, col.fieldType.Bits())
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case reflect.Int:
+			*(*int)(p) = int(i)
+		case reflect.Int8:
+			*(*int8)(p) = int8(i)
+		case reflect.Int16:
+			*(*int16)(p) = int16(i)
+		case reflect.Int32:
+			*(*int32)(p) = int32(i)
+		default:
+			*(*int64)(p) = i
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := parser.parseUint(field, col.fieldType.Bits())
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case reflect.Uint:
+			*(*uint)(p) = uint(u)
+		case reflect.Uint8:
+			*(*uint8)(p) = uint8(u)
+		case reflect.Uint16:
+			*(*uint16)(p) = uint16(u)
+		case reflect.Uint32:
+			*(*uint32)(p) = uint32(u)
+		default:
+			*(*uint64)(p) = u
+		}
+	case reflect.Float32:
+		f, err := parser.parseFloat(field, 32)
+		if err != nil {
+			return err
+		}
+		*(*float32)(p) = float32(f)
+	case reflect.Float64:
+		f, err := parser.parseFloat(field, 64)
+		if err != nil {
+			return err
+		}
+		*(*float64)(p) = f
+	}
+
+	return nil
+}
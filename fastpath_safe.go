@@ -0,0 +1,46 @@
+//go:build csvee_nounsafe
+// +build csvee_nounsafe
+
+package csvee
+
+import (
+	"reflect"
+)
+
+// setPrimitive parses field and sets the column's field on the struct pointed to by structPtr
+// using reflection.
+func setPrimitive(structPtr reflect.Value, col columnPlan, field string, parser *NumberParser, boolParsing BoolParsing) error {
+
+	v := structPtr.Elem().FieldByIndex(col.field.Index)
+
+	switch col.fieldType.Kind() {
+	case reflect.String:
+		v.SetString(field)
+	case reflect.Bool:
+		b, err := parseBool(field, boolParsing)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := parser.parseInt(field, col.fieldType.Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := parser.parseUint(field, col.fieldType.Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := parser.parseFloat(field, col.fieldType.Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	}
+
+	return nil
+}
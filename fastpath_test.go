@@ -0,0 +1,52 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fastReadTo struct {
+	nestedReadTo
+	I8  int8
+	U16 uint16
+	F32 float32
+	B   bool
+	S   string
+	IP  *int
+	IA  []int
+}
+
+// TestReader_UnsafeFastPath verifies the fast path decodes the same values as the default path
+func TestReader_UnsafeFastPath(t *testing.T) {
+
+	inData := `-8,16,1.5,true,"say ""hi""",nested,9,"1,2"` + "\n" + `,,,,,,,`
+	columnNames := []string{"I8", "U16", "F32", "B", "S", "NS", "IP", "IA"}
+
+	read := func(fastPath bool) []fastReadTo {
+		reader, err := NewReader(
+			strings.NewReader(inData),
+			&ReaderOptions{ColumnNames: columnNames, UnsafeFastPath: fastPath},
+		)
+		require.NoError(t, err)
+
+		var actualData []fastReadTo
+		require.NoError(t, reader.ReadAll(&actualData))
+		return actualData
+	}
+
+	expData := read(false)
+	actualData := read(true)
+	require.Len(t, actualData, 2)
+	assert.Equal(t, expData, actualData)
+	assert.Equal(t, int8(-8), actualData[0].I8)
+	assert.Equal(t, `say "hi"`, actualData[0].S)
+	assert.Equal(t, "nested", actualData[0].NS)
+
+	reader, err := NewReader(strings.NewReader("300"), &ReaderOptions{ColumnNames: []string{"I8"}, UnsafeFastPath: true})
+	require.NoError(t, err)
+	var row fastReadTo
+	assert.Error(t, reader.Read(&row))
+}
@@ -0,0 +1,305 @@
+package csvee
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// tagName is the struct tag used to map columns to fields, e.g. `csvee:"Email,alias=E-mail|email_address"`.
+// A tag of `csvee:"-"` skips the field, and for embedded structs, all of their fields. A struct field
+// tagged with a prefix, e.g. `csvee:"prefix=billing_"`, binds the columns named by the prefix
+// followed by the name of one of its own fields, such as "billing_street". A slice or array of
+// structs tagged with an indexed prefix, e.g. `csvee:"prefix=item{n}_"`, binds "item1_name" to the
+// Name field of its first element, "item2_name" to that of its second, and so on. A field tagged
+// omitempty, e.g. `csvee:"Count,omitempty"`, is written as WriterOptions.NullString when it holds
+// its zero value or an empty slice, rather than as "0" or "false".
+const tagName = "csvee"
+
+// fieldTag is a parsed csvee struct tag.
+type fieldTag struct {
+	name      string
+	aliases   []string
+	prefix    string
+	skip      bool
+	omitEmpty bool
+}
+
+func parseFieldTag(field reflect.StructField) fieldTag {
+
+	var tag fieldTag
+
+	value := field.Tag.Get(tagName)
+	if value == "-" {
+		tag.skip = true
+		return tag
+	}
+
+	parts := strings.Split(value, ",")
+	tag.name = strings.TrimSpace(parts[0])
+
+	// A tag that only sets a prefix has no name.
+	options := parts[1:]
+	if strings.HasPrefix(tag.name, "prefix=") {
+		tag.name, options = "", parts
+	}
+
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		switch {
+		case strings.HasPrefix(option, "alias="):
+			for _, alias := range strings.Split(strings.TrimPrefix(option, "alias="), "|") {
+				if alias = strings.TrimSpace(alias); alias != "" {
+					tag.aliases = append(tag.aliases, alias)
+				}
+			}
+		case strings.HasPrefix(option, "prefix="):
+			tag.prefix = strings.TrimPrefix(option, "prefix=")
+		case option == "omitempty":
+			tag.omitEmpty = true
+		}
+	}
+
+	return tag
+}
+
+// fieldForColumn returns the field of the struct type vType that the named column maps to. A field
+// whose csvee tag names the column or lists it as an alias takes precedence over a field with the
+// same name; a field whose tag gives it a different name is not matched by its Go name.
+func fieldForColumn(vType reflect.Type, column string) (reflect.StructField, bool) {
+
+	lookup := fieldLookupFor(vType)
+
+	if field, exists := lookup.tagged[column]; exists {
+		return field, true
+	}
+
+	field, exists := lookup.named[column]
+	if !exists || fieldSkipped(vType, field.Index) {
+		return reflect.StructField{}, false
+	}
+
+	if tag := parseFieldTag(field); tag.name != "" && tag.name != column {
+		return reflect.StructField{}, false
+	}
+
+	return field, true
+}
+
+// nestedFieldForColumn returns the field a compound column name such as "Address.City" maps to,
+// resolving each part of the name, split on separator, to a field of the struct the previous part
+// mapped to. The returned field's Index leads from vType to the nested field, and its Name is the
+// dotted path of Go field names, such as "Address.City".
+func nestedFieldForColumn(vType reflect.Type, column, separator string) (reflect.StructField, bool) {
+
+	if separator == "" || !strings.Contains(column, separator) {
+		return reflect.StructField{}, false
+	}
+
+	var (
+		field reflect.StructField
+		index []int
+		names []string
+	)
+
+	t := vType
+	for _, part := range strings.Split(column, separator) {
+
+		t = getBaseType(t)
+		if t.Kind() != reflect.Struct || isTimeType(t) {
+			return reflect.StructField{}, false
+		}
+
+		var exists bool
+		if field, exists = fieldForColumn(t, part); !exists {
+			return reflect.StructField{}, false
+		}
+
+		index = append(index, field.Index...)
+		names = append(names, field.Name)
+		t = field.Type
+	}
+
+	field.Index, field.Name = index, strings.Join(names, ".")
+	return field, true
+}
+
+// prefixedFieldForColumn returns the field a column such as "billing_street" maps to through a
+// struct field tagged with the prefix "billing_", by resolving the rest of the name, "street", to a
+// field of that struct as a column of its own, including through nested names and prefixes. Longer
+// prefixes are tried first. The returned field's Index leads from vType to the nested field, and its
+// Name is the dotted path of Go field names, such as "Billing.Street".
+func prefixedFieldForColumn(vType reflect.Type, column, separator string) (reflect.StructField, bool) {
+
+	for _, prefixed := range fieldLookupFor(vType).prefixed {
+
+		if !strings.HasPrefix(column, prefixed.prefix) {
+			continue
+		}
+		rest := column[len(prefixed.prefix):]
+
+		t := getBaseType(prefixed.field.Type)
+		field, exists := fieldForColumn(t, rest)
+		if !exists {
+			field, exists = nestedFieldForColumn(t, rest, separator)
+		}
+		if !exists {
+			field, exists = prefixedFieldForColumn(t, rest, separator)
+		}
+		if !exists {
+			continue
+		}
+
+		field.Index = append(append([]int{}, prefixed.field.Index...), field.Index...)
+		field.Name = prefixed.field.Name + "." + field.Name
+		return field, true
+	}
+
+	return reflect.StructField{}, false
+}
+
+// fieldLookup indexes the fields of a struct type by the names that columns can refer to them by,
+// so that binding wide files is not quadratic in the number of columns.
+type fieldLookup struct {
+	tagged map[string]reflect.StructField
+
+	// named holds the fields FieldByName would find, including promoted fields.
+	named map[string]reflect.StructField
+
+	// prefixed holds the struct fields tagged with a prefix, and indexed the slices and arrays of
+	// structs tagged with an indexed prefix, longest prefix first.
+	prefixed []prefixedField
+	indexed  []prefixedField
+}
+
+// prefixedField is a struct field tagged with a column name prefix.
+type prefixedField struct {
+	prefix string
+	field  reflect.StructField
+}
+
+// fieldLookups memoizes field lookups by struct type.
+var fieldLookups sync.Map
+
+func fieldLookupFor(vType reflect.Type) *fieldLookup {
+
+	if cached, exists := fieldLookups.Load(vType); exists {
+		return cached.(*fieldLookup)
+	}
+
+	lookup := &fieldLookup{tagged: taggedFields(vType), named: make(map[string]reflect.StructField)}
+	for _, field := range reflect.VisibleFields(vType) {
+		lookup.named[field.Name] = field
+
+		prefix := parseFieldTag(field).prefix
+		if prefix == "" || fieldSkipped(vType, field.Index) {
+			continue
+		}
+
+		t := getBaseType(field.Type)
+		switch {
+		case strings.Contains(prefix, indexPlaceholder):
+			if isElementGroup(field.Type) {
+				lookup.indexed = append(lookup.indexed, prefixedField{prefix: prefix, field: field})
+			}
+		case t.Kind() == reflect.Struct && !isTimeType(t):
+			lookup.prefixed = append(lookup.prefixed, prefixedField{prefix: prefix, field: field})
+		}
+	}
+
+	for _, prefixed := range [][]prefixedField{lookup.prefixed, lookup.indexed} {
+		sort.SliceStable(prefixed, func(i, j int) bool {
+			return len(prefixed[i].prefix) > len(prefixed[j].prefix)
+		})
+	}
+
+	fieldLookups.Store(vType, lookup)
+	return lookup
+}
+
+// taggedFields maps the names and aliases in the csvee tags of vType's fields, including those
+// promoted from embedded structs, to their fields. As with promoted field names, a shallower field
+// hides deeper ones with the same tag, and a tag shared by fields at the same depth is ambiguous and
+// maps to none of them.
+func taggedFields(vType reflect.Type) map[string]reflect.StructField {
+
+	type embedded struct {
+		t     reflect.Type
+		index []int
+	}
+
+	fields := make(map[string]reflect.StructField)
+	ambiguous := make(map[string]bool)
+	visited := make(map[reflect.Type]bool)
+
+	// Embedded structs are walked breadth first, one depth at a time.
+	current := []embedded{{t: vType}}
+	for len(current) > 0 {
+
+		var next []embedded
+		found := make(map[string][]reflect.StructField)
+		for _, e := range current {
+
+			if visited[e.t] {
+				continue
+			}
+			visited[e.t] = true
+
+			for i := 0; i < e.t.NumField(); i++ {
+
+				field := e.t.Field(i)
+				field.Index = append(append([]int{}, e.index...), i)
+
+				tag := parseFieldTag(field)
+				if tag.skip {
+					continue
+				}
+				// A tag with aliases but no name, such as ",alias=x", keeps the Go field name, which is
+				// matched without a tag, and adds its aliases.
+				for _, alias := range tag.aliases {
+					found[alias] = append(found[alias], field)
+				}
+				if tag.name != "" {
+					found[tag.name] = append(found[tag.name], field)
+					continue
+				}
+
+				if field.Anonymous && getBaseType(field.Type).Kind() == reflect.Struct {
+					next = append(next, embedded{t: getBaseType(field.Type), index: field.Index})
+				}
+			}
+		}
+
+		for name, candidates := range found {
+			if _, exists := fields[name]; exists || ambiguous[name] {
+				continue
+			}
+			if len(candidates) > 1 {
+				ambiguous[name] = true
+				continue
+			}
+			fields[name] = candidates[0]
+		}
+
+		current = next
+	}
+
+	return fields
+}
+
+// fieldSkipped reports whether the field of vType at index, or any embedded struct it is promoted
+// from, is tagged to be skipped.
+func fieldSkipped(vType reflect.Type, index []int) bool {
+
+	t := vType
+	for _, i := range index {
+		field := getBaseType(t).Field(i)
+		if parseFieldTag(field).skip {
+			return true
+		}
+		t = field.Type
+	}
+
+	return false
+}
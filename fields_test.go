@@ -0,0 +1,149 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type aliasedContact struct {
+	Phone string `csvee:"Phone,alias=Tel"`
+}
+
+type aliasedReadTo struct {
+	aliasedContact
+	Email string `csvee:"Email,alias=E-mail|email_address"`
+	Name  string `csvee:"full_name" json:"name"`
+	Code  string `csvee:",alias=sku"`
+}
+
+// TestReader_TagAliases verifies columns are matched to fields through csvee tag names and aliases
+func TestReader_TagAliases(t *testing.T) {
+
+	var testCases = []struct {
+		name    string
+		inData  string
+		expData aliasedReadTo
+	}{
+		{
+			name:    "tag names",
+			inData:  "Email,full_name,Phone\na@example.com,Ann,555\n",
+			expData: aliasedReadTo{Email: "a@example.com", Name: "Ann", aliasedContact: aliasedContact{Phone: "555"}},
+		},
+		{
+			name:    "aliases",
+			inData:  "email_address,Tel\nb@example.com,556\n",
+			expData: aliasedReadTo{Email: "b@example.com", aliasedContact: aliasedContact{Phone: "556"}},
+		},
+		{
+			name:    "alias without a tag name",
+			inData:  "sku\nX1\n",
+			expData: aliasedReadTo{Code: "X1"},
+		},
+		{
+			name:    "go name of a field with only aliases",
+			inData:  "Code\nX2\n",
+			expData: aliasedReadTo{Code: "X2"},
+		},
+		{
+			name:    "renamed field not matched by go name",
+			inData:  "E-mail,Name\nc@example.com,Cat\n",
+			expData: aliasedReadTo{Email: "c@example.com"},
+		},
+	}
+
+	for _, tt := range testCases {
+		for _, fastPath := range []bool{false, true} {
+			t.Run(tt.name, func(t *testing.T) {
+
+				reader, err := NewReader(
+					strings.NewReader(tt.inData),
+					&ReaderOptions{ReadHeaders: true, UnsafeFastPath: fastPath},
+				)
+				require.NoError(t, err)
+
+				var actualData aliasedReadTo
+				require.NoError(t, reader.Read(&actualData))
+				assert.Equal(t, tt.expData, actualData)
+			})
+		}
+	}
+}
+
+type shadowedInner struct {
+	A string `csvee:"a"`
+	B string `csvee:"b"`
+}
+
+type shadowedOther struct {
+	B string `csvee:"b"`
+}
+
+type shadowingReadTo struct {
+	shadowedInner
+	shadowedOther
+	X string `csvee:"a"`
+}
+
+// TestReader_TagShadowing verifies a shallower tagged field hides deeper ones with the same tag, and
+// tags shared at the same depth match no field
+func TestReader_TagShadowing(t *testing.T) {
+
+	for _, fastPath := range []bool{false, true} {
+
+		reader, err := NewReader(
+			strings.NewReader("a,b\nx,y\n"),
+			&ReaderOptions{ReadHeaders: true, UnsafeFastPath: fastPath},
+		)
+		require.NoError(t, err)
+
+		var actualData shadowingReadTo
+		require.NoError(t, reader.Read(&actualData))
+		assert.Equal(t, shadowingReadTo{X: "x"}, actualData)
+	}
+}
+
+type skippedAudit struct {
+	CreatedBy string
+}
+
+type taggedReadTo struct {
+	skippedAudit `csvee:"-"`
+	UserID       int    `csvee:"user_id"`
+	FirstName    string `csvee:"First Name"`
+	Internal     string `csvee:"-"`
+	Email        string
+}
+
+// TestReader_SkipTags verifies fields tagged "-" are never decoded, for both Read and ReadAll
+func TestReader_SkipTags(t *testing.T) {
+
+	const inData = "user_id,First Name,Internal,CreatedBy,Email\n7,Ann,secret,root,a@x.io\n"
+	expData := taggedReadTo{UserID: 7, FirstName: "Ann", Email: "a@x.io"}
+
+	for _, threshold := range []float64{0, 0.5} {
+
+		reader, err := NewReader(strings.NewReader(inData), &ReaderOptions{ReadHeaders: true, FuzzyMatchThreshold: threshold})
+		require.NoError(t, err)
+
+		var actual taggedReadTo
+		require.NoError(t, reader.Read(&actual))
+		assert.Equal(t, expData, actual)
+
+		reader, err = NewReader(strings.NewReader(inData), &ReaderOptions{ReadHeaders: true, FuzzyMatchThreshold: threshold})
+		require.NoError(t, err)
+
+		var actualData []taggedReadTo
+		require.NoError(t, reader.ReadAll(&actualData))
+		assert.Equal(t, []taggedReadTo{expData}, actualData)
+	}
+
+	var buf strings.Builder
+	writer, err := NewWriter(&buf, &WriterOptions{ColumnNames: []string{"user_id", "Internal", "CreatedBy"}})
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(taggedReadTo{UserID: 7, Internal: "secret", skippedAudit: skippedAudit{CreatedBy: "root"}}))
+	require.NoError(t, writer.Flush())
+	assert.Equal(t, "7,,\n", buf.String())
+}
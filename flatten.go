@@ -0,0 +1,199 @@
+package csvee
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ArrayHandling controls how arrays are written when JSON objects are flattened into columns.
+type ArrayHandling int
+
+const (
+	// ArraysJoined writes arrays of scalars comma separated, as the Writer writes slices, and other
+	// arrays as JSON.
+	ArraysJoined ArrayHandling = iota
+
+	// ArraysJSON writes arrays as JSON, which is embedded as an array in JSON output.
+	ArraysJSON
+
+	// ArraysIndexed flattens arrays as if they were objects keyed by the index of each element, so
+	// that ["a", "b"] under the key "tags" fills the columns "tags.0" and "tags.1".
+	ArraysIndexed
+)
+
+// ExplodedColumn describes a column whose cells hold JSON objects that are flattened into columns
+// of their own, named by joining the keys of nested objects with a separator. Blank and null cells
+// have no keys, and null values within the objects are skipped.
+type ExplodedColumn struct {
+	// Column names the column holding JSON objects.
+	Column string
+
+	// Prefix is prepended to each flattened key to name its column. Defaults to the column name
+	// followed by the separator.
+	Prefix string
+
+	// Separator joins the keys of nested objects. Defaults to ".".
+	Separator string
+
+	// Arrays controls how arrays within the objects are written.
+	Arrays ArrayHandling
+
+	// KeepColumn also writes the column itself, as it is.
+	KeepColumn bool
+}
+
+// explodedColumns returns the exploded column, with its defaults applied, of each of the reader's
+// columns, or nil for columns that are not exploded.
+func (r *Reader) explodedColumns(columns []ExplodedColumn) ([]*ExplodedColumn, error) {
+
+	exploded := make([]*ExplodedColumn, len(r.ColumnNames))
+	for _, column := range columns {
+
+		j, exists := r.columnIndex(column.Column)
+		if !exists {
+			return nil, errors.Errorf("column %q not found", column.Column)
+		}
+		if exploded[j] != nil {
+			return nil, errors.Errorf("column %q is exploded more than once", column.Column)
+		}
+
+		column := column
+		if column.Separator == "" {
+			column.Separator = "."
+		}
+		if column.Prefix == "" {
+			column.Prefix = column.Column + column.Separator
+		}
+		exploded[j] = &column
+	}
+
+	return exploded, nil
+}
+
+// explode flattens the JSON object in cell, calling set with the column and value of each key.
+func (r *Reader) explode(column *ExplodedColumn, cell string, set func(key string, value interface{}) error) error {
+
+	if strings.TrimSpace(cell) == "" || r.isNull(cell) {
+		return nil
+	}
+
+	object, err := decodeJSONObject([]byte(cell))
+	if err != nil {
+		return err
+	}
+
+	return flattenJSON(object, column.Prefix, column.Separator, column.Arrays, set)
+}
+
+// decodeJSONObject decodes data, which must hold a single JSON object, keeping numbers as they are
+// written.
+func decodeJSONObject(data []byte) (map[string]interface{}, error) {
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+	if object == nil {
+		return nil, errors.New("value must be a JSON object")
+	}
+	if decoder.More() {
+		return nil, errors.New("value must be a single JSON object")
+	}
+
+	return object, nil
+}
+
+// flattenJSON calls set with the flattened key and value of each value in object, in key order.
+// Values are strings, json.Numbers, bools, or, when arrays are written as JSON, slices.
+func flattenJSON(object map[string]interface{}, prefix, separator string, arrays ArrayHandling, set func(key string, value interface{}) error) error {
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := flattenJSONValue(prefix+key, object[key], separator, arrays, set); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func flattenJSONValue(key string, value interface{}, separator string, arrays ArrayHandling, set func(key string, value interface{}) error) error {
+
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		return flattenJSON(v, key+separator, separator, arrays, set)
+	case []interface{}:
+		switch arrays {
+		case ArraysIndexed:
+			for i, element := range v {
+				if err := flattenJSONValue(key+separator+strconv.Itoa(i), element, separator, arrays, set); err != nil {
+					return err
+				}
+			}
+			return nil
+		case ArraysJoined:
+			cell, err := jsonCell(v)
+			if err != nil {
+				return err
+			}
+			return set(key, cell)
+		}
+	}
+
+	return set(key, value)
+}
+
+// jsonCell formats a decoded JSON value other than an object as a cell. Arrays of scalars are
+// written comma separated and other arrays as JSON.
+func jsonCell(value interface{}) (string, error) {
+
+	switch value := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case json.Number:
+		return value.String(), nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case []interface{}:
+		cells := make([]string, len(value))
+		for i, element := range value {
+			switch element.(type) {
+			case []interface{}, map[string]interface{}:
+				data, err := json.Marshal(value)
+				return string(data), err
+			}
+			cells[i], _ = jsonCell(element)
+		}
+		return strings.Join(cells, ","), nil
+	}
+
+	return "", errors.Errorf("unsupported JSON value %v", value)
+}
+
+// flattenedCell formats a value passed to set by flattenJSON as a cell.
+func flattenedCell(value interface{}) (string, error) {
+
+	if array, isArray := value.([]interface{}); isArray {
+		data, err := json.Marshal(array)
+		return string(data), err
+	}
+
+	return jsonCell(value)
+}
@@ -0,0 +1,236 @@
+package csvee
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const explodeInput = "id,meta\n" +
+	"1,\"{\"\"color\"\": \"\"red\"\", \"\"size\"\": {\"\"w\"\": 2, \"\"h\"\": 3}, \"\"tags\"\": [\"\"a\"\", \"\"b\"\"], \"\"note\"\": null}\"\n" +
+	"2,\n"
+
+// TestToCSV verifies records are copied by column name with JSON columns exploded
+func TestToCSV(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		columns  []string
+		options  *CSVOptions
+		expected string
+		err      string
+	}{
+		{
+			name:     "copied",
+			columns:  []string{"meta", "id", "missing"},
+			expected: "meta,id,missing\n\"{\"\"color\"\": \"\"red\"\", \"\"size\"\": {\"\"w\"\": 2, \"\"h\"\": 3}, \"\"tags\"\": [\"\"a\"\", \"\"b\"\"], \"\"note\"\": null}\",1,\n,2,\n",
+		},
+		{
+			name:     "joined",
+			columns:  []string{"id", "meta.color", "meta.size.w", "meta.size.h", "meta.tags"},
+			options:  &CSVOptions{ExplodeColumns: []ExplodedColumn{{Column: "meta"}}},
+			expected: "id,meta.color,meta.size.w,meta.size.h,meta.tags\n1,red,2,3,\"a,b\"\n2,,,,\n",
+		},
+		{
+			name:    "indexed",
+			columns: []string{"id", "meta", "m_color", "m_size_w", "m_tags_0", "m_tags_1"},
+			options: &CSVOptions{
+				ExplodeColumns:       []ExplodedColumn{{Column: "meta", Prefix: "m_", Separator: "_", Arrays: ArraysIndexed, KeepColumn: true}},
+				IgnoreUnknownColumns: true,
+			},
+			expected: "id,meta,m_color,m_size_w,m_tags_0,m_tags_1\n1,\"{\"\"color\"\": \"\"red\"\", \"\"size\"\": {\"\"w\"\": 2, \"\"h\"\": 3}, \"\"tags\"\": [\"\"a\"\", \"\"b\"\"], \"\"note\"\": null}\",red,2,a,b\n2,,,,,\n",
+		},
+		{
+			name:     "json arrays",
+			columns:  []string{"meta.tags"},
+			options:  &CSVOptions{ExplodeColumns: []ExplodedColumn{{Column: "meta", Arrays: ArraysJSON}}, IgnoreUnknownColumns: true},
+			expected: "meta.tags\n\"[\"\"a\"\",\"\"b\"\"]\"\n\n",
+		},
+		{
+			name:    "unknown key",
+			columns: []string{"id", "meta.color"},
+			options: &CSVOptions{ExplodeColumns: []ExplodedColumn{{Column: "meta"}}},
+			err:     `key "meta.size.h" is not a column of the writer`,
+		},
+		{
+			name:    "unknown column",
+			columns: []string{"meta"},
+			err:     `column "id" is not a column of the writer`,
+		},
+		{
+			name:    "unknown exploded column",
+			columns: []string{"id"},
+			options: &CSVOptions{ExplodeColumns: []ExplodedColumn{{Column: "data"}}},
+			err:     `column "data" not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewReader(strings.NewReader(explodeInput), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			writer, err := NewWriter(&buf, &WriterOptions{ColumnNames: tt.columns, WriteHeaders: true})
+			require.NoError(t, err)
+
+			err = ToCSV(reader, writer, tt.options)
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+// TestToCSV_EscapeFormulas verifies cells copied from the input are escaped when the writer escapes
+// formulas
+func TestToCSV_EscapeFormulas(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader("id,expr\n1,=1+1\n2,@SUM(A1)\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, &WriterOptions{ColumnNames: []string{"id", "expr"}, WriteHeaders: true, EscapeFormulas: true})
+	require.NoError(t, err)
+
+	require.NoError(t, ToCSV(reader, writer, nil))
+	assert.Equal(t, "id,expr\n1,'=1+1\n2,'@SUM(A1)\n", buf.String())
+}
+
+// TestToCSV_Header verifies the header row is written, renamed, copied from the input, or suppressed
+// as the header mode directs
+func TestToCSV_Header(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		mode         HeaderMode
+		writeHeaders bool
+		expected     string
+	}{
+		{name: "default without headers", mode: HeaderDefault, expected: "a,1,\n"},
+		{name: "default with headers", mode: HeaderDefault, writeHeaders: true, expected: "name,id,extra\na,1,\n"},
+		{name: "renamed", mode: HeaderRenamed, expected: "name,id,extra\na,1,\n"},
+		{name: "original", mode: HeaderOriginal, expected: "Full Name,ID,extra\na,1,\n"},
+		{name: "suppressed", mode: HeaderSuppress, writeHeaders: true, expected: "a,1,\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewReader(strings.NewReader("ID,\"Full Name\"\n1,a\n"), &ReaderOptions{
+				ReadHeaders: true,
+				HeaderNormalizer: func(header string) string {
+					return strings.ToLower(strings.TrimPrefix(header, "Full "))
+				},
+			})
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			writer, err := NewWriter(&buf, &WriterOptions{ColumnNames: []string{"name", "id", "extra"}, WriteHeaders: tt.writeHeaders})
+			require.NoError(t, err)
+
+			require.NoError(t, ToCSV(reader, writer, &CSVOptions{Header: tt.mode}))
+			assert.Equal(t, tt.expected, buf.String())
+
+			// The writer's own header setting is restored.
+			assert.Equal(t, tt.writeHeaders, writer.writeHeaders)
+			assert.Nil(t, writer.headerNames)
+		})
+	}
+}
+
+// TestToJSONArray_ExplodeColumns verifies exploded JSON columns keep their value types
+func TestToJSONArray_ExplodeColumns(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		column   ExplodedColumn
+		expected string
+	}{
+		{
+			name:     "joined",
+			column:   ExplodedColumn{Column: "meta"},
+			expected: `[{"id":"1","meta.color":"red","meta.size.h":3,"meta.size.w":2,"meta.tags":"a,b"},{"id":"2"}]` + "\n",
+		},
+		{
+			name:     "json arrays",
+			column:   ExplodedColumn{Column: "meta", Separator: "/", Arrays: ArraysJSON},
+			expected: `[{"id":"1","meta/color":"red","meta/size/h":3,"meta/size/w":2,"meta/tags":["a","b"]},{"id":"2"}]` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewReader(strings.NewReader(explodeInput), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, ToJSONArray(reader, &buf, &JSONOptions{ExplodeColumns: []ExplodedColumn{tt.column}}))
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+// TestToCSV_ColumnKinds verifies cells are coerced to their column's kind and normalized
+func TestToCSV_ColumnKinds(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		err      string
+	}{
+		{
+			name:     "coerced",
+			input:    "id,price,active,date,note\n007,\"1,234.50\",yes,03/04/2021,x\n8,,N,,\n",
+			expected: "id,price,active,date,note\n7,1234.5,true,2021-03-04,x\n8,,false,,\n",
+		},
+		{
+			name:  "invalid",
+			input: "id,price,active,date,note\n1,2,maybe,03/04/2021,x\n",
+			err:   `row 1, column "active"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewReader(strings.NewReader(tt.input), &ReaderOptions{
+				ReadHeaders:   true,
+				BoolParsing:   BoolLenient,
+				ColumnFormats: map[string]string{"date": "01/02/2006"},
+				NumberParsers: map[string]*NumberParser{"price": {
+					ParseFloat: func(s string, bitSize int) (float64, error) {
+						return strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), bitSize)
+					},
+				}},
+			})
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			writer, err := NewWriter(&buf, &WriterOptions{
+				ColumnNames:   []string{"id", "price", "active", "date", "note"},
+				ColumnFormats: map[string]string{"date": "2006-01-02"},
+				WriteHeaders:  true,
+			})
+			require.NoError(t, err)
+
+			err = ToCSV(reader, writer, &CSVOptions{ColumnKinds: map[string]ColumnKind{
+				"id": KindInt, "price": KindFloat, "active": KindBool, "date": KindTime,
+			}})
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
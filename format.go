@@ -0,0 +1,226 @@
+package csvee
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Format identifies how a time column is parsed. It is either the name of a registered format,
+// such as TimeFormatUnix, or a layout as understood by time.Parse.
+type Format string
+
+const (
+	// FormatUnix parses integer seconds since the Unix epoch.
+	FormatUnix Format = Format(TimeFormatUnix)
+
+	// FormatUnixMilli parses integer milliseconds since the Unix epoch.
+	FormatUnixMilli Format = Format(TimeFormatUnixMilli)
+
+	// FormatUnixNano parses integer nanoseconds since the Unix epoch.
+	FormatUnixNano Format = Format(TimeFormatUnixNano)
+)
+
+// TimeParser parses a field into a time.
+type TimeParser func(field string) (time.Time, error)
+
+// TimeFormatter formats a time as a field. It is the inverse of a TimeParser.
+type TimeFormatter func(t time.Time) string
+
+// FormatError is returned when a column format is neither a registered format nor a time layout.
+type FormatError struct {
+	Column string
+	Format string
+}
+
+func (e *FormatError) Error() string {
+
+	if e.Column == "" {
+		return fmt.Sprintf("unknown format %q", e.Format)
+	}
+
+	return fmt.Sprintf("unknown column format %q for column %s", e.Format, e.Column)
+}
+
+var (
+	formatRegistryMu sync.RWMutex
+	formatRegistry   = map[Format]TimeParser{
+		FormatUnix:      parseUnixTime,
+		FormatUnixMilli: parseUnixMilliTime,
+		FormatUnixNano:  parseUnixNanoTime,
+		FormatPeriod:    parsePeriodTime,
+	}
+	formatterRegistry = map[Format]TimeFormatter{
+		FormatUnix:      formatUnixTime,
+		FormatUnixMilli: formatUnixMilliTime,
+		FormatUnixNano:  formatUnixNanoTime,
+	}
+)
+
+// RegisterFormat registers parse as the parser for the named format so it can be used in
+// ReaderOptions.ColumnFormats. Registering a name that already exists replaces its parser.
+func RegisterFormat(name string, parse TimeParser) {
+
+	formatRegistryMu.Lock()
+	defer formatRegistryMu.Unlock()
+
+	formatRegistry[Format(name)] = parse
+}
+
+// RegisterFormatter registers format as the formatter for the named format so it can be used in
+// WriterOptions.ColumnFormats. Registering a name that already exists replaces its formatter.
+func RegisterFormatter(name string, format TimeFormatter) {
+
+	formatRegistryMu.Lock()
+	defer formatRegistryMu.Unlock()
+
+	formatterRegistry[Format(name)] = format
+}
+
+// LayoutFormat returns the Format for a time.Parse layout, or an error if layout does not contain
+// any layout elements.
+func LayoutFormat(layout string) (Format, error) {
+
+	f := Format(layout)
+	if !f.isLayout() {
+		return "", &FormatError{Format: layout}
+	}
+
+	return f, nil
+}
+
+// Valid reports whether f is a registered format or a time layout, optionally followed by the epoch
+// unit that integer fields store its times in.
+func (f Format) Valid() bool {
+
+	f, _ = f.epochUnit()
+	_, registered := f.parser()
+	return registered || f.isLayout()
+}
+
+// Parse parses field according to f.
+func (f Format) Parse(field string) (time.Time, error) {
+
+	f, _ = f.epochUnit()
+	if parse, registered := f.parser(); registered {
+		return parse(field)
+	}
+
+	return time.Parse(string(f), field)
+}
+
+// ParseInLocation parses field according to f as Parse does, but interprets times without zone
+// information in loc, as time.ParseInLocation does. Times parsed by registered formats are returned
+// in loc. An empty f parses RFC 3339.
+func (f Format) ParseInLocation(field string, loc *time.Location) (time.Time, error) {
+
+	f, _ = f.epochUnit()
+	if f == "" {
+		f = time.RFC3339
+	}
+
+	if parse, registered := f.parser(); registered {
+		t, err := parse(field)
+		if err != nil {
+			return t, err
+		}
+		return t.In(loc), nil
+	}
+
+	return time.ParseInLocation(string(f), field, loc)
+}
+
+// Format formats t according to f. Registered formats must also have a registered formatter.
+func (f Format) Format(t time.Time) (string, error) {
+
+	f, _ = f.epochUnit()
+
+	formatRegistryMu.RLock()
+	format, registered := formatterRegistry[f]
+	formatRegistryMu.RUnlock()
+
+	if registered {
+		return format(t), nil
+	}
+
+	if _, parses := f.parser(); parses {
+		return "", fmt.Errorf("format %q has no registered formatter", string(f))
+	}
+
+	return t.Format(string(f)), nil
+}
+
+func (f Format) parser() (TimeParser, bool) {
+
+	formatRegistryMu.RLock()
+	defer formatRegistryMu.RUnlock()
+
+	parse, registered := formatRegistry[f]
+	return parse, registered
+}
+
+// isLayout reports whether f contains at least one time layout element; formatting a reference
+// time with a string that has none returns the string unchanged.
+func (f Format) isLayout() bool {
+
+	reference := time.Date(2001, time.February, 3, 4, 5, 6, 0, time.UTC)
+	return reference.Format(string(f)) != string(f)
+}
+
+func validateColumnFormats(formats map[string]string) error {
+
+	for _, column := range sortedKeys(formats) {
+		format := formats[column]
+		if !Format(format).Valid() {
+			return &FormatError{Column: column, Format: format}
+		}
+	}
+
+	return nil
+}
+
+func parseUnixTime(field string) (time.Time, error) {
+
+	intField, err := strconv.ParseInt(field, 10, 0)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(intField, 0), nil
+}
+
+func formatUnixTime(t time.Time) string {
+
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+func parseUnixMilliTime(field string) (time.Time, error) {
+
+	intField, err := strconv.ParseInt(field, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.UnixMilli(intField), nil
+}
+
+func formatUnixMilliTime(t time.Time) string {
+
+	return strconv.FormatInt(t.UnixMilli(), 10)
+}
+
+func parseUnixNanoTime(field string) (time.Time, error) {
+
+	intField, err := strconv.ParseInt(field, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(0, intField), nil
+}
+
+func formatUnixNanoTime(t time.Time) string {
+
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
@@ -0,0 +1,359 @@
+package csvee
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewReader_ColumnFormats verifies column formats are validated when the reader is constructed
+func TestNewReader_ColumnFormats(t *testing.T) {
+
+	var testCases = []struct {
+		name            string
+		inColumnFormats map[string]string
+		expErrText      string
+	}{
+		{
+			name:            "unix",
+			inColumnFormats: map[string]string{"Tu": TimeFormatUnix},
+		},
+		{
+			name:            "layout",
+			inColumnFormats: map[string]string{"T": "2006-01-02"},
+		},
+		{
+			name:            "typo",
+			inColumnFormats: map[string]string{"Tu": "uinx"},
+			expErrText:      `unknown column format "uinx" for column Tu`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			_, err := NewReader(
+				strings.NewReader(""),
+				&ReaderOptions{ColumnNames: []string{"T", "Tu"}, ColumnFormats: tt.inColumnFormats},
+			)
+
+			if tt.expErrText != "" {
+				assert.EqualError(t, err, tt.expErrText)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// TestRegisterFormat verifies registered formats are used to parse time columns
+func TestRegisterFormat(t *testing.T) {
+
+	RegisterFormat("unixmilli-test", func(field string) (time.Time, error) {
+		tm, err := FormatUnix.Parse(field[:len(field)-3])
+		return tm, err
+	})
+
+	reader, err := NewReader(
+		strings.NewReader("1613235342000"),
+		&ReaderOptions{ColumnNames: []string{"T"}, ColumnFormats: map[string]string{"T": "unixmilli-test"}},
+	)
+	require.NoError(t, err)
+
+	var actualData readTo
+	require.NoError(t, reader.Read(&actualData))
+	assert.Equal(t, int64(1613235342), actualData.T.Unix())
+
+	_, err = LayoutFormat("uinx")
+	assert.Error(t, err)
+	f, err := LayoutFormat(time.RFC3339)
+	require.NoError(t, err)
+	assert.True(t, f.Valid())
+}
+
+type locatedRow struct {
+	Local time.Time
+	Milli time.Time
+	Nano  *time.Time
+}
+
+// TestReader_Location verifies times without zone information are parsed in the reader's location
+// and the default time format applies to columns without one
+func TestReader_Location(t *testing.T) {
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	input := "Local,Milli,Nano\n2021-02-13 16:55:42,1613235342123,1613235342123456789\n"
+
+	var testCases = []struct {
+		name     string
+		location *time.Location
+		expLocal time.Time
+	}{
+		{
+			name:     "utc",
+			expLocal: time.Date(2021, 2, 13, 16, 55, 42, 0, time.UTC),
+		},
+		{
+			name:     "location",
+			location: tokyo,
+			expLocal: time.Date(2021, 2, 13, 16, 55, 42, 0, tokyo),
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader(input), &ReaderOptions{
+				ReadHeaders:       true,
+				DefaultTimeFormat: "2006-01-02 15:04:05",
+				Location:          tt.location,
+				ColumnFormats:     map[string]string{"Milli": TimeFormatUnixMilli, "Nano": TimeFormatUnixNano},
+			})
+			require.NoError(t, err)
+
+			var actual []locatedRow
+			require.NoError(t, reader.ReadAll(&actual))
+			require.Len(t, actual, 1)
+			assert.True(t, tt.expLocal.Equal(actual[0].Local), actual[0].Local)
+			assert.Equal(t, tt.expLocal.Location().String(), actual[0].Local.Location().String())
+			assert.Equal(t, int64(1613235342123), actual[0].Milli.UnixMilli())
+			require.NotNil(t, actual[0].Nano)
+			assert.Equal(t, int64(1613235342123456789), actual[0].Nano.UnixNano())
+			if tt.location != nil {
+				assert.Equal(t, tt.location, actual[0].Milli.Location())
+			}
+
+			config := reader.Config()
+			assert.Equal(t, tt.location, config.Location)
+			assert.Equal(t, "2006-01-02 15:04:05", config.Columns[0].Format)
+			assert.Equal(t, TimeFormatUnixMilli, config.Columns[1].Format)
+		})
+	}
+
+	_, err := NewReader(strings.NewReader(""), &ReaderOptions{ColumnNames: []string{"T"}, DefaultTimeFormat: "uinx"})
+	assert.EqualError(t, err, `unknown format "uinx"`)
+}
+
+// TestWriter_UnixFormats verifies millisecond and nanosecond unix times are written as they are read
+func TestWriter_UnixFormats(t *testing.T) {
+
+	when := time.Unix(1613235342, 123456789)
+
+	var buf strings.Builder
+	writer, err := NewWriter(&buf, &WriterOptions{
+		ColumnNames:   []string{"Local", "Milli", "Nano"},
+		ColumnFormats: map[string]string{"Milli": TimeFormatUnixMilli, "Nano": TimeFormatUnixNano},
+	})
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(locatedRow{Local: when.UTC(), Milli: when, Nano: &when}))
+	require.NoError(t, writer.Flush())
+	assert.Equal(t, "2021-02-13T16:55:42.123456789Z,1613235342123,1613235342123456789\n", buf.String())
+}
+
+type epochRow struct {
+	Created int64
+	Updated uint64
+	Count   int
+	Day     int32
+}
+
+// TestReader_EpochFields verifies formatted time columns decode into integer fields as epoch times
+func TestReader_EpochFields(t *testing.T) {
+
+	formats := map[string]string{
+		"Created": "2006-01-02",
+		"Updated": "2006-01-02 15:04:05|unixmilli",
+		"Count":   TimeFormatUnix,
+		"Day":     "02/01/2006",
+	}
+
+	testCases := []struct {
+		name   string
+		data   string
+		exp    []epochRow
+		expErr string
+	}{
+		{
+			name: "epochs",
+			data: "Created,Updated,Count,Day\n2021-12-31,2021-12-31 23:59:59,42,01/01/1970\n,,,\n",
+			exp: []epochRow{
+				{Created: 1640908800, Updated: 1640995199000, Count: 42},
+				{},
+			},
+		},
+		{
+			name:   "invalid time",
+			data:   "Created,Updated,Count,Day\n31/12/2021,2021-12-31 23:59:59,42,01/01/1970\n",
+			expErr: `row 1, column "Created": parsing time "31/12/2021" as "2006-01-02": cannot parse "31/12/2021" as "2006"; the value matches the layout "02/01/2006", set ColumnFormats["Created"] to it`,
+		},
+		{
+			name:   "before the epoch",
+			data:   "Created,Updated,Count,Day\n2021-12-31,1969-12-31 23:59:59,42,01/01/1970\n",
+			expErr: `row 1, column "Updated": unixmilli time -1000 overflows uint64`,
+		},
+		{
+			name:   "overflow",
+			data:   "Created,Updated,Count,Day\n2021-12-31,2021-12-31 23:59:59,42,01/01/2100\n",
+			expErr: `row 1, column "Day": unix time 4102444800 overflows int32`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader(tt.data), &ReaderOptions{ReadHeaders: true, ColumnFormats: formats})
+			require.NoError(t, err)
+
+			var rows []epochRow
+			err = reader.ReadAll(&rows)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, rows)
+		})
+	}
+
+	assert.True(t, Format("2006-01-02|unixnano").Valid())
+	assert.False(t, Format("|unixmilli").Valid())
+}
+
+type zonedRow struct {
+	At time.Time
+}
+
+// TestReader_ZoneAbbreviations verifies zone abbreviations resolve to the locations in the table
+func TestReader_ZoneAbbreviations(t *testing.T) {
+
+	const data = "At\n2021-05-01 10:00 EST\n2021-05-01 10:00 CEST\n2021-05-01 10:00 XYZ\n"
+
+	zones := CommonZoneAbbreviations()
+	reader, err := NewReader(strings.NewReader(data), &ReaderOptions{
+		ReadHeaders:       true,
+		ColumnFormats:     map[string]string{"At": "2006-01-02 15:04 MST"},
+		ZoneAbbreviations: zones,
+	})
+	require.NoError(t, err)
+
+	var rows []zonedRow
+	require.NoError(t, reader.ReadAll(&rows))
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, time.Date(2021, 5, 1, 15, 0, 0, 0, time.UTC), rows[0].At.UTC())
+	assert.Equal(t, time.Date(2021, 5, 1, 8, 0, 0, 0, time.UTC), rows[1].At.UTC())
+	assert.Equal(t, zones["EST"], rows[0].At.Location())
+
+	name, offset := rows[2].At.Zone()
+	assert.Equal(t, "XYZ", name)
+	assert.Equal(t, 0, offset)
+
+	// Numeric offsets take precedence over the table.
+	reader, err = NewReader(strings.NewReader("At\n2021-05-01 10:00 -0400 EST\n"), &ReaderOptions{
+		ReadHeaders:       true,
+		ColumnFormats:     map[string]string{"At": "2006-01-02 15:04 -0700 MST"},
+		ZoneAbbreviations: zones,
+	})
+	require.NoError(t, err)
+
+	rows = nil
+	require.NoError(t, reader.ReadAll(&rows))
+	assert.Equal(t, time.Date(2021, 5, 1, 14, 0, 0, 0, time.UTC), rows[0].At.UTC())
+}
+
+type datedRow struct {
+	At  time.Time
+	Ptr *time.Time
+}
+
+// TestReader_DatePolicies verifies leap seconds, zero dates, and maximum dates are decoded as their
+// policies say
+func TestReader_DatePolicies(t *testing.T) {
+
+	const layout = "2006-01-02 15:04:05"
+	leap := time.Date(2016, 12, 31, 23, 59, 59, 0, time.UTC)
+	maxDate := time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		value   string
+		options ReaderOptions
+		want    time.Time
+		wantNil bool
+		wantErr error
+	}{
+		{name: "leap second parsed", value: "2016-12-31 23:59:60", wantErr: &time.ParseError{}},
+		{name: "leap second rejected", value: "2016-12-31 23:59:60", options: ReaderOptions{LeapSeconds: DateReject}, wantErr: ErrLeapSecond},
+		{name: "leap second null", value: "2016-12-31 23:59:60", options: ReaderOptions{LeapSeconds: DateNull}, wantNil: true},
+		{name: "leap second clamped", value: "2016-12-31 23:59:60", options: ReaderOptions{LeapSeconds: DateClamp}, want: leap},
+		{name: "zero date parsed", value: "0000-00-00 00:00:00", wantErr: &time.ParseError{}},
+		{name: "zero date rejected", value: "0000-00-00 00:00:00", options: ReaderOptions{ZeroDates: DateReject}, wantErr: ErrZeroDate},
+		{name: "zero date null", value: "0000-00-00 00:00:00", options: ReaderOptions{ZeroDates: DateNull}, wantNil: true},
+		{name: "zero date clamped", value: "0000-00-00 00:00:00", options: ReaderOptions{ZeroDates: DateClamp}, want: time.Time{}},
+		{name: "max date parsed", value: "9999-12-31 00:00:00", want: maxDate},
+		{name: "max date rejected", value: "9999-12-31 00:00:00", options: ReaderOptions{MaxDates: DateReject}, wantErr: ErrMaxDate},
+		{name: "max date null", value: "9999-12-31 00:00:00", options: ReaderOptions{MaxDates: DateNull}, wantNil: true},
+		{name: "max date clamped", value: "9999-12-31 00:00:00", options: ReaderOptions{MaxDates: DateClamp}, want: maxDate},
+		{name: "other errors unchanged", value: "2016-12-31 24:00:60", options: ReaderOptions{LeapSeconds: DateClamp}, wantErr: &time.ParseError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			options := tt.options
+			options.ReadHeaders = true
+			options.ColumnFormats = map[string]string{"At": layout, "Ptr": layout}
+
+			reader, err := NewReader(strings.NewReader("At,Ptr\n"+tt.value+","+tt.value+"\n"), &options)
+			require.NoError(t, err)
+
+			var rows []datedRow
+			err = reader.ReadAll(&rows)
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				if target, ok := tt.wantErr.(*time.ParseError); ok {
+					assert.ErrorAs(t, err, &target)
+				} else {
+					assert.ErrorIs(t, err, tt.wantErr)
+					assert.NotContains(t, err.Error(), "ColumnFormats")
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, rows, 1)
+
+			assert.Equal(t, tt.want, rows[0].At)
+			if tt.wantNil {
+				assert.Nil(t, rows[0].Ptr)
+			} else if assert.NotNil(t, rows[0].Ptr) {
+				assert.Equal(t, tt.want, *rows[0].Ptr)
+			}
+		})
+	}
+}
+
+// TestParseValue_DatePolicies verifies times decoded as null are zero, and nil within pointers and
+// slices, wherever cells are parsed
+func TestParseValue_DatePolicies(t *testing.T) {
+
+	parsing := cellParsing{format: "2006-01-02", dates: datePolicies{zeroDates: DateNull}}
+
+	v, err := parseValue("0000-00-00", reflect.TypeOf(time.Time{}), parsing)
+	require.NoError(t, err)
+	assert.Equal(t, time.Time{}, v.Interface())
+
+	v, err = parseValue("0000-00-00", reflect.TypeOf(&time.Time{}), parsing)
+	require.NoError(t, err)
+	assert.Nil(t, v.Interface())
+
+	v, err = parseValue("2021-01-02,0000-00-00", reflect.TypeOf([]*time.Time{}), parsing)
+	require.NoError(t, err)
+	times := v.Interface().([]*time.Time)
+	require.Len(t, times, 2)
+	assert.Equal(t, time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC), *times[0])
+	assert.Nil(t, times[1])
+}
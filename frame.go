@@ -0,0 +1,227 @@
+package csvee
+
+import (
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Frame is a lightweight in-memory table that stores records column by column. Cells are kept as
+// they were read and parsed by the typed accessors, using the column formats and number parsers of
+// the Reader the frame was read from. Populate a Frame by passing a pointer to one to
+// Reader.ReadAll. Frames are not modified once read; Select, Filter, and Head return new frames that
+// share cells with the original.
+type Frame struct {
+	names   []string
+	columns [][]string
+	parsing []cellParsing
+	index   map[string]int
+}
+
+// Columns returns the names of the frame's columns.
+func (f *Frame) Columns() []string {
+
+	return append([]string(nil), f.names...)
+}
+
+// Len returns the number of rows in the frame.
+func (f *Frame) Len() int {
+
+	if len(f.columns) == 0 {
+		return 0
+	}
+
+	return len(f.columns[0])
+}
+
+// Row returns row i of the frame, numbered from 0.
+func (f *Frame) Row(i int) RawRecord {
+
+	fields := make([]string, len(f.columns))
+	for j, column := range f.columns {
+		fields[j] = column[i]
+	}
+
+	return RawRecord{row: i + 1, fields: fields, columns: f.index}
+}
+
+// Strings returns the cells of the named column.
+func (f *Frame) Strings(name string) ([]string, error) {
+
+	j, err := f.columnIndex(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]string(nil), f.columns[j]...), nil
+}
+
+// Ints returns the named column parsed as integers. Blank cells are zero.
+func (f *Frame) Ints(name string) ([]int64, error) {
+
+	var values []int64
+	return values, f.parseColumn(name, &values)
+}
+
+// Floats returns the named column parsed as floats. Blank cells are zero.
+func (f *Frame) Floats(name string) ([]float64, error) {
+
+	var values []float64
+	return values, f.parseColumn(name, &values)
+}
+
+// Bools returns the named column parsed as bools. Blank cells are false.
+func (f *Frame) Bools(name string) ([]bool, error) {
+
+	var values []bool
+	return values, f.parseColumn(name, &values)
+}
+
+// Times returns the named column parsed as times in the column's format. Blank cells are the zero
+// time.
+func (f *Frame) Times(name string) ([]time.Time, error) {
+
+	var values []time.Time
+	return values, f.parseColumn(name, &values)
+}
+
+// parseColumn parses each cell of the named column into the slice dest points to.
+func (f *Frame) parseColumn(name string, dest interface{}) error {
+
+	j, err := f.columnIndex(name)
+	if err != nil {
+		return err
+	}
+
+	slice := reflect.ValueOf(dest).Elem()
+	slice.Set(reflect.MakeSlice(slice.Type(), len(f.columns[j]), len(f.columns[j])))
+
+	for i, cell := range f.columns[j] {
+		value, err := parseValue(cell, slice.Type().Elem(), f.parsing[j])
+		if err != nil {
+			slice.Set(reflect.Zero(slice.Type()))
+			return &FieldError{Row: i + 1, Column: name, Value: cell, Err: err}
+		}
+		slice.Index(i).Set(value)
+	}
+
+	return nil
+}
+
+// Select returns a frame holding only the named columns, in the order given.
+func (f *Frame) Select(names ...string) (*Frame, error) {
+
+	selected := newFrame(names)
+	for k, name := range names {
+
+		j, err := f.columnIndex(name)
+		if err != nil {
+			return nil, err
+		}
+
+		selected.columns[k] = f.columns[j]
+		selected.parsing[k] = f.parsing[j]
+	}
+
+	if len(selected.index) != len(names) {
+		return nil, errors.New("selected column names must be unique")
+	}
+
+	return selected, nil
+}
+
+// Filter returns a frame holding the rows for which keep returns true. keep is passed each row in
+// turn, numbered from 1.
+func (f *Frame) Filter(keep func(row RawRecord) bool) *Frame {
+
+	filtered := f.empty()
+	for i := 0; i < f.Len(); i++ {
+		if keep(f.Row(i)) {
+			for j, column := range f.columns {
+				filtered.columns[j] = append(filtered.columns[j], column[i])
+			}
+		}
+	}
+
+	return filtered
+}
+
+// Head returns a frame holding the first n rows, or every row if there are fewer than n.
+func (f *Frame) Head(n int) *Frame {
+
+	if n > f.Len() {
+		n = f.Len()
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	head := f.empty()
+	for j, column := range f.columns {
+		head.columns[j] = column[:n:n]
+	}
+
+	return head
+}
+
+// empty returns a frame with the same columns as f and no rows.
+func (f *Frame) empty() *Frame {
+
+	frame := newFrame(f.names)
+	copy(frame.parsing, f.parsing)
+	return frame
+}
+
+func (f *Frame) columnIndex(name string) (int, error) {
+
+	j, exists := f.index[name]
+	if !exists {
+		return 0, errors.Errorf("column %q not found", name)
+	}
+
+	return j, nil
+}
+
+func newFrame(names []string) *Frame {
+
+	frame := &Frame{
+		names:   append([]string(nil), names...),
+		columns: make([][]string, len(names)),
+		parsing: make([]cellParsing, len(names)),
+		index:   make(map[string]int, len(names)),
+	}
+	for j, name := range names {
+		frame.index[name] = j
+	}
+
+	return frame
+}
+
+// readFrame reads the remaining records into frame, replacing its contents.
+func (r *Reader) readFrame(frame *Frame) (int, error) {
+
+	*frame = *newFrame(r.ColumnNames)
+	for j, name := range r.ColumnNames {
+		frame.parsing[j] = r.cellParsing(j, name)
+	}
+
+	for {
+		record, err := r.readRecord()
+		if err == io.EOF {
+			return frame.Len(), nil
+		}
+		if err != nil {
+			return frame.Len(), err
+		}
+
+		if len(record) != len(r.ColumnNames) {
+			return frame.Len(), ErrColumnNamesMismatch
+		}
+
+		for j, cell := range record {
+			frame.columns[j] = append(frame.columns[j], cell)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFrame verifies frames are populated by ReadAll and can be selected, filtered, and sliced
+func TestFrame(t *testing.T) {
+
+	const data = "Name,Qty,Price,Paid,Shipped\na,1,1.5,true,2020-01-02\nb,,2,false,\nc,3,0.25,true,2020-01-04\n"
+
+	reader, err := NewReader(strings.NewReader(data), &ReaderOptions{
+		ReadHeaders:   true,
+		ColumnFormats: map[string]string{"Shipped": "2006-01-02"},
+	})
+	require.NoError(t, err)
+
+	var frame Frame
+	require.NoError(t, reader.ReadAll(&frame))
+
+	assert.Equal(t, []string{"Name", "Qty", "Price", "Paid", "Shipped"}, frame.Columns())
+	assert.Equal(t, 3, frame.Len())
+	assert.Equal(t, "b", frame.Row(1).Raw("Name"))
+
+	names, err := frame.Strings("Name")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, names)
+
+	qty, err := frame.Ints("Qty")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 0, 3}, qty)
+
+	prices, err := frame.Floats("Price")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1.5, 2, 0.25}, prices)
+
+	paid, err := frame.Bools("Paid")
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false, true}, paid)
+
+	shipped, err := frame.Times("Shipped")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), {}, time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC)}, shipped)
+
+	days, err := frame.Ints("Shipped")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1577923200, 0, 1578096000}, days)
+
+	_, err = frame.Ints("Name")
+	assert.EqualError(t, err, `row 1, column "Name": invalid value "a" for int64`)
+	_, err = frame.Floats("Cost")
+	assert.EqualError(t, err, `column "Cost" not found`)
+
+	selected, err := frame.Select("Shipped", "Name")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Shipped", "Name"}, selected.Columns())
+	shipped, err = selected.Times("Shipped")
+	require.NoError(t, err)
+	assert.Len(t, shipped, 3)
+
+	_, err = frame.Select("Name", "Name")
+	assert.Error(t, err)
+	_, err = frame.Select("Cost")
+	assert.Error(t, err)
+
+	paidOnly := frame.Filter(func(row RawRecord) bool { return row.Raw("Paid") == "true" })
+	names, err = paidOnly.Strings("Name")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, names)
+
+	head := frame.Head(2)
+	assert.Equal(t, 2, head.Len())
+	assert.Equal(t, 3, frame.Head(10).Len())
+	assert.Equal(t, 0, frame.Head(-1).Len())
+
+	filtered := head.Filter(func(row RawRecord) bool { return row.Row() == 2 })
+	names, err = filtered.Strings("Name")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, names)
+
+	var empty Frame
+	assert.Equal(t, 0, empty.Len())
+	assert.Equal(t, ErrReadTargetNil, reader.ReadAll((*Frame)(nil)))
+}
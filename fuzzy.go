@@ -0,0 +1,197 @@
+package csvee
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// MatchMethod describes how a column was matched to a field.
+type MatchMethod string
+
+const (
+	// MatchExact means the column matched a field name, csvee tag name, or alias exactly.
+	MatchExact MatchMethod = "exact"
+
+	// MatchFuzzy means the column matched a field name, tag name, or alias within the reader's fuzzy
+	// match threshold.
+	MatchFuzzy MatchMethod = "fuzzy"
+
+	// MatchNormalized means the column matched a field name, tag name, or alias once both were
+	// rewritten by the reader's HeaderNormalizer.
+	MatchNormalized MatchMethod = "normalized"
+
+	// MatchNone means the column did not match any field and is ignored.
+	MatchNone MatchMethod = "none"
+)
+
+// ColumnMatch reports how a column was matched to a field.
+type ColumnMatch struct {
+	Column string
+	Field  string
+	Method MatchMethod
+
+	// Similarity is the normalized similarity, from 0 to 1, between the column and the name it matched.
+	Similarity float64
+}
+
+// ColumnMatches reports how each of the reader's columns is matched to a field of v's struct type.
+func (r *Reader) ColumnMatches(v interface{}) ([]ColumnMatch, error) {
+
+	if v == nil {
+		return nil, ErrReadTargetNil
+	}
+
+	vType := getBaseType(reflect.TypeOf(v))
+	if vType.Kind() != reflect.Struct {
+		return nil, ErrUnsupportedTargetType
+	}
+
+	plan, err := r.planFor(vType)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]ColumnMatch(nil), plan.matches...), nil
+}
+
+// fieldCandidate is a name that a column can match along with the field it maps to.
+type fieldCandidate struct {
+	name  string
+	field reflect.StructField
+}
+
+// fieldCandidates returns every name that columns can be matched against in vType: tag names and
+// aliases of tagged fields and the names of untagged, exported fields, including promoted fields.
+func fieldCandidates(vType reflect.Type, index []int) []fieldCandidate {
+
+	var candidates []fieldCandidate
+	for i := 0; i < vType.NumField(); i++ {
+
+		field := vType.Field(i)
+		field.Index = append(append([]int{}, index...), i)
+
+		tag := parseFieldTag(field)
+		if tag.skip {
+			continue
+		}
+
+		if tag.name != "" {
+			for _, name := range append([]string{tag.name}, tag.aliases...) {
+				candidates = append(candidates, fieldCandidate{name: name, field: field})
+			}
+			continue
+		}
+
+		if field.Anonymous && getBaseType(field.Type).Kind() == reflect.Struct {
+			candidates = append(candidates, fieldCandidates(getBaseType(field.Type), field.Index)...)
+			continue
+		}
+
+		if field.PkgPath == "" {
+			candidates = append(candidates, fieldCandidate{name: field.Name, field: field})
+		}
+	}
+
+	return candidates
+}
+
+// fuzzyMatch returns the candidate most similar to column, provided its similarity is at least
+// threshold, it has not been claimed by another column, and no other field is equally similar.
+func fuzzyMatch(column string, candidates []fieldCandidate, claimed map[string]bool, threshold float64) (fieldCandidate, float64, bool) {
+
+	var best fieldCandidate
+	var bestSimilarity float64
+	ambiguous := false
+
+	for _, candidate := range candidates {
+
+		if claimed[fieldIndexKey(candidate.field)] {
+			continue
+		}
+
+		similarity := nameSimilarity(column, candidate.name)
+		switch {
+		case similarity > bestSimilarity:
+			best, bestSimilarity, ambiguous = candidate, similarity, false
+		case similarity == bestSimilarity && fieldIndexKey(candidate.field) != fieldIndexKey(best.field):
+			ambiguous = true
+		}
+	}
+
+	if ambiguous || bestSimilarity < threshold || bestSimilarity == 0 {
+		return fieldCandidate{}, 0, false
+	}
+
+	return best, bestSimilarity, true
+}
+
+func fieldIndexKey(field reflect.StructField) string {
+
+	return fmt.Sprint(field.Index)
+}
+
+// nameSimilarity returns 1 minus the edit distance between the normalized names divided by the length
+// of the longer name. Names are normalized by lower casing them and removing anything that is not a
+// letter or digit.
+func nameSimilarity(a, b string) float64 {
+
+	na, nb := normalizeName(a), normalizeName(b)
+	longest := len(na)
+	if len(nb) > longest {
+		longest = len(nb)
+	}
+	if longest == 0 {
+		return 0
+	}
+
+	return 1 - float64(levenshtein(na, nb))/float64(longest)
+}
+
+func normalizeName(name string) []rune {
+
+	var normalized []rune
+	for _, c := range strings.ToLower(name) {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			normalized = append(normalized, c)
+		}
+	}
+
+	return normalized
+}
+
+func levenshtein(a, b []rune) int {
+
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,51 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type addressReadTo struct {
+	Address string
+	City    string
+	Zip     string `csvee:"postal_code"`
+}
+
+// TestReader_FuzzyMatchThreshold verifies near-miss headers bind while distant ones are rejected
+func TestReader_FuzzyMatchThreshold(t *testing.T) {
+
+	reader, err := NewReader(
+		strings.NewReader("Adress,CITY,Postal Code,Country\n1 Main St,Springfield,12345,US\n"),
+		&ReaderOptions{ReadHeaders: true, FuzzyMatchThreshold: 0.8},
+	)
+	require.NoError(t, err)
+
+	var actualData addressReadTo
+	require.NoError(t, reader.Read(&actualData))
+	assert.Equal(t, addressReadTo{Address: "1 Main St", City: "Springfield", Zip: "12345"}, actualData)
+
+	matches, err := reader.ColumnMatches(&actualData)
+	require.NoError(t, err)
+	require.Len(t, matches, 4)
+	assert.Equal(t, ColumnMatch{Column: "Adress", Field: "Address", Method: MatchFuzzy, Similarity: matches[0].Similarity}, matches[0])
+	assert.InDelta(t, 6.0/7, matches[0].Similarity, 1e-9)
+	assert.Equal(t, ColumnMatch{Column: "CITY", Field: "City", Method: MatchFuzzy, Similarity: 1}, matches[1])
+	assert.Equal(t, ColumnMatch{Column: "Postal Code", Field: "Zip", Method: MatchFuzzy, Similarity: 1}, matches[2])
+	assert.Equal(t, ColumnMatch{Column: "Country", Method: MatchNone}, matches[3])
+
+	reader, err = NewReader(strings.NewReader("Adress\nx\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	matches, err = reader.ColumnMatches(&actualData)
+	require.NoError(t, err)
+	assert.Equal(t, MatchNone, matches[0].Method)
+}
+
+func TestNameSimilarity(t *testing.T) {
+
+	assert.Equal(t, 1.0, nameSimilarity("first_name", "FirstName"))
+	assert.Equal(t, 0.0, nameSimilarity("", ""))
+	assert.InDelta(t, 0.5, nameSimilarity("abcd", "abxy"), 1e-9)
+}
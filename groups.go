@@ -0,0 +1,258 @@
+package csvee
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// indexPlaceholder stands for the 1-based index of an element in the prefix tag of a slice or array
+// of structs, e.g. `csvee:"prefix=item{n}_"`, which binds the columns "item1_name" and "item2_name"
+// to the Name fields of its first and second elements.
+const indexPlaceholder = "{n}"
+
+// elementBinding binds a column to a field of an element of a slice or array of structs.
+type elementBinding struct {
+	// index leads from the decoded struct to the slice or array, element is the index of the
+	// element within it, and structType is the struct type of its elements.
+	index      []int
+	element    int
+	structType reflect.Type
+}
+
+// indexedFieldForColumn returns the field a column such as "item2_qty" maps to through a slice or
+// array of structs tagged with the prefix "item{n}_", along with the element it binds the column to,
+// by resolving the rest of the name, "qty", to a field of the element's struct type as
+// prefixedFieldForColumn does. The returned field's Index leads from the element's struct type, and
+// its Name is the path of Go field names from vType, such as "Items[1].Qty". Indexes above
+// maxElements, if it is greater than zero, bind nothing.
+func indexedFieldForColumn(vType reflect.Type, column, separator string, maxElements int) (reflect.StructField, *elementBinding, bool) {
+
+	for _, indexed := range fieldLookupFor(vType).indexed {
+
+		before, after, _ := strings.Cut(indexed.prefix, indexPlaceholder)
+		if !strings.HasPrefix(column, before) {
+			continue
+		}
+		rest := column[len(before):]
+
+		digits := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+		n, err := strconv.Atoi(rest[:digits])
+		if err != nil || n < 1 || (maxElements > 0 && n > maxElements) || !strings.HasPrefix(rest[digits:], after) {
+			continue
+		}
+		rest = rest[digits+len(after):]
+
+		sliceType := indexed.field.Type
+		if sliceType.Kind() == reflect.Array && n > sliceType.Len() {
+			continue
+		}
+
+		t := getBaseType(sliceType.Elem())
+		field, exists := fieldForColumn(t, rest)
+		if !exists {
+			field, exists = nestedFieldForColumn(t, rest, separator)
+		}
+		if !exists {
+			field, exists = prefixedFieldForColumn(t, rest, separator)
+		}
+		if !exists {
+			continue
+		}
+
+		binding := &elementBinding{index: indexed.field.Index, element: n - 1, structType: t}
+		field.Name = fmt.Sprintf("%s[%d].%s", indexed.field.Name, n-1, field.Name)
+		return field, binding, true
+	}
+
+	return reflect.StructField{}, nil, false
+}
+
+// isElementGroup reports whether t is a slice or array of structs, or pointers to them, that an
+// indexed prefix can bind columns to.
+func isElementGroup(t reflect.Type) bool {
+
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return false
+	}
+
+	elem := getBaseType(t.Elem())
+	return elem.Kind() == reflect.Struct && !isTimeType(elem)
+}
+
+// resetGroups clears the slices and arrays that the plan binds columns to on the struct pointed to by
+// structPtr, so that a row only holds the elements its own cells fill.
+func resetGroups(structPtr reflect.Value, plan *decodePlan) {
+
+	for _, index := range plan.groups {
+		v := settableField(structPtr.Elem(), index)
+		v.Set(reflect.Zero(v.Type()))
+	}
+}
+
+// elementTarget returns a pointer to the struct that col is decoded into: the struct pointed to by
+// structPtr, or the element of one of its slices or arrays the column is bound to. Slices are grown
+// and pointer elements allocated to hold the element, unless the cell is blank or null and the
+// element does not exist yet, in which case the column is skipped and false is returned. A group of
+// columns only appends an element to a slice if one of them has a value.
+func (r *Reader) elementTarget(structPtr reflect.Value, col columnPlan, record []string) (reflect.Value, bool) {
+
+	if col.element == nil {
+		return structPtr, true
+	}
+
+	v := settableField(structPtr.Elem(), col.element.index)
+	exists := col.element.element < v.Len() && !(v.Index(col.element.element).Kind() == reflect.Ptr && v.Index(col.element.element).IsNil())
+	if cell := record[col.column]; !exists && (strings.TrimSpace(cell) == "" || r.isNull(cell)) {
+		return reflect.Value{}, false
+	}
+
+	if v.Len() <= col.element.element {
+		grown := reflect.MakeSlice(v.Type(), col.element.element+1, col.element.element+1)
+		reflect.Copy(grown, v)
+		v.Set(grown)
+	}
+
+	return allocate(v.Index(col.element.element)).Addr(), true
+}
+
+// expanded returns the options with the column names, and the keys of ColumnFormats,
+// SliceDelimiters, and ColumnLocales, that hold the index placeholder expanded for MaxGroupElements elements.
+func (o *WriterOptions) expanded() *WriterOptions {
+
+	if o.MaxGroupElements == 0 {
+		return o
+	}
+
+	expanded := *o
+	expanded.ColumnNames = expandGroupColumns(o.ColumnNames, o.MaxGroupElements)
+	expanded.ColumnFormats = expandGroupKeys(o.ColumnFormats, o.MaxGroupElements)
+	expanded.SliceDelimiters = expandGroupKeys(o.SliceDelimiters, o.MaxGroupElements)
+	expanded.ColumnLocales = expandGroupKeys(o.ColumnLocales, o.MaxGroupElements)
+	return &expanded
+}
+
+// expandGroupColumns returns columnNames with each run of adjacent names that hold the index
+// placeholder after the same text repeated for each index from 1 to elements, e.g. "item{n}_name"
+// and "item{n}_qty" become "item1_name", "item1_qty", "item2_name", and "item2_qty".
+func expandGroupColumns(columnNames []string, elements int) []string {
+
+	var expanded []string
+	for i := 0; i < len(columnNames); {
+
+		before, _, grouped := strings.Cut(columnNames[i], indexPlaceholder)
+		if !grouped {
+			expanded = append(expanded, columnNames[i])
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(columnNames) && strings.HasPrefix(columnNames[end], before+indexPlaceholder) {
+			end++
+		}
+
+		for n := 1; n <= elements; n++ {
+			for _, name := range columnNames[i:end] {
+				expanded = append(expanded, strings.Replace(name, indexPlaceholder, strconv.Itoa(n), 1))
+			}
+		}
+		i = end
+	}
+
+	return expanded
+}
+
+// expandGroupKeys returns a copy of m with each key that holds the index placeholder replaced by a
+// key for each index from 1 to elements.
+func expandGroupKeys[V any](m map[string]V, elements int) map[string]V {
+
+	if m == nil {
+		return nil
+	}
+
+	expanded := make(map[string]V, len(m))
+	for key, value := range m {
+		if !strings.Contains(key, indexPlaceholder) {
+			expanded[key] = value
+			continue
+		}
+		for n := 1; n <= elements; n++ {
+			expanded[strings.Replace(key, indexPlaceholder, strconv.Itoa(n), 1)] = value
+		}
+	}
+
+	return expanded
+}
+
+// writtenGroup tracks a slice or array of structs that a record is written from, and the number of
+// elements its columns hold.
+type writtenGroup struct {
+	index    []int
+	column   string
+	value    reflect.Value
+	elements int
+}
+
+// groupElementField returns the field of an element of a slice or array of structs in value that
+// column is written from, as indexedFieldForColumn binds it, its value, and whether the element has
+// one. The group is added to groups, which tracks how many elements each group's columns hold.
+func groupElementField(value reflect.Value, column string, groups *[]*writtenGroup) (reflect.StructField, reflect.Value, bool) {
+
+	field, binding, exists := indexedFieldForColumn(value.Type(), column, "", 0)
+	if !exists {
+		return reflect.StructField{}, reflect.Value{}, false
+	}
+
+	group, exists := fieldByIndex(value, binding.index)
+	if !exists {
+		return reflect.StructField{}, reflect.Value{}, false
+	}
+
+	var written *writtenGroup
+	for _, g := range *groups {
+		if reflect.DeepEqual(g.index, binding.index) {
+			written = g
+			break
+		}
+	}
+	if written == nil {
+		written = &writtenGroup{index: binding.index, column: column, value: group}
+		*groups = append(*groups, written)
+	}
+	if binding.element >= written.elements {
+		written.elements = binding.element + 1
+	}
+
+	if binding.element >= group.Len() {
+		return reflect.StructField{}, reflect.Value{}, false
+	}
+
+	element := group.Index(binding.element)
+	for element.Kind() == reflect.Ptr {
+		if element.IsNil() {
+			return reflect.StructField{}, reflect.Value{}, false
+		}
+		element = element.Elem()
+	}
+
+	fieldValue, exists := fieldByIndex(element, field.Index)
+	return field, fieldValue, exists
+}
+
+// checkWrittenGroups fails if a slice in groups has more elements than its columns hold, which
+// would otherwise be lost. row numbers the record in errors.
+func checkWrittenGroups(groups []*writtenGroup, row int) error {
+
+	for _, group := range groups {
+		if group.value.Kind() == reflect.Slice && group.value.Len() > group.elements {
+			return &FieldError{Row: row, Column: group.column, Err: errors.Errorf(
+				"%d elements do not fit in the columns of %d", group.value.Len(), group.elements)}
+		}
+	}
+
+	return nil
+}
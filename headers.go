@@ -0,0 +1,198 @@
+package csvee
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// HeaderNormalizer rewrites a column name so that names which differ only in form, such as
+// "first_name" and "FirstName", compare equal. Normalizers should return names they are given
+// already normalized unchanged.
+type HeaderNormalizer func(header string) string
+
+// TrimHeader removes surrounding whitespace and a leading byte order mark from header.
+func TrimHeader(header string) string {
+
+	return strings.TrimSpace(strings.TrimPrefix(header, "\ufeff"))
+}
+
+// LowerCaseHeader lower cases header, so that columns match fields regardless of case.
+func LowerCaseHeader(header string) string {
+
+	return strings.ToLower(header)
+}
+
+// CamelCaseHeader joins the words of header, separated by underscores, hyphens, or spaces, upper
+// casing the first letter of each, so that "first_name" and "first name" become "FirstName".
+func CamelCaseHeader(header string) string {
+
+	words := strings.FieldsFunc(header, func(r rune) bool {
+		return r == '_' || r == '-' || unicode.IsSpace(r)
+	})
+
+	var b strings.Builder
+	b.Grow(len(header))
+	for _, word := range words {
+		first, size := utf8.DecodeRuneInString(word)
+		b.WriteRune(unicode.ToUpper(first))
+		b.WriteString(word[size:])
+	}
+
+	return b.String()
+}
+
+// ChainHeaderNormalizers returns a HeaderNormalizer that applies each of normalizers in turn, such as
+// TrimHeader, CamelCaseHeader, and then LowerCaseHeader to match "First Name", "first_name", and
+// "FIRSTNAME" to a FirstName field.
+func ChainHeaderNormalizers(normalizers ...HeaderNormalizer) HeaderNormalizer {
+
+	return func(header string) string {
+		for _, normalize := range normalizers {
+			header = normalize(header)
+		}
+		return header
+	}
+}
+
+// normalizedMatch returns the candidate whose name, normalized, equals column normalized, provided
+// it has not been claimed by another column and no other field's name normalizes to the same.
+func normalizedMatch(column string, candidates []fieldCandidate, claimed map[string]bool, normalize HeaderNormalizer) (reflect.StructField, bool) {
+
+	column = normalize(column)
+
+	var match *fieldCandidate
+	for i, candidate := range candidates {
+
+		if claimed[fieldIndexKey(candidate.field)] || normalize(candidate.name) != column {
+			continue
+		}
+
+		if match != nil && fieldIndexKey(match.field) != fieldIndexKey(candidate.field) {
+			return reflect.StructField{}, false
+		}
+		match = &candidates[i]
+	}
+
+	if match == nil {
+		return reflect.StructField{}, false
+	}
+
+	return match.field, true
+}
+
+// HeaderNamer names the column a Go struct field is written to, such as "first_name" for a
+// FirstName field, so that headers follow a naming convention without a tag on every field.
+type HeaderNamer func(fieldName string) string
+
+// SnakeCaseName lower cases the words of fieldName and joins them with underscores, so that
+// "FirstName" and "UserID" become "first_name" and "user_id".
+func SnakeCaseName(fieldName string) string {
+
+	return strings.ToLower(strings.Join(fieldWords(fieldName), "_"))
+}
+
+// KebabCaseName lower cases the words of fieldName and joins them with hyphens, so that "FirstName"
+// and "UserID" become "first-name" and "user-id".
+func KebabCaseName(fieldName string) string {
+
+	return strings.ToLower(strings.Join(fieldWords(fieldName), "-"))
+}
+
+// TitleCaseName joins the words of fieldName with spaces, so that "FirstName" and "UserID" become
+// "First Name" and "User ID".
+func TitleCaseName(fieldName string) string {
+
+	return strings.Join(fieldWords(fieldName), " ")
+}
+
+// fieldWords splits a Go identifier into its words, which begin at each upper case letter that
+// follows a lower case letter or digit, or that starts a lower case word after an acronym, so that
+// "HTTPServerID2" splits into "HTTP", "Server", and "ID2". Underscores also separate words.
+func fieldWords(name string) []string {
+
+	var words []string
+	runes := []rune(name)
+	start := 0
+	for i, r := range runes {
+		switch {
+		case r == '_':
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+		case i > start && unicode.IsUpper(r):
+			prev := runes[i-1]
+			acronymEnd := unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || acronymEnd {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+
+	return words
+}
+
+// ColumnNamesFor returns the columns the fields of v, a struct or a pointer to one, are written to,
+// in field order, for WriterOptions.ColumnNames. Fields promoted from embedded structs are
+// included, and fields that are unexported, tagged to be skipped, or bound to columns by a prefix
+// tag are not. A field is named by its tag if it has one, and otherwise by namer, or by its Go name
+// if namer is nil; the same namer in WriterOptions.HeaderNamer fills the columns from the fields.
+func ColumnNamesFor(v interface{}, namer HeaderNamer) ([]string, error) {
+
+	vType := reflect.TypeOf(v)
+	for vType != nil && vType.Kind() == reflect.Ptr {
+		vType = vType.Elem()
+	}
+	if vType == nil || vType.Kind() != reflect.Struct {
+		return nil, ErrUnsupportedSourceType
+	}
+
+	var names []string
+	for _, field := range reflect.VisibleFields(vType) {
+
+		if field.PkgPath != "" || field.Anonymous && getBaseType(field.Type).Kind() == reflect.Struct ||
+			fieldSkipped(vType, field.Index) {
+			continue
+		}
+
+		tag := parseFieldTag(field)
+		switch {
+		case tag.prefix != "":
+			continue
+		case tag.name != "":
+			names = append(names, tag.name)
+		case namer != nil:
+			names = append(names, namer(field.Name))
+		default:
+			names = append(names, field.Name)
+		}
+	}
+
+	return names, nil
+}
+
+// namedFields maps the names namer gives the untagged fields of the struct type vType that
+// ColumnNamesFor names with it to the fields.
+func namedFields(vType reflect.Type, namer HeaderNamer) map[string]reflect.StructField {
+
+	fields := make(map[string]reflect.StructField)
+	for _, field := range reflect.VisibleFields(vType) {
+
+		if field.PkgPath != "" || field.Anonymous && getBaseType(field.Type).Kind() == reflect.Struct ||
+			fieldSkipped(vType, field.Index) {
+			continue
+		}
+
+		if tag := parseFieldTag(field); tag.name == "" && tag.prefix == "" {
+			fields[namer(field.Name)] = field
+		}
+	}
+
+	return fields
+}
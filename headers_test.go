@@ -0,0 +1,212 @@
+package csvee
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type headerRow struct {
+	FirstName string
+	LastName  string
+	UserID    int    `csvee:"user_id"`
+	Note      string `csvee:"note,alias=memo"`
+}
+
+// TestHeaderNormalizers verifies the built-in normalizers and their chaining
+func TestHeaderNormalizers(t *testing.T) {
+
+	var testCases = []struct {
+		name      string
+		normalize HeaderNormalizer
+		header    string
+		exp       string
+	}{
+		{name: "trim", normalize: TrimHeader, header: "\ufeff First Name \t", exp: "First Name"},
+		{name: "lower", normalize: LowerCaseHeader, header: "FIRSTNAME", exp: "firstname"},
+		{name: "camel snake", normalize: CamelCaseHeader, header: "first_name", exp: "FirstName"},
+		{name: "camel spaces", normalize: CamelCaseHeader, header: " first  name-x ", exp: "FirstNameX"},
+		{name: "camel unchanged", normalize: CamelCaseHeader, header: "FirstName", exp: "FirstName"},
+		{name: "camel unicode", normalize: CamelCaseHeader, header: "été_prix", exp: "ÉtéPrix"},
+		{name: "chain", normalize: ChainHeaderNormalizers(TrimHeader, CamelCaseHeader, LowerCaseHeader), header: "\ufeffFirst Name", exp: "firstname"},
+		{name: "empty chain", normalize: ChainHeaderNormalizers(), header: "A b", exp: "A b"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			assert.Equal(t, tt.exp, tt.normalize(tt.header))
+		})
+	}
+}
+
+// TestReader_HeaderNormalizer verifies normalized headers are matched to fields and used as column
+// names
+func TestReader_HeaderNormalizer(t *testing.T) {
+
+	var testCases = []struct {
+		name       string
+		input      string
+		normalizer HeaderNormalizer
+		exp        headerRow
+		expColumns []string
+		expMethods []MatchMethod
+	}{
+		{
+			name:       "snake case",
+			input:      "\ufefffirst_name,last_name,user_id,memo\nAnn,Lee,7,hi\n",
+			normalizer: ChainHeaderNormalizers(TrimHeader, CamelCaseHeader),
+			exp:        headerRow{FirstName: "Ann", LastName: "Lee", UserID: 7, Note: "hi"},
+			expColumns: []string{"FirstName", "LastName", "UserId", "Memo"},
+			expMethods: []MatchMethod{MatchExact, MatchExact, MatchNormalized, MatchNormalized},
+		},
+		{
+			name:       "case insensitive",
+			input:      "FIRSTNAME, Last Name ,USER_ID,Other\nAnn,Lee,7,x\n",
+			normalizer: ChainHeaderNormalizers(TrimHeader, CamelCaseHeader, LowerCaseHeader),
+			exp:        headerRow{FirstName: "Ann", LastName: "Lee", UserID: 7},
+			expColumns: []string{"firstname", "lastname", "userid", "other"},
+			expMethods: []MatchMethod{MatchNormalized, MatchNormalized, MatchNormalized, MatchNone},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader(tt.input), &ReaderOptions{ReadHeaders: true, HeaderNormalizer: tt.normalizer})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expColumns, reader.Columns())
+
+			var actual headerRow
+			require.NoError(t, reader.Read(&actual))
+			assert.Equal(t, tt.exp, actual)
+
+			matches, err := reader.ColumnMatches(&actual)
+			require.NoError(t, err)
+			for i, method := range tt.expMethods {
+				assert.Equal(t, method, matches[i].Method, matches[i].Column)
+			}
+		})
+	}
+}
+
+// TestReader_HeaderNormalizer_Ambiguous verifies a column is not matched to any of several fields
+// whose names normalize to the same
+func TestReader_HeaderNormalizer_Ambiguous(t *testing.T) {
+
+	type row struct {
+		FirstName string
+		Firstname string
+		Code      string
+	}
+
+	reader, err := NewReader(strings.NewReader("FIRSTNAME,CODE\nAnn,x\n"), &ReaderOptions{ReadHeaders: true, HeaderNormalizer: LowerCaseHeader})
+	require.NoError(t, err)
+
+	var actual row
+	require.NoError(t, reader.Read(&actual))
+	assert.Equal(t, row{Code: "x"}, actual)
+}
+
+// TestReader_RawHeaders verifies the header row is available exactly as the input has it
+func TestReader_RawHeaders(t *testing.T) {
+
+	input := "'first name',\" Last_Name \",user_id\nAnn,Lee,x\n"
+	reader, err := NewReader(strings.NewReader(input), &ReaderOptions{
+		ReadHeaders:      true,
+		HeaderNormalizer: ChainHeaderNormalizers(TrimHeader, CamelCaseHeader),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"'first name'", " Last_Name ", "user_id"}, reader.RawHeaders())
+	assert.Equal(t, []string{"first name", " Last_Name ", "user_id"}, reader.Headers())
+	assert.Equal(t, []string{"FirstName", "LastName", "UserId"}, reader.Columns())
+
+	var row headerRow
+	err = reader.Read(&row)
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+
+	header, exists := reader.RawHeader(fieldErr.Column)
+	assert.True(t, exists)
+	assert.Equal(t, "user_id", header)
+
+	header, exists = reader.RawHeader("LastName")
+	assert.True(t, exists)
+	assert.Equal(t, " Last_Name ", header)
+
+	_, exists = reader.RawHeader("Missing")
+	assert.False(t, exists)
+
+	reader, err = NewReader(strings.NewReader("Ann\n"), &ReaderOptions{ColumnNames: []string{"FirstName"}})
+	require.NoError(t, err)
+	assert.Empty(t, reader.RawHeaders())
+	_, exists = reader.RawHeader("FirstName")
+	assert.False(t, exists)
+}
+
+// TestHeaderNamers verifies the built-in namers split Go field names into words
+func TestHeaderNamers(t *testing.T) {
+
+	var testCases = []struct {
+		name  string
+		namer HeaderNamer
+		field string
+		exp   string
+	}{
+		{name: "snake", namer: SnakeCaseName, field: "FirstName", exp: "first_name"},
+		{name: "snake acronym", namer: SnakeCaseName, field: "UserID", exp: "user_id"},
+		{name: "snake leading acronym", namer: SnakeCaseName, field: "HTTPServerID2", exp: "http_server_id2"},
+		{name: "snake underscore", namer: SnakeCaseName, field: "Zip_Code", exp: "zip_code"},
+		{name: "snake single", namer: SnakeCaseName, field: "A", exp: "a"},
+		{name: "kebab", namer: KebabCaseName, field: "ShipToCity", exp: "ship-to-city"},
+		{name: "title", namer: TitleCaseName, field: "UserID", exp: "User ID"},
+		{name: "title digits", namer: TitleCaseName, field: "Address2Line", exp: "Address2 Line"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			assert.Equal(t, tt.exp, tt.namer(tt.field))
+		})
+	}
+}
+
+// TestWriter_HeaderNamer verifies columns named by a HeaderNamer are filled from their fields
+func TestWriter_HeaderNamer(t *testing.T) {
+
+	type embedded struct {
+		CreatedBy string
+	}
+	type row struct {
+		headerRow
+		embedded
+		Skipped string `csvee:"-"`
+		hidden  string
+	}
+
+	names, err := ColumnNamesFor(&row{}, TitleCaseName)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"First Name", "Last Name", "user_id", "note", "Created By"}, names)
+
+	names, err = ColumnNamesFor(row{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"FirstName", "LastName", "user_id", "note", "CreatedBy"}, names)
+
+	_, err = ColumnNamesFor(3, nil)
+	assert.Equal(t, ErrUnsupportedSourceType, err)
+
+	names, err = ColumnNamesFor(row{}, SnakeCaseName)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, &WriterOptions{ColumnNames: append(names, "LastName"), WriteHeaders: true, HeaderNamer: SnakeCaseName})
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Write(row{headerRow: headerRow{FirstName: "Ann", LastName: "Lee", UserID: 7, Note: "hi"}, embedded: embedded{CreatedBy: "bob"}}))
+	require.NoError(t, writer.Flush())
+	assert.Equal(t, "first_name,last_name,user_id,note,created_by,LastName\nAnn,Lee,7,hi,bob,Lee\n", buf.String())
+}
@@ -0,0 +1,171 @@
+package csvee
+
+import (
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// IdentifierMap maps the columns of a header row to unique, exported Go identifiers, such as
+// "FirstName" for "first name" and "X2ndAddress" for "2nd address", and back, so that headers can
+// name the fields of a generated struct or the keys of a decoded map and still be traced to the
+// columns they came from, even when several columns share a header.
+type IdentifierMap struct {
+	headers     []string
+	identifiers []string
+
+	// byHeader holds the position of the first column with each header, and byName the position of
+	// the column each identifier was mapped from.
+	byHeader map[string]int
+	byName   map[string]int
+}
+
+// GoIdentifiers maps each of headers to a Go identifier with GoIdentifier, suffixing identifiers
+// that would collide, such as the "Total" of "total" and "Total", with the lowest free number from
+// 2, so that every identifier maps back to exactly one header.
+func GoIdentifiers(headers []string) *IdentifierMap {
+
+	m := &IdentifierMap{
+		headers:     make([]string, len(headers)),
+		identifiers: make([]string, len(headers)),
+		byHeader:    make(map[string]int, len(headers)),
+		byName:      make(map[string]int, len(headers)),
+	}
+	_ = copy(m.headers, headers)
+
+	for i, header := range headers {
+
+		identifier := GoIdentifier(header)
+		if _, taken := m.byName[identifier]; taken {
+			base := identifier
+			for n := 2; ; n++ {
+				identifier = base + strconv.Itoa(n)
+				if _, taken = m.byName[identifier]; !taken {
+					break
+				}
+			}
+		}
+
+		m.identifiers[i] = identifier
+		m.byName[identifier] = i
+		if _, exists := m.byHeader[header]; !exists {
+			m.byHeader[header] = i
+		}
+	}
+
+	return m
+}
+
+// GoIdentifier returns header as an exported Go identifier. A header that is already a Go
+// identifier, such as "A_b", only has its first letter upper cased. Otherwise spaces, punctuation,
+// and any other characters that are neither letters nor digits separate words, whose first letters
+// are upper cased. An identifier that would not start with an upper case letter, such as one
+// starting with a digit, is prefixed with "X". A header with no letters or digits becomes "X".
+func GoIdentifier(header string) string {
+
+	if token.IsIdentifier(header) {
+		first, size := utf8.DecodeRuneInString(header)
+		return exportIdentifier(string(unicode.ToUpper(first)) + header[size:])
+	}
+
+	words := strings.FieldsFunc(header, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	b.Grow(len(header) + 1)
+	for _, word := range words {
+		first, size := utf8.DecodeRuneInString(word)
+		b.WriteRune(unicode.ToUpper(first))
+		b.WriteString(word[size:])
+	}
+
+	return exportIdentifier(b.String())
+}
+
+// exportIdentifier returns identifier prefixed with "X" if it does not start with an upper case
+// letter.
+func exportIdentifier(identifier string) string {
+
+	if !token.IsExported(identifier) {
+		return "X" + identifier
+	}
+
+	return identifier
+}
+
+// IsGoIdentifier reports whether header is already an exported Go identifier, which GoIdentifier
+// returns unchanged.
+func IsGoIdentifier(header string) bool {
+
+	return token.IsIdentifier(header) && token.IsExported(header)
+}
+
+// Identifier returns the identifier header maps to. A header that appears more than once maps to
+// the identifier of its first appearance; Identifiers holds those of the others.
+func (m *IdentifierMap) Identifier(header string) (string, bool) {
+
+	i, exists := m.byHeader[header]
+	if !exists {
+		return "", false
+	}
+
+	return m.identifiers[i], true
+}
+
+// Header returns the header that identifier was mapped from.
+func (m *IdentifierMap) Header(identifier string) (string, bool) {
+
+	i, exists := m.byName[identifier]
+	if !exists {
+		return "", false
+	}
+
+	return m.headers[i], true
+}
+
+// Column returns the position of the column that identifier was mapped from, which tells apart
+// columns that share a header.
+func (m *IdentifierMap) Column(identifier string) (int, bool) {
+
+	i, exists := m.byName[identifier]
+	return i, exists
+}
+
+// Headers returns a copy of the headers the map was built from, in order.
+func (m *IdentifierMap) Headers() []string {
+
+	headers := make([]string, len(m.headers))
+	_ = copy(headers, m.headers)
+	return headers
+}
+
+// Identifiers returns the identifier of each header, in order, which can be passed to
+// Reader.SetColumns so that decoded maps are keyed by identifier.
+func (m *IdentifierMap) Identifiers() []string {
+
+	identifiers := make([]string, len(m.identifiers))
+	_ = copy(identifiers, m.identifiers)
+	return identifiers
+}
+
+// Normalizer returns a HeaderNormalizer that rewrites the headers of the map to their identifiers
+// and leaves any other name unchanged, so that a reader of files with the same header row names its
+// columns by identifier.
+func (m *IdentifierMap) Normalizer() HeaderNormalizer {
+
+	return func(header string) string {
+		if identifier, exists := m.Identifier(header); exists {
+			return identifier
+		}
+		return header
+	}
+}
+
+// GoIdentifiers returns the mapping of the reader's column names to Go identifiers.
+func (r *Reader) GoIdentifiers() *IdentifierMap {
+
+	return GoIdentifiers(r.ColumnNames)
+}
@@ -0,0 +1,115 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGoIdentifier verifies headers are sanitized into exported Go identifiers
+func TestGoIdentifier(t *testing.T) {
+
+	var testCases = []struct {
+		name   string
+		header string
+		exp    string
+	}{
+		{name: "spaces", header: "first name", exp: "FirstName"},
+		{name: "punctuation", header: "unit-price ($)", exp: "UnitPrice"},
+		{name: "camel case kept", header: "userID", exp: "UserID"},
+		{name: "leading digit", header: "2nd address", exp: "X2ndAddress"},
+		{name: "unicode", header: "été prix", exp: "ÉtéPrix"},
+		{name: "uncased letters", header: "名前", exp: "X名前"},
+		{name: "no letters", header: " %% ", exp: "X"},
+		{name: "empty", header: "", exp: "X"},
+		{name: "already an identifier", header: "Total", exp: "Total"},
+		{name: "identifier with underscore", header: "A_b", exp: "A_b"},
+		{name: "unexported identifier", header: "first_name", exp: "First_name"},
+		{name: "leading underscore", header: "_id", exp: "X_id"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			actual := GoIdentifier(tt.header)
+			assert.Equal(t, tt.exp, actual)
+			assert.True(t, IsGoIdentifier(actual))
+			assert.Equal(t, tt.header == tt.exp, IsGoIdentifier(tt.header))
+		})
+	}
+}
+
+// TestGoIdentifiers verifies colliding identifiers are suffixed and every identifier maps back to
+// its header
+func TestGoIdentifiers(t *testing.T) {
+
+	headers := []string{"total", "Total", "total 2", "Total2", "id", "id", "AB", "A_b"}
+	m := GoIdentifiers(headers)
+
+	assert.Equal(t, []string{"Total", "Total2", "Total22", "Total23", "Id", "Id2", "AB", "A_b"}, m.Identifiers())
+	assert.Equal(t, headers, m.Headers())
+
+	for i, identifier := range m.Identifiers() {
+		header, exists := m.Header(identifier)
+		require.True(t, exists, identifier)
+		assert.Equal(t, headers[i], header)
+
+		column, exists := m.Column(identifier)
+		require.True(t, exists, identifier)
+		assert.Equal(t, i, column)
+	}
+
+	identifier, exists := m.Identifier("id")
+	assert.True(t, exists)
+	assert.Equal(t, "Id", identifier)
+
+	_, exists = m.Identifier("missing")
+	assert.False(t, exists)
+	_, exists = m.Header("Missing")
+	assert.False(t, exists)
+	_, exists = m.Column("Missing")
+	assert.False(t, exists)
+
+	normalize := m.Normalizer()
+	assert.Equal(t, "Total22", normalize("total 2"))
+	assert.Equal(t, "Other", normalize("Other"))
+}
+
+// TestReader_GoIdentifiers verifies the reader's columns can be renamed to identifiers to bind
+// struct fields and map keys, and renamed back
+func TestReader_GoIdentifiers(t *testing.T) {
+
+	type row struct {
+		FirstName  string
+		X2ndStreet string
+		UnitPrice  float64
+	}
+
+	input := "first name,2nd street,unit-price ($)\nAnn,Elm,1.5\n"
+
+	reader, err := NewReader(strings.NewReader(input), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	m := reader.GoIdentifiers()
+	require.NoError(t, reader.SetColumns(m.Identifiers()))
+
+	var actual row
+	require.NoError(t, reader.Read(&actual))
+	assert.Equal(t, row{FirstName: "Ann", X2ndStreet: "Elm", UnitPrice: 1.5}, actual)
+
+	reader, err = NewReader(strings.NewReader(input), &ReaderOptions{ReadHeaders: true, HeaderNormalizer: m.Normalizer()})
+	require.NoError(t, err)
+	assert.Equal(t, m.Identifiers(), reader.Columns())
+
+	values := map[string]string{}
+	require.NoError(t, reader.Read(&values))
+	assert.Equal(t, map[string]string{"FirstName": "Ann", "X2ndStreet": "Elm", "UnitPrice": "1.5"}, values)
+
+	for identifier := range values {
+		header, exists := m.Header(identifier)
+		assert.True(t, exists)
+		assert.Contains(t, []string{"first name", "2nd street", "unit-price ($)"}, header)
+	}
+}
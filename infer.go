@@ -0,0 +1,217 @@
+package csvee
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The types InferSchema gives columns, which are the types their cells are decoded to in maps.
+const (
+	inferredString = "string"
+	inferredInt    = "int64"
+	inferredFloat  = "float64"
+	inferredBool   = "bool"
+	inferredTime   = "time.Time"
+)
+
+// inferredLayouts are the time layouts InferSchema tries, in order.
+var inferredLayouts = []string{
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006/01/02",
+	"01/02/2006",
+	"01/02/2006 15:04:05",
+	"02-Jan-2006",
+	"Jan 2, 2006",
+	time.RFC1123,
+	time.RFC1123Z,
+}
+
+// InferSchema reads the headers and up to sampleRows records from r and guesses the type of each
+// column from its non-blank cells: int64 if they are all integers, float64 if they are all finite
+// numbers, bool if they are all true or false, time.Time with the first layout that parses them
+// all as its Format, and string otherwise, including for columns with only blank cells. Numbers
+// with leading zeros, such as "007", are taken to be identifiers and make their column a string.
+// Schema.ColumnFormats and Schema.ColumnTypes turn the result into ReaderOptions.
+func InferSchema(r io.Reader, sampleRows int) (Schema, error) {
+
+	if sampleRows <= 0 {
+		return Schema{}, errors.Errorf("sample rows must be positive, got %d", sampleRows)
+	}
+
+	reader, err := NewReader(r, &ReaderOptions{ReadHeaders: true})
+	if err != nil {
+		return Schema{}, err
+	}
+
+	schema, _, err := inferSchema(reader, sampleRows)
+	return schema, err
+}
+
+// inferSchema reads up to sampleRows records from reader and infers the types of its columns from
+// their cells that are neither blank nor null, returning the records it read along with them.
+func inferSchema(reader *Reader, sampleRows int) (Schema, [][]string, error) {
+
+	var records [][]string
+	samples := make([][]string, len(reader.ColumnNames))
+	for i := 0; i < sampleRows; i++ {
+		record, err := reader.ReadRaw()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Schema{}, nil, err
+		}
+
+		fields := make([]string, record.Len())
+		for j := range fields {
+			fields[j] = string([]byte(record.Field(j)))
+			if cell := strings.TrimSpace(fields[j]); cell != "" && !reader.isNull(cell) {
+				samples[j] = append(samples[j], cell)
+			}
+		}
+		records = append(records, fields)
+	}
+
+	schema := Schema{Columns: make([]SchemaColumn, len(reader.ColumnNames))}
+	for j, name := range reader.ColumnNames {
+		schema.Columns[j] = SchemaColumn{Name: name}
+		schema.Columns[j].Type, schema.Columns[j].Format = inferType(samples[j])
+	}
+
+	return schema, records, nil
+}
+
+// inferType returns the type of a column with the given non-blank cells, and its time layout if it
+// holds times.
+func inferType(cells []string) (string, string) {
+
+	if len(cells) == 0 {
+		return inferredString, ""
+	}
+
+	if allCells(cells, func(cell string) bool {
+		_, err := strconv.ParseInt(cell, 10, 64)
+		return err == nil && !hasLeadingZero(cell)
+	}) {
+		return inferredInt, ""
+	}
+
+	if allCells(cells, func(cell string) bool {
+		f, err := strconv.ParseFloat(cell, 64)
+		return err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) && !hasLeadingZero(cell)
+	}) {
+		return inferredFloat, ""
+	}
+
+	if allCells(cells, func(cell string) bool {
+		_, err := parseBool(cell, BoolStrict)
+		return err == nil
+	}) {
+		return inferredBool, ""
+	}
+
+	for _, layout := range inferredLayouts {
+		if allCells(cells, func(cell string) bool {
+			_, err := time.Parse(layout, cell)
+			return err == nil
+		}) {
+			return inferredTime, layout
+		}
+	}
+
+	return inferredString, ""
+}
+
+// inferredValue returns cell converted to the type inferType gave its column, with layout for
+// times, or nil if it is blank or null.
+func (r *Reader) inferredValue(cell string, column SchemaColumn) interface{} {
+
+	trimmed := strings.TrimSpace(cell)
+	if trimmed == "" || r.isNull(trimmed) {
+		return nil
+	}
+
+	switch column.Type {
+	case inferredInt:
+		if i, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+			return i
+		}
+	case inferredFloat:
+		if f, err := strconv.ParseFloat(trimmed, 64); err == nil {
+			return f
+		}
+	case inferredBool:
+		if b, err := parseBool(trimmed, BoolStrict); err == nil {
+			return b
+		}
+	case inferredTime:
+		if t, err := time.Parse(column.Format, trimmed); err == nil {
+			return t
+		}
+	}
+
+	return cell
+}
+
+// allCells reports whether matches is true for every cell.
+func allCells(cells []string, matches func(cell string) bool) bool {
+
+	for _, cell := range cells {
+		if !matches(cell) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasLeadingZero reports whether the number in cell has a zero before other digits, such as "007".
+func hasLeadingZero(cell string) bool {
+
+	cell = strings.TrimLeft(cell, "+-")
+	return len(cell) > 1 && cell[0] == '0' && cell[1] >= '0' && cell[1] <= '9'
+}
+
+// ColumnFormats returns the formats of the schema's columns that have one, for
+// ReaderOptions.ColumnFormats.
+func (s Schema) ColumnFormats() map[string]string {
+
+	formats := make(map[string]string)
+	for _, column := range s.Columns {
+		if column.Format != "" {
+			formats[column.Name] = column.Format
+		}
+	}
+
+	return formats
+}
+
+// ColumnTypes returns the kinds of the schema's columns with integer, float, bool, or time types,
+// for ReaderOptions.ColumnTypes, so that maps are decoded with values of those types.
+func (s Schema) ColumnTypes() map[string]ColumnKind {
+
+	kinds := make(map[string]ColumnKind)
+	for _, column := range s.Columns {
+		switch strings.TrimPrefix(column.Type, "*") {
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+			kinds[column.Name] = KindInt
+		case "float32", "float64":
+			kinds[column.Name] = KindFloat
+		case "bool":
+			kinds[column.Name] = KindBool
+		case "time.Time":
+			kinds[column.Name] = KindTime
+		}
+	}
+
+	return kinds
+}
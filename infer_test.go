@@ -0,0 +1,77 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInferSchema verifies column types and time layouts are guessed from sample rows
+func TestInferSchema(t *testing.T) {
+
+	input := "ID,Zip,Count,Price,Active,Joined,Seen,Note,Empty,Late\n" +
+		"1,02134,10,9.5,true,2021-03-04,2021-03-04 10:00:00,hello,,1\n" +
+		"2,10001,-3,12,false,,2021-03-05 11:30:00,42,,2\n" +
+		"3,94105,7,1e3,true,2021-12-31,2021-03-06 12:00:00,x,,oops\n"
+
+	var testCases = []struct {
+		name       string
+		sampleRows int
+		expLate    string
+	}{
+		{name: "all rows", sampleRows: 10, expLate: inferredString},
+		{name: "fewer rows", sampleRows: 2, expLate: inferredInt},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			schema, err := InferSchema(strings.NewReader(input), tt.sampleRows)
+			require.NoError(t, err)
+			assert.Equal(t, []SchemaColumn{
+				{Name: "ID", Type: "int64"},
+				{Name: "Zip", Type: "string"},
+				{Name: "Count", Type: "int64"},
+				{Name: "Price", Type: "float64"},
+				{Name: "Active", Type: "bool"},
+				{Name: "Joined", Type: "time.Time", Format: "2006-01-02"},
+				{Name: "Seen", Type: "time.Time", Format: "2006-01-02 15:04:05"},
+				{Name: "Note", Type: "string"},
+				{Name: "Empty", Type: "string"},
+				{Name: "Late", Type: tt.expLate},
+			}, schema.Columns)
+		})
+	}
+
+	_, err := InferSchema(strings.NewReader(input), 0)
+	assert.EqualError(t, err, "sample rows must be positive, got 0")
+}
+
+// TestInferSchema_Options verifies an inferred schema configures a reader to decode typed maps
+func TestInferSchema_Options(t *testing.T) {
+
+	input := "Name,Age,Joined\nann,34,2021-03-04\nbob,,2020-01-02\n"
+
+	schema, err := InferSchema(strings.NewReader(input), 5)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Joined": "2006-01-02"}, schema.ColumnFormats())
+	assert.Equal(t, map[string]ColumnKind{"Age": KindInt, "Joined": KindTime}, schema.ColumnTypes())
+
+	reader, err := NewReader(strings.NewReader(input), &ReaderOptions{
+		ReadHeaders:   true,
+		ColumnFormats: schema.ColumnFormats(),
+		ColumnTypes:   schema.ColumnTypes(),
+	})
+	require.NoError(t, err)
+
+	var row map[string]interface{}
+	require.NoError(t, reader.Read(&row))
+	assert.Equal(t, map[string]interface{}{
+		"Name":   "ann",
+		"Age":    int64(34),
+		"Joined": time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+	}, row)
+}
@@ -0,0 +1,274 @@
+package csvee
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// JSONOptions configures ToJSONArray and ToJSONLines.
+type JSONOptions struct {
+	// ColumnKinds maps column names to the kind of JSON value their cells are written as: numbers
+	// for KindInt and KindFloat, true or false for KindBool, and RFC 3339 strings for KindTime, with
+	// times parsed in the column's format. Blank cells in these columns are written as null.
+	ColumnKinds map[string]ColumnKind
+
+	// InferKinds writes cells of columns not in ColumnKinds that parse as finite numbers as JSON
+	// numbers, and cells that are true or false, ignoring case, as JSON bools. Other cells are
+	// strings.
+	InferKinds bool
+
+	// ExplodeColumns flattens the JSON objects held in columns into keys of their own, which follow
+	// the column's key, or replace it if it is not kept. Flattened values keep their JSON types.
+	ExplodeColumns []ExplodedColumn
+}
+
+// ToJSONArray streams the reader's remaining records to w as a single JSON array with an object per
+// record, whose keys are the column names in order. Cells are written as strings unless opts, which
+// may be nil, gives them another kind. Cells the reader reads as null, according to its NullValues,
+// are written as null. If an error occurs, what was written to w is not a complete array.
+func ToJSONArray(reader *Reader, w io.Writer, opts *JSONOptions) error {
+
+	return writeJSON(reader, w, opts, false)
+}
+
+// ToJSONLines streams the reader's remaining records to w as newline delimited JSON, one object per
+// line, written as ToJSONArray writes them. It is the inverse of FromJSONLines.
+func ToJSONLines(reader *Reader, w io.Writer, opts *JSONOptions) error {
+
+	return writeJSON(reader, w, opts, true)
+}
+
+// ToJSON streams the reader's remaining records to w as a JSON array, as ToJSONArray does, with the
+// cells of columns in ColumnTypes written as their kinds and those of columns with a format in
+// ColumnFormats or ConditionalFormats written as RFC 3339 times.
+func (r *Reader) ToJSON(w io.Writer) error {
+
+	return writeJSON(r, w, r.jsonOptions(), false)
+}
+
+// ToNDJSON streams the reader's remaining records to w as newline delimited JSON, with cells written
+// as ToJSON writes them.
+func (r *Reader) ToNDJSON(w io.Writer) error {
+
+	return writeJSON(r, w, r.jsonOptions(), true)
+}
+
+// ReadAsJSON reads the next record and returns it as a JSON object, with its cells written as ToJSON
+// writes them, so that single records can be inspected while troubleshooting, such as by piping
+// them to jq or logging them. It returns io.EOF once there are no more records.
+func (r *Reader) ReadAsJSON() (string, error) {
+
+	opts := r.jsonOptions()
+	kinds, err := r.cellKinds(opts.ColumnKinds, opts.InferKinds)
+	if err != nil {
+		return "", err
+	}
+
+	exploded, err := r.explodedColumns(nil)
+	if err != nil {
+		return "", err
+	}
+
+	record, err := r.readRecord()
+	if err != nil {
+		return "", err
+	}
+	if len(record) != len(r.ColumnNames) {
+		r.counts.errored++
+		return "", ErrColumnNamesMismatch
+	}
+
+	keys := make([][]byte, len(r.ColumnNames))
+	for j, name := range r.ColumnNames {
+		keys[j] = appendJSONString(nil, name)
+	}
+
+	buf, err := r.appendJSONObject(nil, record, keys, kinds, exploded)
+	if err != nil {
+		r.counts.errored++
+		return "", err
+	}
+
+	return string(buf), nil
+}
+
+// jsonOptions returns the options ToJSON and ToNDJSON write the reader's columns with.
+func (r *Reader) jsonOptions() *JSONOptions {
+
+	opts := &JSONOptions{ColumnKinds: make(map[string]ColumnKind)}
+	for _, name := range r.ColumnNames {
+		if kind, exists := r.columnTypes[name]; exists {
+			opts.ColumnKinds[name] = kind
+			continue
+		}
+		if _, exists := r.conditionalFormats[name]; exists || r.ColumnFormats[name] != "" {
+			opts.ColumnKinds[name] = KindTime
+		}
+	}
+
+	return opts
+}
+
+// writeJSON streams the reader's remaining records to w as a JSON array, or if lines is true, as
+// newline delimited JSON.
+func writeJSON(reader *Reader, w io.Writer, opts *JSONOptions, lines bool) error {
+
+	if opts == nil {
+		opts = &JSONOptions{}
+	}
+
+	kinds, err := reader.cellKinds(opts.ColumnKinds, opts.InferKinds)
+	if err != nil {
+		return err
+	}
+
+	exploded, err := reader.explodedColumns(opts.ExplodeColumns)
+	if err != nil {
+		return err
+	}
+
+	keys := make([][]byte, len(reader.ColumnNames))
+	for j, name := range reader.ColumnNames {
+		keys[j] = appendJSONString(nil, name)
+	}
+
+	return reader.recordRun(func() (int, error) {
+		reader.startPipeline()
+		defer reader.stopPipeline()
+
+		bw := bufio.NewWriter(w)
+		var buf []byte
+		if !lines {
+			buf = append(buf, '[')
+		}
+
+		var rowsWritten int
+		for {
+			record, err := reader.readRecord()
+			if err == io.EOF {
+				break
+			}
+			if err == nil && len(record) != len(reader.ColumnNames) {
+				err = ErrColumnNamesMismatch
+			}
+			if err != nil {
+				return rowsWritten, err
+			}
+
+			if rowsWritten > 0 && !lines {
+				buf = append(buf, ',')
+			}
+			if buf, err = reader.appendJSONObject(buf, record, keys, kinds, exploded); err != nil {
+				return rowsWritten, err
+			}
+			if lines {
+				buf = append(buf, '\n')
+			}
+			rowsWritten++
+
+			if _, err := bw.Write(buf); err != nil {
+				return rowsWritten, err
+			}
+			buf = buf[:0]
+		}
+
+		if !lines {
+			buf = append(buf, ']', '\n')
+			if _, err := bw.Write(buf); err != nil {
+				return rowsWritten, err
+			}
+		}
+
+		return rowsWritten, bw.Flush()
+	})
+}
+
+// appendJSONObject appends record to buf as a JSON object with the given keys for its columns.
+func (r *Reader) appendJSONObject(buf []byte, record []string, keys [][]byte, kinds cellKinds, exploded []*ExplodedColumn) ([]byte, error) {
+
+	buf = append(buf, '{')
+	fields := 0
+	for j, cell := range record {
+
+		if exploded[j] == nil || exploded[j].KeepColumn {
+			if fields > 0 {
+				buf = append(buf, ',')
+			}
+			fields++
+
+			buf = append(append(buf, keys[j]...), ':')
+			value, err := r.typedCell(j, cell, kinds)
+			if err == nil {
+				buf, err = appendJSONValue(buf, value)
+			}
+			if err != nil {
+				return buf, &FieldError{Row: r.rowsRead, Column: r.ColumnNames[j], Value: cell, Err: err}
+			}
+		}
+
+		if exploded[j] == nil {
+			continue
+		}
+
+		err := r.explode(exploded[j], cell, func(key string, value interface{}) error {
+			if fields > 0 {
+				buf = append(buf, ',')
+			}
+			fields++
+
+			buf = append(appendJSONString(buf, key), ':')
+			if number, isNumber := value.(json.Number); isNumber {
+				buf = append(buf, number...)
+				return nil
+			}
+			data, err := json.Marshal(value)
+			buf = append(buf, data...)
+			return err
+		})
+		if err != nil {
+			return buf, &FieldError{Row: r.rowsRead, Column: r.ColumnNames[j], Value: cell, Err: err}
+		}
+	}
+
+	return append(buf, '}'), nil
+}
+
+// appendJSONValue appends value, as returned by Reader.typedCell, to buf as a JSON value.
+func appendJSONValue(buf []byte, value interface{}) ([]byte, error) {
+
+	switch value := value.(type) {
+	case nil:
+		return append(buf, "null"...), nil
+	case int64:
+		return strconv.AppendInt(buf, value, 10), nil
+	case float64:
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return buf, errors.Errorf("%v cannot be represented in JSON", value)
+		}
+		return strconv.AppendFloat(buf, value, 'g', -1, 64), nil
+	case bool:
+		return strconv.AppendBool(buf, value), nil
+	case time.Time:
+		data, err := value.MarshalJSON()
+		if err != nil {
+			return buf, err
+		}
+		return append(buf, data...), nil
+	}
+
+	return appendJSONString(buf, value.(string)), nil
+}
+
+// appendJSONString appends s to buf as a JSON string.
+func appendJSONString(buf []byte, s string) []byte {
+
+	// Marshaling a string cannot fail.
+	data, _ := json.Marshal(s)
+	return append(buf, data...)
+}
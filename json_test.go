@@ -0,0 +1,174 @@
+package csvee
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestToJSONArray verifies records are streamed as a JSON array of objects with typed values
+func TestToJSONArray(t *testing.T) {
+
+	input := "Name,Age,Score,Active,Joined,Note\n" +
+		"Ann,34,9.5,true,2021-03-04,\"says \"\"hi\"\"\"\n" +
+		"Bob,,NaN,FALSE,,N/A\n"
+
+	tests := []struct {
+		name     string
+		options  *ReaderOptions
+		json     *JSONOptions
+		expected string
+		err      string
+	}{
+		{
+			name:     "strings",
+			options:  &ReaderOptions{ReadHeaders: true},
+			expected: `[{"Name":"Ann","Age":"34","Score":"9.5","Active":"true","Joined":"2021-03-04","Note":"says \"hi\""},{"Name":"Bob","Age":"","Score":"NaN","Active":"FALSE","Joined":"","Note":"N/A"}]` + "\n",
+		},
+		{
+			name:    "kinds",
+			options: &ReaderOptions{ReadHeaders: true, BoolParsing: BoolLenient, ColumnFormats: map[string]string{"Joined": "2006-01-02"}, NullValues: []string{"N/A"}},
+			json: &JSONOptions{ColumnKinds: map[string]ColumnKind{
+				"Age": KindInt, "Active": KindBool, "Joined": KindTime,
+			}},
+			expected: `[{"Name":"Ann","Age":34,"Score":"9.5","Active":true,"Joined":"2021-03-04T00:00:00Z","Note":"says \"hi\""},{"Name":"Bob","Age":null,"Score":"NaN","Active":false,"Joined":null,"Note":null}]` + "\n",
+		},
+		{
+			name:     "inferred",
+			options:  &ReaderOptions{ReadHeaders: true},
+			json:     &JSONOptions{InferKinds: true},
+			expected: `[{"Name":"Ann","Age":34,"Score":9.5,"Active":true,"Joined":"2021-03-04","Note":"says \"hi\""},{"Name":"Bob","Age":"","Score":"NaN","Active":false,"Joined":"","Note":"N/A"}]` + "\n",
+		},
+		{
+			name:    "non-finite float",
+			options: &ReaderOptions{ReadHeaders: true},
+			json:    &JSONOptions{ColumnKinds: map[string]ColumnKind{"Score": KindFloat}},
+			err:     "cannot be represented in JSON",
+		},
+		{
+			name:    "unknown column",
+			options: &ReaderOptions{ReadHeaders: true},
+			json:    &JSONOptions{ColumnKinds: map[string]ColumnKind{"Missing": KindInt}},
+			err:     `column "Missing" not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewReader(strings.NewReader(input), tt.options)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			err = ToJSONArray(reader, &buf, tt.json)
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+			assert.True(t, json.Valid(buf.Bytes()))
+		})
+	}
+}
+
+// TestToJSONArray_Empty verifies a reader with no records produces an empty array
+func TestToJSONArray_Empty(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader("Name,Age\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, ToJSONArray(reader, &buf, nil))
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+// TestToJSONLines verifies records are streamed as one JSON object per line
+func TestToJSONLines(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader("Name,Age\nAnn,34\nBob,\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, ToJSONLines(reader, &buf, &JSONOptions{ColumnKinds: map[string]ColumnKind{"Age": KindInt}}))
+	assert.Equal(t, `{"Name":"Ann","Age":34}`+"\n"+`{"Name":"Bob","Age":null}`+"\n", buf.String())
+
+	reader, err = NewReader(strings.NewReader("Name,Age\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	buf.Reset()
+	require.NoError(t, ToJSONLines(reader, &buf, nil))
+	assert.Empty(t, buf.String())
+}
+
+// TestReader_ToJSON verifies the reader's column types and formats decide how its cells are written
+func TestReader_ToJSON(t *testing.T) {
+
+	input := "Name,Age,Joined,Left,Kind\n" +
+		"Ann,34,04/03/2021,2022-01-02,iso\n" +
+		"Bob,,05/06/2020,20220102,compact\n"
+	options := func() *ReaderOptions {
+		return &ReaderOptions{
+			ReadHeaders:        true,
+			ColumnTypes:        map[string]ColumnKind{"Age": KindInt},
+			ColumnFormats:      map[string]string{"Joined": "02/01/2006"},
+			ConditionalFormats: map[string]ConditionalFormat{"Left": {Column: "Kind", Formats: map[string]string{"iso": "2006-01-02", "compact": "20060102"}}},
+		}
+	}
+
+	objects := []string{
+		`{"Name":"Ann","Age":34,"Joined":"2021-03-04T00:00:00Z","Left":"2022-01-02T00:00:00Z","Kind":"iso"}`,
+		`{"Name":"Bob","Age":null,"Joined":"2020-06-05T00:00:00Z","Left":"2022-01-02T00:00:00Z","Kind":"compact"}`,
+	}
+
+	reader, err := NewReader(strings.NewReader(input), options())
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, reader.ToJSON(&buf))
+	assert.Equal(t, "["+strings.Join(objects, ",")+"]\n", buf.String())
+
+	reader, err = NewReader(strings.NewReader(input), options())
+	require.NoError(t, err)
+
+	buf.Reset()
+	require.NoError(t, reader.ToNDJSON(&buf))
+	assert.Equal(t, strings.Join(objects, "\n")+"\n", buf.String())
+
+	reader, err = NewReader(strings.NewReader(input), options())
+	require.NoError(t, err)
+
+	for _, object := range objects {
+		row, err := reader.ReadAsJSON()
+		require.NoError(t, err)
+		assert.Equal(t, object, row)
+	}
+	_, err = reader.ReadAsJSON()
+	assert.Equal(t, io.EOF, err)
+}
+
+// TestReader_ReadAsJSON_Errors verifies rows that cannot be written as JSON fail without stopping
+// the reader
+func TestReader_ReadAsJSON_Errors(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader("Name,Age\nAnn,x\nBob,41\n"), &ReaderOptions{
+		ReadHeaders: true,
+		ColumnTypes: map[string]ColumnKind{"Age": KindInt},
+	})
+	require.NoError(t, err)
+
+	_, err = reader.ReadAsJSON()
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "Age", fieldErr.Column)
+	assert.Equal(t, int64(1), reader.RowsErrored())
+
+	row, err := reader.ReadAsJSON()
+	require.NoError(t, err)
+	assert.Equal(t, `{"Name":"Bob","Age":41}`, row)
+}
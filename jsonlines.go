@@ -0,0 +1,102 @@
+package csvee
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// JSONLinesOptions configures FromJSONLines.
+type JSONLinesOptions struct {
+	// Separator joins the keys of nested objects into column names, such as "address.city".
+	// Defaults to ".".
+	Separator string
+
+	// Arrays controls how arrays are written. Defaults to ArraysJoined.
+	Arrays ArrayHandling
+
+	// IgnoreUnknownKeys drops values whose flattened keys are not columns of the Writer, which are
+	// otherwise an error.
+	IgnoreUnknownKeys bool
+}
+
+// FromJSONLines reads newline delimited JSON objects from r and writes each as a row to w, the
+// inverse of reading a CSV into JSON. Nested objects are flattened, their keys joined to their
+// parent's with the separator, and each flattened key fills the column of w with the same name;
+// columns with no key are left empty. Strings are written as they are, numbers as written in the
+// JSON, and bools as true or false. Nulls are skipped, leaving their cells empty. Arrays are written
+// as opts.Arrays directs. Blank lines are skipped. w is flushed once every line has been written; opts may be nil.
+func FromJSONLines(r io.Reader, w *Writer, opts *JSONLinesOptions) error {
+
+	if r == nil {
+		return ErrReaderNil
+	}
+	if w == nil {
+		return ErrWriterNil
+	}
+
+	if opts == nil {
+		opts = &JSONLinesOptions{}
+	}
+	separator := opts.Separator
+	if separator == "" {
+		separator = "."
+	}
+
+	columns := make(map[string]int, len(w.ColumnNames))
+	for i, name := range w.ColumnNames {
+		columns[name] = i
+	}
+
+	br := bufio.NewReader(r)
+	for line := 1; ; line++ {
+
+		data, readErr := br.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
+			if readErr == io.EOF {
+				break
+			}
+			continue
+		}
+
+		object, err := decodeJSONObject(data)
+		if err != nil {
+			return errors.Wrapf(err, "line %d", line)
+		}
+
+		record := make([]string, len(w.ColumnNames))
+		filled := make([]bool, len(w.ColumnNames))
+		if err := flattenJSON(object, "", separator, opts.Arrays, func(key string, value interface{}) error {
+			i, exists := columns[key]
+			if !exists {
+				if opts.IgnoreUnknownKeys {
+					return nil
+				}
+				return errors.Errorf("key %q is not a column", key)
+			}
+			if filled[i] {
+				return errors.Errorf("more than one value for key %q", key)
+			}
+			cell, err := flattenedCell(value)
+			record[i], filled[i] = cell, true
+			return err
+		}); err != nil {
+			return errors.Wrapf(err, "line %d", line)
+		}
+
+		if err := w.WriteRecord(record); err != nil {
+			return err
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,101 @@
+package csvee
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFromJSONLines verifies JSON objects are flattened into rows
+func TestFromJSONLines(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		columns  []string
+		options  *JSONLinesOptions
+		expected string
+		err      string
+	}{
+		{
+			name: "flattened",
+			input: `{"id": 1, "name": "Ann", "address": {"city": "Oslo", "geo": {"lat": 59.9}}, "tags": ["a", "b"], "active": true}` + "\n" +
+				"\n" +
+				`{"id": 2, "name": "Bob, Jr.", "address": null, "tags": [], "active": false, "extra": null}`,
+			columns:  []string{"id", "name", "address.city", "address.geo.lat", "tags", "active", "extra"},
+			expected: "id,name,address.city,address.geo.lat,tags,active,extra\n1,Ann,Oslo,59.9,\"a,b\",true,\n2,\"Bob, Jr.\",,,,false,\n",
+		},
+		{
+			name:     "separator",
+			input:    `{"a": {"b": "x"}, "n": [[1, 2], 3]}`,
+			columns:  []string{"a_b", "n"},
+			options:  &JSONLinesOptions{Separator: "_"},
+			expected: "a_b,n\nx,\"[[1,2],3]\"\n",
+		},
+		{
+			name:     "indexed arrays",
+			input:    `{"tags": ["a", {"b": 1}]}`,
+			columns:  []string{"tags.0", "tags.1.b"},
+			options:  &JSONLinesOptions{Arrays: ArraysIndexed},
+			expected: "tags.0,tags.1.b\na,1\n",
+		},
+		{
+			name:     "ignore unknown",
+			input:    `{"a": "x", "b": "y"}`,
+			columns:  []string{"a"},
+			options:  &JSONLinesOptions{IgnoreUnknownKeys: true},
+			expected: "a\nx\n",
+		},
+		{
+			name:    "unknown key",
+			input:   `{"a": "x", "b": "y"}`,
+			columns: []string{"a"},
+			err:     `line 1: key "b" is not a column`,
+		},
+		{
+			name:    "duplicate key",
+			input:   `{"a": {"b": 1}, "a.b": 2}`,
+			columns: []string{"a.b"},
+			err:     `more than one value for key "a.b"`,
+		},
+		{
+			name:    "not an object",
+			input:   "{\"a\": 1}\n[1]\n",
+			columns: []string{"a"},
+			err:     "line 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writer, err := NewWriter(&buf, &WriterOptions{ColumnNames: tt.columns, WriteHeaders: true})
+			require.NoError(t, err)
+
+			err = FromJSONLines(strings.NewReader(tt.input), writer, tt.options)
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+// TestFromJSONLines_EscapeFormulas verifies values that spreadsheets would evaluate are escaped when
+// the writer escapes formulas
+func TestFromJSONLines_EscapeFormulas(t *testing.T) {
+
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, &WriterOptions{ColumnNames: []string{"link", "n"}, WriteHeaders: true, EscapeFormulas: true})
+	require.NoError(t, err)
+
+	input := `{"link": "=HYPERLINK(\"http://x\")", "n": -1}`
+	require.NoError(t, FromJSONLines(strings.NewReader(input), writer, nil))
+	assert.Equal(t, "link,n\n\"'=HYPERLINK(\"\"http://x\"\")\",'-1\n", buf.String())
+}
@@ -0,0 +1,196 @@
+package csvee
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Locale describes how a Writer formats numbers and times for people in a region to read, rather
+// than for machines. Cells written with a locale are not meant to be read back by a Reader.
+type Locale struct {
+	// DecimalSeparator separates the integer and fractional parts of floats. Defaults to ".".
+	DecimalSeparator string
+
+	// GroupSeparator, if set, separates each group of three digits in the integer part of numbers,
+	// such as "." in "1.234.567,8".
+	GroupSeparator string
+
+	// TimeFormat is the format times are written in, unless their column has one in ColumnFormats.
+	// Defaults to RFC 3339.
+	TimeFormat string
+}
+
+var (
+	// LocaleUS writes numbers such as "1,234.5" and dates such as "12/31/2021".
+	LocaleUS = Locale{DecimalSeparator: ".", GroupSeparator: ",", TimeFormat: "01/02/2006"}
+
+	// LocaleUK writes numbers such as "1,234.5" and dates such as "31/12/2021".
+	LocaleUK = Locale{DecimalSeparator: ".", GroupSeparator: ",", TimeFormat: "02/01/2006"}
+
+	// LocaleDE writes numbers such as "1.234,5" and dates such as "31.12.2021".
+	LocaleDE = Locale{DecimalSeparator: ",", GroupSeparator: ".", TimeFormat: "02.01.2006"}
+
+	// LocaleFR writes numbers such as "1 234,5", grouped with narrow no-break spaces, and dates such
+	// as "31/12/2021".
+	LocaleFR = Locale{DecimalSeparator: ",", GroupSeparator: "\u202f", TimeFormat: "02/01/2006"}
+)
+
+// validate checks that the locale's separators differ and its time format, if it has one, is valid.
+func (l *Locale) validate() error {
+
+	if l.decimalSeparator() == l.GroupSeparator {
+		return errors.Errorf("decimal and group separators must differ, both are %q", l.GroupSeparator)
+	}
+
+	if l.TimeFormat != "" && !Format(l.TimeFormat).Valid() {
+		return &FormatError{Format: l.TimeFormat}
+	}
+
+	return nil
+}
+
+// formatInt formats the integer whose decimal digits are in digits, with a leading minus sign if it
+// is negative, with the locale's group separator.
+func (l *Locale) formatInt(digits string) string {
+
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+
+	if l.GroupSeparator == "" || len(digits) <= 3 {
+		return sign + digits
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	first := len(digits) % 3
+	if first == 0 {
+		first = 3
+	}
+	b.WriteString(digits[:first])
+	for i := first; i < len(digits); i += 3 {
+		b.WriteString(l.GroupSeparator)
+		b.WriteString(digits[i : i+3])
+	}
+
+	return b.String()
+}
+
+// formatFloat formats f without an exponent, in as few digits as read back as f, with the locale's
+// separators. Infinities and NaN are formatted as strconv formats them.
+func (l *Locale) formatFloat(f float64, bitSize int) string {
+
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return strconv.FormatFloat(f, 'g', -1, bitSize)
+	}
+
+	integer, fraction, _ := strings.Cut(strconv.FormatFloat(f, 'f', -1, bitSize), ".")
+	if fraction == "" {
+		return l.formatInt(integer)
+	}
+
+	return l.formatInt(integer) + l.decimalSeparator() + fraction
+}
+
+// NumberParser returns a parser, for ReaderOptions.NumberParsers, of numbers formatted as in the
+// locale, such as "1.234,5" for LocaleDE. Group separators may be omitted, but where they are
+// present they must separate groups of three digits.
+func (l *Locale) NumberParser() *NumberParser {
+
+	locale := *l
+	return &NumberParser{
+		ParseInt: func(s string, bitSize int) (int64, error) {
+			canonical, ok := locale.canonicalNumber(s)
+			if !ok {
+				return 0, &strconv.NumError{Func: "ParseInt", Num: s, Err: strconv.ErrSyntax}
+			}
+			return strconv.ParseInt(canonical, 10, bitSize)
+		},
+		ParseUint: func(s string, bitSize int) (uint64, error) {
+			canonical, ok := locale.canonicalNumber(s)
+			if !ok {
+				return 0, &strconv.NumError{Func: "ParseUint", Num: s, Err: strconv.ErrSyntax}
+			}
+			return strconv.ParseUint(canonical, 10, bitSize)
+		},
+		ParseFloat: func(s string, bitSize int) (float64, error) {
+			canonical, ok := locale.canonicalNumber(s)
+			if !ok {
+				return 0, &strconv.NumError{Func: "ParseFloat", Num: s, Err: strconv.ErrSyntax}
+			}
+			return strconv.ParseFloat(canonical, bitSize)
+		},
+	}
+}
+
+// canonicalNumber returns s, a number formatted as in the locale, as strconv formats it, or false if
+// its digits are grouped incorrectly.
+func (l *Locale) canonicalNumber(s string) (string, bool) {
+
+	integer, fraction, decimal := s, "", false
+	if i := strings.LastIndex(s, l.decimalSeparator()); i >= 0 {
+		integer, fraction, decimal = s[:i], s[i+len(l.decimalSeparator()):], true
+	}
+
+	if l.GroupSeparator != "" && strings.Contains(integer, l.GroupSeparator) {
+		sign := ""
+		if strings.HasPrefix(integer, "-") || strings.HasPrefix(integer, "+") {
+			sign, integer = integer[:1], integer[1:]
+		}
+
+		groups := strings.Split(integer, l.GroupSeparator)
+		if len(groups[0]) == 0 || len(groups[0]) > 3 {
+			return "", false
+		}
+		for _, group := range groups[1:] {
+			if len(group) != 3 {
+				return "", false
+			}
+		}
+		integer = sign + strings.Join(groups, "")
+	}
+
+	if !decimal {
+		return integer, true
+	}
+
+	return integer + "." + fraction, true
+}
+
+// decimalSeparator returns the locale's decimal separator, which defaults to ".".
+func (l *Locale) decimalSeparator() string {
+
+	if l.DecimalSeparator == "" {
+		return "."
+	}
+
+	return l.DecimalSeparator
+}
+
+// formatTime formats t in format, or in the locale's time format if format is empty.
+func (l *Locale) formatTime(t time.Time, format Format) (string, error) {
+
+	if format == "" {
+		format = Format(l.TimeFormat)
+	}
+	if format == "" {
+		return t.Format(time.RFC3339Nano), nil
+	}
+
+	return format.Format(t)
+}
+
+// columnLocale returns the locale of the named column, or the Writer's locale, which may be nil.
+func (w *Writer) columnLocale(column string) *Locale {
+
+	if locale, exists := w.columnLocales[column]; exists {
+		return locale
+	}
+
+	return w.locale
+}
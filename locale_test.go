@@ -0,0 +1,102 @@
+package csvee
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type localizedRow struct {
+	Count  int
+	Big    uint64
+	Amount float64
+	Ratio  float32
+	When   time.Time
+	Due    *time.Time
+	Parts  []float64
+}
+
+// TestWriter_Locale verifies numbers and times are formatted with the Writer's and columns' locales
+func TestWriter_Locale(t *testing.T) {
+
+	when := time.Date(2021, time.December, 31, 16, 5, 0, 0, time.UTC)
+	row := localizedRow{Count: -1234567, Big: 1000, Amount: 1234.5, Ratio: 0.25, When: when, Due: &when, Parts: []float64{1.5, 2}}
+	columns := []string{"Count", "Big", "Amount", "Ratio", "When", "Due", "Parts"}
+
+	var testCases = []struct {
+		name    string
+		options WriterOptions
+		exp     string
+	}{
+		{
+			name:    "none",
+			options: WriterOptions{ColumnNames: columns},
+			exp:     "-1234567,1000,1234.5,0.25,2021-12-31T16:05:00Z,2021-12-31T16:05:00Z,\"1.5,2\"\n",
+		},
+		{
+			name:    "de",
+			options: WriterOptions{ColumnNames: columns, Locale: &LocaleDE, SliceDelimiters: map[string]string{"Parts": ";"}},
+			exp:     "-1.234.567,1.000,\"1.234,5\",\"0,25\",31.12.2021,31.12.2021,\"1,5;2\"\n",
+		},
+		{
+			name:    "no time format",
+			options: WriterOptions{ColumnNames: columns, Locale: &Locale{DecimalSeparator: ","}, SliceDelimiters: map[string]string{"Parts": ";"}},
+			exp:     "-1234567,1000,\"1234,5\",\"0,25\",2021-12-31T16:05:00Z,2021-12-31T16:05:00Z,\"1,5;2\"\n",
+		},
+		{
+			name: "column locales and formats",
+			options: WriterOptions{
+				ColumnNames:   columns,
+				Locale:        &LocaleUS,
+				ColumnLocales: map[string]*Locale{"Amount": &LocaleFR, "Big": nil, "Due": {TimeFormat: "02 Jan 2006"}},
+				ColumnFormats: map[string]string{"When": "2006-01-02 15:04"},
+			},
+			exp: "\"-1,234,567\",1000,\"1\u202f234,5\",0.25,2021-12-31 16:05,31 Dec 2021,\"1.5,2\"\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var buf bytes.Buffer
+			writer, err := NewWriter(&buf, &tt.options)
+			require.NoError(t, err)
+
+			require.NoError(t, writer.Write(row))
+			require.NoError(t, writer.Flush())
+			assert.Equal(t, tt.exp, buf.String())
+		})
+	}
+}
+
+// TestLocale_Format verifies the grouping of digits and the formatting of floats
+func TestLocale_Format(t *testing.T) {
+
+	assert.Equal(t, "0", LocaleUK.formatInt("0"))
+	assert.Equal(t, "-123", LocaleUK.formatInt("-123"))
+	assert.Equal(t, "1,234", LocaleUK.formatInt("1234"))
+	assert.Equal(t, "123,456", LocaleUK.formatInt("123456"))
+	assert.Equal(t, "1234567", (&Locale{}).formatInt("1234567"))
+
+	assert.Equal(t, "1.000.000.000.000.000.000.000", LocaleDE.formatFloat(1e21, 64))
+	assert.Equal(t, "-0,000001", LocaleDE.formatFloat(-1e-6, 64))
+	assert.Equal(t, "+Inf", LocaleDE.formatFloat(math.Inf(1), 64))
+	assert.Equal(t, "NaN", LocaleDE.formatFloat(math.NaN(), 64))
+}
+
+// TestWriter_Locale_Invalid verifies invalid locales are rejected
+func TestWriter_Locale_Invalid(t *testing.T) {
+
+	_, err := NewWriter(&bytes.Buffer{}, &WriterOptions{ColumnNames: []string{"A"}, Locale: &Locale{GroupSeparator: "."}})
+	assert.EqualError(t, err, `decimal and group separators must differ, both are "."`)
+
+	_, err = NewWriter(&bytes.Buffer{}, &WriterOptions{ColumnNames: []string{"A"}, ColumnLocales: map[string]*Locale{"B": &LocaleDE}})
+	assert.EqualError(t, err, `locale provided for unknown column "B"`)
+
+	_, err = NewWriter(&bytes.Buffer{}, &WriterOptions{ColumnNames: []string{"A"}, ColumnLocales: map[string]*Locale{"A": {TimeFormat: "nope"}}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package csvee
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"time"
+)
+
+// Manifest summarizes a single import run. It is written as JSON to ReaderOptions.Manifest once
+// ReadAll or Pump completes, whether or not the run succeeded.
+type Manifest struct {
+	// SHA256 is the hash of the input read by the run. It covers the whole input unless Partial is
+	// set, in which case the run stopped before the end of the input and the hash covers only the
+	// bytes read up to that point.
+	SHA256        string            `json:"sha256"`
+	Partial       bool              `json:"partial,omitempty"`
+	RowsRead      int               `json:"rowsRead"`
+	RowsDecoded   int               `json:"rowsDecoded"`
+	RejectedRows  []RejectedRow     `json:"rejectedRows"`
+	ColumnNames   []string          `json:"columnNames"`
+	ColumnFormats map[string]string `json:"columnFormats"`
+	Started       time.Time         `json:"started"`
+	Duration      string            `json:"duration"`
+}
+
+// RejectedRow identifies a row that could not be decoded and the reason why.
+type RejectedRow struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// hashingReader computes a running hash of everything read through it and notes when the end of
+// the input is reached.
+type hashingReader struct {
+	r    io.Reader
+	hash hash.Hash
+	eof  bool
+}
+
+func newHashingReader(r io.Reader) *hashingReader {
+
+	h := sha256.New()
+	return &hashingReader{
+		r:    io.TeeReader(r, h),
+		hash: h,
+	}
+}
+
+// Read reads from the underlying reader and adds the bytes read to the hash.
+func (hr *hashingReader) Read(p []byte) (n int, err error) {
+
+	n, err = hr.r.Read(p)
+	if err == io.EOF {
+		hr.eof = true
+	}
+
+	return n, err
+}
+
+// Sum returns the hex encoded hash of the bytes read so far.
+func (hr *hashingReader) Sum() string {
+
+	return hex.EncodeToString(hr.hash.Sum(nil))
+}
+
+// manifestRecorder accumulates the details of a run until it is written out.
+type manifestRecorder struct {
+	w        io.Writer
+	hasher   *hashingReader
+	manifest Manifest
+}
+
+func (mr *manifestRecorder) start(r *Reader) {
+
+	mr.manifest = Manifest{
+		RejectedRows:  []RejectedRow{},
+		ColumnNames:   append([]string{}, r.ColumnNames...),
+		ColumnFormats: make(map[string]string, len(r.ColumnFormats)),
+		Started:       time.Now(),
+	}
+
+	for k, v := range r.ColumnFormats {
+		mr.manifest.ColumnFormats[k] = v
+	}
+}
+
+func (mr *manifestRecorder) reject(row int, err error) {
+
+	mr.manifest.RejectedRows = append(mr.manifest.RejectedRows, RejectedRow{Row: row, Error: err.Error()})
+}
+
+func (mr *manifestRecorder) finish(rowsRead, rowsDecoded int) error {
+
+	mr.manifest.SHA256 = mr.hasher.Sum()
+	mr.manifest.Partial = !mr.hasher.eof
+	mr.manifest.RowsRead = rowsRead
+	mr.manifest.RowsDecoded = rowsDecoded
+	mr.manifest.Duration = time.Since(mr.manifest.Started).String()
+
+	return json.NewEncoder(mr.w).Encode(mr.manifest)
+}
@@ -0,0 +1,129 @@
+package csvee
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_ReadAllManifest verifies a manifest is written summarizing a ReadAll run
+func TestReader_ReadAllManifest(t *testing.T) {
+
+	var testCases = []struct {
+		name            string
+		inData          string
+		inOnError       ErrorPolicy
+		inBudget        int
+		expRowsRead     int
+		expRowsDecoded  int
+		expRejectedRows []RejectedRow
+		expPartial      bool
+		expErr          bool
+	}{
+		{
+			name:            "success",
+			inData:          "I,S,Tu\n1,a,1613235342\n2,b,1613235342\n",
+			expRowsRead:     2,
+			expRowsDecoded:  2,
+			expRejectedRows: []RejectedRow{},
+		},
+		{
+			name:            "rejected row",
+			inData:          "I,S,Tu\n1,a,1613235342\nx,b,1613235342\n",
+			expRowsRead:     2,
+			expRowsDecoded:  1,
+			expRejectedRows: []RejectedRow{{Row: 2, Error: `row 2, column "I": invalid value "x" for int`}},
+			expPartial:      true,
+			expErr:          true,
+		},
+		{
+			name:           "skipped rows",
+			inData:         "I,S,Tu\n1,a,1613235342\nx,b,1613235342\n3,c,1613235342\ny,d,1613235342\n",
+			inOnError:      ErrorSkip,
+			expRowsRead:    4,
+			expRowsDecoded: 2,
+			expRejectedRows: []RejectedRow{
+				{Row: 2, Error: `row 2, column "I": invalid value "x" for int`},
+				{Row: 4, Error: `row 4, column "I": invalid value "y" for int`},
+			},
+		},
+		{
+			name:           "collected rows",
+			inData:         "I,S,Tu\n1,a,1613235342\nx,b,1613235342\n3,c,1613235342\ny,d,1613235342\n",
+			inOnError:      ErrorCollect,
+			expRowsRead:    4,
+			expRowsDecoded: 2,
+			expRejectedRows: []RejectedRow{
+				{Row: 2, Error: `row 2, column "I": invalid value "x" for int`},
+				{Row: 4, Error: `row 4, column "I": invalid value "y" for int`},
+			},
+			expErr: true,
+		},
+		{
+			name:        "error budget exceeded",
+			inData:      "I,S,Tu\nx,a,1613235342\ny,b,1613235342\n3,c,1613235342\n",
+			inOnError:   ErrorCollect,
+			inBudget:    1,
+			expRowsRead: 2,
+			expRejectedRows: []RejectedRow{
+				{Row: 1, Error: `row 1, column "I": invalid value "x" for int`},
+				{Row: 2, Error: `row 2, column "I": invalid value "y" for int`},
+			},
+			expPartial: true,
+			expErr:     true,
+		},
+		{
+			name:            "stopped before the end of a large input",
+			inData:          "I,S,Tu\nx,a,1613235342\n" + strings.Repeat("1,a,1613235342\n", 1000),
+			expRowsRead:     1,
+			expRejectedRows: []RejectedRow{{Row: 1, Error: `row 1, column "I": invalid value "x" for int`}},
+			expPartial:      true,
+			expErr:          true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var manifestBuf bytes.Buffer
+			reader, err := NewReader(
+				strings.NewReader(tt.inData),
+				&ReaderOptions{
+					ReadHeaders:   true,
+					ColumnFormats: map[string]string{"Tu": TimeFormatUnix},
+					Manifest:      &manifestBuf,
+					OnError:       tt.inOnError,
+					ErrorBudget:   tt.inBudget,
+				},
+			)
+			require.NoError(t, err)
+
+			var actualData []readTo
+			err = reader.ReadAll(&actualData)
+			require.Equal(t, tt.expErr, err != nil, err)
+
+			var manifest Manifest
+			require.NoError(t, json.Unmarshal(manifestBuf.Bytes(), &manifest))
+
+			sum := sha256.Sum256([]byte(tt.inData))
+			assert.Equal(t, tt.expPartial, manifest.Partial)
+			if tt.expPartial {
+				assert.Len(t, manifest.SHA256, 64)
+			} else {
+				assert.Equal(t, hex.EncodeToString(sum[:]), manifest.SHA256)
+			}
+			assert.Equal(t, tt.expRowsRead, manifest.RowsRead)
+			assert.Equal(t, tt.expRowsDecoded, manifest.RowsDecoded)
+			assert.Equal(t, tt.expRejectedRows, manifest.RejectedRows)
+			assert.Equal(t, []string{"I", "S", "Tu"}, manifest.ColumnNames)
+			assert.Equal(t, map[string]string{"Tu": TimeFormatUnix}, manifest.ColumnFormats)
+			assert.NotEmpty(t, manifest.Duration)
+		})
+	}
+}
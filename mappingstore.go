@@ -0,0 +1,180 @@
+package csvee
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// MappingStore saves confirmed mappings keyed by the fingerprint of the header row they apply to.
+type MappingStore interface {
+	// Load returns the mapping saved for fingerprint and whether one exists.
+	Load(fingerprint string) (Mapping, bool, error)
+
+	// Save saves mapping for fingerprint, replacing any existing mapping.
+	Save(fingerprint string, mapping Mapping) error
+}
+
+// HeaderFingerprint returns a hash identifying a header row. Files from the same producer with the
+// same shape share a fingerprint.
+func HeaderFingerprint(headers []string) string {
+
+	sum := sha256.Sum256([]byte(strings.Join(headers, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// MemoryMappingStore is a MappingStore that keeps mappings in memory.
+type MemoryMappingStore struct {
+	mu       sync.RWMutex
+	mappings map[string]Mapping
+}
+
+// NewMemoryMappingStore returns an empty MemoryMappingStore.
+func NewMemoryMappingStore() *MemoryMappingStore {
+
+	return &MemoryMappingStore{mappings: make(map[string]Mapping)}
+}
+
+// Load returns the mapping saved for fingerprint.
+func (s *MemoryMappingStore) Load(fingerprint string) (Mapping, bool, error) {
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	mapping, exists := s.mappings[fingerprint]
+	return append(Mapping(nil), mapping...), exists, nil
+}
+
+// Save saves mapping for fingerprint.
+func (s *MemoryMappingStore) Save(fingerprint string, mapping Mapping) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.mappings[fingerprint] = append(Mapping(nil), mapping...)
+	return nil
+}
+
+// DirMappingStore is a MappingStore that keeps each mapping in a JSON file named after its
+// fingerprint within a directory.
+type DirMappingStore struct {
+	Dir string
+}
+
+// Load reads the mapping saved for fingerprint.
+func (s *DirMappingStore) Load(fingerprint string) (Mapping, bool, error) {
+
+	data, err := ioutil.ReadFile(s.path(fingerprint))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var mapping Mapping
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, false, errors.Wrapf(err, "Could not parse mapping %s", fingerprint)
+	}
+
+	return mapping, true, nil
+}
+
+// Save writes mapping for fingerprint, creating the directory if necessary.
+func (s *DirMappingStore) Save(fingerprint string, mapping Mapping) error {
+
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(mapping, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(s.path(fingerprint), data, 0644)
+}
+
+func (s *DirMappingStore) path(fingerprint string) string {
+
+	return filepath.Join(s.Dir, fingerprint+".json")
+}
+
+// Headers returns the header row as it was read, before any saved mapping was applied. It is empty
+// unless ReadHeaders was set.
+func (r *Reader) Headers() []string {
+
+	return append([]string(nil), r.headers...)
+}
+
+// RawHeaders returns the header row exactly as it appears in the input, including the surrounding
+// quotes and apostrophes that Headers strips, before any HeaderNormalizer or saved mapping renamed
+// its columns. It is empty unless ReadHeaders was set.
+func (r *Reader) RawHeaders() []string {
+
+	return append([]string(nil), r.rawHeaders...)
+}
+
+// RawHeader returns the raw header of the named column, as RawHeaders holds it, so that output and
+// error messages can refer to the column as the input names it. It reports false if the reader has
+// no column of that name or did not read headers.
+func (r *Reader) RawHeader(column string) (string, bool) {
+
+	for i, name := range r.ColumnNames {
+		if name == column && i < len(r.rawHeaders) {
+			return r.rawHeaders[i], true
+		}
+	}
+
+	return "", false
+}
+
+// HeaderFingerprint returns the fingerprint of the header row that was read.
+func (r *Reader) HeaderFingerprint() string {
+
+	return HeaderFingerprint(r.headers)
+}
+
+// SaveMapping saves mapping to the reader's mapping store for its header row and applies it, so that
+// future files with the same header row are configured automatically.
+func (r *Reader) SaveMapping(mapping Mapping) error {
+
+	if r.mappingStore == nil {
+		return ErrMappingStoreNil
+	}
+
+	if err := r.SetColumns(mapping.ColumnNames()); err != nil {
+		return err
+	}
+
+	return r.mappingStore.Save(r.HeaderFingerprint(), mapping)
+}
+
+// applySavedMapping renames the reader's columns according to the mapping saved for its header row,
+// if there is one.
+func (r *Reader) applySavedMapping() error {
+
+	if r.mappingStore == nil || len(r.headers) == 0 {
+		return nil
+	}
+
+	fingerprint := r.HeaderFingerprint()
+	mapping, exists, err := r.mappingStore.Load(fingerprint)
+	if err != nil || !exists {
+		return err
+	}
+
+	if len(mapping) != len(r.headers) {
+		return errors.Errorf("saved mapping %s has %d columns but the header has %d", fingerprint, len(mapping), len(r.headers))
+	}
+
+	r.ColumnNames = mapping.ColumnNames()
+	return nil
+}
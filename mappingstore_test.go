@@ -0,0 +1,58 @@
+package csvee
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_MappingStore verifies a saved mapping configures a reader for files with the same header row
+func TestReader_MappingStore(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "csvee-mappings")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	stores := map[string]MappingStore{
+		"memory": NewMemoryMappingStore(),
+		"dir":    &DirMappingStore{Dir: dir},
+	}
+
+	const inData = "first name,E-Mail\nann,a@x.io\n"
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+
+			options := &ReaderOptions{ReadHeaders: true, MappingStore: store}
+
+			reader, err := NewReader(strings.NewReader(inData), options)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"first name", "E-Mail"}, reader.ColumnNames)
+
+			mapping, _ := SuggestMapping(reader.Headers(), reflect.TypeOf(suggestReadTo{}))
+			require.NoError(t, reader.SaveMapping(mapping))
+
+			reader, err = NewReader(strings.NewReader(inData), options)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"FirstName", "email"}, reader.ColumnNames)
+			assert.Equal(t, []string{"first name", "E-Mail"}, reader.Headers())
+
+			var actualData []suggestReadTo
+			require.NoError(t, reader.ReadAll(&actualData))
+			assert.Equal(t, []suggestReadTo{{FirstName: "ann", Email: "a@x.io"}}, actualData)
+
+			reader, err = NewReader(strings.NewReader("other\nx\n"), options)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"other"}, reader.ColumnNames)
+		})
+	}
+
+	reader, err := NewReader(strings.NewReader(inData), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	assert.Equal(t, ErrMappingStoreNil, reader.SaveMapping(Mapping{}))
+}
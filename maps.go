@@ -0,0 +1,151 @@
+package csvee
+
+import (
+	"reflect"
+	"strings"
+)
+
+// isMapTarget reports whether records can be decoded into maps of type t, which must have string
+// keys and either string, []string, or interface{} values.
+func isMapTarget(t reflect.Type) bool {
+
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String {
+		return false
+	}
+
+	elem := t.Elem()
+	return elem.Kind() == reflect.String || elem.Kind() == reflect.Slice && elem.Elem().Kind() == reflect.String ||
+		elem.Kind() == reflect.Interface && elem.NumMethod() == 0
+}
+
+// decodeMap sets an entry of the map v points to for each column of record, allocating the map if
+// it is nil. Maps of strings hold the cells as they are, with null cells empty. Maps of interface{}
+// hold the cells of columns with a converter as it converts them, and the others as typed by the
+// reader's ColumnTypes. Cells skipped by their whitespace policy remove their entries, unless
+// merging, when they and empty cells leave them untouched. Repeated columns, and every column of maps
+// of []string, are set by setCollected.
+func (r *Reader) decodeMap(record []string, v interface{}) error {
+
+	m := reflect.ValueOf(v)
+	for m.Kind() == reflect.Ptr {
+		if m.IsNil() {
+			return ErrReadTargetNil
+		}
+		if m.Elem().Kind() == reflect.Ptr && m.Elem().IsNil() {
+			m.Elem().Set(reflect.New(m.Elem().Type().Elem()))
+		}
+		m = m.Elem()
+	}
+
+	if m.IsNil() {
+		if !m.CanSet() {
+			return ErrReadTargetNil
+		}
+		m.Set(reflect.MakeMapWithSize(m.Type(), len(record)))
+	}
+
+	keyType, elemType := m.Type().Key(), m.Type().Elem()
+	typed := elemType.Kind() == reflect.Interface
+	lists := elemType.Kind() == reflect.Slice
+
+	settings := r.settings()
+	for j, name := range r.ColumnNames {
+
+		key := reflect.ValueOf(name).Convert(keyType)
+
+		if indexes, repeated := r.columnRepeats[name]; repeated || lists {
+			if !repeated {
+				indexes = []int{j}
+			} else if indexes[0] != j {
+				continue
+			}
+			if err := r.setCollected(m, key, name, indexes, record); err != nil {
+				return err
+			}
+			continue
+		}
+
+		field, skip := applyWhitespacePolicy(settings[j].whitespace, record[j])
+		null := r.isNull(field)
+
+		if r.merge && (skip || null || field == "") {
+			continue
+		}
+		if skip {
+			m.SetMapIndex(key, reflect.Value{})
+			continue
+		}
+
+		if !typed {
+			if null {
+				field = ""
+			}
+			m.SetMapIndex(key, reflect.ValueOf(field).Convert(elemType))
+			continue
+		}
+
+		value, err := r.mapValue(j, name, field, null)
+		if err != nil {
+			return &FieldError{Row: r.rowsRead, Column: name, Value: field, Err: err}
+		}
+
+		m.SetMapIndex(key, reflect.ValueOf(&value).Elem())
+	}
+
+	return nil
+}
+
+// mapValue returns the value of field, from the named column at index j, in a map of interface{}.
+func (r *Reader) mapValue(j int, name, field string, null bool) (interface{}, error) {
+
+	if converter, exists := r.converters[name]; exists && !null {
+		return converter(field)
+	}
+
+	return r.typedCell(j, field, r.columnKinds)
+}
+
+// setCollected sets the entry of m for the named column from the cells of its columns at indexes,
+// leaving out those that are null or blank: as a slice in maps of []string, joined with the
+// column's slice delimiter in maps of strings, and as a []interface{} of values typed as
+// decodeMap types them in maps of interface{}. Entries without any cells are removed, unless
+// merging, when they are left untouched.
+func (r *Reader) setCollected(m, key reflect.Value, name string, indexes []int, record []string) error {
+
+	cells := r.collectedCells(indexes, record)
+	if len(cells) == 0 {
+		if !r.merge {
+			m.SetMapIndex(key, reflect.Value{})
+		}
+		return nil
+	}
+
+	elemType := m.Type().Elem()
+	switch elemType.Kind() {
+	case reflect.Slice:
+		values := reflect.MakeSlice(elemType, len(cells), len(cells))
+		for i, cell := range cells {
+			values.Index(i).SetString(cell)
+		}
+		m.SetMapIndex(key, values)
+	case reflect.String:
+		delimiter := r.settings()[indexes[0]].sliceDelimiter
+		quoted := make([]string, len(cells))
+		for i, cell := range cells {
+			quoted[i] = quoteSliceElement(cell, delimiter)
+		}
+		m.SetMapIndex(key, reflect.ValueOf(strings.Join(quoted, delimiter)).Convert(elemType))
+	default:
+		values := make([]interface{}, len(cells))
+		for i, cell := range cells {
+			value, err := r.mapValue(indexes[0], name, cell, false)
+			if err != nil {
+				return &FieldError{Row: r.rowsRead, Column: name, Value: cell, Err: err}
+			}
+			values[i] = value
+		}
+		m.SetMapIndex(key, reflect.ValueOf(values))
+	}
+
+	return nil
+}
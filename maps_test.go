@@ -0,0 +1,87 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_ReadMaps verifies records are decoded into maps, typed by ColumnTypes
+func TestReader_ReadMaps(t *testing.T) {
+
+	input := "Name,Age,Score,Active,Joined,Code\nAnn,34,9.5,true,2021-03-04,x1\nBob,,,false,,NULL\n"
+	options := &ReaderOptions{
+		ReadHeaders:   true,
+		ColumnFormats: map[string]string{"Joined": "2006-01-02"},
+		NullValues:    []string{"NULL"},
+		ColumnTypes: map[string]ColumnKind{
+			"Age": KindInt, "Score": KindFloat, "Active": KindBool, "Joined": KindTime,
+		},
+		ColumnConverters: map[string]Converter{"Code": func(field string) (interface{}, error) {
+			return strings.ToUpper(field), nil
+		}},
+	}
+
+	reader, err := NewReader(strings.NewReader(input), options)
+	require.NoError(t, err)
+
+	var rows []map[string]interface{}
+	require.NoError(t, reader.ReadAll(&rows))
+	assert.Equal(t, []map[string]interface{}{
+		{"Name": "Ann", "Age": int64(34), "Score": 9.5, "Active": true, "Joined": time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC), "Code": "X1"},
+		{"Name": "Bob", "Age": nil, "Score": nil, "Active": false, "Joined": nil, "Code": nil},
+	}, rows)
+
+	reader, err = NewReader(strings.NewReader(input), options)
+	require.NoError(t, err)
+
+	var row map[string]string
+	require.NoError(t, reader.Read(&row))
+	assert.Equal(t, map[string]string{"Name": "Ann", "Age": "34", "Score": "9.5", "Active": "true", "Joined": "2021-03-04", "Code": "x1"}, row)
+
+	existing := map[string]string{"Extra": "kept"}
+	require.NoError(t, reader.Read(existing))
+	assert.Equal(t, map[string]string{"Extra": "kept", "Name": "Bob", "Age": "", "Score": "", "Active": "false", "Joined": "", "Code": ""}, existing)
+
+	reader, err = NewReader(strings.NewReader("Age\nold\n"), &ReaderOptions{ReadHeaders: true, ColumnTypes: map[string]ColumnKind{"Age": KindInt}})
+	require.NoError(t, err)
+	err = reader.Read(&map[string]interface{}{})
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "Age", fieldErr.Column)
+	assert.EqualError(t, fieldErr.Err, `invalid value "old" for int64`)
+
+	reader, err = NewReader(strings.NewReader("Age\n1\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	assert.Equal(t, ErrUnsupportedTargetType, reader.Read(&map[string]int{}))
+
+	_, err = NewReader(strings.NewReader("Age\n1\n"), &ReaderOptions{ReadHeaders: true, ColumnTypes: map[string]ColumnKind{"Missing": KindInt}})
+	assert.EqualError(t, err, `column type provided for unknown column "Missing"`)
+}
+
+// TestReader_ReadMapsMerge verifies merging leaves entries for empty cells untouched and whitespace policies remove entries
+func TestReader_ReadMapsMerge(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader("A,B,C\n1,, \n"), &ReaderOptions{ReadHeaders: true, Merge: true})
+	require.NoError(t, err)
+
+	row := map[string]string{"A": "0", "B": "kept", "C": "kept"}
+	require.NoError(t, reader.Read(&row))
+	assert.Equal(t, map[string]string{"A": "1", "B": "kept", "C": " "}, row)
+
+	reader, err = NewReader(strings.NewReader("A,B\n1, \n"), &ReaderOptions{ReadHeaders: true, WhitespacePolicy: WhitespaceNull})
+	require.NoError(t, err)
+
+	row = map[string]string{"B": "stale"}
+	require.NoError(t, reader.Read(row))
+	assert.Equal(t, map[string]string{"A": "1"}, row)
+
+	typed, err := NewTypedReader[map[string]interface{}](strings.NewReader("A\n1\n"), &ReaderOptions{ReadHeaders: true, ColumnTypes: map[string]ColumnKind{"A": KindFloat}})
+	require.NoError(t, err)
+	values, err := typed.ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"A": 1.0}}, values)
+}
@@ -0,0 +1,74 @@
+package csvee
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mergeReadTo struct {
+	S  string
+	I  int
+	SA []string
+	N  sql.NullString
+}
+
+// TestReader_Merge verifies only non-empty cells overwrite pre-populated fields when merging
+func TestReader_Merge(t *testing.T) {
+
+	defaults := mergeReadTo{S: "s", I: 1, SA: []string{"a"}, N: sql.NullString{String: "n", Valid: true}}
+
+	var testCases = []struct {
+		name    string
+		inData  string
+		options ReaderOptions
+		expData mergeReadTo
+	}{
+		{
+			name:    "empty cells",
+			inData:  "S,I,SA,N\n,,,\n",
+			options: ReaderOptions{Merge: true},
+			expData: defaults,
+		},
+		{
+			name:    "non-empty cells",
+			inData:  "S,I,SA,N\nt,2,\"b,c\",m\n",
+			options: ReaderOptions{Merge: true},
+			expData: mergeReadTo{S: "t", I: 2, SA: []string{"b", "c"}, N: sql.NullString{String: "m", Valid: true}},
+		},
+		{
+			name:    "fast path",
+			inData:  "S,I,SA,N\n,3,,\n",
+			options: ReaderOptions{Merge: true, UnsafeFastPath: true},
+			expData: mergeReadTo{S: "s", I: 3, SA: []string{"a"}, N: sql.NullString{String: "n", Valid: true}},
+		},
+		{
+			name:    "whitespace null",
+			inData:  "S,I,SA,N\n  ,,,  \n",
+			options: ReaderOptions{Merge: true, WhitespacePolicy: WhitespaceNull},
+			expData: defaults,
+		},
+		{
+			name:    "without merge",
+			inData:  "S,I,SA,N\n,,,\n",
+			expData: mergeReadTo{S: "", I: 1, SA: []string{""}},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.options.ReadHeaders = true
+			reader, err := NewReader(strings.NewReader(tt.inData), &tt.options)
+			require.NoError(t, err)
+
+			actual := defaults
+			actual.SA = append([]string(nil), defaults.SA...)
+			require.NoError(t, reader.Read(&actual))
+			assert.Equal(t, tt.expData, actual)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package csvee
+
+import (
+	"errors"
+	"sync"
+)
+
+// Code identifies an error in the message catalog, such as "CSVEE-001". Unlike messages, codes never
+// change between releases, so they can be matched on and used to look up translations.
+type Code string
+
+// Error is an error from the message catalog. Each of the package's sentinel errors, such as
+// ErrColumnNamesMismatch, is an *Error.
+type Error struct {
+	Code    Code
+	message string
+}
+
+// Error returns the error's message, as translated by the Translator if one is set.
+func (e *Error) Error() string {
+
+	translatorMu.RLock()
+	translate := translator
+	translatorMu.RUnlock()
+
+	if translate != nil {
+		if message := translate(e.Code, e.message); message != "" {
+			return message
+		}
+	}
+
+	return e.message
+}
+
+// Translator returns the message for the error with the given code, whose English message is
+// message, so that errors shown to end users can be localized or reworded. Returning an empty
+// string keeps the English message.
+type Translator func(code Code, message string) string
+
+var (
+	translatorMu sync.RWMutex
+	translator   Translator
+
+	catalog = map[Code]*Error{}
+)
+
+// SetTranslator sets the Translator used for the messages of every catalog error, replacing any set
+// before. A nil Translator restores the English messages.
+func SetTranslator(translate Translator) {
+
+	translatorMu.Lock()
+	defer translatorMu.Unlock()
+
+	translator = translate
+}
+
+// Messages returns the English message of every error in the catalog, keyed by code, as a starting
+// point for translations.
+func Messages() map[Code]string {
+
+	messages := make(map[Code]string, len(catalog))
+	for code, err := range catalog {
+		messages[code] = err.message
+	}
+
+	return messages
+}
+
+// ErrorCode returns the code of the first catalog error in err's chain, such as one wrapped in a
+// FieldError, and false if there is none.
+func ErrorCode(err error) (Code, bool) {
+
+	var catalogErr *Error
+	if errors.As(err, &catalogErr) {
+		return catalogErr.Code, true
+	}
+
+	return "", false
+}
+
+// newError adds an error with the given code and English message to the catalog.
+func newError(code Code, message string) error {
+
+	if _, exists := catalog[code]; exists {
+		panic("csvee: duplicate error code " + string(code))
+	}
+
+	err := &Error{Code: code, message: message}
+	catalog[code] = err
+	return err
+}
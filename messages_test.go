@@ -0,0 +1,84 @@
+package csvee
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMessages verifies every catalog error has a well formed code and a message
+func TestMessages(t *testing.T) {
+
+	messages := Messages()
+	require.NotEmpty(t, messages)
+	assert.Equal(t, "The number of column names does not match the number of fields in the record.", messages["CSVEE-001"])
+
+	for code, message := range messages {
+		assert.Regexp(t, regexp.MustCompile(`^CSVEE-\d{3}$`), string(code))
+		assert.NotEmpty(t, message)
+	}
+}
+
+// TestErrorCode verifies codes are found through wrapped errors
+func TestErrorCode(t *testing.T) {
+
+	var testCases = []struct {
+		name    string
+		err     error
+		expCode Code
+		expOK   bool
+	}{
+		{name: "sentinel", err: ErrReaderNil, expCode: "CSVEE-006", expOK: true},
+		{name: "field error", err: &FieldError{Row: 1, Column: "A", Err: ErrInvalidFieldType}, expCode: "CSVEE-003", expOK: true},
+		{name: "wrapped", err: errors.Wrap(ErrColumnNamesMismatch, "row 2"), expCode: "CSVEE-001", expOK: true},
+		{name: "other", err: errors.New("boom")},
+		{name: "nil"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			code, ok := ErrorCode(tt.err)
+			assert.Equal(t, tt.expCode, code)
+			assert.Equal(t, tt.expOK, ok)
+		})
+	}
+}
+
+// TestSetTranslator verifies a translator replaces messages until it is removed
+func TestSetTranslator(t *testing.T) {
+
+	defer SetTranslator(nil)
+
+	SetTranslator(func(code Code, message string) string {
+		if code == "CSVEE-006" {
+			return "Le io.Reader fourni à NewReader ne doit pas être nil."
+		}
+		return ""
+	})
+
+	_, err := NewReader(nil, &ReaderOptions{})
+	assert.Equal(t, "Le io.Reader fourni à NewReader ne doit pas être nil.", err.Error())
+	assert.Equal(t, ErrReaderNil, err)
+	assert.Equal(t, "Column names must be provided to NewWriter.", ErrWriterColumnNamesRequired.Error())
+
+	SetTranslator(nil)
+	assert.Equal(t, "The io.Reader provided to NewReader must be non nil.", ErrReaderNil.Error())
+}
+
+// TestOptions_DeterministicErrors verifies options with several problems always report the same one
+func TestOptions_DeterministicErrors(t *testing.T) {
+
+	for i := 0; i < 20; i++ {
+		_, err := NewReader(strings.NewReader("A\n"), &ReaderOptions{
+			ReadHeaders:   true,
+			ColumnFormats: map[string]string{"d": "unix", "c": "unix", "b": "unix"},
+		})
+		require.Error(t, err)
+		assert.Equal(t, `column format provided for unknown column "b"`, err.Error())
+	}
+}
@@ -0,0 +1,247 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nestedAddress struct {
+	Street string `csvee:"street"`
+	City   string `csvee:"city"`
+	Zip    int
+}
+
+type nestedOwner struct {
+	Name  string
+	Since time.Time
+}
+
+type nestedRow struct {
+	ID      int
+	Address nestedAddress `csvee:"address"`
+	Owner   *nestedOwner  `csvee:"owner"`
+}
+
+// TestReader_NestedColumns verifies compound column names populate nested struct fields
+func TestReader_NestedColumns(t *testing.T) {
+
+	var testCases = []struct {
+		name    string
+		data    string
+		options ReaderOptions
+		expRows []nestedRow
+		expErr  bool
+	}{
+		{
+			name: "dotted",
+			data: "ID,address.street,address.city,address.Zip,owner.Name,owner.Since,owner.Missing\n" +
+				"1,Main St,Springfield,12345,Ann,2020-01-02T00:00:00Z,x\n",
+			expRows: []nestedRow{{
+				ID:      1,
+				Address: nestedAddress{Street: "Main St", City: "Springfield", Zip: 12345},
+				Owner:   &nestedOwner{Name: "Ann", Since: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+			}},
+		},
+		{
+			name:    "fast path",
+			data:    "ID,address.Zip\n1,12345\n",
+			options: ReaderOptions{UnsafeFastPath: true},
+			expRows: []nestedRow{{ID: 1, Address: nestedAddress{Zip: 12345}}},
+		},
+		{
+			name:    "custom separator",
+			data:    "ID,address__city,address.city\n1,Springfield,ignored\n",
+			options: ReaderOptions{NestedSeparator: "__"},
+			expRows: []nestedRow{{ID: 1, Address: nestedAddress{City: "Springfield"}}},
+		},
+		{
+			name:    "untagged name does not match tagged field",
+			data:    "ID,Address.city,owner.Since.Year\n1,Springfield,2020\n",
+			expRows: []nestedRow{{ID: 1}},
+		},
+		{
+			name:   "invalid nested value",
+			data:   "ID,address.Zip\n1,abc\n",
+			expErr: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.options.ReadHeaders = true
+			reader, err := NewReader(strings.NewReader(tt.data), &tt.options)
+			require.NoError(t, err)
+
+			var rows []nestedRow
+			err = reader.ReadAll(&rows)
+			if tt.expErr {
+				assert.EqualError(t, err, `row 1, column "address.Zip": invalid value "abc" for int`)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expRows, rows)
+		})
+	}
+
+	reader, err := NewReader(strings.NewReader("address.city\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	matches, err := reader.ColumnMatches(nestedRow{})
+	require.NoError(t, err)
+	assert.Equal(t, []ColumnMatch{{Column: "address.city", Field: "Address.City", Method: MatchExact, Similarity: 1}}, matches)
+}
+
+type prefixedOrder struct {
+	ID       int
+	Billing  nestedAddress  `csvee:"prefix=billing_"`
+	Shipping *nestedAddress `csvee:"shipping,prefix=ship_"`
+	Shipper  struct {
+		Name    string        `csvee:"name"`
+		Address nestedAddress `csvee:"prefix=addr_"`
+	} `csvee:"prefix=ship_by_"`
+}
+
+// TestReader_PrefixedColumns verifies columns are bound to the fields of structs tagged with their prefix
+func TestReader_PrefixedColumns(t *testing.T) {
+
+	input := "ID,billing_street,billing_city,billing_Zip,ship_street,ship_by_name,ship_by_addr_city,ship_by_address.city\n" +
+		"1,1 Main St,Springfield,12345,2 Elm St,Acme,Shelbyville,\n"
+
+	reader, err := NewReader(strings.NewReader(input), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	var orders []prefixedOrder
+	require.NoError(t, reader.ReadAll(&orders))
+	require.Len(t, orders, 1)
+
+	order := orders[0]
+	assert.Equal(t, 1, order.ID)
+	assert.Equal(t, nestedAddress{Street: "1 Main St", City: "Springfield", Zip: 12345}, order.Billing)
+	assert.Equal(t, &nestedAddress{Street: "2 Elm St"}, order.Shipping)
+	assert.Equal(t, "Acme", order.Shipper.Name)
+	assert.Equal(t, nestedAddress{City: "Shelbyville"}, order.Shipper.Address)
+
+	matches, err := reader.ColumnMatches(prefixedOrder{})
+	require.NoError(t, err)
+	assert.Equal(t, ColumnMatch{Column: "ship_by_addr_city", Field: "Shipper.Address.City", Method: MatchExact, Similarity: 1}, matches[6])
+	assert.Equal(t, ColumnMatch{Column: "ship_by_address.city", Method: MatchNone}, matches[7])
+}
+
+type groupedItem struct {
+	Name string `csvee:"name"`
+	Qty  int    `csvee:"qty"`
+}
+
+type groupedOrder struct {
+	ID     int
+	Items  []groupedItem   `csvee:"prefix=item{n}_"`
+	Extras [2]*groupedItem `csvee:"prefix=extra_{n}."`
+}
+
+// TestReader_IndexedColumnGroups verifies numbered groups of columns are decoded into slices of structs
+func TestReader_IndexedColumnGroups(t *testing.T) {
+
+	input := "ID,item1_name,item1_qty,item2_name,item2_qty,item3_name,item3_qty,extra_2.name,extra_3.name\n" +
+		"1,apple,3,pear,,,,bag,ignored\n" +
+		"2,,,kiwi,1,,,,\n" +
+		"3,,,,,,,,\n"
+
+	reader, err := NewReader(strings.NewReader(input), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	var orders []groupedOrder
+	require.NoError(t, reader.ReadAll(&orders))
+	assert.Equal(t, []groupedOrder{
+		{ID: 1, Items: []groupedItem{{Name: "apple", Qty: 3}, {Name: "pear"}}, Extras: [2]*groupedItem{nil, {Name: "bag"}}},
+		{ID: 2, Items: []groupedItem{{}, {Name: "kiwi", Qty: 1}}},
+		{ID: 3},
+	}, orders)
+
+	reader, err = NewReader(strings.NewReader(input), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	var order groupedOrder
+	require.NoError(t, reader.Read(&order))
+	require.NoError(t, reader.Read(&order))
+	assert.Equal(t, groupedOrder{ID: 2, Items: []groupedItem{{}, {Name: "kiwi", Qty: 1}}}, order)
+
+	matches, err := reader.ColumnMatches(groupedOrder{})
+	require.NoError(t, err)
+	assert.Equal(t, ColumnMatch{Column: "item2_qty", Field: "Items[1].Qty", Method: MatchExact, Similarity: 1}, matches[4])
+	assert.Equal(t, ColumnMatch{Column: "extra_3.name", Method: MatchNone}, matches[8])
+
+	reader, err = NewReader(strings.NewReader("item1_qty\nmany\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	assert.EqualError(t, reader.Read(&order), `row 1, column "item1_qty": invalid value "many" for int`)
+}
+
+// TestReader_MaxGroupElements verifies header indexes beyond MaxGroupElements are left unmapped
+func TestReader_MaxGroupElements(t *testing.T) {
+
+	var testCases = []struct {
+		name     string
+		inHeader string
+		inMax    int
+		expItems []groupedItem
+		expMatch ColumnMatch
+		expErr   string
+	}{
+		{
+			name:     "within default",
+			inHeader: "ID,item1000_name",
+			expItems: append(make([]groupedItem, 999), groupedItem{Name: "x"}),
+			expMatch: ColumnMatch{Column: "item1000_name", Field: "Items[999].Name", Method: MatchExact, Similarity: 1},
+		},
+		{
+			name:     "oversized index",
+			inHeader: "ID,item1000000000000_name",
+			expMatch: ColumnMatch{Column: "item1000000000000_name", Method: MatchNone},
+		},
+		{
+			name:     "beyond option",
+			inHeader: "ID,item3_name",
+			inMax:    2,
+			expMatch: ColumnMatch{Column: "item3_name", Method: MatchNone},
+		},
+		{
+			name:     "within option",
+			inHeader: "ID,item2_name",
+			inMax:    2,
+			expItems: []groupedItem{{}, {Name: "x"}},
+			expMatch: ColumnMatch{Column: "item2_name", Field: "Items[1].Name", Method: MatchExact, Similarity: 1},
+		},
+		{
+			name:     "negative",
+			inHeader: "ID,item1_name",
+			inMax:    -1,
+			expErr:   "max group elements must not be negative, got -1",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			input := tt.inHeader + "\n1,x\n"
+			reader, err := NewReader(strings.NewReader(input), &ReaderOptions{ReadHeaders: true, MaxGroupElements: tt.inMax})
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var orders []groupedOrder
+			require.NoError(t, reader.ReadAll(&orders))
+			assert.Equal(t, []groupedOrder{{ID: 1, Items: tt.expItems}}, orders)
+
+			matches, err := reader.ColumnMatches(groupedOrder{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expMatch, matches[1])
+		})
+	}
+}
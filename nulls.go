@@ -0,0 +1,118 @@
+package csvee
+
+import (
+	"reflect"
+	"strings"
+)
+
+// nullValueIndex reports whether t is a nullable wrapper, such as sql.NullString or sql.NullTime:
+// a struct with a Valid bool field and one other field holding the value. It returns the index of
+// the value field.
+func nullValueIndex(t reflect.Type) (int, bool) {
+
+	if t.Kind() != reflect.Struct || t.NumField() != 2 || isTimeType(t) {
+		return 0, false
+	}
+
+	valid, exists := t.FieldByName("Valid")
+	if !exists || len(valid.Index) != 1 || valid.Type.Kind() != reflect.Bool {
+		return 0, false
+	}
+
+	index := 1 - valid.Index[0]
+	value := t.Field(index)
+	if value.PkgPath != "" || !typeIsValid(value.Type) {
+		return 0, false
+	}
+
+	return index, true
+}
+
+// setNullable sets a nullable wrapper field of the struct pointed to by structPtr. Empty cells, and
+// cells the whitespace policy treats as null, leave the wrapper invalid, unless merging, in which
+// case they leave it untouched.
+func (r *Reader) setNullable(structPtr reflect.Value, col columnPlan, field string, skip bool) error {
+
+	if skip && r.merge {
+		return nil
+	}
+
+	wrapper := settableField(structPtr.Elem(), col.field.Index)
+	wrapper.Set(reflect.Zero(wrapper.Type()))
+
+	if skip || field == "" || col.fieldType.Kind() != reflect.String && strings.TrimSpace(field) == "" {
+		return nil
+	}
+
+	value, err := parseCell(field, col.fieldType, r.cellParsing(col.column, col.name))
+	if err == errNullDate {
+		return nil
+	}
+	if err != nil {
+		return &FieldError{Row: r.rowsRead, Column: col.name, Value: field, Err: err}
+	}
+
+	wrapper.Field(col.nullValue).Set(value)
+	wrapper.FieldByName("Valid").SetBool(true)
+	return nil
+}
+
+// settableField returns the field of v at index, allocating any nil pointers on the way to it,
+// including a pointer to the field itself.
+func settableField(v reflect.Value, index []int) reflect.Value {
+
+	for i, x := range index {
+		if i > 0 {
+			v = allocate(v)
+		}
+		v = v.Field(x)
+	}
+
+	return allocate(v)
+}
+
+// fieldAt returns the field of v at index, allocating any nil pointers on the way to it but not the
+// field itself.
+func fieldAt(v reflect.Value, index []int) reflect.Value {
+
+	for i, x := range index {
+		if i > 0 {
+			v = allocate(v)
+		}
+		v = v.Field(x)
+	}
+
+	return v
+}
+
+// zeroField sets the column's field on the struct pointed to by structPtr to its zero value, which
+// is nil for pointers and invalid for nullable wrappers.
+func zeroField(structPtr reflect.Value, col columnPlan) {
+
+	v := fieldAt(structPtr.Elem(), col.field.Index)
+	v.Set(reflect.Zero(v.Type()))
+}
+
+// isNull reports whether field is one of the reader's null values. Fields are compared with their
+// surrounding whitespace trimmed, and if there are null values, blank fields are null too.
+func (r *Reader) isNull(field string) bool {
+
+	if r.nullValues == nil {
+		return false
+	}
+
+	field = strings.TrimSpace(field)
+	return field == "" || r.nullValues[field]
+}
+
+func allocate(v reflect.Value) reflect.Value {
+
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	return v
+}
@@ -0,0 +1,175 @@
+package csvee
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nullReadTo struct {
+	S  sql.NullString
+	I  sql.NullInt64
+	F  sql.NullFloat64
+	B  sql.NullBool
+	T  sql.NullTime
+	P  *sql.NullInt32
+	B8 sql.NullByte
+}
+
+// TestReader_NullTypes verifies sql.Null* fields are valid only for non-empty cells
+func TestReader_NullTypes(t *testing.T) {
+
+	when := time.Unix(1613235342, 0)
+
+	var testCases = []struct {
+		name    string
+		inData  string
+		options ReaderOptions
+		expData []nullReadTo
+		expErr  string
+	}{
+		{
+			name:   "valid and null",
+			inData: "S,I,F,B,T,P,B8\na,1,1.5,true,1613235342,2,3\n,,,,,,\n",
+			expData: []nullReadTo{
+				{
+					S:  sql.NullString{String: "a", Valid: true},
+					I:  sql.NullInt64{Int64: 1, Valid: true},
+					F:  sql.NullFloat64{Float64: 1.5, Valid: true},
+					B:  sql.NullBool{Bool: true, Valid: true},
+					T:  sql.NullTime{Time: when, Valid: true},
+					P:  &sql.NullInt32{Int32: 2, Valid: true},
+					B8: sql.NullByte{Byte: 3, Valid: true},
+				},
+				{P: &sql.NullInt32{}},
+			},
+		},
+		{
+			name:    "whitespace null",
+			inData:  "S,I,F,B,T,P,B8\n  ,  ,,,,,\n",
+			options: ReaderOptions{WhitespacePolicy: WhitespaceNull},
+			expData: []nullReadTo{{P: &sql.NullInt32{}}},
+		},
+		{
+			name:    "literal whitespace string",
+			inData:  "S,I,F,B,T,P,B8\n  ,  ,,,,,\n",
+			expData: []nullReadTo{{S: sql.NullString{String: "  ", Valid: true}, P: &sql.NullInt32{}}},
+		},
+		{
+			name:    "fast path",
+			inData:  "S,I,F,B,T,P,B8\na,1,,yes,,,\n",
+			options: ReaderOptions{UnsafeFastPath: true, BoolParsing: BoolLenient},
+			expData: []nullReadTo{{S: sql.NullString{String: "a", Valid: true}, I: sql.NullInt64{Int64: 1, Valid: true}, B: sql.NullBool{Bool: true, Valid: true}, P: &sql.NullInt32{}}},
+		},
+		{
+			name:   "invalid value",
+			inData: "S,I,F,B,T,P,B8\na,x,,,,,\n",
+			expErr: `row 1, column "I": invalid value "x" for int64`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.options.ReadHeaders = true
+			tt.options.ColumnFormats = map[string]string{"T": TimeFormatUnix}
+
+			reader, err := NewReader(strings.NewReader(tt.inData), &tt.options)
+			require.NoError(t, err)
+
+			var actualData []nullReadTo
+			err = reader.ReadAll(&actualData)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expData, actualData)
+		})
+	}
+}
+
+// TestReader_NullTypesOverwrite verifies a null cell resets a previously valid value
+func TestReader_NullTypesOverwrite(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader("I\n1\n\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	actual := struct{ I sql.NullInt64 }{I: sql.NullInt64{Int64: 9, Valid: true}}
+	require.NoError(t, reader.Read(&actual))
+	assert.Equal(t, sql.NullInt64{Int64: 1, Valid: true}, actual.I)
+
+	reader, err = NewReader(strings.NewReader("I,J\n,1\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	actual.I = sql.NullInt64{Int64: 9, Valid: true}
+	require.NoError(t, reader.Read(&actual))
+	assert.Equal(t, sql.NullInt64{}, actual.I)
+}
+
+type nullValuesRow struct {
+	Name  *string
+	Qty   *int
+	Price float64
+	When  *time.Time
+	Count sql.NullInt64
+	Label string
+}
+
+// TestReader_NullValues verifies blank cells and null tokens decode as nil, invalid, or zero
+func TestReader_NullValues(t *testing.T) {
+
+	const data = "Name,Qty,Price,When,Count,Label\nNULL, N/A ,\\N,,NULL,NULL\na,1,2.5,2020-01-02T00:00:00Z,3,x\n"
+
+	a, one := "a", 1
+	when := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	full := nullValuesRow{Name: &a, Qty: &one, Price: 2.5, When: &when, Count: sql.NullInt64{Int64: 3, Valid: true}, Label: "x"}
+
+	for _, fastPath := range []bool{false, true} {
+
+		reader, err := NewReader(strings.NewReader(data), &ReaderOptions{
+			ReadHeaders:    true,
+			NullValues:     []string{"NULL", "N/A", `\N`},
+			UnsafeFastPath: fastPath,
+		})
+		require.NoError(t, err)
+
+		var rows []nullValuesRow
+		require.NoError(t, reader.ReadAll(&rows))
+		assert.Equal(t, []nullValuesRow{{}, full}, rows)
+
+		// Reading into a populated value clears the fields of null cells.
+		reader, err = NewReader(strings.NewReader(data), &ReaderOptions{ReadHeaders: true, NullValues: []string{"NULL", "N/A", `\N`}})
+		require.NoError(t, err)
+
+		row := full
+		require.NoError(t, reader.Read(&row))
+		assert.Equal(t, nullValuesRow{}, row)
+	}
+
+	// Merging leaves the fields of null cells untouched.
+	reader, err := NewReader(strings.NewReader(data), &ReaderOptions{ReadHeaders: true, NullValues: []string{"NULL", "N/A", `\N`}, Merge: true})
+	require.NoError(t, err)
+
+	row := full
+	require.NoError(t, reader.Read(&row))
+	assert.Equal(t, full, row)
+
+	// Without null values, tokens are parsed as usual.
+	reader, err = NewReader(strings.NewReader(data), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	assert.Error(t, reader.Read(&nullValuesRow{}))
+
+	reader, err = NewReader(strings.NewReader(data), &ReaderOptions{ReadHeaders: true, NullValues: []string{"NULL", "N/A", `\N`}})
+	require.NoError(t, err)
+
+	var qty []*int
+	var labels []string
+	require.NoError(t, reader.ReadColumns(map[string]interface{}{"Qty": &qty, "Label": &labels}))
+	assert.Equal(t, []*int{nil, &one}, qty)
+	assert.Equal(t, []string{"", "x"}, labels)
+}
@@ -0,0 +1,112 @@
+package csvee
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// NumberParser parses the numeric fields of a column. It allows faster or more lenient parsers to
+// be plugged in for columns that hold large amounts of numeric data. Any function that is nil falls
+// back to the strconv equivalent. Parsers must respect bitSize, returning a *strconv.NumError with
+// strconv.ErrRange for values that do not fit.
+type NumberParser struct {
+	ParseInt   func(s string, bitSize int) (int64, error)
+	ParseUint  func(s string, bitSize int) (uint64, error)
+	ParseFloat func(s string, bitSize int) (float64, error)
+}
+
+// defaultNumberParser parses numbers with strconv.
+var defaultNumberParser = &NumberParser{}
+
+// baseNumberParser returns a NumberParser that parses integers in the given base.
+func baseNumberParser(base int) *NumberParser {
+
+	return &NumberParser{
+		ParseInt: func(s string, bitSize int) (int64, error) {
+			return strconv.ParseInt(s, base, bitSize)
+		},
+		ParseUint: func(s string, bitSize int) (uint64, error) {
+			return strconv.ParseUint(s, base, bitSize)
+		},
+	}
+}
+
+func (p *NumberParser) parseInt(s string, bitSize int) (int64, error) {
+
+	if p.ParseInt != nil {
+		return p.ParseInt(s, bitSize)
+	}
+
+	return strconv.ParseInt(s, 10, bitSize)
+}
+
+func (p *NumberParser) parseUint(s string, bitSize int) (uint64, error) {
+
+	if p.ParseUint != nil {
+		return p.ParseUint(s, bitSize)
+	}
+
+	return strconv.ParseUint(s, 10, bitSize)
+}
+
+func (p *NumberParser) parseFloat(s string, bitSize int) (float64, error) {
+
+	if p.ParseFloat != nil {
+		return p.ParseFloat(s, bitSize)
+	}
+
+	return strconv.ParseFloat(s, bitSize)
+}
+
+// numberParser returns the parser configured for the named column, or the default parser.
+func (r *Reader) numberParser(column string) *NumberParser {
+
+	if p, exists := r.numberParsers[column]; exists {
+		return p
+	}
+
+	return defaultNumberParser
+}
+
+// numberError describes why value could not be parsed into a field of type t, naming the row and
+// column it came from, and suggests a parser for numbers that are formatted for a locale.
+func (r *Reader) numberError(column, value string, t reflect.Type, err error) error {
+
+	described := describeNumberError(value, t, err)
+
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) && numErr.Err == strconv.ErrSyntax {
+		described = r.suggestNumberRepair(column, value, t, described)
+	}
+
+	return &FieldError{Row: r.rowsRead, Column: column, Value: value, Err: described}
+}
+
+// describeNumberError describes why value could not be parsed into a value of type t.
+func describeNumberError(value string, t reflect.Type, err error) error {
+
+	var numErr *strconv.NumError
+	switch {
+	case errors.As(err, &numErr) && numErr.Err == strconv.ErrRange:
+		err = errors.Errorf("value %s overflows %s", value, t)
+	case isUnsignedKind(t.Kind()) && strings.HasPrefix(strings.TrimSpace(value), "-"):
+		err = errors.Errorf("negative value %s cannot be stored in %s", value, t)
+	case errors.As(err, &numErr):
+		err = errors.Errorf("invalid value %q for %s", value, t)
+	}
+
+	return err
+}
+
+func isUnsignedKind(k reflect.Kind) bool {
+
+	return k == reflect.Uint || k == reflect.Uint8 || k == reflect.Uint16 || k == reflect.Uint32 || k == reflect.Uint64
+}
+
+func isIntegerKind(k reflect.Kind) bool {
+
+	return k == reflect.Int || k == reflect.Int8 || k == reflect.Int16 || k == reflect.Int32 || k == reflect.Int64 || isUnsignedKind(k)
+}
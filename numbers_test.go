@@ -0,0 +1,205 @@
+package csvee
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseDecimal parses plain decimal numbers without exponents, as commonly found in sensor data.
+func parseDecimal(s string, bitSize int) (float64, error) {
+
+	var whole, frac, scale float64 = 0, 0, 1
+	negative := strings.HasPrefix(s, "-")
+	if negative {
+		s = s[1:]
+	}
+
+	seenPoint := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' && !seenPoint:
+			seenPoint = true
+		case c >= '0' && c <= '9' && !seenPoint:
+			whole = whole*10 + float64(c-'0')
+		case c >= '0' && c <= '9':
+			scale *= 10
+			frac = frac*10 + float64(c-'0')
+		default:
+			return 0, errors.New("invalid decimal " + strconv.Quote(s))
+		}
+	}
+
+	f := whole + frac/scale
+	if negative {
+		f = -f
+	}
+	return f, nil
+}
+
+// TestReader_NumberParsers verifies per column number parsers are used on every decode path
+func TestReader_NumberParsers(t *testing.T) {
+
+	thousands := &NumberParser{
+		ParseInt: func(s string, bitSize int) (int64, error) {
+			return strconv.ParseInt(strings.ReplaceAll(s, "_", ""), 10, bitSize)
+		},
+		ParseFloat: parseDecimal,
+	}
+
+	for _, fastPath := range []bool{false, true} {
+
+		reader, err := NewReader(
+			strings.NewReader(`1_000,-2.5,"1_0,2_0"`),
+			&ReaderOptions{
+				ColumnNames:    []string{"I", "F", "IA"},
+				NumberParsers:  map[string]*NumberParser{"I": thousands, "F": thousands, "IA": thousands},
+				UnsafeFastPath: fastPath,
+			},
+		)
+		require.NoError(t, err)
+
+		var actualData readTo
+		require.NoError(t, reader.Read(&actualData))
+		assert.Equal(t, 1000, actualData.I)
+		assert.Equal(t, -2.5, actualData.F)
+		assert.Equal(t, []int{10, 20}, actualData.IA)
+	}
+}
+
+func benchmarkNumberParsers(b *testing.B, parsers map[string]*NumberParser) {
+
+	var sb strings.Builder
+	for i := 0; i < 1000; i++ {
+		sb.WriteString("29.4,1.25,-3.5\n")
+	}
+	inData := sb.String()
+
+	type floats struct {
+		A, B, C float64
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		reader, err := NewReader(
+			strings.NewReader(inData),
+			&ReaderOptions{ColumnNames: []string{"A", "B", "C"}, NumberParsers: parsers, UnsafeFastPath: true},
+		)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		var actualData []floats
+		if err := reader.ReadAll(&actualData); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNumberParser_Strconv(b *testing.B) {
+
+	benchmarkNumberParsers(b, nil)
+}
+
+func BenchmarkNumberParser_Decimal(b *testing.B) {
+
+	decimal := &NumberParser{ParseFloat: parseDecimal}
+	benchmarkNumberParsers(b, map[string]*NumberParser{"A": decimal, "B": decimal, "C": decimal})
+}
+
+// TestReader_NumberErrors verifies numeric conversion errors name the row, column, and type
+func TestReader_NumberErrors(t *testing.T) {
+
+	type widths struct {
+		I8  int8
+		U   uint
+		F32 float32
+		UA  []uint16
+	}
+
+	var testCases = []struct {
+		name       string
+		inData     string
+		expErrText string
+	}{
+		{
+			name:       "int overflow",
+			inData:     "1,1,1,1\n300,1,1,1",
+			expErrText: `row 2, column "I8": value 300 overflows int8`,
+		},
+		{
+			name:       "negative unsigned",
+			inData:     "1,-1,1,1",
+			expErrText: `row 1, column "U": negative value -1 cannot be stored in uint`,
+		},
+		{
+			name:       "float overflow",
+			inData:     "1,1,1e39,1",
+			expErrText: `row 1, column "F32": value 1e39 overflows float32`,
+		},
+		{
+			name:       "slice element overflow",
+			inData:     `1,1,1,"1,70000"`,
+			expErrText: `row 1, column "UA": value 70000 overflows uint16`,
+		},
+		{
+			name:       "invalid",
+			inData:     "x,1,1,1",
+			expErrText: `row 1, column "I8": invalid value "x" for int8`,
+		},
+	}
+
+	for _, tt := range testCases {
+		for _, fastPath := range []bool{false, true} {
+			t.Run(tt.name, func(t *testing.T) {
+
+				reader, err := NewReader(
+					strings.NewReader(tt.inData),
+					&ReaderOptions{ColumnNames: []string{"I8", "U", "F32", "UA"}, UnsafeFastPath: fastPath},
+				)
+				require.NoError(t, err)
+
+				var actualData []widths
+				err = reader.ReadAll(&actualData)
+				assert.EqualError(t, err, tt.expErrText)
+
+				var fieldErr *FieldError
+				assert.True(t, errors.As(err, &fieldErr))
+			})
+		}
+	}
+}
+
+// TestReader_IntegerBases verifies integer columns can be parsed in other bases
+func TestReader_IntegerBases(t *testing.T) {
+
+	type registers struct {
+		Hex  uint16
+		Auto []int
+		Bin  int8
+	}
+
+	reader, err := NewReader(
+		strings.NewReader(`1f,"0x1F,0o755,0b1010,42",-101`),
+		&ReaderOptions{
+			ColumnNames:  []string{"Hex", "Auto", "Bin"},
+			IntegerBases: map[string]int{"Hex": 16, "Auto": 0, "Bin": 2},
+		},
+	)
+	require.NoError(t, err)
+
+	var actualData registers
+	require.NoError(t, reader.Read(&actualData))
+	assert.Equal(t, registers{Hex: 31, Auto: []int{31, 493, 10, 42}, Bin: -5}, actualData)
+
+	_, err = NewReader(
+		strings.NewReader(""),
+		&ReaderOptions{ColumnNames: []string{"Hex"}, IntegerBases: map[string]int{"Hex": 1}},
+	)
+	assert.EqualError(t, err, `integer base for column "Hex" must be 0 or between 2 and 36, got 1`)
+}
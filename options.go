@@ -0,0 +1,250 @@
+package csvee
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// validate checks the options for settings that are invalid or contradict each other before any
+// data is read.
+func (o *ReaderOptions) validate() error {
+
+	if !o.ReadHeaders && len(o.ColumnNames) == 0 {
+		return ErrColumnNamesRequired
+	}
+
+	if err := o.dialect().validate(); err != nil {
+		return err
+	}
+
+	if o.RateLimit < 0 {
+		return errors.Errorf("rate limit must not be negative, got %v", o.RateLimit)
+	}
+
+	if o.DedupWindow < 0 {
+		return errors.Errorf("dedup window must not be negative, got %d", o.DedupWindow)
+	}
+
+	if o.SlabSize < 0 {
+		return errors.Errorf("slab size must not be negative, got %d", o.SlabSize)
+	}
+
+	if o.ExpectedRows < 0 {
+		return errors.Errorf("expected rows must not be negative, got %d", o.ExpectedRows)
+	}
+
+	if o.BufferSize < 0 {
+		return errors.Errorf("buffer size must not be negative, got %d", o.BufferSize)
+	}
+
+	for _, column := range sortedKeys(o.IntegerBases) {
+		base := o.IntegerBases[column]
+		if base != 0 && (base < 2 || base > 36) {
+			return errors.Errorf("integer base for column %q must be 0 or between 2 and 36, got %d", column, base)
+		}
+		if _, exists := o.NumberParsers[column]; exists {
+			return errors.Errorf("column %q cannot have both a number parser and an integer base", column)
+		}
+	}
+
+	if o.FuzzyMatchThreshold < 0 || o.FuzzyMatchThreshold > 1 {
+		return errors.Errorf("fuzzy match threshold must be between 0 and 1, got %v", o.FuzzyMatchThreshold)
+	}
+
+	if o.OnError < ErrorFail || o.OnError > ErrorCollect {
+		return errors.Errorf("unknown error policy %d", o.OnError)
+	}
+
+	if o.ErrorBudget < 0 {
+		return errors.Errorf("error budget must not be negative, got %d", o.ErrorBudget)
+	}
+
+	if o.RowTimeout < 0 {
+		return errors.Errorf("row timeout must not be negative, got %v", o.RowTimeout)
+	}
+
+	if o.MaxGroupElements < 0 {
+		return errors.Errorf("max group elements must not be negative, got %d", o.MaxGroupElements)
+	}
+
+	if o.PipelineDepth < 0 {
+		return errors.Errorf("pipeline depth must not be negative, got %d", o.PipelineDepth)
+	}
+
+	for _, column := range sortedKeys(o.ColumnConverters) {
+		converter := o.ColumnConverters[column]
+		if converter == nil {
+			return errors.Errorf("converter for column %q must be non nil", column)
+		}
+	}
+
+	for _, column := range sortedKeys(o.ColumnConverterNames) {
+		name := o.ColumnConverterNames[column]
+		if _, exists := o.ColumnConverters[column]; exists {
+			return errors.Errorf("column %q has both a converter and a converter name", column)
+		}
+		if _, registered := registeredConverter(name); !registered {
+			return errors.Errorf("unknown converter %q for column %q", name, column)
+		}
+	}
+
+	if o.ValidatorName != "" {
+		if o.Validate != nil {
+			return errors.New("only one of Validate and ValidatorName may be set")
+		}
+		if _, registered := registeredValidator(o.ValidatorName); !registered {
+			return errors.Errorf("unknown validator %q", o.ValidatorName)
+		}
+	}
+
+	if o.SliceDelimiter != "" {
+		if err := validateSliceDelimiter(o.SliceDelimiter); err != nil {
+			return err
+		}
+	}
+	for _, column := range sortedKeys(o.SliceDelimiters) {
+		delimiter := o.SliceDelimiters[column]
+		if err := validateSliceDelimiter(delimiter); err != nil {
+			return errors.Wrapf(err, "column %q", column)
+		}
+	}
+
+	if !o.Encoding.Valid() {
+		return errors.Errorf("unknown encoding %q", o.Encoding)
+	}
+
+	if o.DefaultTimeFormat != "" && !Format(o.DefaultTimeFormat).Valid() {
+		return &FormatError{Format: o.DefaultTimeFormat}
+	}
+
+	if err := validateConditionalFormats(o.ConditionalFormats); err != nil {
+		return err
+	}
+
+	return validateColumnFormats(o.ColumnFormats)
+}
+
+// validateColumnReferences checks that the options only refer to columns the reader knows about.
+// It must be called once the reader's column names have been determined. Column names supplied by
+// the caller must be unique and non empty; headers read from the file are taken as they are.
+func (o *ReaderOptions) validateColumnReferences(columnNames []string) error {
+
+	if !o.ReadHeaders {
+		if err := validateColumnNames(columnNames, o.RepeatedColumns); err != nil {
+			return err
+		}
+	}
+
+	if o.IgnoreUnknownColumns {
+		return nil
+	}
+
+	if err := validateFormatColumns(o.ColumnFormats, columnNames); err != nil {
+		return err
+	}
+
+	known := make(map[string]bool, len(columnNames))
+	for _, name := range columnNames {
+		known[name] = true
+	}
+
+	for _, column := range sortedKeys(o.NumberParsers) {
+		if !known[column] {
+			return errors.Errorf("number parser provided for unknown column %q", column)
+		}
+	}
+
+	for _, column := range sortedKeys(o.WhitespacePolicies) {
+		if !known[column] {
+			return errors.Errorf("whitespace policy provided for unknown column %q", column)
+		}
+	}
+
+	for _, column := range sortedKeys(o.IntegerBases) {
+		if !known[column] {
+			return errors.Errorf("integer base provided for unknown column %q", column)
+		}
+	}
+
+	for _, column := range sortedKeys(o.ColumnConverters) {
+		if !known[column] {
+			return errors.Errorf("converter provided for unknown column %q", column)
+		}
+	}
+
+	for _, column := range sortedKeys(o.ColumnConverterNames) {
+		if !known[column] {
+			return errors.Errorf("converter provided for unknown column %q", column)
+		}
+	}
+
+	for _, column := range sortedKeys(o.SliceDelimiters) {
+		if !known[column] {
+			return errors.Errorf("slice delimiter provided for unknown column %q", column)
+		}
+	}
+
+	for _, column := range sortedKeys(o.ColumnTypes) {
+		if !known[column] {
+			return errors.Errorf("column type provided for unknown column %q", column)
+		}
+	}
+
+	for _, column := range sortedKeys(o.ConditionalFormats) {
+		conditional := o.ConditionalFormats[column]
+		if !known[column] {
+			return errors.Errorf("conditional format provided for unknown column %q", column)
+		}
+		if !known[conditional.Column] {
+			return errors.Errorf("conditional format for column %q depends on unknown column %q", column, conditional.Column)
+		}
+	}
+
+	return nil
+}
+
+func validateColumnNames(columnNames []string, repeated bool) error {
+
+	known := make(map[string]bool, len(columnNames))
+	for _, name := range columnNames {
+		if name == "" {
+			return errors.New("column names must not be empty")
+		}
+		if known[name] && !repeated {
+			return errors.Errorf("duplicate column name %q", name)
+		}
+		known[name] = true
+	}
+
+	return nil
+}
+
+func validateFormatColumns(formats map[string]string, columnNames []string) error {
+
+	known := make(map[string]bool, len(columnNames))
+	for _, name := range columnNames {
+		known[name] = true
+	}
+
+	for _, column := range sortedKeys(formats) {
+		if !known[column] {
+			return errors.Errorf("column format provided for unknown column %q", column)
+		}
+	}
+
+	return nil
+}
+
+// sortedKeys returns the keys of m in order, so that options are validated, and their errors
+// reported, in the same order on every run.
+func sortedKeys[V any](m map[string]V) []string {
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,315 @@
+package csvee
+
+import (
+	"encoding/json"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// readerOptionsJSON is the JSON form of ReaderOptions. Runes are written as strings, durations as
+// time.Duration strings, and the location by name.
+type readerOptionsJSON struct {
+	ReadHeaders   bool              `json:"readHeaders,omitempty"`
+	ColumnNames   []string          `json:"columnNames,omitempty"`
+	ColumnFormats map[string]string `json:"columnFormats,omitempty"`
+
+	Dialect          *dialectJSON `json:"dialect,omitempty"`
+	Delimiter        string       `json:"delimiter,omitempty"`
+	Comment          string       `json:"comment,omitempty"`
+	LazyQuotes       bool         `json:"lazyQuotes,omitempty"`
+	TrimLeadingSpace bool         `json:"trimLeadingSpace,omitempty"`
+
+	Encoding          Encoding `json:"encoding,omitempty"`
+	DefaultTimeFormat string   `json:"defaultTimeFormat,omitempty"`
+	Location          string   `json:"location,omitempty"`
+
+	LeapSeconds DatePolicy `json:"leapSeconds,omitempty"`
+	ZeroDates   DatePolicy `json:"zeroDates,omitempty"`
+	MaxDates    DatePolicy `json:"maxDates,omitempty"`
+
+	RateLimit          float64                     `json:"rateLimit,omitempty"`
+	DedupWindow        int                         `json:"dedupWindow,omitempty"`
+	ColumnDocs         map[string]ColumnDoc        `json:"columnDocs,omitempty"`
+	SlabSize           int                         `json:"slabSize,omitempty"`
+	ExpectedRows       int                         `json:"expectedRows,omitempty"`
+	UnsafeFastPath     bool                        `json:"unsafeFastPath,omitempty"`
+	PipelineDepth      int                         `json:"pipelineDepth,omitempty"`
+	BufferSize         int                         `json:"bufferSize,omitempty"`
+	IntegerBases       map[string]int              `json:"integerBases,omitempty"`
+	DetectLeadingZeros bool                        `json:"detectLeadingZeros,omitempty"`
+	BoolParsing        BoolParsing                 `json:"boolParsing,omitempty"`
+	WhitespacePolicy   WhitespacePolicy            `json:"whitespacePolicy,omitempty"`
+	WhitespacePolicies map[string]WhitespacePolicy `json:"whitespacePolicies,omitempty"`
+
+	FuzzyMatchThreshold float64 `json:"fuzzyMatchThreshold,omitempty"`
+	TrackProvenance     bool    `json:"trackProvenance,omitempty"`
+	SourceName          string  `json:"sourceName,omitempty"`
+	Merge               bool    `json:"merge,omitempty"`
+
+	ConditionalFormats   map[string]ConditionalFormat `json:"conditionalFormats,omitempty"`
+	AllowRaggedRows      bool                         `json:"allowRaggedRows,omitempty"`
+	NullValues           []string                     `json:"nullValues,omitempty"`
+	NestedSeparator      string                       `json:"nestedSeparator,omitempty"`
+	RepeatedColumns      bool                         `json:"repeatedColumns,omitempty"`
+	ColumnConverterNames map[string]string            `json:"columnConverterNames,omitempty"`
+	SliceDelimiter       string                       `json:"sliceDelimiter,omitempty"`
+	SliceDelimiters      map[string]string            `json:"sliceDelimiters,omitempty"`
+	ColumnTypes          map[string]ColumnKind        `json:"columnTypes,omitempty"`
+
+	RowTimeout     string      `json:"rowTimeout,omitempty"`
+	ValidatorName  string      `json:"validatorName,omitempty"`
+	OnError        ErrorPolicy `json:"onError,omitempty"`
+	ErrorBudget    int         `json:"errorBudget,omitempty"`
+	PartialResults bool        `json:"partialResults,omitempty"`
+	RuneLengths    bool        `json:"runeLengths,omitempty"`
+	Rules          []ruleJSON  `json:"rules,omitempty"`
+
+	IgnoreUnknownColumns bool `json:"ignoreUnknownColumns,omitempty"`
+	MaxGroupElements     int  `json:"maxGroupElements,omitempty"`
+}
+
+// dialectJSON is the JSON form of Dialect.
+type dialectJSON struct {
+	Delimiter        string `json:"delimiter,omitempty"`
+	Comment          string `json:"comment,omitempty"`
+	LazyQuotes       bool   `json:"lazyQuotes,omitempty"`
+	TrimLeadingSpace bool   `json:"trimLeadingSpace,omitempty"`
+}
+
+// ruleJSON is the JSON form of a Rule given by an expression.
+type ruleJSON struct {
+	Expr    string   `json:"expr"`
+	Name    string   `json:"name,omitempty"`
+	Columns []string `json:"columns,omitempty"`
+}
+
+// MarshalJSON encodes the options as JSON so that a decoding configuration can be shipped to other
+// processes, such as workers reading shards of the same files. Options holding functions or other
+// values that only exist in this process cannot be encoded and make it fail: hooks, Validate,
+// ColumnConverters, NumberParsers, HeaderNormalizer, Limiter, Manifest, MappingStore, OnWarning,
+// OnTrace, TraceWriter, ZoneAbbreviations, and Rules with a Check. ValidatorName and ColumnConverterNames refer to validators and converters
+// by name instead. Location is encoded by name, so it must be UTC, Local, or loaded with
+// time.LoadLocation. Formats registered with RegisterFormat, and converters and validators
+// registered by name, must also be registered in the process that decodes the options.
+func (o ReaderOptions) MarshalJSON() ([]byte, error) {
+
+	local := []struct {
+		name string
+		set  bool
+	}{
+		{"BeforeRow", o.BeforeRow != nil},
+		{"AfterRow", o.AfterRow != nil},
+		{"Validate", o.Validate != nil},
+		{"ColumnConverters", len(o.ColumnConverters) > 0},
+		{"NumberParsers", len(o.NumberParsers) > 0},
+		{"HeaderNormalizer", o.HeaderNormalizer != nil},
+		{"Limiter", o.Limiter != nil},
+		{"Manifest", o.Manifest != nil},
+		{"MappingStore", o.MappingStore != nil},
+		{"OnWarning", o.OnWarning != nil},
+		{"OnTrace", o.OnTrace != nil},
+		{"TraceWriter", o.TraceWriter != nil},
+		{"ZoneAbbreviations", len(o.ZoneAbbreviations) > 0},
+	}
+	for _, field := range local {
+		if field.set {
+			return nil, errors.Errorf("%s cannot be marshaled", field.name)
+		}
+	}
+
+	data := readerOptionsJSON{
+		ReadHeaders:          o.ReadHeaders,
+		ColumnNames:          o.ColumnNames,
+		ColumnFormats:        o.ColumnFormats,
+		Delimiter:            runeString(o.Delimiter),
+		Comment:              runeString(o.Comment),
+		LazyQuotes:           o.LazyQuotes,
+		TrimLeadingSpace:     o.TrimLeadingSpace,
+		Encoding:             o.Encoding,
+		DefaultTimeFormat:    o.DefaultTimeFormat,
+		LeapSeconds:          o.LeapSeconds,
+		ZeroDates:            o.ZeroDates,
+		MaxDates:             o.MaxDates,
+		RateLimit:            o.RateLimit,
+		DedupWindow:          o.DedupWindow,
+		ColumnDocs:           o.ColumnDocs,
+		SlabSize:             o.SlabSize,
+		ExpectedRows:         o.ExpectedRows,
+		UnsafeFastPath:       o.UnsafeFastPath,
+		PipelineDepth:        o.PipelineDepth,
+		BufferSize:           o.BufferSize,
+		IntegerBases:         o.IntegerBases,
+		DetectLeadingZeros:   o.DetectLeadingZeros,
+		BoolParsing:          o.BoolParsing,
+		WhitespacePolicy:     o.WhitespacePolicy,
+		WhitespacePolicies:   o.WhitespacePolicies,
+		FuzzyMatchThreshold:  o.FuzzyMatchThreshold,
+		TrackProvenance:      o.TrackProvenance,
+		SourceName:           o.SourceName,
+		Merge:                o.Merge,
+		ConditionalFormats:   o.ConditionalFormats,
+		AllowRaggedRows:      o.AllowRaggedRows,
+		NullValues:           o.NullValues,
+		NestedSeparator:      o.NestedSeparator,
+		RepeatedColumns:      o.RepeatedColumns,
+		ColumnConverterNames: o.ColumnConverterNames,
+		SliceDelimiter:       o.SliceDelimiter,
+		SliceDelimiters:      o.SliceDelimiters,
+		ColumnTypes:          o.ColumnTypes,
+		ValidatorName:        o.ValidatorName,
+		OnError:              o.OnError,
+		ErrorBudget:          o.ErrorBudget,
+		PartialResults:       o.PartialResults,
+		RuneLengths:          o.RuneLengths,
+		IgnoreUnknownColumns: o.IgnoreUnknownColumns,
+		MaxGroupElements:     o.MaxGroupElements,
+	}
+
+	if o.Dialect != nil {
+		data.Dialect = &dialectJSON{
+			Delimiter:        runeString(o.Dialect.Delimiter),
+			Comment:          runeString(o.Dialect.Comment),
+			LazyQuotes:       o.Dialect.LazyQuotes,
+			TrimLeadingSpace: o.Dialect.TrimLeadingSpace,
+		}
+	}
+
+	if o.Location != nil {
+		if _, err := time.LoadLocation(o.Location.String()); err != nil {
+			return nil, errors.Errorf("location %q cannot be marshaled", o.Location)
+		}
+		data.Location = o.Location.String()
+	}
+
+	if o.RowTimeout != 0 {
+		data.RowTimeout = o.RowTimeout.String()
+	}
+
+	for i, rule := range o.Rules {
+		if rule.Check != nil {
+			return nil, errors.Errorf("rule %d cannot be marshaled: it has a Check", i)
+		}
+		data.Rules = append(data.Rules, ruleJSON{Expr: rule.Expr, Name: rule.Name, Columns: rule.Columns})
+	}
+
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON decodes options encoded by MarshalJSON, replacing all of o.
+func (o *ReaderOptions) UnmarshalJSON(b []byte) error {
+
+	var data readerOptionsJSON
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+
+	options := ReaderOptions{
+		ReadHeaders:          data.ReadHeaders,
+		ColumnNames:          data.ColumnNames,
+		ColumnFormats:        data.ColumnFormats,
+		LazyQuotes:           data.LazyQuotes,
+		TrimLeadingSpace:     data.TrimLeadingSpace,
+		Encoding:             data.Encoding,
+		DefaultTimeFormat:    data.DefaultTimeFormat,
+		LeapSeconds:          data.LeapSeconds,
+		ZeroDates:            data.ZeroDates,
+		MaxDates:             data.MaxDates,
+		RateLimit:            data.RateLimit,
+		DedupWindow:          data.DedupWindow,
+		ColumnDocs:           data.ColumnDocs,
+		SlabSize:             data.SlabSize,
+		ExpectedRows:         data.ExpectedRows,
+		UnsafeFastPath:       data.UnsafeFastPath,
+		PipelineDepth:        data.PipelineDepth,
+		BufferSize:           data.BufferSize,
+		IntegerBases:         data.IntegerBases,
+		DetectLeadingZeros:   data.DetectLeadingZeros,
+		BoolParsing:          data.BoolParsing,
+		WhitespacePolicy:     data.WhitespacePolicy,
+		WhitespacePolicies:   data.WhitespacePolicies,
+		FuzzyMatchThreshold:  data.FuzzyMatchThreshold,
+		TrackProvenance:      data.TrackProvenance,
+		SourceName:           data.SourceName,
+		Merge:                data.Merge,
+		ConditionalFormats:   data.ConditionalFormats,
+		AllowRaggedRows:      data.AllowRaggedRows,
+		NullValues:           data.NullValues,
+		NestedSeparator:      data.NestedSeparator,
+		RepeatedColumns:      data.RepeatedColumns,
+		ColumnConverterNames: data.ColumnConverterNames,
+		SliceDelimiter:       data.SliceDelimiter,
+		SliceDelimiters:      data.SliceDelimiters,
+		ColumnTypes:          data.ColumnTypes,
+		ValidatorName:        data.ValidatorName,
+		OnError:              data.OnError,
+		ErrorBudget:          data.ErrorBudget,
+		PartialResults:       data.PartialResults,
+		RuneLengths:          data.RuneLengths,
+		IgnoreUnknownColumns: data.IgnoreUnknownColumns,
+		MaxGroupElements:     data.MaxGroupElements,
+	}
+
+	var err error
+	if options.Delimiter, err = parseRune("delimiter", data.Delimiter); err != nil {
+		return err
+	}
+	if options.Comment, err = parseRune("comment", data.Comment); err != nil {
+		return err
+	}
+
+	if data.Dialect != nil {
+		options.Dialect = &Dialect{LazyQuotes: data.Dialect.LazyQuotes, TrimLeadingSpace: data.Dialect.TrimLeadingSpace}
+		if options.Dialect.Delimiter, err = parseRune("dialect delimiter", data.Dialect.Delimiter); err != nil {
+			return err
+		}
+		if options.Dialect.Comment, err = parseRune("dialect comment", data.Dialect.Comment); err != nil {
+			return err
+		}
+	}
+
+	if data.Location != "" {
+		if options.Location, err = time.LoadLocation(data.Location); err != nil {
+			return errors.Wrap(err, "location")
+		}
+	}
+
+	if data.RowTimeout != "" {
+		if options.RowTimeout, err = time.ParseDuration(data.RowTimeout); err != nil {
+			return errors.Wrap(err, "row timeout")
+		}
+	}
+
+	for _, rule := range data.Rules {
+		options.Rules = append(options.Rules, Rule{Expr: rule.Expr, Name: rule.Name, Columns: rule.Columns})
+	}
+
+	*o = options
+	return nil
+}
+
+// runeString returns r as a string, or an empty string if r is zero.
+func runeString(r rune) string {
+
+	if r == 0 {
+		return ""
+	}
+
+	return string(r)
+}
+
+// parseRune returns the single rune in s, or zero if s is empty.
+func parseRune(name, s string) (rune, error) {
+
+	if s == "" {
+		return 0, nil
+	}
+
+	r, size := utf8.DecodeRuneInString(s)
+	if size != len(s) {
+		return 0, errors.Errorf("%s must be a single character, got %q", name, s)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,178 @@
+package csvee
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReaderOptions_JSON verifies options survive a round trip through JSON
+func TestReaderOptions_JSON(t *testing.T) {
+
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	options := ReaderOptions{
+		ReadHeaders:          true,
+		ColumnFormats:        map[string]string{"When": TimeFormatUnixMilli},
+		Dialect:              &Dialect{Delimiter: '\t', Comment: '#'},
+		Delimiter:            '|',
+		LazyQuotes:           true,
+		DefaultTimeFormat:    "2006-01-02 15:04:05",
+		Location:             newYork,
+		RateLimit:            10,
+		ColumnDocs:           map[string]ColumnDoc{"When": {Description: "when it happened", Unit: "ms"}},
+		IntegerBases:         map[string]int{"Count": 16},
+		ZeroDates:            DateNull,
+		BoolParsing:          BoolLenient,
+		WhitespacePolicies:   map[string]WhitespacePolicy{"Name": WhitespaceEmpty},
+		ConditionalFormats:   map[string]ConditionalFormat{"When": {Column: "Kind", Formats: map[string]string{"unix": TimeFormatUnix}}},
+		NullValues:           []string{"N/A"},
+		ColumnConverterNames: map[string]string{"Name": "upper"},
+		SliceDelimiters:      map[string]string{"Tags": ";"},
+		ColumnTypes:          map[string]ColumnKind{"Count": KindInt},
+		RowTimeout:           1500 * time.Millisecond,
+		ValidatorName:        "positive count",
+		OnError:              ErrorCollect,
+		ErrorBudget:          3,
+		MaxGroupElements:     50,
+		Rules:                []Rule{{Expr: "Count > 0", Name: "positive", Columns: []string{"Count"}}},
+	}
+
+	data, err := json.Marshal(options)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"delimiter":"|"`)
+	assert.Contains(t, string(data), `"location":"America/New_York"`)
+	assert.Contains(t, string(data), `"rowTimeout":"1.5s"`)
+
+	var actual ReaderOptions
+	require.NoError(t, json.Unmarshal(data, &actual))
+	assert.Equal(t, options, actual)
+
+	pointerData, err := json.Marshal(&options)
+	require.NoError(t, err)
+	assert.Equal(t, data, pointerData)
+}
+
+// TestReaderOptions_JSON_Decodes verifies unmarshaled options decode with registered validators and
+// converters
+func TestReaderOptions_JSON_Decodes(t *testing.T) {
+
+	RegisterConverter("upper", func(s string) (interface{}, error) { return strings.ToUpper(s), nil })
+	RegisterValidator("positive count", func(v interface{}, line int) error {
+		if v.(*validatedRow).Age <= 0 {
+			return errors.New("age must be positive")
+		}
+		return nil
+	})
+
+	data, err := json.Marshal(ReaderOptions{
+		ReadHeaders:          true,
+		ColumnConverterNames: map[string]string{"Name": "upper"},
+		ValidatorName:        "positive count",
+		OnError:              ErrorSkip,
+	})
+	require.NoError(t, err)
+
+	var options ReaderOptions
+	require.NoError(t, json.Unmarshal(data, &options))
+
+	reader, err := NewReader(strings.NewReader("Name,Age\nann,1\nbob,0\ncat,3\n"), &options)
+	require.NoError(t, err)
+
+	var actual []validatedRow
+	require.NoError(t, reader.ReadAll(&actual))
+	assert.Equal(t, []validatedRow{{Name: "ANN", Age: 1}, {Name: "CAT", Age: 3}}, actual)
+}
+
+// TestReaderOptions_JSON_Errors verifies options that only exist in this process are not marshaled
+// and invalid JSON options are rejected
+func TestReaderOptions_JSON_Errors(t *testing.T) {
+
+	marshalCases := []struct {
+		name    string
+		options ReaderOptions
+		expErr  string
+	}{
+		{
+			name:    "hook",
+			options: ReaderOptions{AfterRow: func(n int, v interface{}) error { return nil }},
+			expErr:  "AfterRow cannot be marshaled",
+		},
+		{
+			name:    "converter",
+			options: ReaderOptions{ColumnConverters: map[string]Converter{"Name": nil}},
+			expErr:  "ColumnConverters cannot be marshaled",
+		},
+		{
+			name:    "rule check",
+			options: ReaderOptions{Rules: []Rule{{Expr: "A > 1"}, {Check: func(v interface{}) error { return nil }}}},
+			expErr:  "rule 1 cannot be marshaled: it has a Check",
+		},
+		{
+			name:    "fixed zone",
+			options: ReaderOptions{Location: time.FixedZone("JST", 9*60*60)},
+			expErr:  `location "JST" cannot be marshaled`,
+		},
+	}
+
+	for _, tt := range marshalCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			_, err := json.Marshal(tt.options)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expErr)
+		})
+	}
+
+	unmarshalCases := []struct {
+		name   string
+		data   string
+		expErr string
+	}{
+		{name: "delimiter", data: `{"delimiter":"ab"}`, expErr: `delimiter must be a single character, got "ab"`},
+		{name: "dialect", data: `{"dialect":{"comment":"##"}}`, expErr: `dialect comment must be a single character, got "##"`},
+		{name: "duration", data: `{"rowTimeout":"soon"}`, expErr: "row timeout"},
+		{name: "location", data: `{"location":"Nowhere/Special"}`, expErr: "location"},
+	}
+
+	for _, tt := range unmarshalCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var options ReaderOptions
+			err := json.Unmarshal([]byte(tt.data), &options)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expErr)
+		})
+	}
+
+	_, err := NewReader(strings.NewReader(""), &ReaderOptions{ColumnNames: []string{"A"}, ValidatorName: "missing"})
+	assert.EqualError(t, err, `unknown validator "missing"`)
+
+	_, err = NewReader(strings.NewReader(""), &ReaderOptions{ColumnNames: []string{"A"}, ColumnConverterNames: map[string]string{"A": "missing"}})
+	assert.EqualError(t, err, `unknown converter "missing" for column "A"`)
+}
+
+// TestReaderOptions_JSON_Fields verifies every option is either marshaled or refused
+func TestReaderOptions_JSON_Fields(t *testing.T) {
+
+	refused := map[string]bool{
+		"BeforeRow": true, "AfterRow": true, "Validate": true, "ColumnConverters": true, "NumberParsers": true,
+		"HeaderNormalizer": true, "Limiter": true, "Manifest": true, "MappingStore": true, "OnWarning": true,
+		"OnTrace": true, "TraceWriter": true, "ZoneAbbreviations": true,
+	}
+
+	marshaled := reflect.TypeOf(readerOptionsJSON{})
+	options := reflect.TypeOf(ReaderOptions{})
+	for i := 0; i < options.NumField(); i++ {
+		name := options.Field(i).Name
+		_, exists := marshaled.FieldByName(name)
+		assert.True(t, exists || refused[name], name)
+	}
+}
@@ -0,0 +1,108 @@
+package csvee
+
+import (
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ReadAllParallel reads the CSV file at path into v, a pointer to a slice, by splitting the file
+// into workers shards with ShardPlan and decoding them concurrently, one goroutine per shard. The
+// rows are appended to v in the order they appear in the file. options, if given, configure the
+// reader of each shard, and default to reading headers; the first shard reads the headers if
+// ReadHeaders is set and the others use the columns it found.
+//
+// Each shard is read by its own Reader, so hooks, converters, Rule checks, and the Validate hook
+// must be safe for concurrent use, and the row numbers they and errors are given count from the
+// start of the shard. Errors are wrapped with the shard they occurred in; if several shards fail,
+// the error of the first is returned. The file must be in an encoding that represents newlines
+// and quotes as single bytes, such as UTF-8 or Windows-1252, and options cannot set a Manifest.
+func ReadAllParallel(path string, workers int, v interface{}, options ...*ReaderOptions) error {
+
+	if workers <= 0 {
+		return errors.Errorf("workers must be positive, got %d", workers)
+	}
+
+	direct, _, _, err := readAllTarget(v)
+	if err != nil {
+		return err
+	}
+
+	rOptions := &ReaderOptions{ReadHeaders: true}
+	if len(options) > 0 && options[0] != nil {
+		rOptions = options[0]
+	}
+	if rOptions.Manifest != nil {
+		return errors.New("a manifest cannot be written when reading in parallel")
+	}
+	if rOptions.Encoding == EncodingUTF16LE || rOptions.Encoding == EncodingUTF16BE {
+		return errors.Errorf("%s input cannot be read in parallel", rOptions.Encoding)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	shards, err := ShardPlan(file, workers)
+	if err != nil {
+		return err
+	}
+	if len(shards) == 0 {
+		return nil
+	}
+
+	// The first shard is opened now so that the others can be given the columns it read.
+	first, err := NewReader(shards[0].Section(file), rOptions)
+	if err != nil {
+		return errors.Wrapf(err, "shard 1")
+	}
+
+	rest := *rOptions
+	rest.ReadHeaders = false
+	rest.ColumnNames = first.Columns()
+
+	results := make([]reflect.Value, len(shards))
+	errs := make([]error, len(shards))
+
+	var wg sync.WaitGroup
+	for i, shard := range shards {
+
+		results[i] = reflect.New(direct.Type())
+
+		wg.Add(1)
+		go func(i int, shard Shard) {
+			defer wg.Done()
+
+			reader := first
+			if i > 0 {
+				if reader, errs[i] = NewReader(shard.Section(file), &rest); errs[i] != nil {
+					return
+				}
+			}
+			errs[i] = reader.ReadAll(results[i].Interface())
+		}(i, shard)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return errors.Wrapf(err, "shard %d (bytes %d to %d)", i+1, shards[i].Start, shards[i].End)
+		}
+	}
+
+	size := direct.Len()
+	for _, result := range results {
+		size += result.Elem().Len()
+	}
+	growSlice(direct, size)
+
+	for _, result := range results {
+		direct.Set(reflect.AppendSlice(direct, result.Elem()))
+	}
+
+	return nil
+}
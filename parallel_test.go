@@ -0,0 +1,89 @@
+package csvee
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeShardFile writes rows of shardRow, with quoted newlines in some notes, to a file in dir.
+func writeShardFile(t *testing.T, dir string, rows int, header bool) (string, []shardRow) {
+
+	var b strings.Builder
+	if header {
+		b.WriteString("ID,Note\n")
+	}
+	var expected []shardRow
+	for i := 1; i <= rows; i++ {
+		note := fmt.Sprintf("note %d", i)
+		if i%4 == 0 {
+			note = fmt.Sprintf("a\n\"quoted\"\nnote %d", i)
+		}
+		expected = append(expected, shardRow{ID: i, Note: note})
+		fmt.Fprintf(&b, "%d,\"%s\"\n", i, strings.ReplaceAll(note, `"`, `""`))
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("rows-%d.csv", rows))
+	require.NoError(t, ioutil.WriteFile(path, []byte(b.String()), 0644))
+
+	return path, expected
+}
+
+// TestReadAllParallel verifies shards are decoded concurrently and stitched together in order
+func TestReadAllParallel(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "csvee-parallel")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path, expected := writeShardFile(t, dir, 500, true)
+
+	for _, workers := range []int{1, 3, 8} {
+		t.Run(fmt.Sprint(workers), func(t *testing.T) {
+
+			actual := []*shardRow{{ID: -1}}
+			require.NoError(t, ReadAllParallel(path, workers, &actual))
+			require.Len(t, actual, len(expected)+1)
+			assert.Equal(t, -1, actual[0].ID)
+			for i, row := range actual[1:] {
+				assert.Equal(t, expected[i], *row)
+			}
+		})
+	}
+
+	path, expected = writeShardFile(t, dir, 50, false)
+	var actual []shardRow
+	require.NoError(t, ReadAllParallel(path, 4, &actual, &ReaderOptions{ColumnNames: []string{"ID", "Note"}}))
+	assert.Equal(t, expected, actual)
+}
+
+// TestReadAllParallel_Errors verifies errors name the shard they occurred in
+func TestReadAllParallel_Errors(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "csvee-parallel")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "rows.csv")
+	require.NoError(t, ioutil.WriteFile(path, []byte("ID,Note\n1,a\n2,b\n3,c\nx,d\n"), 0644))
+
+	var actual []shardRow
+	err = ReadAllParallel(path, 2, &actual)
+	require.Error(t, err)
+	assert.Equal(t, `shard 2 (bytes 12 to 24): row 3, column "ID": invalid value "x" for int`, err.Error())
+	assert.Empty(t, actual)
+
+	assert.EqualError(t, ReadAllParallel(path, 0, &actual), "workers must be positive, got 0")
+	assert.Equal(t, ErrReadAllNotSlicePointer, ReadAllParallel(path, 2, actual))
+	assert.EqualError(t, ReadAllParallel(path, 2, &actual, &ReaderOptions{ReadHeaders: true, Encoding: EncodingUTF16LE}),
+		"utf-16le input cannot be read in parallel")
+
+	err = ReadAllParallel(filepath.Join(dir, "missing.csv"), 2, &actual)
+	assert.True(t, os.IsNotExist(err))
+}
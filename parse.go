@@ -0,0 +1,263 @@
+package csvee
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ParseInto converts a single cell to a value of type t using the same rules as the Reader, so
+// tools outside a full Reader flow can reuse them. t may be any type a struct field can have: a
+// string, bool, integer, float, or time.Time, a type whose pointer implements
+// encoding.TextUnmarshaler or json.Unmarshaler, a nullable wrapper such as sql.NullInt64, a slice of
+// those with comma separated elements, or a pointer to any of them. format applies to times and is
+// a registered format name, such as TimeFormatUnix, or a time layout; if it is empty, times are
+// parsed as RFC 3339. As for struct fields, integers parsed with a time layout hold the time as an
+// epoch value. Empty cells yield the zero value of t: for pointers, a nil pointer, and for nullable
+// wrappers, an invalid one.
+func ParseInto(value string, t reflect.Type, format string) (interface{}, error) {
+
+	if t == nil {
+		return nil, ErrInvalidFieldType
+	}
+
+	if format != "" && !Format(format).Valid() {
+		return nil, &FormatError{Format: format}
+	}
+
+	v, err := parseValue(value, t, cellParsing{format: Format(format), numbers: defaultNumberParser, epochs: format != ""})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.Interface(), nil
+}
+
+// cellParsing holds the settings that control how a cell is converted.
+type cellParsing struct {
+	format         Format
+	numbers        *NumberParser
+	boolParsing    BoolParsing
+	sliceDelimiter string
+	location       *time.Location
+	zones          map[string]*time.Location
+	dates          datePolicies
+
+	// epochs is true if the column has a format, which makes integers hold epoch times when it is a
+	// time layout.
+	epochs bool
+}
+
+// parseValue converts value to a new value of type t. Times a DatePolicy decodes as null are zero,
+// and pointers to them nil.
+func parseValue(value string, t reflect.Type, parsing cellParsing) (reflect.Value, error) {
+
+	v, err := parseCell(value, t, parsing)
+	if err == errNullDate {
+		return reflect.New(t).Elem(), nil
+	}
+
+	return v, err
+}
+
+// parseCell converts value to a new value of type t, as parseValue does, but returns errNullDate for
+// times a DatePolicy decodes as null.
+func parseCell(value string, t reflect.Type, parsing cellParsing) (reflect.Value, error) {
+
+	v := reflect.New(t).Elem()
+
+	if t.Kind() == reflect.Ptr {
+		if t.Elem().Kind() != reflect.String && strings.TrimSpace(value) == "" {
+			return v, nil
+		}
+
+		elem, err := parseCell(value, t.Elem(), parsing)
+		if err != nil {
+			return v, err
+		}
+
+		v.Set(reflect.New(t.Elem()))
+		v.Elem().Set(elem)
+		return v, nil
+	}
+
+	if index, nullable := nullValueIndex(t); nullable {
+		inner := t.Field(index).Type
+		if value == "" || inner.Kind() != reflect.String && strings.TrimSpace(value) == "" {
+			return v, nil
+		}
+
+		elem, err := parseCell(value, inner, parsing)
+		if err != nil {
+			return v, err
+		}
+
+		v.Field(index).Set(elem)
+		v.FieldByName("Valid").SetBool(true)
+		return v, nil
+	}
+
+	if isUnmarshaler(t) {
+		if strings.TrimSpace(value) != "" {
+			if err := unmarshalCell(v, value); err != nil {
+				return v, err
+			}
+		}
+		return v, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(value)
+		return v, nil
+	case reflect.Slice:
+		if _, _, valid := getFieldTypeInfo(t); !valid && !isUnmarshaler(getBaseType(t.Elem())) {
+			return v, ErrInvalidFieldType
+		}
+
+		if strings.TrimSpace(value) == "" && (t.Elem().Kind() != reflect.String || isUnmarshaler(t.Elem())) {
+			v.Set(reflect.MakeSlice(t, 0, 0))
+			return v, nil
+		}
+
+		delimiter := parsing.sliceDelimiter
+		if delimiter == "" {
+			delimiter = defaultSliceDelimiter
+		}
+		elements, err := splitSlice(value, delimiter)
+		if err != nil {
+			return v, err
+		}
+		v.Set(reflect.MakeSlice(t, len(elements), len(elements)))
+		for i, element := range elements {
+			if getBaseType(t.Elem()).Kind() != reflect.String {
+				element = strings.TrimSpace(element)
+			}
+
+			elem, err := parseCell(element, t.Elem(), parsing)
+			if err == errNullDate {
+				continue
+			}
+			if err != nil {
+				return v, err
+			}
+			v.Index(i).Set(elem)
+		}
+		return v, nil
+	}
+
+	if !isTimeType(t) && !typeIsValid(t) {
+		return v, ErrInvalidFieldType
+	}
+
+	if strings.TrimSpace(value) == "" {
+		return v, nil
+	}
+
+	if err := parsing.setScalar(v, value); err != nil {
+		if err == errNullDate || isTimeType(t) {
+			return v, err
+		}
+		return v, describeNumberError(value, t, err)
+	}
+
+	return v, nil
+}
+
+// setScalar parses value, which is not blank, into v, a string, bool, number, or time.Time. It is
+// the conversion every way of decoding a cell shares: integers of columns whose format is a time
+// layout hold the time as an epoch value, and times a DatePolicy decodes as null return errNullDate.
+// Errors are returned as the parsers report them, for callers to describe.
+func (p cellParsing) setScalar(v reflect.Value, value string) error {
+
+	t := v.Type()
+
+	if isTimeType(t) {
+		tm, err := p.parseTime(value)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(tm))
+		return nil
+	}
+
+	if t.Kind() == reflect.String {
+		v.SetString(value)
+		return nil
+	}
+
+	if unit, epoch := p.epochUnit(t); epoch {
+		tm, err := p.parseTime(value)
+		if err != nil {
+			return err
+		}
+		return setEpoch(v, tm, unit)
+	}
+
+	return p.setPrimitive(v, value)
+}
+
+// setPrimitive parses value into v, a bool or numeric value.
+func (p cellParsing) setPrimitive(v reflect.Value, value string) error {
+
+	t := v.Type()
+
+	var err error
+	switch t.Kind() {
+	case reflect.Bool:
+		var b bool
+		if b, err = parseBool(value, p.boolParsing); err == nil {
+			v.SetBool(b)
+		}
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = p.numbers.parseInt(strings.TrimSpace(value), t.Bits()); err == nil {
+			v.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		if u, err = p.numbers.parseUint(strings.TrimSpace(value), t.Bits()); err == nil {
+			v.SetUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = p.numbers.parseFloat(strings.TrimSpace(value), t.Bits()); err == nil {
+			v.SetFloat(f)
+		}
+	default:
+		return ErrInvalidFieldType
+	}
+
+	return err
+}
+
+// parseTime parses value in the format and decodes placeholder times as the date policies do.
+func (p cellParsing) parseTime(value string) (time.Time, error) {
+
+	t, err := p.parseFormatted(value)
+	if p.dates == (datePolicies{}) {
+		return t, err
+	}
+
+	return p.dates.apply(value, t, err, p.parseFormatted)
+}
+
+// parseFormatted parses value in the format, without applying the date policies.
+func (p cellParsing) parseFormatted(value string) (time.Time, error) {
+
+	if len(p.zones) > 0 && p.format.hasZoneAbbreviation() {
+		if t, resolved := p.format.parseZoned(value, p.zones); resolved {
+			return t, nil
+		}
+	}
+
+	if p.location != nil {
+		return p.format.ParseInLocation(value, p.location)
+	}
+	if p.format == "" {
+		return time.Parse(time.RFC3339, value)
+	}
+
+	return p.format.Parse(value)
+}
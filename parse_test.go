@@ -0,0 +1,69 @@
+package csvee
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseInto verifies cells are converted with the Reader's rules
+func TestParseInto(t *testing.T) {
+
+	seven := 7
+	when := time.Date(2021, time.February, 13, 16, 55, 42, 0, time.UTC)
+
+	var testCases = []struct {
+		name   string
+		value  string
+		t      reflect.Type
+		format string
+		exp    interface{}
+		expErr string
+	}{
+		{name: "string", value: " a ", t: reflect.TypeOf(""), exp: " a "},
+		{name: "int", value: " -7 ", t: reflect.TypeOf(0), exp: -7},
+		{name: "uint8", value: "255", t: reflect.TypeOf(uint8(0)), exp: uint8(255)},
+		{name: "float32", value: "1.5", t: reflect.TypeOf(float32(0)), exp: float32(1.5)},
+		{name: "bool", value: "true", t: reflect.TypeOf(false), exp: true},
+		{name: "empty int", value: "", t: reflect.TypeOf(0), exp: 0},
+		{name: "pointer", value: "7", t: reflect.TypeOf(&seven), exp: &seven},
+		{name: "empty pointer", value: " ", t: reflect.TypeOf(&seven), exp: (*int)(nil)},
+		{name: "time", value: "2021-02-13T16:55:42Z", t: reflect.TypeOf(when), exp: when},
+		{name: "unix time", value: "1613235342", t: reflect.TypeOf(when), format: TimeFormatUnix, exp: time.Unix(1613235342, 0)},
+		{name: "layout time", value: "2021-02-13 16:55:42", t: reflect.TypeOf(when), format: "2006-01-02 15:04:05", exp: when},
+		{name: "epoch int", value: "2021-02-13 16:55:42", t: reflect.TypeOf(int64(0)), format: "2006-01-02 15:04:05", exp: int64(1613235342)},
+		{name: "null int", value: " 7 ", t: reflect.TypeOf(sql.NullInt64{}), exp: sql.NullInt64{Int64: 7, Valid: true}},
+		{name: "empty null int", value: " ", t: reflect.TypeOf(sql.NullInt64{}), exp: sql.NullInt64{}},
+		{name: "null string", value: " ", t: reflect.TypeOf(sql.NullString{}), exp: sql.NullString{String: " ", Valid: true}},
+		{name: "null time", value: "2021-02-13 16:55:42", t: reflect.TypeOf(sql.NullTime{}), format: "2006-01-02 15:04:05", exp: sql.NullTime{Time: when, Valid: true}},
+		{name: "null pointer", value: "1.5", t: reflect.TypeOf(&sql.NullFloat64{}), exp: &sql.NullFloat64{Float64: 1.5, Valid: true}},
+		{name: "int slice", value: "1, 2,3", t: reflect.TypeOf([]int{}), exp: []int{1, 2, 3}},
+		{name: "empty int slice", value: "", t: reflect.TypeOf([]int{}), exp: []int{}},
+		{name: "string slice", value: "a, b", t: reflect.TypeOf([]string{}), exp: []string{"a", " b"}},
+		{name: "invalid int", value: "x", t: reflect.TypeOf(0), expErr: `invalid value "x" for int`},
+		{name: "overflow", value: "256", t: reflect.TypeOf(uint8(0)), expErr: "value 256 overflows uint8"},
+		{name: "negative uint", value: "-1", t: reflect.TypeOf(uint(0)), expErr: "negative value -1 cannot be stored in uint"},
+		{name: "invalid bool", value: "yes", t: reflect.TypeOf(false), expErr: `invalid value "yes" for bool`},
+		{name: "invalid null int", value: "x", t: reflect.TypeOf(sql.NullInt64{}), expErr: `invalid value "x" for int64`},
+		{name: "invalid slice element", value: "1,x", t: reflect.TypeOf([]int{}), expErr: `invalid value "x" for int`},
+		{name: "unknown format", value: "1", t: reflect.TypeOf(when), format: "nope", expErr: `unknown format "nope"`},
+		{name: "unsupported type", value: "1", t: reflect.TypeOf(map[string]int{}), expErr: ErrInvalidFieldType.Error()},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			actual, err := ParseInto(tt.value, tt.t, tt.format)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.exp, actual)
+		})
+	}
+}
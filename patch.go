@@ -0,0 +1,218 @@
+package csvee
+
+import (
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Columns is an ordered list of column names.
+type Columns []string
+
+// PatchOperation is what a patch row does to the base row with the same key.
+type PatchOperation int
+
+const (
+	// PatchUpsert replaces the base row's cells with the patch row's, or adds the row if the key is
+	// not in the base.
+	PatchUpsert PatchOperation = iota
+
+	// PatchDelete removes the base row.
+	PatchDelete
+)
+
+// PatchOptions configures ApplyPatch.
+type PatchOptions struct {
+	// OperationColumn is the patch column holding each row's operation. It defaults to "op".
+	OperationColumn string
+
+	// Operations maps the values of the operation column to operations. Values are trimmed and
+	// compared case insensitively. It defaults to "upsert", "insert", "update", "u", and "i" for
+	// PatchUpsert and "delete" and "d" for PatchDelete.
+	Operations map[string]PatchOperation
+}
+
+// PatchResult counts the changes ApplyPatch made to the base.
+type PatchResult struct {
+	Inserted int
+	Updated  int
+	Deleted  int
+}
+
+var defaultPatchOperations = map[string]PatchOperation{
+	"upsert": PatchUpsert,
+	"insert": PatchUpsert,
+	"update": PatchUpsert,
+	"u":      PatchUpsert,
+	"i":      PatchUpsert,
+	"delete": PatchDelete,
+	"d":      PatchDelete,
+}
+
+// ApplyPatch folds the rows of patch into the rows of base and writes the resulting snapshot to w,
+// which must have the same columns as base. Rows are matched on the key columns, which both readers
+// must have. Patch rows are applied in order; an upsert only replaces the cells of columns the patch
+// has, so a patch may carry a subset of the base columns. Surviving base rows keep their order and
+// inserted rows follow them. The base is held in memory. ApplyPatch flushes w but does not close it.
+func ApplyPatch(base, patch *Reader, key Columns, w *Writer, options *PatchOptions) (PatchResult, error) {
+
+	var result PatchResult
+
+	if options == nil {
+		options = &PatchOptions{}
+	}
+
+	operationColumn := options.OperationColumn
+	if operationColumn == "" {
+		operationColumn = "op"
+	}
+
+	operations := defaultPatchOperations
+	if options.Operations != nil {
+		operations = make(map[string]PatchOperation, len(options.Operations))
+		for value, operation := range options.Operations {
+			operations[strings.ToLower(strings.TrimSpace(value))] = operation
+		}
+	}
+
+	if len(key) == 0 {
+		return result, errors.New("at least one key column is required")
+	}
+
+	if !sameColumns(w.ColumnNames, base.ColumnNames) {
+		return result, errors.New("the writer's columns must match the base's columns")
+	}
+
+	baseKey, err := columnIndexes(base.ColumnNames, key)
+	if err != nil {
+		return result, errors.Wrap(err, "base")
+	}
+
+	patchKey, err := columnIndexes(patch.ColumnNames, key)
+	if err != nil {
+		return result, errors.Wrap(err, "patch")
+	}
+
+	opIndex, err := columnIndexes(patch.ColumnNames, Columns{operationColumn})
+	if err != nil {
+		return result, errors.Wrap(err, "patch")
+	}
+
+	// patchColumns maps each base column to its patch column, or -1 if the patch does not have it.
+	patchColumns := make([]int, len(base.ColumnNames))
+	for i, name := range base.ColumnNames {
+		patchColumns[i] = -1
+		for j, patchName := range patch.ColumnNames {
+			if patchName == name {
+				patchColumns[i] = j
+			}
+		}
+	}
+
+	var rows [][]string
+	index := make(map[string]int)
+
+	for {
+		record, err := base.ReadRaw()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+
+		fields := copyFields(record)
+
+		index[recordKey(fields, baseKey)] = len(rows)
+		rows = append(rows, fields)
+	}
+
+	for {
+		record, err := patch.ReadRaw()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+
+		fields := copyFields(record)
+
+		value := fields[opIndex[0]]
+		operation, known := operations[strings.ToLower(strings.TrimSpace(value))]
+		if !known {
+			return result, &FieldError{Row: record.Row(), Column: operationColumn, Value: value, Err: errors.Errorf("unknown patch operation %q", value)}
+		}
+
+		k := recordKey(fields, patchKey)
+		i, exists := index[k]
+
+		switch {
+		case operation == PatchDelete && exists:
+			rows[i] = nil
+			delete(index, k)
+			result.Deleted++
+		case operation == PatchUpsert && exists:
+			applyPatchRow(rows[i], fields, patchColumns)
+			result.Updated++
+		case operation == PatchUpsert:
+			row := make([]string, len(base.ColumnNames))
+			applyPatchRow(row, fields, patchColumns)
+			index[k] = len(rows)
+			rows = append(rows, row)
+			result.Inserted++
+		}
+	}
+
+	for _, row := range rows {
+		if row == nil {
+			continue
+		}
+		if err := w.WriteRecord(row); err != nil {
+			return result, err
+		}
+	}
+
+	return result, w.Flush()
+}
+
+func applyPatchRow(row, patch []string, patchColumns []int) {
+
+	for i, j := range patchColumns {
+		if j >= 0 {
+			row[i] = patch[j]
+		}
+	}
+}
+
+// columnIndexes returns the index of each of columns in names.
+func columnIndexes(names []string, columns Columns) ([]int, error) {
+
+	indexes := make([]int, len(columns))
+	for i, column := range columns {
+		indexes[i] = -1
+		for j, name := range names {
+			if name == column {
+				indexes[i] = j
+				break
+			}
+		}
+		if indexes[i] < 0 {
+			return nil, errors.Errorf("column %q not found", column)
+		}
+	}
+
+	return indexes, nil
+}
+
+// recordKey joins the key fields of record into a single map key.
+func recordKey(record []string, key []int) string {
+
+	fields := make([]string, len(key))
+	for i, k := range key {
+		fields[i] = record[k]
+	}
+
+	return strings.Join(fields, "\x00")
+}
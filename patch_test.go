@@ -0,0 +1,85 @@
+package csvee
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestApplyPatch verifies patch rows are upserted and deleted by key
+func TestApplyPatch(t *testing.T) {
+
+	const baseData = "ID,Region,Name,Qty\n1,eu,a,1\n2,eu,b,2\n2,us,c,3\n"
+
+	var testCases = []struct {
+		name      string
+		patchData string
+		key       Columns
+		options   *PatchOptions
+		expData   string
+		expResult PatchResult
+		expErr    string
+	}{
+		{
+			name:      "upsert and delete",
+			patchData: "op,ID,Region,Qty\nupsert,2,eu,20\ndelete,1,eu,\nI,3,us,30\nd,9,us,\n",
+			key:       Columns{"ID", "Region"},
+			expData:   "ID,Region,Name,Qty\n2,eu,b,20\n2,us,c,3\n3,us,,30\n",
+			expResult: PatchResult{Inserted: 1, Updated: 1, Deleted: 1},
+		},
+		{
+			name:      "delete then insert",
+			patchData: "op,ID,Region,Name,Qty\ndelete,1,eu,,\nupsert,1,eu,z,9\n",
+			key:       Columns{"ID", "Region"},
+			expData:   "ID,Region,Name,Qty\n2,eu,b,2\n2,us,c,3\n1,eu,z,9\n",
+			expResult: PatchResult{Inserted: 1, Deleted: 1},
+		},
+		{
+			name:      "custom operations",
+			patchData: "Action,ID,Region,Qty\nCHG,2,us,7\n",
+			key:       Columns{"ID", "Region"},
+			options:   &PatchOptions{OperationColumn: "Action", Operations: map[string]PatchOperation{"chg": PatchUpsert}},
+			expData:   "ID,Region,Name,Qty\n1,eu,a,1\n2,eu,b,2\n2,us,c,7\n",
+			expResult: PatchResult{Updated: 1},
+		},
+		{
+			name:      "unknown operation",
+			patchData: "op,ID,Region\nmerge,1,eu\n",
+			key:       Columns{"ID", "Region"},
+			expErr:    `row 1, column "op": unknown patch operation "merge"`,
+		},
+		{
+			name:      "missing key",
+			patchData: "op,ID\nupsert,1\n",
+			key:       Columns{"ID", "Region"},
+			expErr:    `patch: column "Region" not found`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			base, err := NewReader(strings.NewReader(baseData), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+			patch, err := NewReader(strings.NewReader(tt.patchData), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			writer, err := NewWriter(&buf, &WriterOptions{ColumnNames: base.Columns(), WriteHeaders: true})
+			require.NoError(t, err)
+
+			result, err := ApplyPatch(base, patch, tt.key, writer, tt.options)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expResult, result)
+			assert.Equal(t, tt.expData, buf.String())
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package csvee
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// TimeFormatPeriod parses periods, such as "2021-W05", "2021Q3", or "2021-07", into the time
+	// they start at, as ParsePeriod parses them.
+	TimeFormatPeriod string = "period"
+
+	// FormatPeriod is the Format of TimeFormatPeriod.
+	FormatPeriod Format = Format(TimeFormatPeriod)
+)
+
+// parsePeriodTime parses field as a period, returning the time it starts at.
+func parsePeriodTime(field string) (time.Time, error) {
+
+	p, err := ParsePeriod(field)
+	return p.Start, err
+}
+
+// PeriodUnit is the length of a Period.
+type PeriodUnit int
+
+const (
+	PeriodYear PeriodUnit = iota + 1
+	PeriodQuarter
+	PeriodMonth
+	PeriodWeek
+)
+
+func (u PeriodUnit) String() string {
+
+	switch u {
+	case PeriodYear:
+		return "year"
+	case PeriodQuarter:
+		return "quarter"
+	case PeriodMonth:
+		return "month"
+	case PeriodWeek:
+		return "week"
+	}
+
+	return fmt.Sprintf("PeriodUnit(%d)", int(u))
+}
+
+// Period is a calendar year, quarter, month, or ISO 8601 week, as found in business reporting
+// extracts. Cells decode into Period fields as ParsePeriod parses them, and Periods are written as
+// String formats them.
+type Period struct {
+	Unit PeriodUnit
+
+	// Start is midnight UTC on the first day of the period. ISO weeks start on Mondays.
+	Start time.Time
+}
+
+// ParsePeriod parses a year, such as "2021", a quarter, such as "2021Q3" or "2021-Q3", a month,
+// such as "2021-07", or an ISO 8601 week, such as "2021-W05" or "2021W05". The letters Q and W may
+// be lower case.
+func ParsePeriod(s string) (Period, error) {
+
+	trimmed := strings.ToUpper(strings.TrimSpace(s))
+	if len(trimmed) < 4 {
+		return Period{}, errors.Errorf("invalid period %q", s)
+	}
+
+	year, err := strconv.Atoi(trimmed[:4])
+	if err != nil || trimmed[0] == '+' || trimmed[0] == '-' {
+		return Period{}, errors.Errorf("invalid period %q", s)
+	}
+
+	rest := trimmed[4:]
+	if rest == "" {
+		return Period{Unit: PeriodYear, Start: time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)}, nil
+	}
+
+	dashed := strings.HasPrefix(rest, "-")
+	rest = strings.TrimPrefix(rest, "-")
+
+	unit := PeriodMonth
+	switch {
+	case strings.HasPrefix(rest, "Q"):
+		unit, rest = PeriodQuarter, rest[1:]
+	case strings.HasPrefix(rest, "W"):
+		unit, rest = PeriodWeek, rest[1:]
+	case !dashed:
+		return Period{}, errors.Errorf("invalid period %q", s)
+	}
+
+	n, err := strconv.Atoi(rest)
+	if err != nil || rest[0] == '+' || rest[0] == '-' || unit != PeriodQuarter && len(rest) != 2 {
+		return Period{}, errors.Errorf("invalid period %q", s)
+	}
+
+	switch unit {
+	case PeriodQuarter:
+		if n < 1 || n > 4 {
+			return Period{}, errors.Errorf("invalid quarter in period %q", s)
+		}
+		return Period{Unit: unit, Start: time.Date(year, time.Month(3*n-2), 1, 0, 0, 0, 0, time.UTC)}, nil
+	case PeriodWeek:
+		start := isoWeekStart(year, n)
+		if wy, wn := start.ISOWeek(); n < 1 || wy != year || wn != n {
+			return Period{}, errors.Errorf("invalid week in period %q", s)
+		}
+		return Period{Unit: unit, Start: start}, nil
+	}
+
+	if n < 1 || n > 12 {
+		return Period{}, errors.Errorf("invalid month in period %q", s)
+	}
+	return Period{Unit: unit, Start: time.Date(year, time.Month(n), 1, 0, 0, 0, 0, time.UTC)}, nil
+}
+
+// isoWeekStart returns the Monday that starts ISO week n of year. Week 1 is the week with the
+// year's first Thursday, which always holds January 4th.
+func isoWeekStart(year, n int) time.Time {
+
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+	return monday.AddDate(0, 0, 7*(n-1))
+}
+
+// End returns the start of the period after p.
+func (p Period) End() time.Time {
+
+	switch p.Unit {
+	case PeriodYear:
+		return p.Start.AddDate(1, 0, 0)
+	case PeriodQuarter:
+		return p.Start.AddDate(0, 3, 0)
+	case PeriodMonth:
+		return p.Start.AddDate(0, 1, 0)
+	case PeriodWeek:
+		return p.Start.AddDate(0, 0, 7)
+	}
+
+	return p.Start
+}
+
+// Contains reports whether t falls within the period.
+func (p Period) Contains(t time.Time) bool {
+
+	return !t.Before(p.Start) && t.Before(p.End())
+}
+
+// String formats p as "2021", "2021-Q3", "2021-07", or "2021-W05", or as an empty string if it is
+// the zero Period.
+func (p Period) String() string {
+
+	switch p.Unit {
+	case PeriodYear:
+		return fmt.Sprintf("%04d", p.Start.Year())
+	case PeriodQuarter:
+		return fmt.Sprintf("%04d-Q%d", p.Start.Year(), (int(p.Start.Month())+2)/3)
+	case PeriodMonth:
+		return fmt.Sprintf("%04d-%02d", p.Start.Year(), int(p.Start.Month()))
+	case PeriodWeek:
+		year, week := p.Start.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	}
+
+	return ""
+}
+
+// MarshalText formats p as String does.
+func (p Period) MarshalText() ([]byte, error) {
+
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText parses text as ParsePeriod does.
+func (p *Period) UnmarshalText(text []byte) error {
+
+	period, err := ParsePeriod(string(text))
+	if err != nil {
+		return err
+	}
+
+	*p = period
+	return nil
+}
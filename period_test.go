@@ -0,0 +1,97 @@
+package csvee
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParsePeriod verifies years, quarters, months, and ISO weeks are parsed
+func TestParsePeriod(t *testing.T) {
+
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		in     string
+		exp    Period
+		end    time.Time
+		str    string
+		expErr string
+	}{
+		{in: "2021", exp: Period{Unit: PeriodYear, Start: date(2021, 1, 1)}, end: date(2022, 1, 1), str: "2021"},
+		{in: "2021Q3", exp: Period{Unit: PeriodQuarter, Start: date(2021, 7, 1)}, end: date(2021, 10, 1), str: "2021-Q3"},
+		{in: "2021-q4", exp: Period{Unit: PeriodQuarter, Start: date(2021, 10, 1)}, end: date(2022, 1, 1), str: "2021-Q4"},
+		{in: "2021-07", exp: Period{Unit: PeriodMonth, Start: date(2021, 7, 1)}, end: date(2021, 8, 1), str: "2021-07"},
+		{in: "2021-W05", exp: Period{Unit: PeriodWeek, Start: date(2021, 2, 1)}, end: date(2021, 2, 8), str: "2021-W05"},
+		{in: " 2020w53 ", exp: Period{Unit: PeriodWeek, Start: date(2020, 12, 28)}, end: date(2021, 1, 4), str: "2020-W53"},
+		{in: "2026-W01", exp: Period{Unit: PeriodWeek, Start: date(2025, 12, 29)}, end: date(2026, 1, 5), str: "2026-W01"},
+		{in: "2021-W53", expErr: `invalid week in period "2021-W53"`},
+		{in: "2021-W00", expErr: `invalid week in period "2021-W00"`},
+		{in: "2021Q5", expErr: `invalid quarter in period "2021Q5"`},
+		{in: "2021-13", expErr: `invalid month in period "2021-13"`},
+		{in: "2021-7", expErr: `invalid period "2021-7"`},
+		{in: "202107", expErr: `invalid period "202107"`},
+		{in: "2021-Q", expErr: `invalid period "2021-Q"`},
+		{in: "21", expErr: `invalid period "21"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+
+			p, err := ParsePeriod(tt.in)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, p)
+			assert.Equal(t, tt.end, p.End())
+			assert.Equal(t, tt.str, p.String())
+			assert.True(t, p.Contains(p.Start))
+			assert.False(t, p.Contains(p.End()))
+		})
+	}
+}
+
+type periodRow struct {
+	Period Period
+	Start  time.Time
+}
+
+// TestPeriods_ReadWrite verifies periods decode into Period and time fields and are written back
+func TestPeriods_ReadWrite(t *testing.T) {
+
+	const data = "Period,Start\n2021-W05,2021Q3\n2021-07,2021\n"
+
+	reader, err := NewReader(strings.NewReader(data), &ReaderOptions{
+		ReadHeaders:   true,
+		ColumnFormats: map[string]string{"Start": TimeFormatPeriod},
+	})
+	require.NoError(t, err)
+
+	var rows []periodRow
+	require.NoError(t, reader.ReadAll(&rows))
+	assert.Equal(t, []periodRow{
+		{Period: Period{Unit: PeriodWeek, Start: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)}, Start: time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{Period: Period{Unit: PeriodMonth, Start: time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)}, Start: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}, rows)
+
+	var out bytes.Buffer
+	writer, err := NewWriter(&out, &WriterOptions{WriteHeaders: true, ColumnNames: []string{"Period"}})
+	require.NoError(t, err)
+	for _, row := range rows {
+		require.NoError(t, writer.Write(row))
+	}
+	require.NoError(t, writer.Flush())
+	assert.Equal(t, "Period\n2021-W05\n2021-07\n", out.String())
+
+	reader, err = NewReader(strings.NewReader("Period,Start\n2021-W53,\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	assert.EqualError(t, reader.ReadAll(&rows), `row 1, column "Period": invalid week in period "2021-W53"`)
+}
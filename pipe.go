@@ -0,0 +1,121 @@
+package csvee
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// NewStdinReader returns a new Reader that reads from standard input. Readers never seek, so
+// standard input may be a pipe.
+func NewStdinReader(options ...*ReaderOptions) (*Reader, error) {
+
+	return NewReader(os.Stdin, options...)
+}
+
+// IsBrokenPipe reports whether err was caused by writing to a pipe whose reader has gone away,
+// as happens when output is piped to a command like head. Filters should treat this as a
+// request to stop rather than a failure.
+func IsBrokenPipe(err error) bool {
+
+	return errors.Is(err, syscall.EPIPE)
+}
+
+// SilenceFlushWriter buffers writes to an underlying writer and flushes them once no writes have
+// occurred for a period of time, so that downstream commands in a pipeline see output promptly
+// even when input arrives slowly.
+type SilenceFlushWriter struct {
+	mu      sync.Mutex
+	buf     *bufio.Writer
+	silence time.Duration
+	timer   *time.Timer
+	err     error
+}
+
+// NewSilenceFlushWriter returns a SilenceFlushWriter that writes to w, flushing after silence has
+// elapsed without a write.
+func NewSilenceFlushWriter(w io.Writer, silence time.Duration) *SilenceFlushWriter {
+
+	return &SilenceFlushWriter{
+		buf:     bufio.NewWriter(w),
+		silence: silence,
+	}
+}
+
+// NewStdoutWriter returns a SilenceFlushWriter that writes to standard output. It ignores SIGPIPE
+// for the rest of the process: otherwise the Go runtime terminates a program whose write to standard
+// output fails with a broken pipe before the write returns. Writes instead fail with an error that
+// IsBrokenPipe reports, so that the program can stop cleanly.
+func NewStdoutWriter(silence time.Duration) *SilenceFlushWriter {
+
+	signal.Ignore(syscall.SIGPIPE)
+	return NewSilenceFlushWriter(os.Stdout, silence)
+}
+
+// Write buffers p and resets the silence timer. It returns any error from a previous background
+// flush, such as a broken pipe.
+func (w *SilenceFlushWriter) Write(p []byte) (int, error) {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	n, err := w.buf.Write(p)
+	if err != nil {
+		w.err = err
+		return n, err
+	}
+
+	if w.timer == nil {
+		w.timer = time.AfterFunc(w.silence, w.flushOnSilence)
+	} else {
+		w.timer.Reset(w.silence)
+	}
+
+	return n, nil
+}
+
+func (w *SilenceFlushWriter) flushOnSilence() {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err == nil {
+		w.err = w.buf.Flush()
+	}
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (w *SilenceFlushWriter) Flush() error {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.err == nil {
+		w.err = w.buf.Flush()
+	}
+
+	return w.err
+}
+
+// Close stops the silence timer and flushes any buffered data. It does not close the underlying
+// writer.
+func (w *SilenceFlushWriter) Close() error {
+
+	w.mu.Lock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mu.Unlock()
+
+	return w.Flush()
+}
@@ -0,0 +1,99 @@
+package csvee
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestSilenceFlushWriter verifies buffered output is flushed after a period without writes
+func TestSilenceFlushWriter(t *testing.T) {
+
+	var out lockedBuffer
+	w := NewSilenceFlushWriter(&out, 10*time.Millisecond)
+
+	_, err := w.Write([]byte("a,b\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "", out.String())
+
+	assert.Eventually(t, func() bool { return out.String() == "a,b\n" }, time.Second, time.Millisecond)
+
+	_, err = w.Write([]byte("c,d\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	assert.Equal(t, "a,b\nc,d\n", out.String())
+}
+
+// TestIsBrokenPipe verifies broken pipe errors are detected through wrapping
+func TestIsBrokenPipe(t *testing.T) {
+
+	err := &os.PathError{Op: "write", Path: "/dev/stdout", Err: syscall.EPIPE}
+	assert.True(t, IsBrokenPipe(err))
+	assert.True(t, IsBrokenPipe(fmt.Errorf("writing: %w", err)))
+	assert.False(t, IsBrokenPipe(syscall.EINVAL))
+}
+
+// brokenPipeChild is set in the environment of the subprocess TestNewStdoutWriter_BrokenPipe runs.
+const brokenPipeChild = "CSVEE_BROKEN_PIPE_CHILD"
+
+// TestNewStdoutWriter_BrokenPipe verifies writing to standard output after its reader has gone away
+// returns a broken pipe error rather than terminating the process
+func TestNewStdoutWriter_BrokenPipe(t *testing.T) {
+
+	if os.Getenv(brokenPipeChild) != "" {
+		w := NewStdoutWriter(time.Hour)
+		if _, err := w.Write([]byte("a,b\n")); err != nil {
+			os.Exit(4)
+		}
+		if IsBrokenPipe(w.Flush()) {
+			os.Exit(3)
+		}
+		os.Exit(4)
+	}
+
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGPIPE is not raised on Windows")
+	}
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestNewStdoutWriter_BrokenPipe$")
+	cmd.Env = append(os.Environ(), brokenPipeChild+"=1")
+	cmd.Stdout = w
+	err = cmd.Run()
+	require.NoError(t, w.Close())
+
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 3, exitErr.ExitCode(), exitErr.String())
+}
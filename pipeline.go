@@ -0,0 +1,147 @@
+package csvee
+
+import (
+	"encoding/csv"
+
+	"github.com/pkg/errors"
+)
+
+// recordItem is a tokenized record along with its 1-based row number, or the error that ended
+// tokenization along with the number of the row that could not be read.
+type recordItem struct {
+	record []string
+	row    int
+	err    error
+
+	// duplicates is the number of records dropped by the dedup window before it.
+	duplicates int
+
+	// line, offset, and end locate the record in the input. fields locates each of its fields if it
+	// was tokenized ahead of being read, since the csv.Reader only does so until its next read.
+	line   int
+	offset int64
+	end    int64
+	fields []fieldPosition
+}
+
+// pipeline tokenizes records on a separate goroutine so that reading record N+1 overlaps with
+// converting record N.
+type pipeline struct {
+	items    chan recordItem
+	done     chan struct{}
+	finished chan struct{}
+	leftover []recordItem
+}
+
+// tokenize reads the next record that is not a duplicate of one in the dedup window.
+func (r *Reader) tokenize() recordItem {
+
+	duplicates := 0
+	for {
+
+		offset := r.CSVReader.InputOffset()
+
+		// This handles any CSV read errors we might encounter.
+		record, err := r.nextRecord()
+		if err != nil {
+			return recordItem{err: err, row: r.tokenized + 1, duplicates: duplicates}
+		}
+		r.tokenized++
+
+		if r.dedup != nil && r.dedup.duplicate(record) {
+			duplicates++
+			continue
+		}
+
+		item := recordItem{record: record, row: r.tokenized, duplicates: duplicates}
+		r.positionRecord(&item, offset)
+		return item
+	}
+}
+
+// nextItem returns the next tokenized record, taking it from records left over by a stopped
+// pipeline first, then from a running pipeline, and otherwise tokenizing it directly.
+func (r *Reader) nextItem() recordItem {
+
+	if len(r.pending) > 0 {
+		item := r.pending[0]
+		r.pending = r.pending[1:]
+		return item
+	}
+
+	if r.pipe != nil {
+		return <-r.pipe.items
+	}
+
+	return r.tokenize()
+}
+
+// startPipeline starts tokenizing records in the background if the reader was configured with a
+// pipeline depth. It must be paired with stopPipeline.
+func (r *Reader) startPipeline() {
+
+	if r.pipelineDepth <= 0 || r.pipe != nil || len(r.pending) > 0 {
+		return
+	}
+
+	p := &pipeline{
+		items:    make(chan recordItem, r.pipelineDepth),
+		done:     make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	r.pipe = p
+
+	go func() {
+
+		defer close(p.finished)
+
+		for {
+
+			item := r.tokenize()
+			if item.record != nil && item.fields == nil {
+				item.fields = r.fieldPositions(len(item.record))
+			}
+
+			// Records are handed to another goroutine, so the csv.Reader must not reuse them.
+			if r.CSVReader.ReuseRecord && item.record != nil {
+				item.record = append([]string(nil), item.record...)
+			}
+
+			select {
+			case p.items <- item:
+				// Malformed records do not stop the csv.Reader, so they do not stop the pipeline.
+				var csvErr *csv.ParseError
+				if item.err != nil && !errors.As(item.err, &csvErr) {
+					return
+				}
+			case <-p.done:
+				p.leftover = append(p.leftover, item)
+				return
+			}
+		}
+	}()
+}
+
+// stopPipeline stops background tokenization and keeps any records that were read ahead so that
+// subsequent reads see them in order.
+func (r *Reader) stopPipeline() {
+
+	p := r.pipe
+	if p == nil {
+		return
+	}
+	r.pipe = nil
+
+	close(p.done)
+	<-p.finished
+
+	for {
+		select {
+		case item := <-p.items:
+			r.pending = append(r.pending, item)
+		default:
+			r.pending = append(r.pending, p.leftover...)
+			return
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package csvee
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_PipelineDepth verifies pipelined reads decode the same rows in order
+func TestReader_PipelineDepth(t *testing.T) {
+
+	var sb strings.Builder
+	sb.WriteString("I,S\n")
+	for i := 1; i <= 100; i++ {
+		sb.WriteString(strconv.Itoa(i) + ",s\n")
+	}
+
+	var rows []int
+	reader, err := NewReader(
+		strings.NewReader(sb.String()),
+		&ReaderOptions{
+			ReadHeaders:   true,
+			PipelineDepth: 4,
+			BeforeRow: func(n int, record []string) error {
+				rows = append(rows, n)
+				return nil
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	var actualData []readTo
+	require.NoError(t, reader.ReadAll(&actualData))
+	require.Len(t, actualData, 100)
+	require.Len(t, rows, 100)
+	for i, row := range actualData {
+		assert.Equal(t, i+1, row.I)
+		assert.Equal(t, i+1, rows[i])
+	}
+}
+
+// TestReader_PipelineDepthStopped verifies records read ahead are kept when ReadAll stops early
+func TestReader_PipelineDepthStopped(t *testing.T) {
+
+	errStop := errors.New("stop")
+	reader, err := NewReader(
+		strings.NewReader("I\n1\n2\n3\n4\n5\n"),
+		&ReaderOptions{
+			ReadHeaders:   true,
+			PipelineDepth: 3,
+			AfterRow: func(n int, v interface{}) error {
+				if v.(*readTo).I == 2 {
+					return errStop
+				}
+				return nil
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	var actualData []readTo
+	assert.Equal(t, errStop, reader.ReadAll(&actualData))
+
+	var row readTo
+	for _, expected := range []int{3, 4, 5} {
+		require.NoError(t, reader.Read(&row))
+		assert.Equal(t, expected, row.I)
+	}
+}
@@ -0,0 +1,561 @@
+package csvee
+
+import (
+	"container/list"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// decodePlan holds the result of the reflection work needed to decode records with a given set of
+// columns into a given struct type, so that it is only done once.
+type decodePlan struct {
+	columns []columnPlan
+	matches []ColumnMatch
+
+	// byName maps column names to their index in columns.
+	byName map[string]int
+
+	// groups holds the indexes of the slices and arrays of structs that columns are bound to the
+	// elements of.
+	groups [][]int
+}
+
+// columnPlan describes how a single column is decoded.
+type columnPlan struct {
+	column    int
+	name      string
+	field     reflect.StructField
+	fieldType reflect.Type
+	sliceType reflect.Type
+
+	// fast is true if the field is a primitive that can be set directly at offset from the start of
+	// the struct.
+	fast   bool
+	offset uintptr
+
+	// converted is true if the column has a converter, which may assign fields of any type.
+	converted bool
+
+	// unmarshal is true if the field's type, or for slices their elements' type, implements
+	// encoding.TextUnmarshaler or json.Unmarshaler, which decode its cells.
+	unmarshal bool
+
+	// nullable is true if the field is a wrapper such as sql.NullString, in which case fieldType is
+	// the type of its value field at index nullValue.
+	nullable  bool
+	nullValue int
+
+	// repeats holds the indexes of every column with the column's name if there is more than one,
+	// whose cells are collected into its slice or array field.
+	repeats []int
+
+	// element binds the column to a field of an element of a slice or array of structs, in which
+	// case field is a field of the element's struct type.
+	element *elementBinding
+}
+
+type planKey struct {
+	t       reflect.Type
+	columns string
+	config  planConfig
+}
+
+// planConfig holds the reader settings that affect how columns are matched to fields.
+type planConfig struct {
+	fuzzyThreshold float64
+	separator      string
+
+	// converted holds the names of the columns that have converters, which bypass the usual field
+	// type checks.
+	converted string
+
+	// repeated is true if columns may share a name, in which case their cells are collected.
+	repeated bool
+
+	// maxElements is the largest element index a column may bind to a slice of structs.
+	maxElements int
+}
+
+// defaultPlanCacheSize is the number of plans the shared cache holds unless SetPlanCacheSize says
+// otherwise.
+const defaultPlanCacheSize = 1024
+
+// planCache memoizes plans across readers, keyed by struct type and column set. It is bounded so that
+// reading files whose headers keep changing does not grow it forever.
+var planCache = newPlanLRU(defaultPlanCacheSize)
+
+// planLRU is a cache of decode plans that evicts the least recently used plan once it is full.
+type planLRU struct {
+	mu   sync.Mutex
+	size int
+
+	// order holds a *planEntry for each plan, the most recently used first.
+	order   *list.List
+	entries map[planKey]*list.Element
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+type planEntry struct {
+	key  planKey
+	plan *decodePlan
+}
+
+func newPlanLRU(size int) *planLRU {
+
+	return &planLRU{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[planKey]*list.Element),
+	}
+}
+
+// get returns the plan cached for key, counting the lookup as a hit or a miss.
+func (c *planLRU) get(key planKey) (*decodePlan, bool) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, exists := c.entries[key]
+	if !exists {
+		c.misses++
+		return nil, false
+	}
+
+	c.hits++
+	c.order.MoveToFront(element)
+	return element.Value.(*planEntry).plan, true
+}
+
+// add caches plan for key and returns it, unless another plan was cached for key meanwhile, in
+// which case that one is returned so that only one is kept.
+func (c *planLRU) add(key planKey, plan *decodePlan) *decodePlan {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, exists := c.entries[key]; exists {
+		c.order.MoveToFront(element)
+		return element.Value.(*planEntry).plan
+	}
+
+	if c.size <= 0 {
+		return plan
+	}
+
+	c.entries[key] = c.order.PushFront(&planEntry{key: key, plan: plan})
+	c.evict()
+	return plan
+}
+
+// evict removes the least recently used plans until no more than size remain.
+func (c *planLRU) evict() {
+
+	for c.order.Len() > c.size && c.order.Len() > 0 {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*planEntry).key)
+		c.evictions++
+	}
+}
+
+// CacheStats describes the use of a cache.
+type CacheStats struct {
+	// Hits and Misses count the lookups that found an entry and those that had to build one.
+	Hits   uint64
+	Misses uint64
+
+	// Evictions counts the entries removed to make room for others.
+	Evictions uint64
+
+	// Entries is the number of entries the cache holds.
+	Entries int
+}
+
+// PlanCacheStats returns statistics of the cache of decode plans shared by all readers, which holds
+// the field mapping of each struct type for each set of columns and options it has been read with.
+// Readers with a HeaderNormalizer do not use it. A high miss rate means types are being read with
+// many different column sets, such as files whose headers vary.
+func PlanCacheStats() CacheStats {
+
+	planCache.mu.Lock()
+	defer planCache.mu.Unlock()
+
+	return CacheStats{
+		Hits:      planCache.hits,
+		Misses:    planCache.misses,
+		Evictions: planCache.evictions,
+		Entries:   planCache.order.Len(),
+	}
+}
+
+// SetPlanCacheSize sets the number of decode plans the shared cache holds, evicting the least
+// recently used plans once there are more. A size of zero or less disables the cache. The size
+// defaults to 1024.
+func SetPlanCacheSize(size int) {
+
+	planCache.mu.Lock()
+	defer planCache.mu.Unlock()
+
+	planCache.size = size
+	planCache.evict()
+}
+
+// ResetPlanCache empties the cache of decode plans and resets its statistics. Readers keep the
+// plans they have already used.
+func ResetPlanCache() {
+
+	planCache.mu.Lock()
+	defer planCache.mu.Unlock()
+
+	planCache.order.Init()
+	planCache.entries = make(map[planKey]*list.Element)
+	planCache.hits, planCache.misses, planCache.evictions = 0, 0, 0
+}
+
+// planFor returns the decode plan for vType and the reader's current columns, building it on first use.
+func (r *Reader) planFor(vType reflect.Type) (*decodePlan, error) {
+
+	if plan, exists := r.plans[vType]; exists {
+		return plan, nil
+	}
+
+	// Normalizers are functions, which cannot be compared, so plans that use one are not shared.
+	if r.headerNormalizer != nil {
+		plan, err := buildDecodePlan(vType, r.ColumnNames, r.planConfig, r.headerNormalizer)
+		if err != nil {
+			return nil, err
+		}
+		r.cachePlan(vType, plan)
+		return plan, nil
+	}
+
+	key := planKey{t: vType, columns: strings.Join(r.ColumnNames, "\x00"), config: r.planConfig}
+	if plan, exists := planCache.get(key); exists {
+		r.cachePlan(vType, plan)
+		return plan, nil
+	}
+
+	plan, err := buildDecodePlan(vType, r.ColumnNames, r.planConfig, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	plan = planCache.add(key, plan)
+	r.cachePlan(vType, plan)
+	return plan, nil
+}
+
+func (r *Reader) cachePlan(vType reflect.Type, plan *decodePlan) {
+
+	if r.plans == nil {
+		r.plans = make(map[reflect.Type]*decodePlan)
+	}
+	r.plans[vType] = plan
+}
+
+// columnSettings holds the reader's settings for a single column, which are otherwise looked up by
+// column name.
+type columnSettings struct {
+	whitespace     WhitespacePolicy
+	numbers        *NumberParser
+	sliceDelimiter string
+
+	// formatted is true if the column has a format, which may make integer fields epoch times.
+	formatted bool
+}
+
+// settings returns the reader's settings for each column, indexed like its column names, so that
+// decoding a cell does not look anything up by name.
+func (r *Reader) settings() []columnSettings {
+
+	if len(r.columnSettings) == len(r.ColumnNames) {
+		return r.columnSettings
+	}
+
+	r.columnSettings = make([]columnSettings, len(r.ColumnNames))
+	r.columnKinds = cellKinds{kinds: make([]ColumnKind, len(r.ColumnNames)), typed: make([]bool, len(r.ColumnNames))}
+	for i, name := range r.ColumnNames {
+
+		r.columnKinds.kinds[i], r.columnKinds.typed[i] = r.columnTypes[name]
+
+		policy, exists := r.whitespacePolicies[name]
+		if !exists {
+			policy = r.whitespacePolicy
+		}
+
+		delimiter, exists := r.sliceDelimiters[name]
+		if !exists {
+			delimiter = r.sliceDelimiter
+		}
+
+		_, formatted := r.ColumnFormats[name]
+		if _, conditional := r.conditionalFormats[name]; conditional {
+			formatted = true
+		}
+
+		r.columnSettings[i] = columnSettings{
+			whitespace:     policy,
+			numbers:        r.numberParser(name),
+			sliceDelimiter: delimiter,
+			formatted:      formatted,
+		}
+	}
+
+	r.columnRepeats = nil
+	if r.planConfig.repeated {
+		r.columnRepeats = repeatedColumns(r.ColumnNames)
+	}
+
+	return r.columnSettings
+}
+
+func buildDecodePlan(vType reflect.Type, columnNames []string, config planConfig, normalize HeaderNormalizer) (*decodePlan, error) {
+
+	plan := &decodePlan{matches: make([]ColumnMatch, len(columnNames)), byName: make(map[string]int)}
+	fields := make([]*reflect.StructField, len(columnNames))
+	elements := make([]*elementBinding, len(columnNames))
+	claimed := make(map[string]bool)
+
+	converted := make(map[string]bool)
+	if config.converted != "" {
+		for _, column := range strings.Split(config.converted, "\x00") {
+			converted[column] = true
+		}
+	}
+
+	// Repeated columns are matched once, by their first occurrence, which decodes them all.
+	var repeats map[string][]int
+	if config.repeated {
+		repeats = repeatedColumns(columnNames)
+	}
+	later := func(i int) bool {
+		indexes, repeated := repeats[columnNames[i]]
+		return repeated && indexes[0] != i
+	}
+
+	for i, name := range columnNames {
+
+		plan.matches[i] = ColumnMatch{Column: name, Method: MatchNone}
+		if later(i) {
+			continue
+		}
+
+		structField, exists := fieldForColumn(vType, name)
+		if !exists {
+			structField, exists = nestedFieldForColumn(vType, name, config.separator)
+		}
+		if !exists {
+			structField, exists = prefixedFieldForColumn(vType, name, config.separator)
+		}
+		if !exists {
+			structField, elements[i], exists = indexedFieldForColumn(vType, name, config.separator, config.maxElements)
+		}
+		if !exists {
+			continue
+		}
+
+		fields[i] = &structField
+		if elements[i] == nil {
+			claimed[fieldIndexKey(structField)] = true
+		}
+		plan.matches[i] = ColumnMatch{Column: name, Field: structField.Name, Method: MatchExact, Similarity: 1}
+	}
+
+	// Columns without an exact match may still be matched to a field whose name normalizes to the
+	// same, or to a similarly named field, that no other column has claimed.
+	if normalize != nil {
+		candidates := fieldCandidates(vType, nil)
+		for i, name := range columnNames {
+
+			if fields[i] != nil || later(i) {
+				continue
+			}
+
+			field, matched := normalizedMatch(name, candidates, claimed, normalize)
+			if !matched {
+				continue
+			}
+
+			fields[i] = &field
+			claimed[fieldIndexKey(field)] = true
+			plan.matches[i] = ColumnMatch{Column: name, Field: field.Name, Method: MatchNormalized, Similarity: 1}
+		}
+	}
+
+	if config.fuzzyThreshold > 0 {
+		candidates := fieldCandidates(vType, nil)
+		for i, name := range columnNames {
+
+			if fields[i] != nil || later(i) {
+				continue
+			}
+
+			candidate, similarity, matched := fuzzyMatch(name, candidates, claimed, config.fuzzyThreshold)
+			if !matched {
+				continue
+			}
+
+			fields[i] = &candidate.field
+			claimed[fieldIndexKey(candidate.field)] = true
+			plan.matches[i] = ColumnMatch{Column: name, Field: candidate.field.Name, Method: MatchFuzzy, Similarity: similarity}
+		}
+	}
+
+	for i, name := range columnNames {
+
+		if later(i) {
+			plan.matches[i] = plan.matches[repeats[name][0]]
+		}
+
+		// Skip this column if it doesn't exist in the struct.
+		if fields[i] == nil {
+			continue
+		}
+		structField := *fields[i]
+
+		// Fields of elements are set on the element's struct.
+		owner := vType
+		if elements[i] != nil {
+			owner = elements[i].structType
+			if !fieldSettable(vType, elements[i].index) {
+				continue
+			}
+			plan.addGroup(elements[i].index)
+		}
+
+		// Fields that cannot be set through reflection are ignored.
+		if !fieldSettable(owner, structField.Index) {
+			continue
+		}
+
+		col := columnPlan{
+			column:  i,
+			name:    name,
+			field:   structField,
+			repeats: repeats[name],
+			element: elements[i],
+		}
+
+		if col.repeats != nil {
+			fieldType, sliceType, isValidType := getFieldTypeInfo(structField.Type)
+			if sliceType != nil && isUnmarshaler(sliceType) {
+				col.unmarshal, isValidType = true, true
+			}
+			if converted[name] || sliceType == nil || !isValidType {
+				return nil, errors.Errorf("repeated column %q must map to a slice or array field", name)
+			}
+			col.fieldType, col.sliceType = fieldType, sliceType
+			plan.byName[name] = len(plan.columns)
+			plan.columns = append(plan.columns, col)
+			continue
+		}
+
+		if converted[name] {
+			col.converted, col.fieldType = true, structField.Type
+			plan.byName[name] = len(plan.columns)
+			plan.columns = append(plan.columns, col)
+			continue
+		}
+
+		if isUnmarshaler(getBaseType(structField.Type)) {
+			col.unmarshal, col.fieldType = true, getBaseType(structField.Type)
+			plan.byName[name] = len(plan.columns)
+			plan.columns = append(plan.columns, col)
+			continue
+		}
+
+		if index, nullable := nullValueIndex(getBaseType(structField.Type)); nullable {
+			col.nullable, col.nullValue = true, index
+			col.fieldType = getBaseType(structField.Type).Field(index).Type
+			plan.byName[name] = len(plan.columns)
+			plan.columns = append(plan.columns, col)
+			continue
+		}
+
+		fieldType, sliceType, isValidType := getFieldTypeInfo(structField.Type)
+		if sliceType != nil && isUnmarshaler(sliceType) {
+			col.unmarshal, isValidType = true, true
+		}
+		if !isValidType {
+			return nil, ErrInvalidFieldType
+		}
+
+		col.fieldType, col.sliceType = fieldType, sliceType
+		col.offset, col.fast = fastPathOffset(owner, structField)
+
+		plan.byName[name] = len(plan.columns)
+		plan.columns = append(plan.columns, col)
+	}
+
+	return plan, nil
+}
+
+// addGroup adds the slice or array of structs at index to the plan's groups unless it holds it.
+func (p *decodePlan) addGroup(index []int) {
+
+	for _, group := range p.groups {
+		if reflect.DeepEqual(group, index) {
+			return
+		}
+	}
+
+	p.groups = append(p.groups, index)
+}
+
+// fieldSettable reports whether the field of vType at index is exported and not promoted through
+// an embedded pointer to an unexported struct type, which could not be allocated.
+func fieldSettable(vType reflect.Type, index []int) bool {
+
+	t := vType
+	for i, x := range index {
+		field := getBaseType(t).Field(x)
+		last := i == len(index)-1
+		if field.PkgPath != "" && (last || field.Type.Kind() == reflect.Ptr) {
+			return false
+		}
+		t = field.Type
+	}
+
+	return true
+}
+
+// fastPathOffset returns the offset of field from the start of vType and whether the field can be set
+// on the fast path. Only exported, non pointer primitive fields that are not reached through an
+// embedded pointer are eligible.
+func fastPathOffset(vType reflect.Type, field reflect.StructField) (uintptr, bool) {
+
+	if field.PkgPath != "" || !isPrimitiveKind(field.Type.Kind()) {
+		return 0, false
+	}
+
+	var offset uintptr
+	t := vType
+	for _, index := range field.Index {
+		if t.Kind() != reflect.Struct {
+			return 0, false
+		}
+		f := t.Field(index)
+		offset += f.Offset
+		t = f.Type
+	}
+
+	return offset, true
+}
+
+func isPrimitiveKind(k reflect.Kind) bool {
+
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Bool, reflect.String:
+		return true
+	}
+
+	return false
+}
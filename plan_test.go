@@ -0,0 +1,100 @@
+package csvee
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_planFor verifies decode plans are memoized per struct type and column set
+func TestReader_planFor(t *testing.T) {
+
+	newReader := func(columnNames ...string) *Reader {
+		reader, err := NewReader(strings.NewReader(""), &ReaderOptions{ColumnNames: columnNames})
+		require.NoError(t, err)
+		return reader
+	}
+
+	vType := reflect.TypeOf(readTo{})
+
+	plan, err := newReader("I", "X", "S").planFor(vType)
+	require.NoError(t, err)
+	require.Len(t, plan.columns, 2)
+	assert.Equal(t, 0, plan.columns[0].column)
+	assert.Equal(t, 2, plan.columns[1].column)
+	assert.Equal(t, reflect.String, plan.columns[1].fieldType.Kind())
+
+	samePlan, err := newReader("I", "X", "S").planFor(vType)
+	require.NoError(t, err)
+	assert.True(t, plan == samePlan)
+
+	reader := newReader("I", "X", "S")
+	require.NoError(t, reader.SetColumns([]string{"S", "I"}))
+	otherPlan, err := reader.planFor(vType)
+	require.NoError(t, err)
+	assert.False(t, plan == otherPlan)
+	assert.Equal(t, "S", otherPlan.columns[0].name)
+
+	_, err = newReader("C").planFor(reflect.TypeOf(struct{ C chan int }{}))
+	assert.Equal(t, ErrInvalidFieldType, err)
+}
+
+// TestPlanCacheStats verifies plan cache lookups are counted and the cache can be emptied
+func TestPlanCacheStats(t *testing.T) {
+
+	ResetPlanCache()
+	assert.Equal(t, CacheStats{}, PlanCacheStats())
+
+	read := func(input string) {
+		reader, err := NewReader(strings.NewReader(input), &ReaderOptions{ReadHeaders: true})
+		require.NoError(t, err)
+		var rows []readTo
+		require.NoError(t, reader.ReadAll(&rows))
+	}
+
+	read("I,S\n1,a\n2,b\n")
+	read("I,S\n3,c\n")
+	read("S,I\nd,4\n")
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 2, Entries: 2}, PlanCacheStats())
+
+	ResetPlanCache()
+	assert.Equal(t, CacheStats{}, PlanCacheStats())
+
+	read("I,S\n1,a\n")
+	assert.Equal(t, CacheStats{Misses: 1, Entries: 1}, PlanCacheStats())
+}
+
+// TestSetPlanCacheSize verifies the plan cache evicts the least recently used plans beyond its size
+func TestSetPlanCacheSize(t *testing.T) {
+
+	ResetPlanCache()
+	defer ResetPlanCache()
+	defer SetPlanCacheSize(defaultPlanCacheSize)
+
+	read := func(input string) {
+		reader, err := NewReader(strings.NewReader(input), &ReaderOptions{ReadHeaders: true})
+		require.NoError(t, err)
+		var rows []readTo
+		require.NoError(t, reader.ReadAll(&rows))
+	}
+
+	SetPlanCacheSize(2)
+	read("I,S\n1,a\n")
+	read("S,I\na,1\n")
+	read("I,S\n1,a\n")
+	read("I\n1\n")
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 3, Evictions: 1, Entries: 2}, PlanCacheStats())
+
+	// "S,I" was the least recently used, so it was evicted and "I,S" was kept.
+	read("I,S\n1,a\n")
+	read("S,I\na,1\n")
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 4, Evictions: 2, Entries: 2}, PlanCacheStats())
+
+	SetPlanCacheSize(0)
+	assert.Equal(t, 0, PlanCacheStats().Entries)
+	read("I,S\n1,a\n")
+	assert.Equal(t, 0, PlanCacheStats().Entries)
+}
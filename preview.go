@@ -0,0 +1,146 @@
+package csvee
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// previewSampleBytes is how much of the input Preview reads to detect its dialect.
+const previewSampleBytes = 64 * 1024
+
+// previewDialects are the dialects Preview chooses between, in order of preference.
+var previewDialects = []Dialect{DialectExcel, DialectSemicolonEU, DialectTSV, {Delimiter: '|'}}
+
+// FilePreview summarizes the start of a CSV file, as an import screen shows it before its columns
+// are mapped.
+type FilePreview struct {
+	// Dialect is the dialect the file was read with.
+	Dialect Dialect
+
+	// Headers are the names of the file's columns.
+	Headers []string
+
+	// Schema holds the type of each column, inferred from the sample rows as InferSchema does.
+	Schema Schema
+
+	// Rows holds the sample records, with each cell converted to the type of its column: int64,
+	// float64, bool, time.Time, or string, or nil if it is blank or null.
+	Rows [][]interface{}
+
+	// Widths holds the width of each column in characters, which is that of its longest header or
+	// sample cell.
+	Widths []int
+}
+
+// Preview reads the headers and up to rows records from r and returns them with the types inferred
+// for their columns, for a user to review before the file is imported. Unless options set a Dialect
+// or Delimiter, the dialect is detected from the start of the input: the delimiter among comma,
+// semicolon, tab, and pipe that splits its lines into the same number of fields most consistently.
+// options default to reading headers, and their other settings, such as NullValues and Encoding,
+// apply as they would to a Reader.
+func Preview(r io.Reader, rows int, options *ReaderOptions) (FilePreview, error) {
+
+	if rows <= 0 {
+		return FilePreview{}, errors.Errorf("sample rows must be positive, got %d", rows)
+	}
+
+	rOptions := ReaderOptions{ReadHeaders: true}
+	if options != nil {
+		rOptions = *options
+	}
+
+	sample := make([]byte, previewSampleBytes)
+	n, err := io.ReadFull(r, sample)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return FilePreview{}, err
+	}
+	sample = sample[:n]
+
+	if rOptions.Dialect == nil && rOptions.Delimiter == 0 {
+		decoded, err := io.ReadAll(newDecodingReader(bytes.NewReader(sample), rOptions.Encoding))
+		if err != nil {
+			return FilePreview{}, err
+		}
+		dialect := detectDialect(decoded, n == previewSampleBytes)
+		rOptions.Dialect = &dialect
+	}
+
+	reader, err := NewReader(io.MultiReader(bytes.NewReader(sample), r), &rOptions)
+	if err != nil {
+		return FilePreview{}, err
+	}
+
+	schema, records, err := inferSchema(reader, rows)
+	if err != nil {
+		return FilePreview{}, err
+	}
+
+	preview := FilePreview{
+		Dialect: rOptions.dialect(),
+		Headers: append([]string(nil), reader.ColumnNames...),
+		Schema:  schema,
+		Rows:    make([][]interface{}, len(records)),
+		Widths:  make([]int, len(reader.ColumnNames)),
+	}
+	if preview.Dialect.Delimiter == 0 {
+		preview.Dialect.Delimiter = ','
+	}
+
+	for j, name := range preview.Headers {
+		preview.Widths[j] = utf8.RuneCountInString(name)
+	}
+	for i, record := range records {
+		preview.Rows[i] = make([]interface{}, len(record))
+		for j, cell := range record {
+			preview.Rows[i][j] = reader.inferredValue(cell, schema.Columns[j])
+			if width := utf8.RuneCountInString(cell); width > preview.Widths[j] {
+				preview.Widths[j] = width
+			}
+		}
+	}
+
+	return preview, nil
+}
+
+// detectDialect returns the dialect among previewDialects whose delimiter splits the most records
+// of sample into as many fields as its first, preferring those that split it into more fields. The
+// last line of a truncated sample, which may be incomplete, is ignored. Comma separated input is
+// assumed if no delimiter splits the first record.
+func detectDialect(sample []byte, truncated bool) Dialect {
+
+	if truncated {
+		if end := bytes.LastIndexByte(sample, '\n'); end >= 0 {
+			sample = sample[:end+1]
+		}
+	}
+
+	best, bestConsistent, bestFields := DialectExcel, 0, 1
+	for _, dialect := range previewDialects {
+
+		reader := csv.NewReader(bytes.NewReader(sample))
+		reader.FieldsPerRecord = -1
+		dialect.apply(reader)
+
+		records, err := reader.ReadAll()
+		if err != nil || len(records) == 0 || len(records[0]) < 2 {
+			continue
+		}
+
+		consistent := 0
+		for _, record := range records {
+			if len(record) == len(records[0]) {
+				consistent++
+			}
+		}
+
+		if consistent > bestConsistent || consistent == bestConsistent && len(records[0]) > bestFields {
+			best, bestConsistent, bestFields = dialect, consistent, len(records[0])
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,83 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPreview verifies the headers, typed sample rows, inferred types, widths, and dialect of a file
+func TestPreview(t *testing.T) {
+
+	input := "id;name;price;joined;active\n1;Zoë;1,5;2021-03-04;true\n2;\"Bob; Jr.\";;2021-03-05;false\n3;Al;2;2021-03-06;true\n"
+
+	preview, err := Preview(strings.NewReader(input), 2, &ReaderOptions{ReadHeaders: true, Dialect: &DialectSemicolonEU})
+	require.NoError(t, err)
+	assert.Equal(t, DialectSemicolonEU, preview.Dialect)
+
+	preview, err = Preview(strings.NewReader(input), 2, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, DialectSemicolonEU, preview.Dialect)
+	assert.Equal(t, []string{"id", "name", "price", "joined", "active"}, preview.Headers)
+	assert.Equal(t, []SchemaColumn{
+		{Name: "id", Type: "int64"},
+		{Name: "name", Type: "string"},
+		{Name: "price", Type: "string"},
+		{Name: "joined", Type: "time.Time", Format: "2006-01-02"},
+		{Name: "active", Type: "bool"},
+	}, preview.Schema.Columns)
+	assert.Equal(t, [][]interface{}{
+		{int64(1), "Zoë", "1,5", time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC), true},
+		{int64(2), "Bob; Jr.", nil, time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC), false},
+	}, preview.Rows)
+	assert.Equal(t, []int{2, 8, 5, 10, 6}, preview.Widths)
+}
+
+// TestPreview_Dialects verifies the delimiter is detected from the start of the input
+func TestPreview_Dialects(t *testing.T) {
+
+	var testCases = []struct {
+		name       string
+		input      string
+		expDialect Dialect
+		expHeaders []string
+	}{
+		{name: "comma", input: "a,b;c\n1,2;3\n", expDialect: DialectExcel, expHeaders: []string{"a", "b;c"}},
+		{name: "tab", input: "a\tb\tc,d\n1\t2\t3,4\n", expDialect: DialectTSV, expHeaders: []string{"a", "b", "c,d"}},
+		{name: "tie", input: "a\tb,c\n1\t2,3\n", expDialect: DialectExcel, expHeaders: []string{"a\tb", "c"}},
+		{name: "pipe", input: "a|b|c\n1|2|3\n", expDialect: Dialect{Delimiter: '|'}, expHeaders: []string{"a", "b", "c"}},
+		{name: "quoted delimiters", input: "a;\"b,c,d\"\n1;\"2,3,4\"\n", expDialect: DialectSemicolonEU, expHeaders: []string{"a", "b,c,d"}},
+		{name: "single column", input: "a\n1\n", expDialect: DialectExcel, expHeaders: []string{"a"}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			preview, err := Preview(strings.NewReader(tt.input), 10, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expDialect, preview.Dialect)
+			assert.Equal(t, tt.expHeaders, preview.Headers)
+			assert.Len(t, preview.Rows, 1)
+		})
+	}
+
+	_, err := Preview(strings.NewReader("a\n"), 0, nil)
+	assert.EqualError(t, err, "sample rows must be positive, got 0")
+}
+
+// TestPreview_Truncated verifies the input beyond the detection sample is still read
+func TestPreview_Truncated(t *testing.T) {
+
+	input := "name|note\n" + strings.Repeat("x|"+strings.Repeat("y", 100)+"\n", 1000)
+
+	preview, err := Preview(strings.NewReader(input), 1000, &ReaderOptions{ReadHeaders: true, NullValues: []string{"x"}})
+	require.NoError(t, err)
+	assert.Equal(t, Dialect{Delimiter: '|'}, preview.Dialect)
+	assert.Len(t, preview.Rows, 1000)
+	assert.Equal(t, []interface{}{nil, strings.Repeat("y", 100)}, preview.Rows[999])
+	assert.Equal(t, []int{4, 100}, preview.Widths)
+}
@@ -0,0 +1,63 @@
+package csvee
+
+// Provenance describes where a record came from.
+type Provenance struct {
+	// Source is ReaderOptions.SourceName, or the name of the input if it is a file.
+	Source string
+
+	// Line is the 1-based line the record starts on. It differs from Row when there are headers or
+	// quoted fields span lines.
+	Line int
+
+	// Offset is the byte offset in the input at which reading the record began. Any blank lines
+	// skipped before the record are included.
+	Offset int64
+
+	// Row is the 1-based number of the record, excluding headers.
+	Row int
+
+	// Raw holds the record's original cells.
+	Raw []string
+}
+
+// LastProvenance returns the provenance of the record most recently read, if ReaderOptions.
+// TrackProvenance was set. To pair each value decoded by ReadAll or Pump with its provenance, call
+// it from the AfterRow hook.
+func (r *Reader) LastProvenance() Provenance {
+
+	return r.provenance
+}
+
+// namedSource is implemented by inputs such as *os.File.
+type namedSource interface {
+	Name() string
+}
+
+// positionRecord records where the record in item starts, given the input offset before it was read.
+func (r *Reader) positionRecord(item *recordItem, offset int64) {
+
+	// Records from a RecordSource have no position in any input.
+	if r.records != nil {
+		item.fields = []fieldPosition{}
+		return
+	}
+
+	item.offset, item.end = offset, r.CSVReader.InputOffset()
+	item.line, _ = r.CSVReader.FieldPos(0)
+}
+
+// trackRecord sets the provenance of the record that was just read.
+func (r *Reader) trackRecord(item recordItem) {
+
+	if !r.trackProvenance {
+		return
+	}
+
+	r.provenance = Provenance{
+		Source: r.sourceName,
+		Line:   item.line,
+		Offset: item.offset,
+		Row:    item.row,
+		Raw:    append([]string(nil), item.record...),
+	}
+}
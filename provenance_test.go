@@ -0,0 +1,73 @@
+package csvee
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_Provenance verifies each decoded row can be paired with where it came from
+func TestReader_Provenance(t *testing.T) {
+
+	const inData = "I,S\n1,a\n\n2,\"b,c\"\n3,d\n"
+
+	for _, depth := range []int{0, 2} {
+
+		var reader *Reader
+		var actualProvenance []Provenance
+
+		reader, err := NewReader(strings.NewReader(inData), &ReaderOptions{
+			ReadHeaders:     true,
+			TrackProvenance: true,
+			SourceName:      "vendor.csv",
+			PipelineDepth:   depth,
+			AfterRow: func(n int, v interface{}) error {
+				actualProvenance = append(actualProvenance, reader.LastProvenance())
+				return nil
+			},
+		})
+		require.NoError(t, err)
+
+		var actualData []readTo
+		require.NoError(t, reader.ReadAll(&actualData))
+
+		assert.Equal(t, []Provenance{
+			{Source: "vendor.csv", Line: 2, Offset: 4, Row: 1, Raw: []string{"1", "a"}},
+			{Source: "vendor.csv", Line: 4, Offset: 8, Row: 2, Raw: []string{"2", "b,c"}},
+			{Source: "vendor.csv", Line: 5, Offset: 17, Row: 3, Raw: []string{"3", "d"}},
+		}, actualProvenance)
+	}
+}
+
+// TestReader_ProvenanceSource verifies the source defaults to the input's name and quoted line breaks are accounted for
+func TestReader_ProvenanceSource(t *testing.T) {
+
+	const inData = "I,S\n1,\"a\nb\"\n2,c\n"
+
+	file, err := ioutil.TempFile("", "csvee-provenance-*.csv")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(inData)
+	require.NoError(t, err)
+	_, err = file.Seek(0, 0)
+	require.NoError(t, err)
+
+	reader, err := NewReader(file, &ReaderOptions{ReadHeaders: true, TrackProvenance: true})
+	require.NoError(t, err)
+
+	_, err = reader.ReadRaw()
+	require.NoError(t, err)
+	_, err = reader.ReadRaw()
+	require.NoError(t, err)
+	assert.Equal(t, Provenance{Source: file.Name(), Line: 4, Offset: 12, Row: 2, Raw: []string{"2", "c"}}, reader.LastProvenance())
+
+	reader, err = NewReader(strings.NewReader(inData), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	_, err = reader.ReadRaw()
+	require.NoError(t, err)
+	assert.Equal(t, Provenance{}, reader.LastProvenance())
+}
@@ -0,0 +1,203 @@
+package csvee
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"time"
+)
+
+// Sink receives each row decoded by Pump.
+type Sink interface {
+	Write(v interface{}) error
+}
+
+// SinkFunc is an adapter to allow the use of ordinary functions as a Sink.
+type SinkFunc func(v interface{}) error
+
+// Write calls f(v).
+func (f SinkFunc) Write(v interface{}) error {
+
+	return f(v)
+}
+
+// RetryPolicy controls how Pump retries rows that the Sink fails to write.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a row is written before it is considered poisoned.
+	// Values less than one are treated as one.
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry; it doubles on each subsequent retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff, if greater than zero, caps the wait between retries.
+	MaxBackoff time.Duration
+
+	// Poison, if set, is called with rows that could not be written after MaxAttempts. Returning nil
+	// skips the row and continues pumping; returning an error stops the pump.
+	Poison func(n int, v interface{}, err error) error
+}
+
+// Pump reads each line of the CSV into a new instance of v's type and writes it to sink until the
+// end of the CSV data is reached. v is only used to determine the type of the values passed to sink.
+func (r *Reader) Pump(v interface{}, sink Sink, policy ...*RetryPolicy) error {
+
+	if v == nil {
+		return ErrReadTargetNil
+	}
+	if sink == nil {
+		return ErrPumpSinkNil
+	}
+
+	retry := &RetryPolicy{}
+	if len(policy) > 0 && policy[0] != nil {
+		retry = policy[0]
+	}
+
+	base := getBaseType(reflect.TypeOf(v))
+
+	return r.recordRun(func() (int, error) {
+
+		r.startPipeline()
+		defer r.stopPipeline()
+
+		var rowsWritten int
+		for {
+
+			next := reflect.New(base).Interface()
+			err := r.Read(next)
+			if err == io.EOF {
+				return rowsWritten, nil
+			}
+			if err != nil {
+				return rowsWritten, err
+			}
+
+			written, err := r.writeToSink(sink, next, retry)
+			if err != nil {
+				return rowsWritten, err
+			}
+			if written {
+				rowsWritten++
+			}
+		}
+	})
+}
+
+// PumpContext writes rows to sink as Pump does, stopping with ctx's error if ctx is done before
+// every row has been written. Waits between retries are cut short when ctx is done.
+func (r *Reader) PumpContext(ctx context.Context, v interface{}, sink Sink, policy ...*RetryPolicy) error {
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.ctx = ctx
+	defer func() { r.ctx = nil }()
+
+	return r.Pump(v, sink, policy...)
+}
+
+// ReadEach reads each line of the CSV into a new instance of v's type and passes it to fn until the
+// end of the CSV data is reached, so that rows can be processed without holding them all in memory.
+// v is only used to determine the type of the values passed to fn. If fn returns ErrStopReading,
+// ReadEach stops and returns nil, and the rows after the one passed to fn can still be read; any
+// other error stops ReadEach and is returned.
+func (r *Reader) ReadEach(v interface{}, fn func(v interface{}) error) error {
+
+	if v == nil {
+		return ErrReadTargetNil
+	}
+	if fn == nil {
+		return ErrReadEachFuncNil
+	}
+
+	base := getBaseType(reflect.TypeOf(v))
+
+	return r.recordRun(func() (int, error) {
+
+		r.startPipeline()
+		defer r.stopPipeline()
+
+		var rowsRead int
+		for {
+
+			next := reflect.New(base).Interface()
+			err := r.Read(next)
+			if err == io.EOF {
+				return rowsRead, nil
+			}
+			if err != nil {
+				return rowsRead, err
+			}
+
+			rowsRead++
+			if err := fn(next); err == ErrStopReading {
+				return rowsRead, nil
+			} else if err != nil {
+				return rowsRead, err
+			}
+		}
+	})
+}
+
+// writeToSink writes v to sink, retrying according to retry. It returns false if the row was
+// poisoned and skipped. Poisoned rows that stop the pump are recorded in the manifest by recordRun,
+// so only skipped ones are recorded here.
+func (r *Reader) writeToSink(sink Sink, v interface{}, retry *RetryPolicy) (bool, error) {
+
+	attempts := retry.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	backoff := retry.InitialBackoff
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+
+		if err = sink.Write(v); err == nil {
+			return true, nil
+		}
+
+		if attempt == attempts {
+			break
+		}
+
+		if err := r.wait(backoff); err != nil {
+			return false, err
+		}
+		backoff *= 2
+		if retry.MaxBackoff > 0 && backoff > retry.MaxBackoff {
+			backoff = retry.MaxBackoff
+		}
+	}
+
+	if retry.Poison == nil {
+		return false, err
+	}
+
+	if poisonErr := retry.Poison(r.rowsRead, v, err); poisonErr != nil {
+		return false, poisonErr
+	}
+
+	if r.manifest != nil {
+		r.manifest.reject(r.rowsRead, err)
+	}
+
+	return false, nil
+}
+
+// wait pauses for d, returning early with the error of the read's context if it is done first.
+func (r *Reader) wait(d time.Duration) error {
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-r.context().Done():
+		return r.context().Err()
+	}
+}
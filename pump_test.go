@@ -0,0 +1,226 @@
+package csvee
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_Pump verifies rows are written to the sink, with retries and poison handling
+func TestReader_Pump(t *testing.T) {
+
+	errTransient := errors.New("transient")
+
+	var testCases = []struct {
+		name        string
+		inPolicy    *RetryPolicy
+		inFailures  map[int]int
+		expWritten  []int
+		expPoisoned []int
+		expErr      error
+	}{
+		{
+			name:       "success",
+			expWritten: []int{1, 2, 3},
+		},
+		{
+			name:       "retry transient failure",
+			inPolicy:   &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+			inFailures: map[int]int{2: 2},
+			expWritten: []int{1, 2, 3},
+		},
+		{
+			name:       "no retries",
+			inFailures: map[int]int{2: 1},
+			expWritten: []int{1},
+			expErr:     errTransient,
+		},
+		{
+			name:        "poisoned row skipped",
+			inFailures:  map[int]int{2: 5},
+			expWritten:  []int{1, 3},
+			expPoisoned: []int{2},
+			inPolicy: &RetryPolicy{
+				MaxAttempts:    2,
+				InitialBackoff: time.Millisecond,
+				MaxBackoff:     time.Millisecond,
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader("I\n1\n2\n3\n"), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+
+			var poisoned []int
+			if tt.inPolicy != nil && tt.expPoisoned != nil {
+				tt.inPolicy.Poison = func(n int, v interface{}, err error) error {
+					poisoned = append(poisoned, v.(*readTo).I)
+					return nil
+				}
+			}
+
+			var written []int
+			sink := SinkFunc(func(v interface{}) error {
+				row := v.(*readTo)
+				if tt.inFailures[row.I] > 0 {
+					tt.inFailures[row.I]--
+					return errTransient
+				}
+				written = append(written, row.I)
+				return nil
+			})
+
+			err = reader.Pump(readTo{}, sink, tt.inPolicy)
+			assert.Equal(t, tt.expErr, err)
+			assert.Equal(t, tt.expWritten, written)
+			assert.Equal(t, tt.expPoisoned, poisoned)
+		})
+	}
+}
+
+// TestReader_PumpContext verifies cancelling the context interrupts the wait between retries
+func TestReader_PumpContext(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reader, err := NewReader(strings.NewReader("I\n1\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	sink := SinkFunc(func(v interface{}) error {
+		cancel()
+		return errors.New("unavailable")
+	})
+
+	start := time.Now()
+	err = reader.PumpContext(ctx, readTo{}, sink, &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Hour})
+	assert.Equal(t, context.Canceled, err)
+	assert.Less(t, time.Since(start), time.Minute)
+}
+
+// TestReader_PumpManifest verifies a poisoned row is recorded in the manifest once
+func TestReader_PumpManifest(t *testing.T) {
+
+	errPoison := errors.New("poisoned")
+
+	var testCases = []struct {
+		name       string
+		inPoison   error
+		expErr     error
+		expRejects []RejectedRow
+	}{
+		{
+			name:       "poisoned row skipped",
+			expRejects: []RejectedRow{{Row: 2, Error: "unavailable"}},
+		},
+		{
+			name:       "poisoned row stops the pump",
+			inPoison:   errPoison,
+			expErr:     errPoison,
+			expRejects: []RejectedRow{{Row: 2, Error: "poisoned"}},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var manifestBuf bytes.Buffer
+			reader, err := NewReader(
+				strings.NewReader("I\n1\n2\n3\n"),
+				&ReaderOptions{ReadHeaders: true, Manifest: &manifestBuf},
+			)
+			require.NoError(t, err)
+
+			sink := SinkFunc(func(v interface{}) error {
+				if v.(*readTo).I == 2 {
+					return errors.New("unavailable")
+				}
+				return nil
+			})
+			policy := &RetryPolicy{
+				Poison: func(n int, v interface{}, err error) error {
+					return tt.inPoison
+				},
+			}
+
+			err = reader.Pump(readTo{}, sink, policy)
+			assert.Equal(t, tt.expErr, err)
+
+			var manifest Manifest
+			require.NoError(t, json.Unmarshal(manifestBuf.Bytes(), &manifest))
+			assert.Equal(t, tt.expRejects, manifest.RejectedRows)
+		})
+	}
+}
+
+// TestReader_ReadEach verifies each row is passed to the callback and ErrStopReading stops early
+func TestReader_ReadEach(t *testing.T) {
+
+	errCallback := errors.New("callback")
+
+	var testCases = []struct {
+		name     string
+		inStopAt int
+		inErr    error
+		expRead  []int
+		expErr   error
+	}{
+		{
+			name:    "all rows",
+			expRead: []int{1, 2, 3},
+		},
+		{
+			name:     "stopped",
+			inStopAt: 2,
+			inErr:    ErrStopReading,
+			expRead:  []int{1, 2},
+		},
+		{
+			name:     "error",
+			inStopAt: 1,
+			inErr:    errCallback,
+			expRead:  []int{1},
+			expErr:   errCallback,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader("I\n1\n2\n3\n"), &ReaderOptions{ReadHeaders: true, PipelineDepth: 2})
+			require.NoError(t, err)
+
+			var read []int
+			err = reader.ReadEach(readTo{}, func(v interface{}) error {
+				row := v.(*readTo)
+				read = append(read, row.I)
+				if row.I == tt.inStopAt {
+					return tt.inErr
+				}
+				return nil
+			})
+			assert.Equal(t, tt.expErr, err)
+			assert.Equal(t, tt.expRead, read)
+
+			if tt.inStopAt > 0 {
+				var rest []readTo
+				require.NoError(t, reader.ReadAll(&rest))
+				assert.Len(t, rest, 3-tt.inStopAt)
+			}
+		})
+	}
+
+	reader, err := NewReader(strings.NewReader("I\n1\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	assert.Equal(t, ErrReadEachFuncNil, reader.ReadEach(readTo{}, nil))
+}
@@ -0,0 +1,25 @@
+package csvee
+
+import (
+	"fmt"
+	"strings"
+)
+
+// evenRecord returns record with empty fields added for missing trailing columns, or its extra
+// fields removed, raising a warning about the difference.
+func (r *Reader) evenRecord(record []string) []string {
+
+	columns := len(r.ColumnNames)
+	if len(record) > columns {
+		extra := record[columns:]
+		r.warn("", strings.Join(extra, ","), fmt.Sprintf("record has %d fields but there are %d columns; the extra fields were ignored", len(record), columns))
+		return record[:columns]
+	}
+
+	missing := r.ColumnNames[len(record):]
+	r.warn(missing[0], "", fmt.Sprintf("record has %d fields but there are %d columns; %s were treated as empty", len(record), columns, strings.Join(missing, ", ")))
+
+	even := make([]string, columns)
+	copy(even, record)
+	return even
+}
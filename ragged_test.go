@@ -0,0 +1,71 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type raggedRow struct {
+	Name string
+	Qty  int
+	Note string
+}
+
+// TestReader_AllowRaggedRows verifies short records are padded and long records truncated
+func TestReader_AllowRaggedRows(t *testing.T) {
+
+	const data = "Name,Qty,Note\na,1,x\nb\nc,3,z,extra,more\n"
+
+	reader, err := NewReader(strings.NewReader(data), &ReaderOptions{ReadHeaders: true, AllowRaggedRows: true})
+	require.NoError(t, err)
+
+	var rows []raggedRow
+	require.NoError(t, reader.ReadAll(&rows))
+	assert.Equal(t, []raggedRow{{Name: "a", Qty: 1, Note: "x"}, {Name: "b"}, {Name: "c", Qty: 3, Note: "z"}}, rows)
+
+	warnings := reader.Warnings()
+	require.Len(t, warnings, 2)
+	assert.Equal(t, "row 2, column \"Qty\": record has 1 fields but there are 3 columns; Qty, Note were treated as empty", warnings[0].String())
+	assert.Equal(t, "row 3: record has 5 fields but there are 3 columns; the extra fields were ignored", warnings[1].String())
+	assert.Equal(t, "extra,more", warnings[1].Value)
+
+	reader, err = NewReader(strings.NewReader(data), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	assert.Error(t, reader.ReadAll(&rows))
+
+	reader, err = NewReader(strings.NewReader(data), &ReaderOptions{ReadHeaders: true, AllowRaggedRows: true})
+	require.NoError(t, err)
+
+	var frame Frame
+	require.NoError(t, reader.ReadAll(&frame))
+	notes, err := frame.Strings("Note")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"x", "", "z"}, notes)
+}
+
+// TestReader_AllowRaggedRowsWarningLimit verifies Warnings keeps only the first warnings of a ragged file while OnWarning sees all of them
+func TestReader_AllowRaggedRowsWarningLimit(t *testing.T) {
+
+	data := "Name,Qty,Note\n" + strings.Repeat("a\n", maxWarnings+10)
+
+	var seen int
+	reader, err := NewReader(strings.NewReader(data), &ReaderOptions{
+		ReadHeaders:     true,
+		AllowRaggedRows: true,
+		OnWarning:       func(Warning) { seen++ },
+	})
+	require.NoError(t, err)
+
+	var rows []raggedRow
+	require.NoError(t, reader.ReadAll(&rows))
+	assert.Len(t, rows, maxWarnings+10)
+	assert.Equal(t, maxWarnings+10, seen)
+
+	warnings := reader.Warnings()
+	require.Len(t, warnings, maxWarnings)
+	assert.Equal(t, 1, warnings[0].Row)
+	assert.Equal(t, maxWarnings, warnings[maxWarnings-1].Row)
+}
@@ -0,0 +1,55 @@
+package csvee
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter throttles the rate at which rows are read. *rate.Limiter from golang.org/x/time/rate
+// satisfies this interface.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// intervalLimiter is a Limiter that spaces rows evenly so no more than a fixed number are
+// allowed per second.
+type intervalLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newIntervalLimiter(rowsPerSecond float64) *intervalLimiter {
+
+	return &intervalLimiter{
+		interval: time.Duration(float64(time.Second) / rowsPerSecond),
+	}
+}
+
+// Wait blocks until the next row is allowed or ctx is done.
+func (l *intervalLimiter) Wait(ctx context.Context) error {
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,45 @@
+package csvee
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingLimiter struct {
+	calls int
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+
+	l.calls++
+	return nil
+}
+
+// TestReader_RateLimit verifies rows are throttled by RateLimit and Limiter
+func TestReader_RateLimit(t *testing.T) {
+
+	inData := "I\n1\n2\n3\n4\n5\n"
+
+	reader, err := NewReader(strings.NewReader(inData), &ReaderOptions{ReadHeaders: true, RateLimit: 100})
+	require.NoError(t, err)
+
+	start := time.Now()
+	var actualData []readTo
+	require.NoError(t, reader.ReadAll(&actualData))
+	assert.Len(t, actualData, 5)
+	assert.True(t, time.Since(start) >= 40*time.Millisecond)
+
+	limiter := &countingLimiter{}
+	reader, err = NewReader(strings.NewReader(inData), &ReaderOptions{ReadHeaders: true, Limiter: limiter})
+	require.NoError(t, err)
+
+	var row readTo
+	require.NoError(t, reader.Read(&row))
+	require.NoError(t, reader.Read(&row))
+	assert.Equal(t, 2, limiter.calls)
+}
@@ -0,0 +1,103 @@
+package csvee
+
+// RawRecord is a single undecoded CSV record. Its backing storage is reused by the reader, so a
+// RawRecord is only valid until the next call to ReadRaw; copy any fields that must outlive it.
+type RawRecord struct {
+	row     int
+	fields  []string
+	columns map[string]int
+}
+
+// Row returns the 1-based number of the record within the CSV data, excluding headers.
+func (rr RawRecord) Row() int {
+
+	return rr.row
+}
+
+// Len returns the number of fields in the record.
+func (rr RawRecord) Len() int {
+
+	return len(rr.fields)
+}
+
+// Field returns the field at index i. It is not copied: the fields of a record are slices of a
+// single string allocated by the csv.Reader.
+func (rr RawRecord) Field(i int) string {
+
+	return rr.fields[i]
+}
+
+// Bytes returns the field at index i as a byte slice that shares the field's memory rather than
+// copying it, for conversion layers that work on bytes. The slice must not be modified. Builds with
+// the csvee_nounsafe tag return a copy instead.
+func (rr RawRecord) Bytes(i int) []byte {
+
+	return stringBytes(rr.fields[i])
+}
+
+// Column returns the field for the named column and whether the column exists.
+func (rr RawRecord) Column(name string) (string, bool) {
+
+	i, exists := rr.columns[name]
+	if !exists || i >= len(rr.fields) {
+		return "", false
+	}
+
+	return rr.fields[i], true
+}
+
+// Raw returns the field for the named column, or an empty string if the column does not exist.
+func (rr RawRecord) Raw(column string) string {
+
+	field, _ := rr.Column(column)
+	return field
+}
+
+// ReadRaw reads the next record without decoding it. The reader's dialect, dedup window, rate
+// limit, and BeforeRow hook all apply. ReadRaw enables record reuse on the underlying csv.Reader
+// while it reads to avoid allocating a new slice per record, and restores the previous setting
+// before it returns, so other reads still get records of their own.
+func (r *Reader) ReadRaw() (RawRecord, error) {
+
+	reuse := r.CSVReader.ReuseRecord
+	r.CSVReader.ReuseRecord = true
+	defer func() { r.CSVReader.ReuseRecord = reuse }()
+
+	record, err := r.readRecord()
+	if err != nil {
+		return RawRecord{}, err
+	}
+
+	if len(record) != len(r.ColumnNames) {
+		return RawRecord{}, ErrColumnNamesMismatch
+	}
+
+	return r.LastRecord(), nil
+}
+
+// LastRecord returns the record most recently read by Read, ReadAll, Pump, or ReadRaw, as it
+// appeared in the input. It is available even if decoding the record failed, so that validation
+// messages can quote exactly what was entered. Like any RawRecord it is only valid until the next
+// read.
+func (r *Reader) LastRecord() RawRecord {
+
+	if r.rawColumns == nil || len(r.rawColumns) != len(r.ColumnNames) {
+		r.rawColumns = make(map[string]int, len(r.ColumnNames))
+		for i, name := range r.ColumnNames {
+			r.rawColumns[name] = i
+		}
+	}
+
+	return RawRecord{row: r.rowsRead, fields: r.lastRecord, columns: r.rawColumns}
+}
+
+// RawField returns field i of the record most recently read, or an empty string if it has no such
+// field.
+func (r *Reader) RawField(i int) string {
+
+	if i < 0 || i >= len(r.lastRecord) {
+		return ""
+	}
+
+	return r.lastRecord[i]
+}
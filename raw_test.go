@@ -0,0 +1,78 @@
+package csvee
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_ReadRaw verifies records are returned undecoded
+func TestReader_ReadRaw(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader("I,S\n1,a\n2,b\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	record, err := reader.ReadRaw()
+	require.NoError(t, err)
+	assert.Equal(t, 1, record.Row())
+	assert.Equal(t, 2, record.Len())
+	assert.Equal(t, "1", record.Field(0))
+	assert.Equal(t, []byte("a"), record.Bytes(1))
+	s, exists := record.Column("S")
+	assert.True(t, exists)
+	assert.Equal(t, "a", s)
+	_, exists = record.Column("X")
+	assert.False(t, exists)
+
+	record, err = reader.ReadRaw()
+	require.NoError(t, err)
+	assert.Equal(t, 2, record.Row())
+	assert.Equal(t, "2", record.Field(0))
+
+	_, err = reader.ReadRaw()
+	assert.Equal(t, io.EOF, err)
+}
+
+// TestReader_ReadRawReuseRecord verifies ReadRaw leaves the csv.Reader's record reuse as it found it
+func TestReader_ReadRawReuseRecord(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader("I,S\n1,a\n2,b\n3,c\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	_, err = reader.ReadRaw()
+	require.NoError(t, err)
+	assert.False(t, reader.CSVReader.ReuseRecord)
+
+	var rows []map[string]string
+	require.NoError(t, reader.ReadAll(&rows))
+	assert.Equal(t, []map[string]string{{"I": "2", "S": "b"}, {"I": "3", "S": "c"}}, rows)
+
+	reader, err = NewReader(strings.NewReader("I,S\n1,a\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	reader.CSVReader.ReuseRecord = true
+
+	_, err = reader.ReadRaw()
+	require.NoError(t, err)
+	assert.True(t, reader.CSVReader.ReuseRecord)
+}
+
+// TestReader_LastRecord verifies the original cells of the last record can be recovered after a failed decode
+func TestReader_LastRecord(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader("I,S\n 1x ,a\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	var actual readTo
+	require.Error(t, reader.Read(&actual))
+
+	record := reader.LastRecord()
+	assert.Equal(t, 1, record.Row())
+	assert.Equal(t, " 1x ", record.Raw("I"))
+	assert.Equal(t, "", record.Raw("X"))
+	assert.Equal(t, " 1x ", reader.RawField(0))
+	assert.Equal(t, "a", reader.RawField(1))
+	assert.Equal(t, "", reader.RawField(2))
+}
@@ -0,0 +1,19 @@
+//go:build !csvee_nounsafe
+// +build !csvee_nounsafe
+
+package csvee
+
+import (
+	"unsafe"
+)
+
+// stringBytes returns the bytes of s without copying them. The result must not be modified.
+func stringBytes(s string) []byte {
+
+	if s == "" {
+		return nil
+	}
+
+	// A string begins with a pointer to its bytes.
+	return unsafe.Slice(*(**byte)(unsafe.Pointer(&s)), len(s))
+}
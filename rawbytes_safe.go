@@ -0,0 +1,14 @@
+//go:build csvee_nounsafe
+// +build csvee_nounsafe
+
+package csvee
+
+// stringBytes returns a copy of the bytes of s.
+func stringBytes(s string) []byte {
+
+	if s == "" {
+		return nil
+	}
+
+	return []byte(s)
+}
@@ -1,11 +1,11 @@
 package csvee
 
 import (
+	"bufio"
+	"context"
 	"encoding/csv"
-	"encoding/json"
 	"io"
 	"reflect"
-	"strconv"
 	"strings"
 	"time"
 
@@ -14,26 +14,428 @@ import (
 
 // Reader embeds *csv.Reader and contains the column names of the CSV data that is to be read.
 type Reader struct {
-	CSVReader     *csv.Reader
-	ColumnNames   []string
+	CSVReader *csv.Reader
+
+	// ColumnNames are the names of the columns in the order they appear in each record.
+	//
+	// Deprecated: mutating ColumnNames while reading is unsafe. Use Columns and SetColumns instead.
+	ColumnNames []string
+
+	// ColumnFormats maps column names to the format used to parse them.
+	//
+	// Deprecated: mutating ColumnFormats while reading is unsafe. Use Formats, SetFormats, and
+	// SetColumnFormat instead.
 	ColumnFormats map[string]string
+
+	manifest     *manifestRecorder
+	rowsRead     int
+	counts       rowCounts
+	beforeRow    func(n int, record []string) error
+	afterRow     func(n int, v interface{}) error
+	limiter      Limiter
+	dedup        *dedupWindow
+	columnDocs   map[string]ColumnDoc
+	rawColumns   map[string]int
+	lastRecord   []string
+	lastLine     int
+	lastSpan     recordSpan
+	slabSize     int
+	source       io.Reader
+	readHeaders  bool
+	expectedRows int
+	plans        map[reflect.Type]*decodePlan
+	fastPath     bool
+
+	columnSettings []columnSettings
+
+	tokenized     int
+	unreadableRow int
+	pipelineDepth int
+	bufferSize    int
+	pipe          *pipeline
+	pending       []recordItem
+
+	// records, if set, yields the records in place of the csv.Reader.
+	records RecordSource
+
+	// dynamicType is the struct type ReadDynamic builds on its first call.
+	dynamicType reflect.Type
+
+	numberParsers map[string]*NumberParser
+	integerBases  map[string]int
+
+	warnings           []Warning
+	onWarning          func(Warning)
+	onTrace            func(RowTrace)
+	traceWriter        io.Writer
+	detectLeadingZeros bool
+	leadingZeroColumns map[string]bool
+	boolParsing        BoolParsing
+	whitespacePolicy   WhitespacePolicy
+	whitespacePolicies map[string]WhitespacePolicy
+	planConfig         planConfig
+	headers            []string
+	rawHeaders         []string
+	mappingStore       MappingStore
+
+	trackProvenance bool
+	sourceName      string
+	provenance      Provenance
+
+	merge       bool
+	rules       []compiledRule
+	runeLengths bool
+
+	conditionalFormats map[string]ConditionalFormat
+	converters         map[string]Converter
+
+	allowRaggedRows bool
+	nullValues      map[string]bool
+
+	columnTypes map[string]ColumnKind
+	columnKinds cellKinds
+
+	// columnRepeats holds the indexes of the columns of each repeated column name, computed with
+	// columnSettings.
+	columnRepeats map[string][]int
+
+	headerNormalizer HeaderNormalizer
+
+	sliceDelimiter  string
+	sliceDelimiters map[string]string
+
+	rowTimeout time.Duration
+	// abandonedRows counts the timed out rows still decoding in the background.
+	abandonedRows *int32
+
+	encoding          Encoding
+	defaultTimeFormat Format
+	location          *time.Location
+	zones             map[string]*time.Location
+	dates             datePolicies
+
+	validate    func(v interface{}, line int) error
+	onError     ErrorPolicy
+	errorBudget int
+
+	partialResults bool
+
+	// ctx is the context of the read in progress, if it has one.
+	ctx context.Context
 }
 
 // ReaderOptions can be provided to the Reader constructor.
 type ReaderOptions struct {
-	ReadHeaders   bool
-	ColumnNames   []string
+	ReadHeaders bool
+	ColumnNames []string
+
+	// ColumnFormats maps column names to the Format their times are parsed in. Integer fields are
+	// decoded from columns with a time layout, or a format other than the epoch formats, by parsing
+	// their cells as times and storing them as seconds since the Unix epoch, or in the epoch unit
+	// that follows the format after a "|", such as "2006-01-02|unixmilli".
 	ColumnFormats map[string]string
+
+	// Dialect, if set, describes the syntax of the input, such as DialectTSV. Delimiter, Comment,
+	// LazyQuotes, and TrimLeadingSpace override it when they are not zero; see Dialect for what each
+	// of them does.
+	Dialect          *Dialect
+	Delimiter        rune
+	Comment          rune
+	LazyQuotes       bool
+	TrimLeadingSpace bool
+
+	// Encoding is the character encoding of the input, which is transcoded to UTF-8 before it is
+	// parsed. Defaults to EncodingDetect, which reads input with a UTF-16 byte order mark as UTF-16
+	// and other input as UTF-8, and drops the byte order mark.
+	Encoding Encoding
+
+	// DefaultTimeFormat is the format used to parse time columns that are not in ColumnFormats: a
+	// registered format name such as TimeFormatUnixMilli, or a time layout. Defaults to RFC 3339.
+	DefaultTimeFormat string
+
+	// Location, if set, is the location in which times without zone information, such as those
+	// parsed with the layout "2006-01-02 15:04:05", are interpreted instead of UTC. Times parsed by
+	// registered formats, such as TimeFormatUnix, are returned in it.
+	Location *time.Location
+
+	// ZoneAbbreviations, if set, maps time zone abbreviations, such as "EST", to the locations times
+	// parsed with them are in, for layouts holding the abbreviation element "MST" but no numeric
+	// offset. Without it, time.Parse only knows the abbreviations of the local zone, and records any
+	// other as a zone with no offset. CommonZoneAbbreviations returns a table for common US and
+	// European zones.
+	ZoneAbbreviations map[string]*time.Location
+
+	// LeapSeconds, ZeroDates, and MaxDates control how times are decoded that database exports hold
+	// in place of real ones: leap seconds, such as "23:59:60", zero dates, such as "0000-00-00" or
+	// "0000-00-00 00:00:00", whose digits are all zero, and maximum dates, which fall on 9999-12-31.
+	// Each defaults to DateParse, which decodes them as the column's format parses them.
+	LeapSeconds DatePolicy
+	ZeroDates   DatePolicy
+	MaxDates    DatePolicy
+
+	// Manifest, if set, receives a JSON Manifest summarizing the run when ReadAll or Pump completes.
+	Manifest io.Writer
+
+	// BeforeRow, if set, is called with the row number and raw record of each row before it is decoded.
+	// Returning an error stops the read and the error is returned to the caller.
+	BeforeRow func(n int, record []string) error
+
+	// AfterRow, if set, is called with the row number and the decoded value of each row.
+	// Returning an error stops the read and the error is returned to the caller.
+	AfterRow func(n int, v interface{}) error
+
+	// RateLimit, if greater than zero, is the maximum number of rows read per second.
+	RateLimit float64
+
+	// Limiter, if set, is waited on before each row is read. It takes precedence over RateLimit.
+	Limiter Limiter
+
+	// DedupWindow, if greater than zero, is the number of most recently read records that each new
+	// record is compared against. Records identical to one in the window are silently skipped.
+	DedupWindow int
+
+	// ColumnDocs documents columns by name for use in the data dictionary produced from Reader.Schema.
+	ColumnDocs map[string]ColumnDoc
+
+	// SlabSize, if greater than one, is the number of values ReadAll allocates at once when the
+	// destination is a slice of pointers. Each slab is a single contiguous allocation, which reduces
+	// garbage collector pressure when decoding very large files.
+	SlabSize int
+
+	// ExpectedRows, if greater than zero, is the number of rows ReadAll should expect so that it can
+	// allocate its destination slice once instead of growing it. Reader.CountRows can provide this for
+	// seekable inputs.
+	ExpectedRows int
+
+	// UnsafeFastPath, if true, sets int, uint, float, bool, and string fields directly through their
+	// precomputed offsets instead of through reflection. Building with the csvee_nounsafe tag
+	// replaces the unsafe writes with equivalent reflection.
+	UnsafeFastPath bool
+
+	// PipelineDepth, if greater than zero, makes ReadAll and Pump tokenize up to this many records
+	// ahead on a separate goroutine while earlier records are being converted. Hooks and the
+	// destination are still only touched by the calling goroutine.
+	PipelineDepth int
+
+	// BufferSize, if greater than the 4096 bytes csv.Reader buffers, is the size in bytes of the
+	// buffer the input is read through instead. Larger buffers make fewer, larger reads, which suits
+	// network file systems and other media where each read is slow.
+	BufferSize int
+
+	// NumberParsers maps column names to the parser used for their numeric fields, in place of strconv.
+	NumberParsers map[string]*NumberParser
+
+	// IntegerBases maps column names to the base their integer fields are written in, from 2 to 36.
+	// A base of 0 detects the base from a 0x, 0o, 0b, or leading 0 prefix as Go literals do.
+	IntegerBases map[string]int
+
+	// DetectLeadingZeros, if true, raises a warning the first time a numeric column contains a value
+	// with leading zeros, which usually means it holds identifiers that should be decoded as strings.
+	DetectLeadingZeros bool
+
+	// OnWarning, if set, is called with each warning as it is raised. Warnings are also available from
+	// Reader.Warnings, which keeps only the first 1000, so callers reading files that raise a warning
+	// on many rows should handle them here.
+	OnWarning func(Warning)
+
+	// OnTrace, if set, is called with a trace of each row decoded into a struct, recording what was
+	// done with each of its cells, to debug why fields end up with the values they do. TraceWriter,
+	// if set, is written a line describing each trace. Tracing is slow, and rows are decoded one at a
+	// time while it is on.
+	OnTrace     func(RowTrace)
+	TraceWriter io.Writer
+
+	// BoolParsing controls which values are accepted for bool fields. Defaults to BoolStrict.
+	BoolParsing BoolParsing
+
+	// WhitespacePolicy controls how cells containing only whitespace are decoded. Defaults to
+	// WhitespaceLiteral. WhitespacePolicies overrides it for individual columns.
+	WhitespacePolicy   WhitespacePolicy
+	WhitespacePolicies map[string]WhitespacePolicy
+
+	// HeaderNormalizer, if set, rewrites each header read when ReadHeaders is set, and columns that do
+	// not exactly match a field are matched to a field whose name, tag name, or alias it rewrites to
+	// the same name. Columns are known by their rewritten names, including in ColumnFormats and the
+	// other options keyed by column. See TrimHeader, LowerCaseHeader, CamelCaseHeader, and
+	// ChainHeaderNormalizers.
+	HeaderNormalizer HeaderNormalizer
+
+	// FuzzyMatchThreshold, if greater than zero, allows columns that do not exactly match a field to be
+	// matched to the most similar field name, tag name, or alias whose similarity is at least the
+	// threshold, between 0 and 1. Reader.ColumnMatches reports how each column was matched.
+	FuzzyMatchThreshold float64
+
+	// MappingStore, if set, is checked for a mapping saved for the header row when ReadHeaders is set.
+	// If one exists, the columns are renamed according to it. Reader.SaveMapping saves new mappings.
+	MappingStore MappingStore
+
+	// TrackProvenance records where each record came from; see Reader.LastProvenance.
+	TrackProvenance bool
+
+	// SourceName identifies the input in provenance. It defaults to the name of the input if it
+	// has one, as *os.File does.
+	SourceName string
+
+	// Merge makes Read only set the fields whose cells are non-empty, leaving the other fields of the
+	// target as they were, so that a partial update file can be applied over pre-populated values.
+	Merge bool
+
+	// ConditionalFormats maps column names to formats chosen by the value of another column in the
+	// same row. They take precedence over ColumnFormats.
+	ConditionalFormats map[string]ConditionalFormat
+
+	// AllowRaggedRows accepts records whose number of fields differs from the number of columns.
+	// Missing trailing fields are treated as empty and extra fields are ignored, and each such record
+	// raises a warning. Reader.Warnings keeps only the first 1000 of them; OnWarning sees them all.
+	AllowRaggedRows bool
+
+	// NullValues lists cell values, such as "NULL", "N/A", or `\N`, that mean a cell has no value.
+	// If it is non nil, blank cells and cells holding one of these values, ignoring surrounding
+	// whitespace, set their fields to nil if they are pointers, to invalid if they are nullable
+	// wrappers such as sql.NullInt64, and otherwise to their zero value, without being parsed. When
+	// merging, they leave their fields untouched.
+	NullValues []string
+
+	// NestedSeparator separates the parts of compound column names, such as "Address.City", that
+	// map to fields of nested structs. A column whose whole name matches a field or tag is not split.
+	// Defaults to ".".
+	NestedSeparator string
+
+	// RepeatedColumns allows several columns to share a name, such as a file with a Tag column for
+	// each of a row's tags, instead of failing. The cells of a repeated column are collected in order
+	// into its field, which must be a slice or array, with null and blank cells left out. Maps of
+	// strings hold them joined with the column's slice delimiter, and maps of interface{} hold them
+	// as a []interface{}; maps of []string hold every column's cells this way, repeated or not.
+	RepeatedColumns bool
+
+	// ColumnConverters maps column names to functions that convert their cells, in place of the
+	// usual parsing. The fields of converted columns may be of any type the converter can produce.
+	ColumnConverters map[string]Converter
+
+	// ColumnConverterNames maps column names to the names of converters registered with
+	// RegisterConverter, which are used as if they were in ColumnConverters. Unlike ColumnConverters,
+	// they survive marshaling the options to JSON.
+	ColumnConverterNames map[string]string
+
+	// SliceDelimiter separates the elements of slice fields within a cell. Elements that contain it
+	// are written in double quotes, with double quotes within them doubled. SliceDelimiters
+	// overrides it for individual columns. Defaults to ",".
+	SliceDelimiter  string
+	SliceDelimiters map[string]string
+
+	// ColumnTypes maps column names to the kinds their cells are decoded as when reading into a
+	// map[string]interface{}: int64 for KindInt, float64 for KindFloat, bool for KindBool, and
+	// time.Time, parsed in the column's format, for KindTime. Blank and null cells in these columns
+	// are nil. Cells of other columns are strings.
+	ColumnTypes map[string]ColumnKind
+
+	// RowTimeout, if greater than zero, bounds how long decoding a single record may take, including
+	// its converters, unmarshalers, and Rules, so that a pathological cell cannot stall a read. A
+	// record that takes longer fails with a *RowTimeoutError. Go cannot interrupt the decoding, which
+	// finishes in the background with its result discarded, so converters must be safe to run then.
+	// A decoding that never finishes leaks its goroutine, so once 8 of a Reader's timed out records
+	// are still decoding, further records fail at once with ErrTooManyAbandonedRows, without being
+	// decoded, until one of them finishes.
+	RowTimeout time.Duration
+
+	// Validate, if set, is called with each row once it is decoded and the line of the input it
+	// starts on. Returning an error rejects the row, which fails with a *ValidationError.
+	Validate func(v interface{}, line int) error
+
+	// ValidatorName is the name of a validator registered with RegisterValidator to use as Validate.
+	// Unlike Validate, it survives marshaling the options to JSON.
+	ValidatorName string
+
+	// OnError controls what ReadAll does with rows that cannot be read, decoded, or validated.
+	// Defaults to ErrorFail.
+	OnError ErrorPolicy
+
+	// ErrorBudget, if greater than zero, is the number of rows that may fail under ErrorSkip or
+	// ErrorCollect. ReadAll stops with an *ErrorBudgetError once more fail.
+	ErrorBudget int
+
+	// PartialResults makes ReadAll, when it stops early, report the error as a *PartialResultError
+	// holding the number of rows decoded before it, which are left in the destination, so that
+	// callers can tell a partial import from a failed one and decide whether to keep it.
+	PartialResults bool
+
+	// Rules are checked in order against each row decoded into a struct. A row that fails one is reported as a
+	// RuleError; see Rule for the expression syntax.
+	Rules []Rule
+
+	// RuneLengths makes len() in rule expressions count the runes of cells rather than their bytes,
+	// so that a limit such as len(Name) <= 20 counts an emoji or CJK character once.
+	RuneLengths bool
+
+	// IgnoreUnknownColumns makes NewReader accept per column options, such as ColumnFormats, that
+	// name columns the reader does not have, so that the same options can be used for files whose
+	// headers differ. Those options then have no effect. Rules must still refer to known columns.
+	IgnoreUnknownColumns bool
+
+	// MaxGroupElements is the largest element index a header may bind to a slice or array of
+	// structs tagged with an indexed prefix, such as `csvee:"prefix=item{n}_"`, mirroring the
+	// WriterOptions field of the same name. Columns with larger indexes, such as "item5000_name",
+	// are left unmapped rather than growing the slice to hold them. Defaults to
+	// DefaultMaxGroupElements.
+	MaxGroupElements int
 }
 
+// DefaultMaxGroupElements is the largest element index a header binds to a slice of structs unless
+// ReaderOptions.MaxGroupElements says otherwise.
+const DefaultMaxGroupElements = 1000
+
+// defaultBufferSize is the size of the buffer csv.Reader reads its input through.
+const defaultBufferSize = 4096
+
 // NewReader returns a new Reader that reads from r.
 func NewReader(
 	r io.Reader,
 	options ...*ReaderOptions,
 ) (*Reader, error) {
 
+	if r == nil {
+		return nil, ErrReaderNil
+	}
+
+	return newReader(r, nil, nil, options)
+}
+
+// NewCSVReader returns a new Reader that reads records from cr, which may be configured beforehand,
+// for instance with ReuseRecord or a custom Comma. The dialect options, where set, override cr's
+// settings. cr reads the input itself, so options that need to see the input before it, Encoding,
+// BufferSize, and Manifest, cannot be set, and rows cannot be counted by seeking.
+func NewCSVReader(cr *csv.Reader, options ...*ReaderOptions) (*Reader, error) {
+
+	if cr == nil {
+		return nil, ErrCSVReaderNil
+	}
+
+	if len(options) > 0 && options[0] != nil {
+		switch {
+		case options[0].Encoding != EncodingDetect:
+			return nil, errors.New("an encoding cannot be set when reading from a csv.Reader")
+		case options[0].BufferSize != 0:
+			return nil, errors.New("a buffer size cannot be set when reading from a csv.Reader")
+		case options[0].Manifest != nil:
+			return nil, errors.New("a manifest cannot be written when reading from a csv.Reader")
+		}
+	}
+
+	return newReader(nil, cr, nil, options)
+}
+
+// newReader returns a new Reader that reads from r, or from cr or src if either is not nil.
+func newReader(r io.Reader, cr *csv.Reader, src RecordSource, options []*ReaderOptions) (*Reader, error) {
+
+	if len(options) == 0 || options[0] == nil {
+		return nil, ErrReaderOptionsRequired
+	}
 	rOptions := options[0]
 
+	if err := rOptions.validate(); err != nil {
+		return nil, err
+	}
+
 	lvColumnFormats := make(map[string]string)
 	if rOptions.ColumnFormats != nil {
 		// Make a copy of whatever is passed in.
@@ -42,15 +444,192 @@ func NewReader(
 		}
 	}
 
+	source := r
+
+	var manifest *manifestRecorder
+	if rOptions.Manifest != nil {
+		manifest = &manifestRecorder{
+			w:      rOptions.Manifest,
+			hasher: newHashingReader(r),
+		}
+		r = manifest.hasher
+	}
+
+	// Readers of a RecordSource keep an empty csv.Reader so that its settings can still be reported.
+	if src != nil {
+		cr = csv.NewReader(strings.NewReader(""))
+	}
+
+	injected := cr != nil
+	if !injected {
+		input := newDecodingReader(r, rOptions.Encoding)
+		if rOptions.BufferSize > defaultBufferSize {
+			// csv.Reader reads through a bufio.Reader at least as large as its own without wrapping it.
+			input = bufio.NewReaderSize(input, rOptions.BufferSize)
+		}
+		cr = csv.NewReader(input)
+	}
+
 	reader := &Reader{
-		CSVReader:     csv.NewReader(r),
+		CSVReader:     cr,
+		records:       src,
 		ColumnFormats: lvColumnFormats,
+		manifest:      manifest,
+		beforeRow:     rOptions.BeforeRow,
+		afterRow:      rOptions.AfterRow,
+		limiter:       rOptions.Limiter,
+		slabSize:      rOptions.SlabSize,
+		source:        source,
+		readHeaders:   rOptions.ReadHeaders,
+		expectedRows:  rOptions.ExpectedRows,
+		fastPath:      rOptions.UnsafeFastPath,
+		pipelineDepth: rOptions.PipelineDepth,
+		bufferSize:    rOptions.BufferSize,
+	}
+
+	if injected {
+		rOptions.dialect().overlay(reader.CSVReader)
+	} else {
+		rOptions.dialect().apply(reader.CSVReader)
+	}
+
+	reader.merge = rOptions.Merge
+
+	if rOptions.NullValues != nil {
+		reader.nullValues = make(map[string]bool, len(rOptions.NullValues))
+		for _, value := range rOptions.NullValues {
+			reader.nullValues[strings.TrimSpace(value)] = true
+		}
+	}
+
+	reader.allowRaggedRows = rOptions.AllowRaggedRows
+	if reader.allowRaggedRows {
+		reader.CSVReader.FieldsPerRecord = -1
+	}
+
+	reader.conditionalFormats = make(map[string]ConditionalFormat, len(rOptions.ConditionalFormats))
+	for column, conditional := range rOptions.ConditionalFormats {
+		formats := make(map[string]string, len(conditional.Formats))
+		for k, v := range conditional.Formats {
+			formats[k] = v
+		}
+		reader.conditionalFormats[column] = ConditionalFormat{Column: conditional.Column, Formats: formats}
 	}
 
-	err := reader.determineReaderColumnNames(rOptions.ColumnNames, rOptions.ReadHeaders)
+	rules, err := compileRules(rOptions.Rules)
 	if err != nil {
 		return nil, err
 	}
+	reader.rules = rules
+	reader.runeLengths = rOptions.RuneLengths
+	reader.trackProvenance = rOptions.TrackProvenance
+	reader.sourceName = rOptions.SourceName
+	if named, isNamed := source.(namedSource); isNamed && reader.sourceName == "" {
+		reader.sourceName = named.Name()
+	}
+
+	if reader.limiter == nil && rOptions.RateLimit > 0 {
+		reader.limiter = newIntervalLimiter(rOptions.RateLimit)
+	}
+
+	reader.columnDocs = make(map[string]ColumnDoc, len(rOptions.ColumnDocs))
+	for k, v := range rOptions.ColumnDocs {
+		reader.columnDocs[k] = v
+	}
+
+	reader.numberParsers = make(map[string]*NumberParser, len(rOptions.NumberParsers))
+	for k, v := range rOptions.NumberParsers {
+		reader.numberParsers[k] = v
+	}
+	reader.integerBases = make(map[string]int, len(rOptions.IntegerBases))
+	for k, base := range rOptions.IntegerBases {
+		reader.numberParsers[k] = baseNumberParser(base)
+		reader.integerBases[k] = base
+	}
+
+	reader.detectLeadingZeros = rOptions.DetectLeadingZeros
+	reader.onWarning = rOptions.OnWarning
+	reader.onTrace, reader.traceWriter = rOptions.OnTrace, rOptions.TraceWriter
+	reader.boolParsing = rOptions.BoolParsing
+
+	reader.sliceDelimiter = rOptions.SliceDelimiter
+	if reader.sliceDelimiter == "" {
+		reader.sliceDelimiter = defaultSliceDelimiter
+	}
+	reader.sliceDelimiters = make(map[string]string, len(rOptions.SliceDelimiters))
+	for k, v := range rOptions.SliceDelimiters {
+		reader.sliceDelimiters[k] = v
+	}
+
+	reader.columnTypes = make(map[string]ColumnKind, len(rOptions.ColumnTypes))
+	for k, v := range rOptions.ColumnTypes {
+		reader.columnTypes[k] = v
+	}
+
+	reader.converters = make(map[string]Converter, len(rOptions.ColumnConverters))
+	for k, v := range rOptions.ColumnConverters {
+		reader.converters[k] = v
+	}
+	for k, name := range rOptions.ColumnConverterNames {
+		reader.converters[k], _ = registeredConverter(name)
+	}
+
+	reader.planConfig = planConfig{
+		fuzzyThreshold: rOptions.FuzzyMatchThreshold,
+		separator:      rOptions.NestedSeparator,
+		converted:      convertedColumns(reader.converters),
+		repeated:       rOptions.RepeatedColumns,
+		maxElements:    rOptions.MaxGroupElements,
+	}
+	if reader.planConfig.separator == "" {
+		reader.planConfig.separator = "."
+	}
+	if reader.planConfig.maxElements == 0 {
+		reader.planConfig.maxElements = DefaultMaxGroupElements
+	}
+
+	reader.whitespacePolicy = rOptions.WhitespacePolicy
+	reader.whitespacePolicies = make(map[string]WhitespacePolicy, len(rOptions.WhitespacePolicies))
+	for k, v := range rOptions.WhitespacePolicies {
+		reader.whitespacePolicies[k] = v
+	}
+
+	reader.headerNormalizer = rOptions.HeaderNormalizer
+	reader.rowTimeout = rOptions.RowTimeout
+	reader.encoding = rOptions.Encoding
+	reader.defaultTimeFormat = Format(rOptions.DefaultTimeFormat)
+	reader.location = rOptions.Location
+	reader.zones = rOptions.ZoneAbbreviations
+	reader.dates = datePolicies{leapSeconds: rOptions.LeapSeconds, zeroDates: rOptions.ZeroDates, maxDates: rOptions.MaxDates}
+	reader.validate = rOptions.Validate
+	if rOptions.ValidatorName != "" {
+		reader.validate, _ = registeredValidator(rOptions.ValidatorName)
+	}
+	reader.onError = rOptions.OnError
+	reader.errorBudget = rOptions.ErrorBudget
+	reader.partialResults = rOptions.PartialResults
+
+	if rOptions.DedupWindow > 0 {
+		reader.dedup = newDedupWindow(rOptions.DedupWindow)
+	}
+
+	err = reader.determineReaderColumnNames(rOptions.ColumnNames, rOptions.ReadHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	reader.mappingStore = rOptions.MappingStore
+	if err := reader.applySavedMapping(); err != nil {
+		return nil, err
+	}
+
+	if err := rOptions.validateColumnReferences(reader.ColumnNames); err != nil {
+		return nil, err
+	}
+
+	if err := validateRuleColumns(reader.rules, reader.ColumnNames); err != nil {
+		return nil, err
+	}
 
 	return reader, nil
 }
@@ -67,7 +646,7 @@ func (r *Reader) determineReaderColumnNames(columnNames []string, readheaders bo
 	}
 
 	// Read the first line of the file and use the data there to set the column names
-	cols, err := r.CSVReader.Read()
+	cols, err := r.nextRecord()
 	if err != nil {
 		return errors.Wrap(err, "Could not read CSV headers")
 	}
@@ -76,106 +655,294 @@ func (r *Reader) determineReaderColumnNames(columnNames []string, readheaders bo
 	columnNamesCopy := make([]string, len(cols))
 	for i, c := range cols {
 		colName := c
+		if colName == "" {
+			continue
+		}
+
 		if colName[0] == '"' || colName[0] == '\'' {
 			colName = colName[1:]
 		}
 
 		lastIndex := len(colName) - 1
-		if c[lastIndex] == '"' || colName[lastIndex] == '\'' {
+		if lastIndex >= 0 && (colName[lastIndex] == '"' || colName[lastIndex] == '\'') {
 			colName = colName[:lastIndex]
 		}
 
 		columnNamesCopy[i] = colName
 	}
 
+	r.rawHeaders = append([]string(nil), cols...)
+	r.headers = append([]string(nil), columnNamesCopy...)
+	if r.headerNormalizer != nil {
+		for i, colName := range columnNamesCopy {
+			columnNamesCopy[i] = r.headerNormalizer(colName)
+		}
+	}
 	r.ColumnNames = columnNamesCopy
 	return nil
 }
 
-// Read reads the next line of the CSV and puts in into a struct.
+// Read reads the next line of the CSV and puts in into a struct. v may also point to a
+// map[string]string or map[string]interface{}, whose entries are keyed by column name, for CSVs whose
+// columns are not known in advance; ReaderOptions.ColumnTypes types the values of the latter.
 func (r *Reader) Read(v interface{}) error {
 
 	if v == nil {
 		return ErrReadTargetNil
 	}
 
-	jsonRecord, err := r.read(v)
+	row, err := r.read(reflect.TypeOf(v))
 	if err != nil {
 		return err
 	}
 
 	// Try to Unmarshal it to the provided interface
-	return json.Unmarshal([]byte(jsonRecord), v)
+	if err := r.decode(row, v); err != nil {
+		return err
+	}
+
+	if r.afterRow != nil {
+		return r.afterRow(r.rowsRead, v)
+	}
+
+	return nil
 }
 
-func (r *Reader) read(v interface{}) (string, error) {
+// row is a record that has been read along with the plan used to decode it.
+type row struct {
+	record []string
+	plan   *decodePlan
+}
 
-	// The easiest way to convert a CSV line to a struct is to label the fields and utilize the
-	// parser in encoding/json.
+func (r *Reader) read(vType reflect.Type) (row, error) {
 
-	// This handles any CSV read errors we might encounter.
-	record, err := r.CSVReader.Read()
+	record, err := r.readRecord()
 	if err != nil {
-		return "", err
+		return row{}, err
 	}
 
 	// It is possible to define behavior so that it processes as many fields as possible until one
 	// of the two slices reaches its limit, but it isn't clear how that might work.
 	if len(record) != len(r.ColumnNames) {
-		return "", ErrColumnNamesMismatch
+		r.counts.errored++
+		return row{}, ErrColumnNamesMismatch
+	}
+
+	// v's type needs to be a struct or a map, which needs no plan
+	vType = getBaseType(vType)
+	if isMapTarget(vType) {
+		return row{record: record}, nil
+	}
+	if vType.Kind() != reflect.Struct {
+		return row{}, ErrUnsupportedTargetType
+	}
+
+	plan, err := r.planFor(vType)
+	if err != nil {
+		return row{}, err
 	}
 
-	// v's type needs to be a struct or a map
-	vType := getBaseType(reflect.TypeOf(v))
-	if vType.Kind() != reflect.Struct && vType.Kind() != reflect.Map {
-		return "", ErrUnsupportedTargetType
+	return row{record: record, plan: plan}, nil
+}
+
+// decode sets the fields of v, which must be a pointer to the struct type row was read for, from
+// the cells of row, within the row timeout if there is one, and then validates it.
+func (r *Reader) decode(row row, v interface{}) error {
+
+	var err error
+	if r.rowTimeout > 0 {
+		err = r.decodeWithin(row, v, r.rowTimeout)
+	} else {
+		err = r.decodeRow(row, v)
+	}
+	if err == nil {
+		err = r.validateRow(v)
+	}
+	if err != nil && isRowError(err) {
+		r.counts.errored++
+	}
+
+	return err
+}
+
+// decodeRow decodes row into v as decode describes, without a timeout.
+func (r *Reader) decodeRow(row row, v interface{}) error {
+
+	if row.plan == nil {
+		return r.decodeMap(row.record, v)
 	}
 
-	labeledFields := []string{}
-	for i, field := range record {
+	structPtr := reflect.ValueOf(v)
+	for structPtr.Elem().Kind() == reflect.Ptr {
+		if structPtr.Elem().IsNil() {
+			structPtr.Elem().Set(reflect.New(structPtr.Elem().Type().Elem()))
+		}
+		structPtr = structPtr.Elem()
+	}
 
-		// Get the struct field; skip this field if it doesn't exist in the struct.
-		structField, exists := vType.FieldByName(r.ColumnNames[i])
-		if !exists {
+	if len(row.plan.groups) > 0 && !r.merge {
+		resetGroups(structPtr, row.plan)
+	}
+
+	tracer := r.startTrace(structPtr)
+	defer tracer.finish(r, row.plan, row.record)
+
+	for _, col := range row.plan.columns {
+
+		target, bound := r.elementTarget(structPtr, col, row.record)
+		if !bound {
+			tracer.cell(col, row.record, target, TraceNoElement, nil)
 			continue
 		}
 
-		fieldType, fieldSliceType, isValidType := getFieldTypeInfo(structField.Type)
-		if !isValidType {
-			return "", ErrInvalidFieldType
+		if col.repeats != nil {
+			if err := r.setRepeated(target, col, row.record); err != nil {
+				tracer.cell(col, row.record, target, TraceFailed, err)
+				return err
+			}
+			tracer.cell(col, row.record, target, TraceCollected, nil)
+			continue
 		}
 
-		fieldValue := field
+		field, skip := r.cell(col, row.record)
 
-		if fieldType.Kind() == reflect.String {
-			fieldValue = strings.ReplaceAll(field, `"`, `\"`)
-			fieldValue = `"` + fieldValue + `"`
-		} else if isTimeType(fieldType) {
-			if fieldValue, err = r.parseTime(field, i); err != nil {
-				return "", err
+		var err error
+		decision := TraceSet
+		switch {
+		case r.isNull(field):
+			if !r.merge {
+				zeroField(target, col)
 			}
-			fieldValue = `"` + fieldValue + `"`
-			// If it is a slice then assign the json array representation to fieldValue
-		} else if fieldSliceType != nil {
-			if fieldValue, err = r.buildSliceFieldValue(fieldSliceType, field, i); err != nil {
-				return "", err
+			tracer.cell(col, row.record, target, TraceNull, nil)
+			continue
+		case col.converted:
+			if skip && r.merge {
+				tracer.cell(col, row.record, target, TraceSkipped, nil)
+				continue
 			}
-			// If this string is blank for a type other than what we've checked so far, then don't add
-			// it to our json object. Just ignore it and let it assume the default value of the struct.
-		} else if strings.TrimSpace(fieldValue) == "" {
+			err = r.setConverted(target, col, field)
+		case col.unmarshal:
+			if skip {
+				tracer.cell(col, row.record, target, TraceSkipped, nil)
+				continue
+			}
+			err = r.setUnmarshaled(target, col, field)
+		case col.nullable:
+			err = r.setNullable(target, col, field, skip)
+		case skip:
+			tracer.cell(col, row.record, target, TraceSkipped, nil)
 			continue
+		case r.fastPath && col.fast:
+			err = r.setFast(target, col, field)
+		default:
+			err = r.setField(target, col, field)
 		}
 
-		labeledFields = append(labeledFields, `"`+r.ColumnNames[i]+`":`+fieldValue)
+		if err != nil {
+			tracer.cell(col, row.record, target, TraceFailed, err)
+			return err
+		}
+		if tracer != nil && blankLeavesField(col, field) && !col.converted && !col.unmarshal && !col.nullable {
+			decision = TraceBlank
+		}
+		tracer.cell(col, row.record, target, decision, nil)
 	}
 
-	// Build the JSON
-	return "{" + strings.Join(labeledFields, ",") + "}", nil
+	if len(r.rules) > 0 {
+		return r.checkRules(row, structPtr)
+	}
+
+	return nil
+}
+
+// readRecord waits on the limiter, takes the next record that is not a duplicate of one in the
+// dedup window, and passes it to the BeforeRow hook. It fails if the read's context is done.
+func (r *Reader) readRecord() ([]string, error) {
+
+	if r.ctx != nil {
+		if err := r.ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.limiter != nil {
+		if err := r.limiter.Wait(r.context()); err != nil {
+			return nil, err
+		}
+	}
+
+	item := r.nextItem()
+	r.counts.read += int64(item.duplicates)
+	r.counts.skipped += int64(item.duplicates)
+	if item.err != nil {
+		var csvErr *csv.ParseError
+		if errors.As(item.err, &csvErr) {
+			r.counts.read++
+			r.counts.errored++
+		}
+		r.unreadableRow = item.row
+		r.lastSpan = recordSpan{}
+		return nil, item.err
+	}
+	r.counts.read++
+	r.rowsRead = item.row
+	r.lastRecord = item.record
+	r.lastLine = item.line
+	r.lastSpan = recordSpan{offset: item.offset, end: item.end, fields: item.fields, live: item.fields == nil}
+	r.trackRecord(item)
+
+	record := item.record
+	if r.allowRaggedRows && len(record) != len(r.ColumnNames) {
+		record = r.evenRecord(record)
+	}
+
+	if r.beforeRow != nil {
+		if err := r.beforeRow(r.rowsRead, record); err != nil {
+			return nil, err
+		}
+	}
+
+	return record, nil
 }
 
-// ReadAll reads all the lines of the CSV and puts in into a slice of structs.
+// ReadAll reads all the lines of the CSV and puts in into a slice of structs, or of maps as Read
+// accepts them. Rows that cannot be read, decoded, or validated are handled as the reader's OnError
+// policy directs. v may also be a *Frame, which is replaced with the remaining records.
 func (r *Reader) ReadAll(v interface{}) error {
 
+	if frame, isFrame := v.(*Frame); isFrame {
+		if frame == nil {
+			return ErrReadTargetNil
+		}
+		return r.recordRun(func() (int, error) {
+			r.startPipeline()
+			defer r.stopPipeline()
+
+			return r.readFrame(frame)
+		})
+	}
+
+	direct, base, isPtr, err := readAllTarget(v)
+	if err != nil {
+		return err
+	}
+
+	return r.recordRun(func() (int, error) {
+		r.startPipeline()
+		defer r.stopPipeline()
+
+		rowsDecoded, err := r.readAll(direct, base, isPtr)
+		if err != nil && r.partialResults {
+			err = &PartialResultError{Rows: rowsDecoded, Err: err}
+		}
+		return rowsDecoded, err
+	})
+}
+
+// readAllTarget checks that v is a pointer to a slice, returning the slice, the type of its
+// elements with any pointer removed, and whether they are pointers.
+func readAllTarget(v interface{}) (direct reflect.Value, base reflect.Type, isPtr bool, err error) {
+
 	// Borrowed this method of dynamically building slice of an arbitrary type the repo at:
 	// github.com/jmoiron/sqlx
 	//
@@ -188,142 +955,132 @@ func (r *Reader) ReadAll(v interface{}) error {
 		return t
 	}
 
-	var rv, rvp reflect.Value
-
 	value := reflect.ValueOf(v)
 	if value.Kind() != reflect.Ptr {
-		return ErrReadAllNotSlicePointer
+		return direct, nil, false, ErrReadAllNotSlicePointer
 	}
 	if value.IsNil() {
-		return ErrReadTargetNil
+		return direct, nil, false, ErrReadTargetNil
 	}
 
-	direct := reflect.Indirect(value)
+	direct = reflect.Indirect(value)
 
 	slice := deref(value.Type())
 	if slice.Kind() != reflect.Slice {
-		return ErrReadAllNotSlicePointer
+		return direct, nil, false, ErrReadAllNotSlicePointer
 	}
 
-	isPtr := slice.Elem().Kind() == reflect.Ptr
-	base := deref(slice.Elem())
+	return direct, deref(slice.Elem()), slice.Elem().Kind() == reflect.Ptr, nil
+}
 
-	var streamParseError error
-	stream := newStringStreamReader()
-	defer stream.Close()
+// recordRun executes run, recording the outcome in the manifest if one was requested. run returns
+// the number of rows successfully decoded.
+func (r *Reader) recordRun(run func() (int, error)) error {
 
-	// Read one line at a time and write it to the stream
-	go func() {
+	if r.manifest == nil {
+		_, err := run()
+		return err
+	}
 
-		// an empty string signals not to read from this channel any more
-		defer stream.Stream("")
+	r.manifest.start(r)
+	rowsDecoded, err := run()
 
-		for {
+	if err != nil && !rejected(err) {
+		r.manifest.reject(r.rowsRead, err)
+	}
+	if mErr := r.manifest.finish(r.rowsRead, rowsDecoded); mErr != nil && err == nil {
+		err = errors.Wrap(mErr, "Could not write manifest")
+	}
 
-			nextJSON, err := r.read(reflect.New(base).Interface())
-			if nextJSON == "" && err == io.EOF {
-				break
-			}
+	return err
+}
 
-			if err != nil {
-				streamParseError = err
-				break
-			}
+// readAllMinGrowth is the minimum number of elements ReadAll grows its destination slice by.
+const readAllMinGrowth = 64
 
-			stream.Stream(nextJSON)
-		}
-	}()
+// readAll decodes one line at a time, appending each to direct, until the end of the CSV data is reached.
+// Rather than appending each value, direct is grown in chunks and values are set by index.
+func (r *Reader) readAll(direct reflect.Value, base reflect.Type, isPtr bool) (int, error) {
 
-	// Decode one line at a time. dec.More() will block while it waits for the next item in the stream
-	// and will return false once io.EOF is read, triggered by writing the empty string, "", to the stream.
-	dec := json.NewDecoder(stream)
-	for dec.More() {
+	// Values appended to a slice of structs are copied, so only pointers benefit from slabs.
+	allocSize := 1
+	if isPtr {
+		allocSize = r.slabSize
+	}
+	alloc := newSlabAllocator(base, allocSize)
 
-		if streamParseError != nil {
-			break
-		}
+	if r.expectedRows > 0 && direct.Cap()-direct.Len() < r.expectedRows {
+		growSlice(direct, direct.Len()+r.expectedRows)
+	}
 
-		// Initialize the new instance of the base type
-		rvp = reflect.New(base)
-		rv = reflect.Indirect(rvp)
+	// Extend the slice to its full capacity while decoding and trim it to the values decoded when done.
+	length := direct.Len()
+	direct.SetLen(direct.Cap())
+	defer func() { direct.SetLen(length) }()
 
-		// Decode it into the struct
-		err := dec.Decode(rvp.Interface())
+	failures := rowFailures{reader: r}
+	var rowsDecoded int
+	for {
+
+		nextRow, err := r.read(base)
+		if err == io.EOF {
+			return rowsDecoded, failures.err()
+		}
 		if err != nil {
-			return err
+			if failures.skip(err) {
+				continue
+			}
+			return rowsDecoded, failures.stop(err)
+		}
+
+		if length == direct.Len() {
+			growSlice(direct, 2*length+readAllMinGrowth)
+			direct.SetLen(direct.Cap())
 		}
 
-		// Append it to the slice
+		// Initialize the new instance of the base type, decoding directly into the slice element when
+		// the slice holds values.
+		var rvp reflect.Value
 		if isPtr {
-			direct.Set(reflect.Append(direct, rvp))
+			rvp = alloc.new()
 		} else {
-			direct.Set(reflect.Append(direct, rv))
+			elem := direct.Index(length)
+			elem.Set(reflect.Zero(base))
+			rvp = elem.Addr()
 		}
-	}
-
-	return streamParseError
-}
-
-func (r *Reader) parseTime(field string, column int) (string, error) {
-
-	// First check whether a format was defined this time column
-	format, exists := r.ColumnFormats[r.ColumnNames[column]]
-	if !exists {
-		// If no format exists, assume the string is formatted correctly as the default RFC3339 format
-		return field, nil
-	}
 
-	var tm time.Time
-
-	// Parse out income time strings from unix or other formats to time.Time
-	if format == TimeFormatUnix {
-
-		intField, err := strconv.ParseInt(field, 10, 0)
-		if err != nil {
-			return "", err
+		// Decode it into the struct
+		if err := r.decode(nextRow, rvp.Interface()); err != nil {
+			if failures.skip(err) {
+				continue
+			}
+			return rowsDecoded, failures.stop(err)
 		}
 
-		tm = time.Unix(intField, 0)
-
-	} else {
+		if r.afterRow != nil {
+			if err := r.afterRow(r.rowsRead, rvp.Interface()); err != nil {
+				return rowsDecoded, err
+			}
+		}
 
-		var err error
-		if tm, err = time.Parse(format, field); err != nil {
-			return "", err
+		if isPtr {
+			direct.Index(length).Set(rvp)
 		}
+		length++
+		rowsDecoded++
 	}
-
-	// Output times in RFC3339 format
-	return tm.Format(time.RFC3339), nil
 }
 
-func (r *Reader) buildSliceFieldValue(t reflect.Type, field string, column int) (string, error) {
-
-	fieldValue := "["
+// growSlice replaces the slice s with a copy whose capacity is at least capacity.
+func growSlice(s reflect.Value, capacity int) {
 
-	if t.Kind() == reflect.String {
-		sliceValues := strings.Split(field, ",")
-		for i := 0; i < len(sliceValues); i++ {
-			sliceValues[i] = `"` + sliceValues[i] + `"`
-		}
-		fieldValue += strings.Join(sliceValues, ",")
-	} else if isTimeType(t) {
-		sliceValues := strings.Split(field, ",")
-		for i := 0; i < len(sliceValues); i++ {
-			value, err := r.parseTime(sliceValues[i], column)
-			if err != nil {
-				return "", err
-			}
-			sliceValues[i] = `"` + value + `"`
-		}
-		fieldValue += strings.Join(sliceValues, ",")
-	} else {
-		fieldValue += field
+	if s.Cap() >= capacity {
+		return
 	}
 
-	fieldValue += "]"
-
-	return fieldValue, nil
+	grown := reflect.MakeSlice(s.Type(), s.Len(), capacity)
+	reflect.Copy(grown, s)
+	s.Set(grown)
 }
 
 func getBaseType(t reflect.Type) reflect.Type {
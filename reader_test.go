@@ -1,6 +1,10 @@
 package csvee
 
 import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
 	"strings"
 	"testing"
 	"time"
@@ -25,7 +29,7 @@ func TestNewReader(t *testing.T) {
 		strings.NewReader("a,b,c"),
 		&ReaderOptions{
 			ColumnNames:   columnNames,
-			ColumnFormats: map[string]string{"1": "a", "2": "b"},
+			ColumnFormats: map[string]string{"1": TimeFormatUnix, "2": "2006-01-02"},
 		},
 	)
 
@@ -34,8 +38,220 @@ func TestNewReader(t *testing.T) {
 	require.NoError(t, err)
 	assert.Exactly(t, columnNames, reader.ColumnNames)
 	require.NotNil(t, reader.ColumnFormats)
-	assert.Equal(t, "a", reader.ColumnFormats["1"])
-	assert.Equal(t, "b", reader.ColumnFormats["2"])
+	assert.Equal(t, TimeFormatUnix, reader.ColumnFormats["1"])
+	assert.Equal(t, "2006-01-02", reader.ColumnFormats["2"])
+}
+
+// TestNewReader_Validation verifies invalid options are rejected with a helpful error
+func TestNewReader_Validation(t *testing.T) {
+
+	var testCases = []struct {
+		name       string
+		inReader   io.Reader
+		inOptions  []*ReaderOptions
+		expErrText string
+	}{
+		{
+			name:       "nil reader",
+			inOptions:  []*ReaderOptions{{ColumnNames: []string{"I"}}},
+			expErrText: ErrReaderNil.Error(),
+		},
+		{
+			name:       "no options",
+			inReader:   strings.NewReader(""),
+			expErrText: ErrReaderOptionsRequired.Error(),
+		},
+		{
+			name:       "no column names",
+			inReader:   strings.NewReader(""),
+			inOptions:  []*ReaderOptions{{}},
+			expErrText: ErrColumnNamesRequired.Error(),
+		},
+		{
+			name:       "format for unknown column",
+			inReader:   strings.NewReader(""),
+			inOptions:  []*ReaderOptions{{ColumnNames: []string{"I"}, ColumnFormats: map[string]string{"T": "unix"}}},
+			expErrText: `column format provided for unknown column "T"`,
+		},
+		{
+			name:       "duplicate column name",
+			inReader:   strings.NewReader(""),
+			inOptions:  []*ReaderOptions{{ColumnNames: []string{"I", "S", "I"}}},
+			expErrText: `duplicate column name "I"`,
+		},
+		{
+			name:       "empty column name",
+			inReader:   strings.NewReader(""),
+			inOptions:  []*ReaderOptions{{ColumnNames: []string{"I", "", "S"}}},
+			expErrText: "column names must not be empty",
+		},
+		{
+			name:       "negative rate limit",
+			inReader:   strings.NewReader(""),
+			inOptions:  []*ReaderOptions{{ColumnNames: []string{"I"}, RateLimit: -1}},
+			expErrText: "rate limit must not be negative, got -1",
+		},
+		{
+			name:       "negative buffer size",
+			inReader:   strings.NewReader(""),
+			inOptions:  []*ReaderOptions{{ColumnNames: []string{"I"}, BufferSize: -1}},
+			expErrText: "buffer size must not be negative, got -1",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(tt.inReader, tt.inOptions...)
+			assert.Nil(t, reader)
+			assert.EqualError(t, err, tt.expErrText)
+		})
+	}
+}
+
+// TestNewReader_FileHeaders verifies files whose headers are empty or repeated can still be read
+func TestNewReader_FileHeaders(t *testing.T) {
+
+	var testCases = []struct {
+		name    string
+		inData  string
+		expRows []readTo
+	}{
+		{
+			name:    "duplicate header",
+			inData:  "I,S,I\n1,a,2\n",
+			expRows: []readTo{{I: 2, S: "a"}},
+		},
+		{
+			name:    "duplicate unmapped header",
+			inData:  "I,x,x\n1,a,b\n",
+			expRows: []readTo{{I: 1}},
+		},
+		{
+			name:    "empty header",
+			inData:  ",I\n1,2\n",
+			expRows: []readTo{{I: 2}},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader(tt.inData), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+
+			var actualData []readTo
+			require.NoError(t, reader.ReadAll(&actualData))
+			assert.Equal(t, tt.expRows, actualData)
+		})
+	}
+}
+
+// TestNewReader_IgnoreUnknownColumns verifies options for columns the headers lack are only accepted
+// when IgnoreUnknownColumns is set
+func TestNewReader_IgnoreUnknownColumns(t *testing.T) {
+
+	options := &ReaderOptions{ReadHeaders: true, ColumnFormats: map[string]string{"Tu": TimeFormatUnix}}
+
+	_, err := NewReader(strings.NewReader("I,S\n1,a\n"), options)
+	assert.EqualError(t, err, `column format provided for unknown column "Tu"`)
+
+	options.IgnoreUnknownColumns = true
+	reader, err := NewReader(strings.NewReader("I,S\n1,a\n"), options)
+	require.NoError(t, err)
+
+	var actualData []readTo
+	require.NoError(t, reader.ReadAll(&actualData))
+	assert.Equal(t, []readTo{{I: 1, S: "a"}}, actualData)
+}
+
+// readSizeRecorder records the size of each read made from it.
+type readSizeRecorder struct {
+	r     io.Reader
+	sizes []int
+}
+
+func (s *readSizeRecorder) Read(p []byte) (int, error) {
+
+	s.sizes = append(s.sizes, len(p))
+	return s.r.Read(p)
+}
+
+// TestReader_BufferSize verifies the input is read through a buffer of the configured size
+func TestReader_BufferSize(t *testing.T) {
+
+	data := "S,I\n" + strings.Repeat("abc,1\n", 5000)
+
+	for _, size := range []int{0, 64, 1 << 16} {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+
+			input := &readSizeRecorder{r: strings.NewReader(data)}
+			reader, err := NewReader(input, &ReaderOptions{ReadHeaders: true, BufferSize: size})
+			require.NoError(t, err)
+
+			var rows []struct {
+				S string
+				I int
+			}
+			require.NoError(t, reader.ReadAll(&rows))
+			assert.Len(t, rows, 5000)
+
+			want := size
+			if size < 4096 {
+				want = 4096
+			}
+			largest := 0
+			for _, n := range input.sizes {
+				if n > largest {
+					largest = n
+				}
+			}
+			assert.Equal(t, want, largest)
+		})
+	}
+}
+
+// TestNewCSVReader verifies a pre-configured csv.Reader is read from, with the options' dialect
+// overriding its settings where set
+func TestNewCSVReader(t *testing.T) {
+
+	type row struct {
+		S string
+		I int
+	}
+
+	cr := csv.NewReader(strings.NewReader("S;I\n# skipped\nabc;1\n"))
+	cr.Comma, cr.Comment = ';', '#'
+
+	reader, err := NewCSVReader(cr, &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	assert.Same(t, cr, reader.CSVReader)
+
+	var rows []row
+	require.NoError(t, reader.ReadAll(&rows))
+	assert.Equal(t, []row{{S: "abc", I: 1}}, rows)
+
+	cr = csv.NewReader(strings.NewReader("S|I\nabc|1\n"))
+	cr.Comma = ';'
+
+	reader, err = NewCSVReader(cr, &ReaderOptions{ReadHeaders: true, Delimiter: '|'})
+	require.NoError(t, err)
+
+	rows = nil
+	require.NoError(t, reader.ReadAll(&rows))
+	assert.Equal(t, []row{{S: "abc", I: 1}}, rows)
+
+	_, err = NewCSVReader(nil, &ReaderOptions{ReadHeaders: true})
+	assert.Equal(t, ErrCSVReaderNil, err)
+
+	for _, options := range []*ReaderOptions{
+		{ReadHeaders: true, Encoding: EncodingLatin1},
+		{ReadHeaders: true, BufferSize: 1024},
+		{ReadHeaders: true, Manifest: io.Discard},
+	} {
+		_, err = NewCSVReader(csv.NewReader(strings.NewReader("")), options)
+		assert.Error(t, err)
+	}
 }
 
 type nestedReadTo struct {
@@ -312,3 +528,76 @@ func TestReader_ReadAll(t *testing.T) {
 	}
 
 }
+
+// TestReader_ReadAllHooks verifies the BeforeRow and AfterRow hooks are called for each row
+func TestReader_ReadAllHooks(t *testing.T) {
+
+	var beforeRows, afterRows []int
+	reader, err := NewReader(
+		strings.NewReader("I,S\n1,a\n2,b\n3,c\n"),
+		&ReaderOptions{
+			ReadHeaders: true,
+			BeforeRow: func(n int, record []string) error {
+				beforeRows = append(beforeRows, n)
+				if record[0] == "3" {
+					return errors.New("stop")
+				}
+				return nil
+			},
+			AfterRow: func(n int, v interface{}) error {
+				afterRows = append(afterRows, n)
+				v.(*readTo).S += "!"
+				return nil
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	var actualData []readTo
+	err = reader.ReadAll(&actualData)
+	require.EqualError(t, err, "stop")
+
+	assert.Equal(t, []int{1, 2, 3}, beforeRows)
+	assert.Equal(t, []int{1, 2}, afterRows)
+	require.Len(t, actualData, 2)
+	assert.Equal(t, "a!", actualData[0].S)
+	assert.Equal(t, "b!", actualData[1].S)
+}
+
+// TestReader_ReadAllReusesCapacity verifies values decoded into spare capacity do not keep stale data
+func TestReader_ReadAllReusesCapacity(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader("I\n1\n2\n3\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	backing := []readTo{{I: 7}, {S: "stale"}, {S: "stale"}}
+	actualData := backing[:1]
+	require.NoError(t, reader.ReadAll(&actualData))
+	require.Len(t, actualData, 4)
+	assert.Equal(t, readTo{I: 7}, actualData[0])
+	assert.Equal(t, readTo{I: 1}, actualData[1])
+	assert.Equal(t, readTo{I: 3}, actualData[3])
+}
+
+func BenchmarkReader_ReadAll(b *testing.B) {
+
+	var sb strings.Builder
+	sb.WriteString("F,I,B,S\n")
+	for i := 0; i < 1000; i++ {
+		sb.WriteString("29.4,3,true,hello\n")
+	}
+	inData := sb.String()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		reader, err := NewReader(strings.NewReader(inData), &ReaderOptions{ReadHeaders: true})
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		var actualData []readTo
+		if err := reader.ReadAll(&actualData); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package csvee
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// RecordSource yields the records of a tabular source other than CSV, such as the rows of a
+// database query, the sheet of a spreadsheet, or the messages of a stream, so that a Reader from
+// NewRecordReader can map and convert them as it does CSV records. Next returns io.EOF once there
+// are no more records. The Reader may keep the records Next returns, so they must not be reused.
+type RecordSource interface {
+	Next() ([]string, error)
+}
+
+// RecordSourceFunc adapts a function to a RecordSource.
+type RecordSourceFunc func() ([]string, error)
+
+// Next calls f.
+func (f RecordSourceFunc) Next() ([]string, error) {
+
+	return f()
+}
+
+// RecordSlice returns a RecordSource that yields records in order.
+func RecordSlice(records [][]string) RecordSource {
+
+	return RecordSourceFunc(func() ([]string, error) {
+		if len(records) == 0 {
+			return nil, io.EOF
+		}
+		record := records[0]
+		records = records[1:]
+		return record, nil
+	})
+}
+
+// NewRecordReader returns a new Reader that reads records from src, with ReadHeaders taking its
+// first record as the header, and decodes them as NewReader's do. There is no input to parse, so
+// the dialect options have no effect; options that need to see the input, Encoding, BufferSize,
+// and Manifest, cannot be set; rows cannot be counted by seeking; and errors carry no line or
+// column positions. Records with the wrong number of fields fail as they would in CSV unless
+// AllowRaggedRows is set.
+func NewRecordReader(src RecordSource, options ...*ReaderOptions) (*Reader, error) {
+
+	if src == nil {
+		return nil, ErrRecordSourceNil
+	}
+
+	if len(options) > 0 && options[0] != nil {
+		switch {
+		case options[0].Encoding != EncodingDetect:
+			return nil, errors.New("an encoding cannot be set when reading from a record source")
+		case options[0].BufferSize != 0:
+			return nil, errors.New("a buffer size cannot be set when reading from a record source")
+		case options[0].Manifest != nil:
+			return nil, errors.New("a manifest cannot be written when reading from a record source")
+		}
+	}
+
+	return newReader(nil, nil, src, options)
+}
+
+// nextRecord returns the next record from the reader's RecordSource, or otherwise its csv.Reader.
+func (r *Reader) nextRecord() ([]string, error) {
+
+	if r.records != nil {
+		return r.records.Next()
+	}
+
+	return r.CSVReader.Read()
+}
@@ -0,0 +1,128 @@
+package csvee
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewRecordReader verifies records from a RecordSource are mapped and converted as CSV records
+// are
+func TestNewRecordReader(t *testing.T) {
+
+	type row struct {
+		Name  string `csvee:"name"`
+		Count int    `csvee:"count"`
+		When  time.Time
+		Note  string `csvee:"note,alias=memo"`
+	}
+
+	records := [][]string{
+		{"name", "count", "When", "memo"},
+		{"a, with comma", "1", "13/02/2021", "line\nbreak"},
+		{"b", "", "01/03/2021", `"quoted"`},
+	}
+	exp := []row{
+		{Name: "a, with comma", Count: 1, When: time.Date(2021, time.February, 13, 0, 0, 0, 0, time.UTC), Note: "line\nbreak"},
+		{Name: "b", When: time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC), Note: `"quoted"`},
+	}
+
+	var testCases = []struct {
+		name    string
+		options ReaderOptions
+	}{
+		{name: "default", options: ReaderOptions{ReadHeaders: true, ColumnFormats: map[string]string{"When": "02/01/2006"}}},
+		{name: "pipelined", options: ReaderOptions{ReadHeaders: true, ColumnFormats: map[string]string{"When": "02/01/2006"}, PipelineDepth: 2}},
+		{name: "dialect ignored", options: ReaderOptions{ReadHeaders: true, ColumnFormats: map[string]string{"When": "02/01/2006"}, Delimiter: ';'}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewRecordReader(RecordSlice(records), &tt.options)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"name", "count", "When", "memo"}, reader.Columns())
+
+			var actual []row
+			require.NoError(t, reader.ReadAll(&actual))
+			assert.Equal(t, exp, actual)
+			assert.Equal(t, int64(2), reader.RowsRead())
+		})
+	}
+}
+
+// TestNewRecordReader_Raw verifies records can be read raw and into maps without headers
+func TestNewRecordReader_Raw(t *testing.T) {
+
+	records := [][]string{{"1", "x"}, {"2", "y"}}
+
+	reader, err := NewRecordReader(RecordSlice(records), &ReaderOptions{ColumnNames: []string{"id", "v"}})
+	require.NoError(t, err)
+
+	record, err := reader.ReadRaw()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "x"}, copyFields(record))
+
+	values := map[string]string{}
+	require.NoError(t, reader.Read(&values))
+	assert.Equal(t, map[string]string{"id": "2", "v": "y"}, values)
+
+	_, err = reader.ReadRaw()
+	assert.Equal(t, io.EOF, err)
+}
+
+// TestNewRecordReader_Errors verifies source errors, mismatched records, and unsupported options
+func TestNewRecordReader_Errors(t *testing.T) {
+
+	type row struct {
+		A string
+		B string
+	}
+
+	sourceErr := errors.New("connection lost")
+	calls := 0
+	failing := RecordSourceFunc(func() ([]string, error) {
+		calls++
+		if calls > 2 {
+			return nil, sourceErr
+		}
+		return []string{"x", "y"}, nil
+	})
+
+	reader, err := NewRecordReader(failing, &ReaderOptions{ColumnNames: []string{"A", "B"}})
+	require.NoError(t, err)
+
+	var actual row
+	require.NoError(t, reader.Read(&actual))
+	require.NoError(t, reader.Read(&actual))
+	assert.Equal(t, sourceErr, errors.Cause(reader.Read(&actual)))
+
+	reader, err = NewRecordReader(RecordSlice([][]string{{"A", "B"}, {"only"}}), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+	assert.Error(t, reader.Read(&actual))
+
+	reader, err = NewRecordReader(RecordSlice([][]string{{"A", "B"}, {"only"}}), &ReaderOptions{ReadHeaders: true, AllowRaggedRows: true})
+	require.NoError(t, err)
+	require.NoError(t, reader.Read(&actual))
+	assert.Equal(t, row{A: "only"}, actual)
+
+	_, err = NewRecordReader(RecordSlice(nil), &ReaderOptions{ReadHeaders: true})
+	assert.True(t, strings.HasPrefix(err.Error(), "Could not read CSV headers"), err.Error())
+
+	_, err = NewRecordReader(nil, &ReaderOptions{ReadHeaders: true})
+	assert.Equal(t, ErrRecordSourceNil, err)
+
+	for _, options := range []*ReaderOptions{
+		{ReadHeaders: true, Encoding: EncodingLatin1},
+		{ReadHeaders: true, BufferSize: 1024},
+		{ReadHeaders: true, Manifest: io.Discard},
+	} {
+		_, err = NewRecordReader(RecordSlice(nil), options)
+		assert.Error(t, err)
+	}
+}
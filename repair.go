@@ -0,0 +1,108 @@
+package csvee
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// repairLayouts are the time layouts a cell that could not be parsed is tried in, in order, to
+// suggest a ColumnFormat for its column.
+var repairLayouts = append(append([]string(nil), inferredLayouts...),
+	"02/01/2006",
+	"02/01/2006 15:04:05",
+	"02.01.2006",
+	"02-01-2006",
+	"2006-01-02 15:04",
+	"01/02/2006 15:04",
+	"02/01/2006 15:04",
+)
+
+// repairLocales are the locales a number that could not be parsed is tried in, in order, to suggest
+// a NumberParser for its column.
+var repairLocales = []struct {
+	name   string
+	locale *Locale
+}{
+	{"LocaleUS", &LocaleUS},
+	{"LocaleDE", &LocaleDE},
+	{"LocaleFR", &LocaleFR},
+}
+
+// RepairError is the cause of a cell that could not be parsed as its column is configured, but that
+// could be with the option Suggestion describes, such as setting ColumnFormats["Date"] to a layout
+// the cell matches.
+type RepairError struct {
+	Err        error
+	Suggestion string
+}
+
+func (e *RepairError) Error() string {
+
+	return fmt.Sprintf("%v; %s", e.Err, e.Suggestion)
+}
+
+// Unwrap returns the underlying error.
+func (e *RepairError) Unwrap() error {
+
+	return e.Err
+}
+
+// suggestTimeRepair returns err, the error parsing field as a time for the named column, with a
+// suggested ColumnFormat if field matches a layout other than the column's. Times rejected by a
+// DatePolicy are not repaired.
+func (r *Reader) suggestTimeRepair(column, field string, err error) error {
+
+	if isDatePolicyError(err) {
+		return err
+	}
+
+	format := r.columnFormat(column)
+	for _, layout := range repairLayouts {
+		if Format(layout) == format {
+			continue
+		}
+		if _, parseErr := time.Parse(layout, field); parseErr == nil {
+			return &RepairError{Err: err, Suggestion: fmt.Sprintf(
+				"the value matches the layout %q, set ColumnFormats[%q] to it", layout, column)}
+		}
+	}
+
+	return err
+}
+
+// suggestNumberRepair returns err, the error for value, which is not a number of type t, in the named
+// column, with a suggested NumberParser if the column has none and value is a number formatted with
+// a decimal comma or group separators that a locale parses.
+func (r *Reader) suggestNumberRepair(column, value string, t reflect.Type, err error) error {
+
+	if _, exists := r.numberParsers[column]; exists {
+		return err
+	}
+	if _, exists := r.integerBases[column]; exists {
+		return err
+	}
+
+	for _, candidate := range repairLocales {
+
+		parser := candidate.locale.NumberParser()
+
+		var parseErr error
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			_, parseErr = parser.parseInt(value, t.Bits())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			_, parseErr = parser.parseUint(value, t.Bits())
+		default:
+			_, parseErr = parser.parseFloat(value, t.Bits())
+		}
+
+		if parseErr == nil {
+			return &RepairError{Err: err, Suggestion: fmt.Sprintf(
+				"the value is formatted as in %s, set NumberParsers[%q] to %s.NumberParser()",
+				candidate.name, column, candidate.name)}
+		}
+	}
+
+	return err
+}
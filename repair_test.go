@@ -0,0 +1,129 @@
+package csvee
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type repairRow struct {
+	Date  time.Time
+	Qty   int
+	Price float64
+}
+
+// TestReader_RepairSuggestions verifies cells that fail to parse suggest the option that would
+// parse them
+func TestReader_RepairSuggestions(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		data       string
+		options    ReaderOptions
+		suggestion string
+	}{
+		{
+			name:       "day first date",
+			data:       "Date,Qty,Price\n31/12/2021,1,2.5\n",
+			options:    ReaderOptions{ColumnFormats: map[string]string{"Date": "01/02/2006"}},
+			suggestion: `the value matches the layout "02/01/2006", set ColumnFormats["Date"] to it`,
+		},
+		{
+			name:       "date without a format",
+			data:       "Date,Qty,Price\n2021-12-31,1,2.5\n",
+			suggestion: `the value matches the layout "2006-01-02", set ColumnFormats["Date"] to it`,
+		},
+		{
+			name:       "decimal comma",
+			data:       "Date,Qty,Price\n2021-12-31T00:00:00Z,1,\"2,5\"\n",
+			suggestion: `the value is formatted as in LocaleDE, set NumberParsers["Price"] to LocaleDE.NumberParser()`,
+		},
+		{
+			name:       "grouped decimal comma",
+			data:       "Date,Qty,Price\n2021-12-31T00:00:00Z,1,\"1.234,5\"\n",
+			suggestion: `the value is formatted as in LocaleDE, set NumberParsers["Price"] to LocaleDE.NumberParser()`,
+		},
+		{
+			name:       "thousands separators",
+			data:       "Date,Qty,Price\n2021-12-31T00:00:00Z,\"1,234\",2.5\n",
+			suggestion: `the value is formatted as in LocaleUS, set NumberParsers["Qty"] to LocaleUS.NumberParser()`,
+		},
+		{
+			name: "not a number",
+			data: "Date,Qty,Price\n2021-12-31T00:00:00Z,1,abc\n",
+		},
+		{
+			name:    "column with a parser",
+			data:    "Date,Qty,Price\n2021-12-31T00:00:00Z,1,\"2,5\"\n",
+			options: ReaderOptions{NumberParsers: map[string]*NumberParser{"Price": defaultNumberParser}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			options := tt.options
+			options.ReadHeaders = true
+			reader, err := NewReader(strings.NewReader(tt.data), &options)
+			require.NoError(t, err)
+
+			var rows []repairRow
+			err = reader.ReadAll(&rows)
+			require.Error(t, err)
+
+			var repairErr *RepairError
+			if tt.suggestion == "" {
+				assert.False(t, errors.As(err, &repairErr), err.Error())
+				return
+			}
+
+			require.True(t, errors.As(err, &repairErr), err.Error())
+			assert.Equal(t, tt.suggestion, repairErr.Suggestion)
+			assert.True(t, strings.HasSuffix(err.Error(), "; "+tt.suggestion))
+
+			var fieldErr *FieldError
+			require.True(t, errors.As(err, &fieldErr))
+			assert.Equal(t, repairErr, fieldErr.Err)
+		})
+	}
+}
+
+// TestLocale_NumberParser verifies locales parse the numbers they format
+func TestLocale_NumberParser(t *testing.T) {
+
+	tests := []struct {
+		locale *Locale
+		value  string
+		want   float64
+		valid  bool
+	}{
+		{locale: &LocaleUS, value: "1,234.5", want: 1234.5, valid: true},
+		{locale: &LocaleUS, value: "-1,234,567", want: -1234567, valid: true},
+		{locale: &LocaleUS, value: "1234.5", want: 1234.5, valid: true},
+		{locale: &LocaleUS, value: "12,34.5"},
+		{locale: &LocaleUS, value: "1,5"},
+		{locale: &LocaleDE, value: "1.234,5", want: 1234.5, valid: true},
+		{locale: &LocaleDE, value: "2,5", want: 2.5, valid: true},
+		{locale: &LocaleDE, value: "1.5"},
+		{locale: &LocaleFR, value: "1 234,5", want: 1234.5, valid: true},
+	}
+
+	for _, tt := range tests {
+		f, err := tt.locale.NumberParser().parseFloat(tt.value, 64)
+		if !tt.valid {
+			assert.Error(t, err, tt.value)
+			continue
+		}
+		if assert.NoError(t, err, tt.value) {
+			assert.Equal(t, tt.want, f, tt.value)
+		}
+	}
+
+	i, err := LocaleDE.NumberParser().parseInt("1.234.567", 64)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1234567), i)
+}
@@ -0,0 +1,68 @@
+package csvee
+
+import (
+	"reflect"
+	"strings"
+)
+
+// repeatedColumns maps each name that appears more than once in columnNames to the indexes of its
+// columns, in order.
+func repeatedColumns(columnNames []string) map[string][]int {
+
+	indexes := make(map[string][]int, len(columnNames))
+	for i, name := range columnNames {
+		indexes[name] = append(indexes[name], i)
+	}
+
+	for name, columns := range indexes {
+		if len(columns) == 1 {
+			delete(indexes, name)
+		}
+	}
+
+	return indexes
+}
+
+// collectedCells returns the cells of record at indexes after applying their whitespace policies,
+// leaving out those that are skipped, null, or blank.
+func (r *Reader) collectedCells(indexes []int, record []string) []string {
+
+	settings := r.settings()
+
+	cells := make([]string, 0, len(indexes))
+	for _, j := range indexes {
+		field, skip := applyWhitespacePolicy(settings[j].whitespace, record[j])
+		if skip || r.isNull(field) || strings.TrimSpace(field) == "" {
+			continue
+		}
+		cells = append(cells, field)
+	}
+
+	return cells
+}
+
+// setRepeated sets the repeated column's slice or array field on the struct pointed to by structPtr
+// from the cells of each of its columns. When merging, a row without any cells to collect leaves the
+// field untouched.
+func (r *Reader) setRepeated(structPtr reflect.Value, col columnPlan, record []string) error {
+
+	cells := r.collectedCells(col.repeats, record)
+	if len(cells) == 0 && r.merge {
+		return nil
+	}
+
+	v := settableField(structPtr.Elem(), col.field.Index)
+	if v.Kind() == reflect.Slice {
+		v.Set(reflect.MakeSlice(v.Type(), len(cells), len(cells)))
+	} else {
+		v.Set(reflect.Zero(v.Type()))
+	}
+
+	for i := 0; i < len(cells) && i < v.Len(); i++ {
+		if err := r.setValue(allocate(v.Index(i)), col, cells[i], true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_RepeatedColumns verifies repeated headers are collected into slice and array fields
+func TestReader_RepeatedColumns(t *testing.T) {
+
+	type item struct {
+		Name  string
+		Tag   []string
+		Score [2]int
+	}
+
+	input := "Name,Tag,Score,Tag,Score,Tag\nAnn,a,1,b,2,c\nBob, ,3,x,,\n"
+
+	_, err := NewReader(strings.NewReader(""), &ReaderOptions{ColumnNames: []string{"Name", "Tag", "Tag"}})
+	assert.EqualError(t, err, `duplicate column name "Tag"`)
+
+	reader, err := NewReader(strings.NewReader(input), &ReaderOptions{ReadHeaders: true, RepeatedColumns: true})
+	require.NoError(t, err)
+
+	var items []item
+	require.NoError(t, reader.ReadAll(&items))
+	assert.Equal(t, []item{
+		{Name: "Ann", Tag: []string{"a", "b", "c"}, Score: [2]int{1, 2}},
+		{Name: "Bob", Tag: []string{"x"}, Score: [2]int{3, 0}},
+	}, items)
+
+	reader, err = NewReader(strings.NewReader("Name,Name\nAnn,Bob\n"), &ReaderOptions{ReadHeaders: true, RepeatedColumns: true})
+	require.NoError(t, err)
+
+	var scalar struct{ Name string }
+	assert.EqualError(t, reader.Read(&scalar), `repeated column "Name" must map to a slice or array field`)
+}
+
+// TestReader_RepeatedColumnMaps verifies repeated headers are collected into the entries of maps
+func TestReader_RepeatedColumnMaps(t *testing.T) {
+
+	input := "Name,Tag,Tag,Score,Score\nAnn,a,\"b,c\",1,2\nBob,,,,\n"
+	options := &ReaderOptions{
+		ReadHeaders:     true,
+		RepeatedColumns: true,
+		ColumnTypes:     map[string]ColumnKind{"Score": KindInt},
+	}
+
+	reader, err := NewReader(strings.NewReader(input), options)
+	require.NoError(t, err)
+
+	var lists []map[string][]string
+	require.NoError(t, reader.ReadAll(&lists))
+	assert.Equal(t, []map[string][]string{
+		{"Name": {"Ann"}, "Tag": {"a", "b,c"}, "Score": {"1", "2"}},
+		{"Name": {"Bob"}},
+	}, lists)
+
+	reader, err = NewReader(strings.NewReader(input), options)
+	require.NoError(t, err)
+
+	var joined map[string]string
+	require.NoError(t, reader.Read(&joined))
+	assert.Equal(t, map[string]string{"Name": "Ann", "Tag": `a,"b,c"`, "Score": "1,2"}, joined)
+
+	reader, err = NewReader(strings.NewReader(input), options)
+	require.NoError(t, err)
+
+	var typed []map[string]interface{}
+	require.NoError(t, reader.ReadAll(&typed))
+	assert.Equal(t, []map[string]interface{}{
+		{"Name": "Ann", "Tag": []interface{}{"a", "b,c"}, "Score": []interface{}{int64(1), int64(2)}},
+		{"Name": "Bob"},
+	}, typed)
+}
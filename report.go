@@ -0,0 +1,201 @@
+package csvee
+
+import (
+	"encoding/csv"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// ReportVersion is the version of the Report format, which changes if fields are removed or change
+// meaning.
+const ReportVersion = 1
+
+// The kinds of failure a ReportEntry describes.
+const (
+	// ReportMalformed is a record that could not be tokenized, such as one with a bare quote.
+	ReportMalformed = "malformed"
+
+	// ReportRecord is a record that does not fit the columns, such as one with too few cells.
+	ReportRecord = "record"
+
+	// ReportCell is a cell that could not be decoded into its field.
+	ReportCell = "cell"
+
+	// ReportRule is a row that failed a validation rule.
+	ReportRule = "rule"
+
+	// ReportValidation is a row the Validate hook rejected.
+	ReportValidation = "validation"
+
+	// ReportTimeout is a row that took longer than the row timeout to decode.
+	ReportTimeout = "timeout"
+
+	// ReportInput is an error reading the input that is not specific to a row.
+	ReportInput = "input"
+)
+
+// Report describes the rows that failed to import, in a form that can be marshaled as JSON and
+// rendered by other programs, such as a web app showing users a table of the problems with a file
+// they uploaded. ReportSchema is its JSON Schema.
+type Report struct {
+	// Version is ReportVersion.
+	Version int `json:"version"`
+
+	// Failed is the number of rows that failed.
+	Failed int `json:"failed"`
+
+	// Budget is the ErrorBudget that was exceeded, which stopped reading, or zero if reading was not
+	// stopped by it.
+	Budget int `json:"budget,omitempty"`
+
+	// Entries describes each failure in the order it occurred.
+	Entries []ReportEntry `json:"entries"`
+}
+
+// ReportEntry describes a failure in a Report.
+type ReportEntry struct {
+	// Kind is one of the Report kinds, such as ReportCell.
+	Kind string `json:"kind"`
+
+	// Row and Line locate the row, as in ParseError, and are zero for input errors. Position is the
+	// byte column within Line the cell starts at, if it is known.
+	Row      int `json:"row"`
+	Line     int `json:"line"`
+	Position int `json:"position,omitempty"`
+
+	// Column and Value are the name and raw contents of the offending cell, for cell failures.
+	Column string `json:"column,omitempty"`
+	Value  string `json:"value,omitempty"`
+
+	// Rule, Columns, and Values are the name of the rule a row failed and the cells it refers to.
+	Rule    string   `json:"rule,omitempty"`
+	Columns []string `json:"columns,omitempty"`
+	Values  []string `json:"values,omitempty"`
+
+	// Code is the catalog code of the error, if it has one.
+	Code Code `json:"code,omitempty"`
+
+	// Message describes the failure, without the row and column it occurred in.
+	Message string `json:"message"`
+
+	// Suggestion is the option that would repair the cell, if one is known, as in RepairError.
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// ReportSchema is the JSON Schema of a Report marshaled as JSON.
+const ReportSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "csvee import report",
+  "type": "object",
+  "required": ["version", "failed", "entries"],
+  "properties": {
+    "version": {"type": "integer", "const": 1},
+    "failed": {"type": "integer", "minimum": 0},
+    "budget": {"type": "integer", "minimum": 1},
+    "entries": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["kind", "row", "line", "message"],
+        "properties": {
+          "kind": {"enum": ["malformed", "record", "cell", "rule", "validation", "timeout", "input"]},
+          "row": {"type": "integer", "minimum": 0},
+          "line": {"type": "integer", "minimum": 0},
+          "position": {"type": "integer", "minimum": 1},
+          "column": {"type": "string"},
+          "value": {"type": "string"},
+          "rule": {"type": "string"},
+          "columns": {"type": "array", "items": {"type": "string"}},
+          "values": {"type": "array", "items": {"type": "string"}},
+          "code": {"type": "string", "pattern": "^CSVEE-[0-9]{3}$"},
+          "message": {"type": "string"},
+          "suggestion": {"type": "string"}
+        },
+        "additionalProperties": false
+      }
+    }
+  },
+  "additionalProperties": false
+}`
+
+// NewReport describes err, as returned by reading, as a Report: a *MultiError collected with
+// ErrorCollect, an *ErrorBudgetError, a *ParseError from a Decoder, or any other error, which is
+// reported as an input error. A nil error makes an empty report.
+func NewReport(err error) Report {
+
+	report := Report{Version: ReportVersion, Entries: []ReportEntry{}}
+
+	var budgetErr *ErrorBudgetError
+	if errors.As(err, &budgetErr) {
+		report.Budget = budgetErr.Budget
+		err = budgetErr.Err
+	}
+
+	var multiErr *MultiError
+	var parseErr *ParseError
+	switch {
+	case err == nil:
+	case errors.As(err, &multiErr):
+		for _, parseErr := range multiErr.Errors {
+			report.Entries = append(report.Entries, reportEntry(parseErr))
+		}
+	case errors.As(err, &parseErr):
+		report.Entries = append(report.Entries, reportEntry(parseErr))
+	default:
+		report.Entries = append(report.Entries, ReportEntry{Kind: ReportInput, Message: err.Error()})
+	}
+
+	for _, entry := range report.Entries {
+		if entry.Kind != ReportInput {
+			report.Failed++
+		}
+	}
+
+	return report
+}
+
+// reportEntry describes the failure of a row.
+func reportEntry(e *ParseError) ReportEntry {
+
+	entry := ReportEntry{
+		Kind:     ReportRecord,
+		Row:      e.Row,
+		Line:     e.Line,
+		Position: e.Position,
+		Column:   e.Column,
+		Value:    e.Value,
+		Message:  e.Err.Error(),
+	}
+	entry.Code, _ = ErrorCode(e.Err)
+
+	var (
+		csvErr        *csv.ParseError
+		repairErr     *RepairError
+		ruleErr       *RuleError
+		validationErr *ValidationError
+		timeoutErr    *RowTimeoutError
+	)
+	switch {
+	case errors.As(e.Err, &csvErr):
+		entry.Kind, entry.Message = ReportMalformed, csvErr.Err.Error()
+	case e.Column != "":
+		entry.Kind = ReportCell
+		if errors.As(e.Err, &repairErr) {
+			entry.Message, entry.Suggestion = repairErr.Err.Error(), repairErr.Suggestion
+		}
+	case errors.As(e.Err, &ruleErr):
+		entry.Kind, entry.Rule, entry.Columns, entry.Values = ReportRule, ruleErr.Rule, ruleErr.Columns, ruleErr.Values
+		entry.Message = fmt.Sprintf("rule %q failed", ruleErr.Rule)
+		if ruleErr.Err != ErrRuleFailed {
+			entry.Message = fmt.Sprintf("rule %q: %v", ruleErr.Rule, ruleErr.Err)
+		}
+	case errors.As(e.Err, &validationErr):
+		entry.Kind, entry.Message = ReportValidation, validationErr.Err.Error()
+	case errors.As(e.Err, &timeoutErr):
+		entry.Kind = ReportTimeout
+		entry.Message = fmt.Sprintf("decoding took longer than %v", timeoutErr.Timeout)
+	}
+
+	return entry
+}
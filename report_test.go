@@ -0,0 +1,115 @@
+package csvee
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewReport verifies the rows that fail are described in a report
+func TestNewReport(t *testing.T) {
+
+	const data = "Name,Age\nann,34\nbob,x\ncat,-1\nold,150\nfoo,\"1,500\"\nsam\n\"dan,5\n"
+
+	reader, err := NewReader(strings.NewReader(data), &ReaderOptions{
+		ReadHeaders: true,
+		Validate:    validateAge,
+		Rules:       []Rule{{Name: "plausible", Expr: "Age <= 120"}},
+		OnError:     ErrorCollect,
+	})
+	require.NoError(t, err)
+	reader.CSVReader.FieldsPerRecord = -1
+
+	var rows []validatedRow
+	report := NewReport(reader.ReadAll(&rows))
+
+	assert.Equal(t, Report{Version: ReportVersion, Failed: 6, Entries: []ReportEntry{
+		{Kind: ReportCell, Row: 2, Line: 3, Position: 5, Column: "Age", Value: "x", Message: `invalid value "x" for int`},
+		{Kind: ReportValidation, Row: 3, Line: 4, Message: "age must not be negative"},
+		{
+			Kind: ReportRule, Row: 4, Line: 5, Rule: "plausible", Columns: []string{"Age"}, Values: []string{"150"},
+			Code: "CSVEE-022", Message: `rule "plausible" failed`,
+		},
+		{
+			Kind: ReportCell, Row: 5, Line: 6, Position: 5, Column: "Age", Value: "1,500", Message: `invalid value "1,500" for int`,
+			Suggestion: `the value is formatted as in LocaleUS, set NumberParsers["Age"] to LocaleUS.NumberParser()`,
+		},
+		{Kind: ReportRecord, Row: 6, Line: 7, Code: "CSVEE-001", Message: ErrColumnNamesMismatch.Error()},
+		{Kind: ReportMalformed, Row: 7, Line: 8, Position: 8, Message: `extraneous or missing " in quoted-field`},
+	}}, report)
+
+	data2, err := json.Marshal(report)
+	require.NoError(t, err)
+	var decoded Report
+	require.NoError(t, json.Unmarshal(data2, &decoded))
+	assert.Equal(t, report, decoded)
+}
+
+// TestNewReport_Errors verifies budgets, input errors, and the absence of errors are reported
+func TestNewReport_Errors(t *testing.T) {
+
+	assert.Equal(t, Report{Version: ReportVersion, Entries: []ReportEntry{}}, NewReport(nil))
+
+	assert.Equal(t, Report{Version: ReportVersion, Entries: []ReportEntry{{Kind: ReportInput, Message: "disk on fire"}}},
+		NewReport(errors.New("disk on fire")))
+
+	reader, err := NewReader(strings.NewReader("Name,Age\na,x\nb,y\nc,z\n"), &ReaderOptions{
+		ReadHeaders: true,
+		OnError:     ErrorCollect,
+		ErrorBudget: 1,
+	})
+	require.NoError(t, err)
+
+	var rows []validatedRow
+	report := NewReport(reader.ReadAll(&rows))
+	assert.Equal(t, 1, report.Budget)
+	assert.Equal(t, 2, report.Failed)
+	assert.Len(t, report.Entries, 2)
+}
+
+// TestReportSchema verifies the schema is valid JSON that describes every field of a report
+func TestReportSchema(t *testing.T) {
+
+	var schema struct {
+		Required   []string                   `json:"required"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(ReportSchema), &schema))
+
+	var entries struct {
+		Items struct {
+			Required   []string                   `json:"required"`
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(schema.Properties["entries"], &entries))
+
+	report := Report{Version: ReportVersion, Budget: 1, Entries: []ReportEntry{{
+		Kind: ReportRule, Row: 1, Line: 2, Position: 1, Column: "A", Value: "v", Rule: "r", Columns: []string{"A"},
+		Values: []string{"v"}, Code: "CSVEE-022", Message: "m", Suggestion: "s",
+	}}}
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+
+	var marshaled struct {
+		Entries []map[string]interface{} `json:"entries"`
+	}
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	require.NoError(t, json.Unmarshal(data, &marshaled))
+
+	for name := range fields {
+		assert.Contains(t, schema.Properties, name)
+	}
+	for _, name := range schema.Required {
+		assert.Contains(t, fields, name)
+	}
+	for name := range marshaled.Entries[0] {
+		assert.Contains(t, entries.Items.Properties, name)
+	}
+	assert.Len(t, marshaled.Entries[0], len(entries.Items.Properties))
+}
@@ -0,0 +1,610 @@
+package csvee
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// Rule is a validation rule involving several columns of a row, checked after the row is decoded.
+//
+// Expr is written in a small expression syntax made of clauses joined by "and", optionally followed
+// by "when" and further clauses that must all hold for the rule to apply:
+//
+//	EndDate >= StartDate
+//	State required when Country == "US"
+//	Qty > 0 and Price >= 0 when Status != "cancelled"
+//
+// A clause either compares two operands with ==, !=, <, <=, >, or >=, or requires that a column's
+// cell is not blank. Operands are column names, which may be quoted in backticks, or literals:
+// double quoted strings, numbers, true, or false. Literals are parsed as the type of the field the
+// other operand is decoded into, using that column's format, so a date can be compared against
+// "2020-01-01" given a suitable format. Columns in comparisons must be decoded into a field whose
+// type is a string, bool, number, or time. A comparison involving a blank cell is unknown: it does
+// not fail the rule, and as a condition it stops the rule from applying.
+//
+// An operand may also be the length of a column's cell, as in len(Name) <= 20 or len(`Full Name`)
+// > 0, which counts bytes, or runes if ReaderOptions.RuneLengths is set. Blank cells have a length
+// of zero, and comparisons of the length of a null cell are unknown.
+type Rule struct {
+	// Expr is the rule in the expression syntax. It is ignored if Check is set.
+	Expr string
+
+	// Name identifies the rule in errors. It defaults to Expr.
+	Name string
+
+	// Check, if set, is called with a pointer to each decoded row in place of evaluating Expr.
+	// Returning an error fails the rule.
+	Check func(v interface{}) error
+
+	// Columns are the columns Check depends on, whose cells are quoted in errors.
+	Columns []string
+}
+
+// RuleError records a row that failed a validation rule, along with the cells of the columns the
+// rule refers to.
+type RuleError struct {
+	Row     int
+	Rule    string
+	Columns []string
+	Values  []string
+	Err     error
+}
+
+func (e *RuleError) Error() string {
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "row %d: rule %q", e.Row, e.Rule)
+	if e.Err == ErrRuleFailed {
+		b.WriteString(" failed")
+	} else {
+		fmt.Fprintf(&b, ": %v", e.Err)
+	}
+
+	for i, column := range e.Columns {
+		if i == 0 {
+			b.WriteString(" (")
+		} else {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s=%q", column, e.Values[i])
+	}
+	if len(e.Columns) > 0 {
+		b.WriteString(")")
+	}
+
+	return b.String()
+}
+
+// Unwrap returns the underlying error.
+func (e *RuleError) Unwrap() error {
+
+	return e.Err
+}
+
+// ruleOperand is a column or literal in a clause, or if length is set, the length of a column's
+// cell.
+type ruleOperand struct {
+	column  string
+	literal string
+	length  bool
+}
+
+// ruleClause compares two operands, or with an op of "required", requires the left operand.
+type ruleClause struct {
+	left, right ruleOperand
+	op          string
+}
+
+// compiledRule is a Rule with its expression parsed.
+type compiledRule struct {
+	Rule
+	checks     []ruleClause
+	conditions []ruleClause
+}
+
+// compileRules parses the expressions of rules.
+func compileRules(rules []Rule) ([]compiledRule, error) {
+
+	compiled := make([]compiledRule, len(rules))
+	for i, rule := range rules {
+
+		if rule.Name == "" {
+			rule.Name = rule.Expr
+		}
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		compiled[i].Rule = rule
+
+		if rule.Check != nil {
+			continue
+		}
+
+		if strings.TrimSpace(rule.Expr) == "" {
+			return nil, errors.Errorf("rule %d must have an expression or a check", i+1)
+		}
+
+		checks, conditions, err := parseRule(rule.Expr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "rule %q", rule.Expr)
+		}
+		compiled[i].checks, compiled[i].conditions = checks, conditions
+
+		compiled[i].Columns = nil
+		seen := make(map[string]bool)
+		for _, clause := range append(append([]ruleClause(nil), checks...), conditions...) {
+			for _, operand := range []ruleOperand{clause.left, clause.right} {
+				if operand.column != "" && !seen[operand.column] {
+					seen[operand.column] = true
+					compiled[i].Columns = append(compiled[i].Columns, operand.column)
+				}
+			}
+		}
+	}
+
+	return compiled, nil
+}
+
+// validateRuleColumns checks that rules only refer to known columns.
+func validateRuleColumns(rules []compiledRule, columnNames []string) error {
+
+	known := make(map[string]bool, len(columnNames))
+	for _, name := range columnNames {
+		known[name] = true
+	}
+
+	for _, rule := range rules {
+		for _, column := range rule.Columns {
+			if !known[column] {
+				return errors.Errorf("rule %q refers to unknown column %q", rule.Name, column)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseRule parses a rule expression into the clauses that must hold and the conditions under
+// which they apply.
+func parseRule(expr string) ([]ruleClause, []ruleClause, error) {
+
+	tokens, err := tokenizeRule(expr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var checks, conditions []ruleClause
+	clauses := &checks
+	for len(tokens) > 0 {
+
+		clause, rest, err := parseClause(tokens)
+		if err != nil {
+			return nil, nil, err
+		}
+		*clauses = append(*clauses, clause)
+		tokens = rest
+
+		if len(tokens) == 0 {
+			break
+		}
+
+		switch {
+		case tokens[0] == "and":
+		case tokens[0] == "when" && clauses == &checks:
+			clauses = &conditions
+		default:
+			return nil, nil, errors.Errorf("unexpected %q", tokens[0])
+		}
+
+		tokens = tokens[1:]
+		if len(tokens) == 0 {
+			return nil, nil, errors.New("unexpected end of expression")
+		}
+	}
+
+	return checks, conditions, nil
+}
+
+func parseClause(tokens []string) (ruleClause, []string, error) {
+
+	left, err := parseOperand(tokens[0])
+	if err != nil {
+		return ruleClause{}, nil, err
+	}
+
+	if len(tokens) < 2 {
+		return ruleClause{}, nil, errors.New("unexpected end of expression")
+	}
+
+	if tokens[1] == "required" {
+		if left.column == "" || left.length {
+			return ruleClause{}, nil, errors.Errorf("only columns can be required, got %s", tokens[0])
+		}
+		return ruleClause{left: left, op: "required"}, tokens[2:], nil
+	}
+
+	switch tokens[1] {
+	case "==", "!=", "<", "<=", ">", ">=":
+	default:
+		return ruleClause{}, nil, errors.Errorf("expected a comparison or required after %s, got %q", tokens[0], tokens[1])
+	}
+
+	if len(tokens) < 3 {
+		return ruleClause{}, nil, errors.New("unexpected end of expression")
+	}
+
+	right, err := parseOperand(tokens[2])
+	if err != nil {
+		return ruleClause{}, nil, err
+	}
+
+	if left.column == "" && right.column == "" {
+		return ruleClause{}, nil, errors.Errorf("%s %s %s compares two literals", tokens[0], tokens[1], tokens[2])
+	}
+
+	return ruleClause{left: left, right: right, op: tokens[1]}, tokens[3:], nil
+}
+
+func parseOperand(token string) (ruleOperand, error) {
+
+	switch {
+	case token == "and" || token == "when" || token == "required":
+		return ruleOperand{}, errors.Errorf("expected a column or literal, got %q", token)
+	case strings.HasPrefix(token, "len(") && strings.HasSuffix(token, ")"):
+		operand, err := parseOperand(token[len("len(") : len(token)-1])
+		if err != nil || operand.column == "" || operand.length {
+			return ruleOperand{}, errors.Errorf("expected a column in %s", token)
+		}
+		operand.length = true
+		return operand, nil
+	case token == "``":
+		return ruleOperand{}, errors.New("column names must not be empty")
+	case strings.HasPrefix(token, "`"):
+		return ruleOperand{column: token[1 : len(token)-1]}, nil
+	case strings.HasPrefix(token, `"`):
+		literal, err := strconv.Unquote(token)
+		if err != nil {
+			return ruleOperand{}, errors.Errorf("invalid string %s", token)
+		}
+		return ruleOperand{literal: literal}, nil
+	case token == "true" || token == "false":
+		return ruleOperand{literal: token}, nil
+	case token[0] == '-' || token[0] == '.' || unicode.IsDigit(rune(token[0])):
+		if _, err := strconv.ParseFloat(token, 64); err != nil {
+			return ruleOperand{}, errors.Errorf("invalid number %s", token)
+		}
+		return ruleOperand{literal: token}, nil
+	case unicode.IsLetter(rune(token[0])) || token[0] == '_':
+		return ruleOperand{column: token}, nil
+	}
+
+	return ruleOperand{}, errors.Errorf("expected a column or literal, got %q", token)
+}
+
+// tokenizeRule splits expr into operators, quoted strings, backtick quoted columns, and words.
+func tokenizeRule(expr string) ([]string, error) {
+
+	var tokens []string
+	for i := 0; i < len(expr); {
+
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.ContainsRune("=!<>", rune(c)):
+			j := i + 1
+			if j < len(expr) && expr[j] == '=' {
+				j++
+			}
+			if op := expr[i:j]; op == "=" || op == "!" {
+				return nil, errors.Errorf("invalid operator %q", op)
+			}
+			tokens = append(tokens, expr[i:j])
+			i = j
+		case c == '"' || c == '`':
+			j := i + 1
+			for j < len(expr) && expr[j] != c {
+				if c == '"' && expr[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(expr) {
+				return nil, errors.Errorf("unterminated %c", c)
+			}
+			tokens = append(tokens, expr[i:j+1])
+			i = j + 1
+		default:
+			j := i
+			for j < len(expr) && !strings.ContainsRune(" \t\r\n=!<>\"`", rune(expr[j])) {
+				j++
+			}
+
+			// The length of a column quoted in backticks is a single token.
+			if expr[i:j] == "len(" && j < len(expr) && expr[j] == '`' {
+				end := strings.IndexByte(expr[j+1:], '`')
+				if end < 0 {
+					return nil, errors.New("unterminated `")
+				}
+				j += end + 2
+				if j >= len(expr) || expr[j] != ')' {
+					return nil, errors.New("unterminated len(")
+				}
+				j++
+			}
+
+			tokens = append(tokens, expr[i:j])
+			i = j
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil, errors.New("empty expression")
+	}
+
+	return tokens, nil
+}
+
+// checkRules checks the reader's rules against the decoded row in structPtr.
+func (r *Reader) checkRules(row row, structPtr reflect.Value) error {
+
+	for _, rule := range r.rules {
+		if err := r.checkRule(rule, row, structPtr); err != nil {
+			record := r.LastRecord()
+			values := make([]string, len(rule.Columns))
+			for i, column := range rule.Columns {
+				values[i] = record.Raw(column)
+			}
+
+			return &RuleError{Row: r.rowsRead, Rule: rule.Name, Columns: rule.Columns, Values: values, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// checkRule returns ErrRuleFailed if the row does not satisfy rule, or another error if the rule
+// could not be evaluated.
+func (r *Reader) checkRule(rule compiledRule, row row, structPtr reflect.Value) error {
+
+	if rule.Check != nil {
+		return rule.Check(structPtr.Interface())
+	}
+
+	for _, condition := range rule.conditions {
+		holds, known, err := r.evaluateClause(condition, row, structPtr)
+		if err != nil {
+			return err
+		}
+		if !holds || !known {
+			return nil
+		}
+	}
+
+	for _, check := range rule.checks {
+		holds, known, err := r.evaluateClause(check, row, structPtr)
+		if err != nil {
+			return err
+		}
+		if known && !holds {
+			return ErrRuleFailed
+		}
+	}
+
+	return nil
+}
+
+// evaluateClause reports whether clause holds for the row, and whether that is known, which it is
+// not for comparisons involving blank cells.
+func (r *Reader) evaluateClause(clause ruleClause, row row, structPtr reflect.Value) (bool, bool, error) {
+
+	record := r.LastRecord()
+
+	if clause.op == "required" {
+		cell, exists := record.Column(clause.left.column)
+		if !exists {
+			return false, false, errors.Errorf("column %q not found", clause.left.column)
+		}
+		return strings.TrimSpace(cell) != "" && !r.isNull(cell), true, nil
+	}
+
+	// Resolve the column operands first so that literals can be parsed as the type of their field.
+	var col columnPlan
+	operands := [2]ruleOperand{clause.left, clause.right}
+	values := [2]reflect.Value{}
+	for i, operand := range operands {
+
+		if operand.column == "" {
+			continue
+		}
+
+		cell, exists := record.Column(operand.column)
+		if !exists {
+			return false, false, errors.Errorf("column %q not found", operand.column)
+		}
+		if operand.length {
+			if r.isNull(cell) && strings.TrimSpace(cell) != "" {
+				return false, false, nil
+			}
+			values[i] = reflect.ValueOf(int64(r.cellLength(cell)))
+			continue
+		}
+		if strings.TrimSpace(cell) == "" || r.isNull(cell) {
+			return false, false, nil
+		}
+
+		index, decoded := row.plan.byName[operand.column]
+		if !decoded {
+			return false, false, errors.Errorf("column %q is not decoded into a field", operand.column)
+		}
+		col = row.plan.columns[index]
+
+		target, _ := r.elementTarget(structPtr, col, row.record)
+		value, err := ruleValue(target, col)
+		if err != nil {
+			return false, false, err
+		}
+		values[i] = value
+	}
+
+	for i, operand := range operands {
+
+		if operand.column != "" {
+			continue
+		}
+
+		parsing := cellParsing{
+			format:      r.columnFormat(col.name),
+			numbers:     r.numberParser(col.name),
+			boolParsing: r.boolParsing,
+			location:    r.location,
+			zones:       r.zones,
+			dates:       r.dates,
+		}
+
+		value, err := parseValue(operand.literal, values[1-i].Type(), parsing)
+		if err != nil {
+			return false, false, errors.Wrapf(err, "literal %q for column %q", operand.literal, col.name)
+		}
+		values[i] = value
+	}
+
+	cmp, err := compareValues(values[0], values[1])
+	if err != nil {
+		return false, false, err
+	}
+
+	switch clause.op {
+	case "==":
+		return cmp == 0, true, nil
+	case "!=":
+		return cmp != 0, true, nil
+	case "<":
+		return cmp < 0, true, nil
+	case "<=":
+		return cmp <= 0, true, nil
+	case ">":
+		return cmp > 0, true, nil
+	}
+
+	return cmp >= 0, true, nil
+}
+
+// cellLength returns the length of cell in bytes, or in runes if the reader counts lengths in runes.
+func (r *Reader) cellLength(cell string) int {
+
+	if r.runeLengths {
+		return utf8.RuneCountInString(cell)
+	}
+
+	return len(cell)
+}
+
+// ruleValue returns the value of the field col is decoded into, dereferencing pointers and
+// unwrapping nullable wrappers.
+func ruleValue(structPtr reflect.Value, col columnPlan) (reflect.Value, error) {
+
+	value, _ := fieldByIndex(structPtr.Elem(), col.field.Index)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if col.nullable {
+		value = value.Field(col.nullValue)
+	}
+
+	if !isTimeType(value.Type()) && !isPrimitiveKind(value.Kind()) {
+		return value, errors.Errorf("column %q is decoded into a %s, which cannot be compared", col.name, value.Type())
+	}
+
+	return value, nil
+}
+
+// compareValues returns -1, 0, or 1 as a is less than, equal to, or greater than b. false is
+// ordered before true.
+func compareValues(a, b reflect.Value) (int, error) {
+
+	switch {
+	case isTimeType(a.Type()) && isTimeType(b.Type()):
+		ta, tb := a.Interface().(time.Time), b.Interface().(time.Time)
+		switch {
+		case ta.Before(tb):
+			return -1, nil
+		case ta.After(tb):
+			return 1, nil
+		}
+		return 0, nil
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String()), nil
+	case a.Kind() == reflect.Bool && b.Kind() == reflect.Bool:
+		return compareFloats(boolRank(a.Bool()), boolRank(b.Bool())), nil
+	case isIntKind(a.Kind()) && isIntKind(b.Kind()):
+		return compareInts(a.Int(), b.Int()), nil
+	case isNumberKind(a.Kind()) && isNumberKind(b.Kind()):
+		return compareFloats(numberFloat(a), numberFloat(b)), nil
+	}
+
+	return 0, errors.Errorf("cannot compare %s with %s", a.Type(), b.Type())
+}
+
+func isIntKind(k reflect.Kind) bool {
+
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isNumberKind(k reflect.Kind) bool {
+
+	return isPrimitiveKind(k) && k != reflect.String && k != reflect.Bool
+}
+
+func numberFloat(v reflect.Value) float64 {
+
+	switch {
+	case isIntKind(v.Kind()):
+		return float64(v.Int())
+	case v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64:
+		return float64(v.Uint())
+	}
+
+	return v.Float()
+}
+
+func compareInts(a, b int64) int {
+
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func compareFloats(a, b float64) int {
+
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func boolRank(b bool) float64 {
+
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,230 @@
+package csvee
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ruleRow struct {
+	Country   string
+	State     string
+	Qty       int
+	Price     *float64
+	Status    sql.NullString
+	StartDate sql.NullTime
+	EndDate   sql.NullTime
+}
+
+// TestReader_Rules verifies cross-field rules are checked against each decoded row
+func TestReader_Rules(t *testing.T) {
+
+	const header = "Country,State,Qty,Price,Status,StartDate,EndDate\n"
+
+	var testCases = []struct {
+		name    string
+		data    string
+		rules   []Rule
+		expRows int
+		expErr  string
+	}{
+		{
+			name:    "dates in order",
+			data:    "US,CA,1,2,,2020-01-01,2020-02-01\nUS,CA,1,2,,2020-01-01,2020-01-01\n",
+			rules:   []Rule{{Expr: "EndDate >= StartDate"}},
+			expRows: 2,
+		},
+		{
+			name:   "dates out of order",
+			data:   "US,CA,1,2,,2020-01-01,2020-02-01\nUS,CA,1,2,,2020-03-01,2020-02-01\n",
+			rules:  []Rule{{Expr: "EndDate >= StartDate"}},
+			expErr: `row 2: rule "EndDate >= StartDate" failed (EndDate="2020-02-01", StartDate="2020-03-01")`,
+		},
+		{
+			name:    "blank cells are unknown",
+			data:    "US,CA,1,2,,2020-01-01,\n",
+			rules:   []Rule{{Expr: "EndDate >= StartDate"}},
+			expRows: 1,
+		},
+		{
+			name:    "required when condition holds",
+			data:    "US,CA,1,2,,,\nFR,,1,2,,,\n",
+			rules:   []Rule{{Expr: `State required when Country == "US"`}},
+			expRows: 2,
+		},
+		{
+			name:   "required but blank",
+			data:   "US, ,1,2,,,\n",
+			rules:  []Rule{{Expr: `State required when Country == "US"`, Name: "us-state"}},
+			expErr: `row 1: rule "us-state" failed (State=" ", Country="US")`,
+		},
+		{
+			name:   "literals, pointers, and nullable wrappers",
+			data:   "US,CA,3,0.5,open,,\nUS,CA,0,-1,open,,\n",
+			rules:  []Rule{{Expr: "Qty > 0 and Price >= 0 when Status != \"cancelled\" and `Country` == \"US\""}},
+			expErr: `row 2: rule "Qty > 0 and Price >= 0 when Status != \"cancelled\" and ` + "`Country`" + ` == \"US\"" failed (Qty="0", Price="-1", Status="open", Country="US")`,
+		},
+		{
+			name:    "condition on blank nullable",
+			data:    "US,CA,0,,,,\n",
+			rules:   []Rule{{Expr: `Qty > 0 when Status == "open"`}},
+			expRows: 1,
+		},
+		{
+			name:   "incomparable types",
+			data:   "US,CA,1,2,,2020-01-01,\n",
+			rules:  []Rule{{Expr: "Qty < StartDate"}},
+			expErr: `row 1: rule "Qty < StartDate": cannot compare int with time.Time (Qty="1", StartDate="2020-01-01")`,
+		},
+		{
+			name: "check function",
+			data: "US,CA,1,2,,,\nUS,NY,1,2,,,\n",
+			rules: []Rule{{
+				Name:    "no new york",
+				Columns: []string{"State"},
+				Check: func(v interface{}) error {
+					if v.(*ruleRow).State == "NY" {
+						return errors.New("NY is not served")
+					}
+					return nil
+				},
+			}},
+			expErr: `row 2: rule "no new york": NY is not served (State="NY")`,
+		},
+		{
+			name:   "syntax error",
+			rules:  []Rule{{Expr: "EndDate => StartDate"}},
+			expErr: `rule "EndDate => StartDate": invalid operator "="`,
+		},
+		{
+			name:   "unknown column",
+			rules:  []Rule{{Expr: "Finish >= StartDate"}},
+			expErr: `rule "Finish >= StartDate" refers to unknown column "Finish"`,
+		},
+		{
+			name:   "two literals",
+			rules:  []Rule{{Expr: `1 == "1"`}},
+			expErr: `rule "1 == \"1\"": 1 == "1" compares two literals`,
+		},
+		{
+			name:   "dangling and",
+			rules:  []Rule{{Expr: "State required and"}},
+			expErr: `rule "State required and": unexpected end of expression`,
+		},
+		{
+			name:   "empty",
+			rules:  []Rule{{}},
+			expErr: "rule 1 must have an expression or a check",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			options := &ReaderOptions{
+				ReadHeaders:   true,
+				ColumnFormats: map[string]string{"StartDate": "2006-01-02", "EndDate": "2006-01-02"},
+				Rules:         tt.rules,
+			}
+
+			reader, err := NewReader(strings.NewReader(header+tt.data), options)
+			if err != nil {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+
+			var rows int
+			for {
+				var row ruleRow
+				err = reader.Read(&row)
+				if err != nil {
+					break
+				}
+				rows++
+			}
+
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+
+			assert.Equal(t, io.EOF, err)
+			assert.Equal(t, tt.expRows, rows)
+		})
+	}
+}
+
+// TestReader_Rules_Length verifies len() counts the bytes of cells, or their runes if the reader
+// counts lengths in runes
+func TestReader_Rules_Length(t *testing.T) {
+
+	type nameRow struct {
+		Name string
+	}
+
+	var testCases = []struct {
+		name        string
+		data        string
+		expr        string
+		runeLengths bool
+		expRows     int
+		expErr      string
+	}{
+		{name: "ascii", data: "Ann\n", expr: "len(Name) <= 3", expRows: 1},
+		{name: "bytes", data: "Zoë😀\n", expr: "len(Name) <= 3", expErr: `row 1: rule "len(Name) <= 3" failed (Name="Zoë😀")`},
+		{name: "runes", data: "Zoë😀\n", expr: "len(Name) == 4", runeLengths: true, expRows: 1},
+		{name: "cjk runes", data: "東京都\n", expr: "len(`Name`) < 4", runeLengths: true, expRows: 1},
+		{name: "blank", data: "\"\"\n", expr: "len(Name) > 0", expErr: `row 1: rule "len(Name) > 0" failed (Name="")`},
+		{name: "null", data: "N/A\n", expr: "len(Name) < 3", expRows: 1},
+		{name: "not a column", data: "Ann\n", expr: "len(5) > 0", expErr: `rule "len(5) > 0": expected a column in len(5)`},
+		{name: "unterminated", data: "Ann\n", expr: "len(`Name > 0", expErr: "rule \"len(`Name > 0\": unterminated `"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader("Name\n"+tt.data), &ReaderOptions{
+				ReadHeaders: true,
+				NullValues:  []string{"N/A"},
+				Rules:       []Rule{{Expr: tt.expr}},
+				RuneLengths: tt.runeLengths,
+			})
+			if err != nil {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+
+			var rows []nameRow
+			err = reader.ReadAll(&rows)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, rows, tt.expRows)
+		})
+	}
+}
+
+// TestReader_RulesErrorType verifies rule failures can be inspected
+func TestReader_RulesErrorType(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader("Qty,Price\n5,1\n"), &ReaderOptions{
+		ReadHeaders: true,
+		Rules:       []Rule{{Expr: "Qty <= 3"}},
+	})
+	require.NoError(t, err)
+
+	var rows []ruleRow
+	err = reader.ReadAll(&rows)
+
+	var ruleErr *RuleError
+	require.True(t, errors.As(err, &ruleErr))
+	assert.True(t, errors.Is(err, ErrRuleFailed))
+	assert.Equal(t, &RuleError{Row: 1, Rule: "Qty <= 3", Columns: []string{"Qty"}, Values: []string{"5"}, Err: ErrRuleFailed}, ruleErr)
+}
@@ -0,0 +1,256 @@
+package csvee
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ColumnDoc documents the meaning of a column.
+type ColumnDoc struct {
+	Description string
+	Unit        string
+}
+
+// Schema describes the columns of a CSV file and the types they are decoded to.
+type Schema struct {
+	Columns []SchemaColumn `json:"columns"`
+}
+
+// SchemaColumn describes a single column of a Schema.
+type SchemaColumn struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Format      string `json:"format,omitempty"`
+	Description string `json:"description,omitempty"`
+	Unit        string `json:"unit,omitempty"`
+}
+
+// Schema returns the schema of the reader's columns when decoded into v. Column documentation is
+// taken from the desc and unit struct tags of v's fields and from ReaderOptions.ColumnDocs, which
+// takes precedence. Columns that do not map to a field have an empty type.
+func (r *Reader) Schema(v interface{}) (*Schema, error) {
+
+	if v == nil {
+		return nil, ErrReadTargetNil
+	}
+
+	vType := getBaseType(reflect.TypeOf(v))
+	if vType.Kind() != reflect.Struct {
+		return nil, ErrUnsupportedTargetType
+	}
+
+	schema := &Schema{Columns: make([]SchemaColumn, len(r.ColumnNames))}
+	for i, name := range r.ColumnNames {
+
+		column := SchemaColumn{
+			Name:   name,
+			Format: r.ColumnFormats[name],
+		}
+
+		if structField, exists := fieldForColumn(vType, name); exists {
+			column.Type = structField.Type.String()
+			column.Description = structField.Tag.Get("desc")
+			column.Unit = structField.Tag.Get("unit")
+		}
+
+		if doc, exists := r.columnDocs[name]; exists {
+			if doc.Description != "" {
+				column.Description = doc.Description
+			}
+			if doc.Unit != "" {
+				column.Unit = doc.Unit
+			}
+		}
+
+		schema.Columns[i] = column
+	}
+
+	return schema, nil
+}
+
+// Fingerprint returns a hash of the schema's column names in order, which is the HeaderFingerprint of
+// a header row naming them, so that files can be routed to a handler or saved mapping by their
+// shape. Types, formats, and documentation are not included.
+func (s Schema) Fingerprint() string {
+
+	return HeaderFingerprint(s.columnNames())
+}
+
+// UnorderedFingerprint returns a hash of the schema's column names regardless of their order, which
+// is the Fingerprint of the same columns sorted by name.
+func (s Schema) UnorderedFingerprint() string {
+
+	names := s.columnNames()
+	sort.Strings(names)
+	return HeaderFingerprint(names)
+}
+
+// columnNames returns the names of the schema's columns in order.
+func (s Schema) columnNames() []string {
+
+	names := make([]string, len(s.Columns))
+	for i, column := range s.Columns {
+		names[i] = column.Name
+	}
+
+	return names
+}
+
+// DataDictionary returns a JSON data dictionary describing each column of schema.
+func DataDictionary(schema *Schema) ([]byte, error) {
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// DataDictionaryCSV returns a CSV data dictionary describing each column of schema, with a header
+// row followed by one row per column.
+func DataDictionaryCSV(schema *Schema) ([]byte, error) {
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	records := [][]string{{"name", "type", "format", "description", "unit"}}
+	for _, c := range schema.Columns {
+		records = append(records, []string{c.Name, c.Type, c.Format, c.Description, c.Unit})
+	}
+
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// SchemaDiff describes how the columns of one schema differ from those of another, such as the
+// schema of an earlier file of a feed.
+type SchemaDiff struct {
+	// Added and Removed hold the columns only the second and only the first schema have.
+	Added   []SchemaColumn
+	Removed []SchemaColumn
+
+	// Retyped holds the columns both schemas have whose type or format differs.
+	Retyped []SchemaChange
+
+	// Reordered is true if the columns both schemas have are in a different order.
+	Reordered bool
+}
+
+// SchemaChange describes a column whose type or format differs between two schemas.
+type SchemaChange struct {
+	Old SchemaColumn
+	New SchemaColumn
+}
+
+// CompareSchemas returns how the columns of b differ from those of a, matching columns by name, and
+// columns that share a name by the order they appear in. Descriptions and units are not compared.
+func CompareSchemas(a, b Schema) SchemaDiff {
+
+	var diff SchemaDiff
+
+	oldKeys, newKeys := schemaColumnKeys(a.Columns), schemaColumnKeys(b.Columns)
+	old := make(map[schemaColumnKey]SchemaColumn, len(a.Columns))
+	for i, column := range a.Columns {
+		old[oldKeys[i]] = column
+	}
+	current := make(map[schemaColumnKey]bool, len(b.Columns))
+	for _, key := range newKeys {
+		current[key] = true
+	}
+
+	var shared []schemaColumnKey
+	for i, column := range b.Columns {
+		previous, exists := old[newKeys[i]]
+		switch {
+		case !exists:
+			diff.Added = append(diff.Added, column)
+			continue
+		case previous.Type != column.Type || previous.Format != column.Format:
+			diff.Retyped = append(diff.Retyped, SchemaChange{Old: previous, New: column})
+		}
+		shared = append(shared, newKeys[i])
+	}
+
+	i := 0
+	for j, column := range a.Columns {
+		if !current[oldKeys[j]] {
+			diff.Removed = append(diff.Removed, column)
+			continue
+		}
+		if shared[i] != oldKeys[j] {
+			diff.Reordered = true
+		}
+		i++
+	}
+
+	return diff
+}
+
+// schemaColumnKey identifies a column of a schema by its name and the number of columns of the same
+// name before it.
+type schemaColumnKey struct {
+	name       string
+	occurrence int
+}
+
+// schemaColumnKeys returns the key of each of columns.
+func schemaColumnKeys(columns []SchemaColumn) []schemaColumnKey {
+
+	seen := make(map[string]int, len(columns))
+	keys := make([]schemaColumnKey, len(columns))
+	for i, column := range columns {
+		keys[i] = schemaColumnKey{name: column.Name, occurrence: seen[column.Name]}
+		seen[column.Name]++
+	}
+
+	return keys
+}
+
+// Empty reports whether the schemas compared are the same.
+func (d SchemaDiff) Empty() bool {
+
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Retyped) == 0 && !d.Reordered
+}
+
+// String describes the differences, such as `added column "email"; column "id" changed from int64 to
+// string`, or returns "no changes".
+func (d SchemaDiff) String() string {
+
+	var changes []string
+	for _, column := range d.Added {
+		changes = append(changes, fmt.Sprintf("added column %q", column.Name))
+	}
+	for _, column := range d.Removed {
+		changes = append(changes, fmt.Sprintf("removed column %q", column.Name))
+	}
+	for _, change := range d.Retyped {
+		changes = append(changes, fmt.Sprintf("column %q changed from %s to %s", change.New.Name,
+			describeSchemaType(change.Old), describeSchemaType(change.New)))
+	}
+	if d.Reordered {
+		changes = append(changes, "columns reordered")
+	}
+
+	if len(changes) == 0 {
+		return "no changes"
+	}
+
+	return strings.Join(changes, "; ")
+}
+
+// describeSchemaType describes the type of column, with its format if it has one.
+func describeSchemaType(column SchemaColumn) string {
+
+	if column.Type == "" {
+		return "no type"
+	}
+	if column.Format == "" {
+		return column.Type
+	}
+
+	return fmt.Sprintf("%s (%s)", column.Type, column.Format)
+}
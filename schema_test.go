@@ -0,0 +1,144 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type documentedReadTo struct {
+	Temp float64 `desc:"Air temperature" unit:"C"`
+	City string  `desc:"City name"`
+}
+
+// TestDataDictionary verifies column documentation is exported from tags and options
+func TestDataDictionary(t *testing.T) {
+
+	reader, err := NewReader(
+		strings.NewReader(""),
+		&ReaderOptions{
+			ColumnNames:   []string{"Temp", "City", "Extra"},
+			ColumnFormats: map[string]string{"Extra": TimeFormatUnix},
+			ColumnDocs:    map[string]ColumnDoc{"City": {Description: "Nearest city"}, "Extra": {Unit: "s"}},
+		},
+	)
+	require.NoError(t, err)
+
+	schema, err := reader.Schema(&documentedReadTo{})
+	require.NoError(t, err)
+	assert.Equal(t, []SchemaColumn{
+		{Name: "Temp", Type: "float64", Description: "Air temperature", Unit: "C"},
+		{Name: "City", Type: "string", Description: "Nearest city"},
+		{Name: "Extra", Format: TimeFormatUnix, Unit: "s"},
+	}, schema.Columns)
+
+	dictionary, err := DataDictionaryCSV(schema)
+	require.NoError(t, err)
+	assert.Equal(t, "name,type,format,description,unit\n"+
+		"Temp,float64,,Air temperature,C\n"+
+		"City,string,,Nearest city,\n"+
+		"Extra,,unix,,s\n", string(dictionary))
+
+	dictionary, err = DataDictionary(schema)
+	require.NoError(t, err)
+	assert.Contains(t, string(dictionary), `"description": "Air temperature"`)
+}
+
+// TestCompareSchemas verifies added, removed, retyped, and reordered columns are reported
+func TestCompareSchemas(t *testing.T) {
+
+	first, err := InferSchema(strings.NewReader("id,name,joined,score\n1,Ann,2021-03-04,5\n"), 10)
+	require.NoError(t, err)
+
+	diff := CompareSchemas(first, first)
+	assert.True(t, diff.Empty())
+	assert.Equal(t, "no changes", diff.String())
+
+	later, err := InferSchema(strings.NewReader("name,id,joined,email\nAnn,A1,03/04/2021,a@x.io\n"), 10)
+	require.NoError(t, err)
+
+	diff = CompareSchemas(first, later)
+	assert.False(t, diff.Empty())
+	assert.Equal(t, []SchemaColumn{{Name: "email", Type: "string"}}, diff.Added)
+	assert.Equal(t, []SchemaColumn{{Name: "score", Type: "int64"}}, diff.Removed)
+	assert.Equal(t, []SchemaChange{
+		{Old: SchemaColumn{Name: "id", Type: "int64"}, New: SchemaColumn{Name: "id", Type: "string"}},
+		{Old: SchemaColumn{Name: "joined", Type: "time.Time", Format: "2006-01-02"}, New: SchemaColumn{Name: "joined", Type: "time.Time", Format: "01/02/2006"}},
+	}, diff.Retyped)
+	assert.True(t, diff.Reordered)
+	assert.Equal(t, `added column "email"; removed column "score"; column "id" changed from int64 to string; `+
+		`column "joined" changed from time.Time (2006-01-02) to time.Time (01/02/2006); columns reordered`, diff.String())
+
+	var testCases = []struct {
+		name string
+		inA  []SchemaColumn
+		inB  []SchemaColumn
+		exp  string
+	}{
+		{
+			name: "added, removed, and retyped",
+			inA:  []SchemaColumn{{Name: "a"}, {Name: "b"}},
+			inB:  []SchemaColumn{{Name: "a", Type: "string"}, {Name: "c"}},
+			exp:  `added column "c"; removed column "b"; column "a" changed from no type to string`,
+		},
+		{
+			name: "repeated name removed",
+			inA:  []SchemaColumn{{Name: "X"}, {Name: "X"}},
+			inB:  []SchemaColumn{{Name: "X"}},
+			exp:  `removed column "X"`,
+		},
+		{
+			name: "repeated name added",
+			inA:  []SchemaColumn{{Name: "X"}, {Name: "Y"}},
+			inB:  []SchemaColumn{{Name: "X"}, {Name: "Y"}, {Name: "X", Type: "int64"}},
+			exp:  `added column "X"`,
+		},
+		{
+			name: "repeated name retyped",
+			inA:  []SchemaColumn{{Name: "X", Type: "string"}, {Name: "X", Type: "string"}},
+			inB:  []SchemaColumn{{Name: "X", Type: "string"}, {Name: "X", Type: "int64"}},
+			exp:  `column "X" changed from string to int64`,
+		},
+		{
+			name: "repeated name reordered",
+			inA:  []SchemaColumn{{Name: "X"}, {Name: "Y"}, {Name: "X"}},
+			inB:  []SchemaColumn{{Name: "X"}, {Name: "X"}, {Name: "Y"}},
+			exp:  "columns reordered",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			diff := CompareSchemas(Schema{Columns: tt.inA}, Schema{Columns: tt.inB})
+			assert.Equal(t, tt.exp, diff.String())
+		})
+	}
+}
+
+// TestSchema_Fingerprint verifies fingerprints identify column names with and without their order
+func TestSchema_Fingerprint(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader("id,name,joined\n1,Ann,2021-03-04\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	schema, err := InferSchema(strings.NewReader("id,name,joined\n1,Ann,2021-03-04\n"), 10)
+	require.NoError(t, err)
+	assert.Equal(t, reader.HeaderFingerprint(), schema.Fingerprint())
+	assert.Len(t, schema.Fingerprint(), 64)
+
+	retyped, err := InferSchema(strings.NewReader("id,name,joined\nA,Ann,x\n"), 10)
+	require.NoError(t, err)
+	assert.Equal(t, schema.Fingerprint(), retyped.Fingerprint())
+
+	reordered, err := InferSchema(strings.NewReader("joined,id,name\n2021-03-04,1,Ann\n"), 10)
+	require.NoError(t, err)
+	assert.NotEqual(t, schema.Fingerprint(), reordered.Fingerprint())
+	assert.Equal(t, schema.UnorderedFingerprint(), reordered.UnorderedFingerprint())
+	assert.Equal(t, HeaderFingerprint([]string{"id", "joined", "name"}), reordered.UnorderedFingerprint())
+
+	assert.NotEqual(t, schema.UnorderedFingerprint(), Schema{Columns: schema.Columns[:2]}.UnorderedFingerprint())
+	assert.Equal(t, []string{"id", "name", "joined"}, schema.columnNames())
+}
@@ -0,0 +1,108 @@
+package csvee
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Shard is a byte range of a CSV file, from Start up to but not including End, that begins and ends
+// on record boundaries so that it can be read on its own.
+type Shard struct {
+	Start int64
+	End   int64
+}
+
+// Section returns a reader of the shard's bytes in file.
+func (s Shard) Section(file io.ReaderAt) *io.SectionReader {
+
+	return io.NewSectionReader(file, s.Start, s.End-s.Start)
+}
+
+// ShardPlan splits file into at most nShards shards of about equal size so that as many workers can
+// each read their own part of it in parallel. Shards only end after a newline outside quoted
+// fields, so records with quoted newlines are never split, and a shard may be larger than its share
+// when a record is. Fewer shards are returned when the file has fewer records than nShards.
+//
+// The first shard starts at the beginning of the file, so it holds the header row if there is one;
+// read it with ReaderOptions.ReadHeaders, and the other shards with the column names it yields as
+// ReaderOptions.ColumnNames. Quotes are assumed to follow RFC 4180, as they do unless
+// ReaderOptions.LazyQuotes is needed to read the file. file is read from its beginning and then
+// returned to its previous position.
+func ShardPlan(file io.ReadSeeker, nShards int) ([]Shard, error) {
+
+	if nShards <= 0 {
+		return nil, errors.Errorf("shard count must be positive, got %d", nShards)
+	}
+
+	offset, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	boundaries, err := recordBoundaries(file, size, nShards)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var shards []Shard
+	start := int64(0)
+	for _, end := range append(boundaries, size) {
+		if end > start {
+			shards = append(shards, Shard{Start: start, End: end})
+			start = end
+		}
+	}
+
+	return shards, nil
+}
+
+// recordBoundaries scans the size bytes of r and returns the offsets of the first record to start
+// at or after each of the nShards-1 evenly spaced targets, in order and without duplicates.
+func recordBoundaries(r io.Reader, size int64, nShards int) ([]int64, error) {
+
+	var (
+		boundaries []int64
+		quoted     bool
+		pos        int64
+		next       = 1
+		buf        = make([]byte, 64*1024)
+	)
+
+	target := func(i int) int64 { return size * int64(i) / int64(nShards) }
+
+	for next < nShards {
+		n, err := r.Read(buf)
+		for _, b := range buf[:n] {
+			pos++
+			switch {
+			case b == '"':
+				quoted = !quoted
+			case b == '\n' && !quoted && next < nShards && pos >= target(next):
+				boundaries = append(boundaries, pos)
+				for next < nShards && target(next) <= pos {
+					next++
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return boundaries, nil
+}
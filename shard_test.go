@@ -0,0 +1,101 @@
+package csvee
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type shardRow struct {
+	ID   int
+	Note string
+}
+
+// readShards decodes each shard of input, reading headers from the first.
+func readShards(t *testing.T, input string, shards []Shard) []shardRow {
+
+	var (
+		rows    []shardRow
+		columns []string
+	)
+	for i, shard := range shards {
+		options := &ReaderOptions{ReadHeaders: true}
+		if i > 0 {
+			options = &ReaderOptions{ColumnNames: columns}
+		}
+
+		reader, err := NewReader(shard.Section(strings.NewReader(input)), options)
+		require.NoError(t, err)
+		columns = reader.Columns()
+
+		var shardRows []shardRow
+		require.NoError(t, reader.ReadAll(&shardRows))
+		rows = append(rows, shardRows...)
+	}
+
+	return rows
+}
+
+// TestShardPlan verifies shards end on record boundaries and together hold every record
+func TestShardPlan(t *testing.T) {
+
+	var b strings.Builder
+	b.WriteString("ID,Note\n")
+	var expected []shardRow
+	for i := 1; i <= 50; i++ {
+		note := fmt.Sprintf("note %d", i)
+		if i%3 == 0 {
+			note = fmt.Sprintf("line one\nline \"two\"\n%d", i)
+		}
+		expected = append(expected, shardRow{ID: i, Note: note})
+		fmt.Fprintf(&b, "%d,\"%s\"\n", i, strings.ReplaceAll(note, `"`, `""`))
+	}
+	input := b.String()
+
+	for _, nShards := range []int{1, 2, 3, 7, 16, 100} {
+		t.Run(fmt.Sprint(nShards), func(t *testing.T) {
+
+			file := strings.NewReader(input)
+			_, err := file.Seek(5, io.SeekStart)
+			require.NoError(t, err)
+
+			shards, err := ShardPlan(file, nShards)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(shards), nShards)
+			if nShards <= 16 {
+				assert.Len(t, shards, nShards)
+			}
+			assert.Equal(t, int64(0), shards[0].Start)
+			assert.Equal(t, int64(len(input)), shards[len(shards)-1].End)
+			for i := 1; i < len(shards); i++ {
+				assert.Equal(t, shards[i-1].End, shards[i].Start)
+			}
+
+			position, err := file.Seek(0, io.SeekCurrent)
+			require.NoError(t, err)
+			assert.Equal(t, int64(5), position)
+
+			assert.Equal(t, expected, readShards(t, input, shards))
+		})
+	}
+}
+
+// TestShardPlan_Small verifies small inputs yield fewer shards and invalid counts are rejected
+func TestShardPlan_Small(t *testing.T) {
+
+	input := "ID,Note\n1,a\n"
+	shards, err := ShardPlan(strings.NewReader(input), 4)
+	require.NoError(t, err)
+	assert.Equal(t, []Shard{{Start: 0, End: 8}, {Start: 8, End: 12}}, shards)
+
+	shards, err = ShardPlan(strings.NewReader(""), 4)
+	require.NoError(t, err)
+	assert.Empty(t, shards)
+
+	_, err = ShardPlan(strings.NewReader(input), 0)
+	assert.EqualError(t, err, "shard count must be positive, got 0")
+}
@@ -0,0 +1,46 @@
+package csvee
+
+import (
+	"crypto/ed25519"
+)
+
+// Signer signs the checksum sidecars written beside exported files so recipients can verify their
+// integrity and origin.
+type Signer interface {
+	// Sign returns a detached signature of data.
+	Sign(data []byte) ([]byte, error)
+}
+
+// SignerFunc is an adapter to allow the use of ordinary functions as a Signer.
+type SignerFunc func(data []byte) ([]byte, error)
+
+// Sign calls f(data).
+func (f SignerFunc) Sign(data []byte) ([]byte, error) {
+
+	return f(data)
+}
+
+// Ed25519Signer returns a Signer that signs with key. Signatures can be checked with
+// ed25519.Verify and the matching public key.
+func Ed25519Signer(key ed25519.PrivateKey) Signer {
+
+	return SignerFunc(func(data []byte) ([]byte, error) {
+		return ed25519.Sign(key, data), nil
+	})
+}
+
+// writeSignature writes the signature of the sidecar contents to a file beside it with a ".sig"
+// extension.
+func (w *Writer) writeSignature(sidecar string, contents []byte) error {
+
+	if w.signer == nil {
+		return nil
+	}
+
+	signature, err := w.signer.Sign(contents)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(sidecar+".sig", signature, w.sync)
+}
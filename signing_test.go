@@ -0,0 +1,43 @@
+package csvee
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriter_Signer verifies checksum sidecars are signed
+func TestWriter_Signer(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "csvee-signing")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	public, private, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "out.csv")
+	require.NoError(t, WriteFile(path, writeFrom{Name: "a"}, &WriterOptions{
+		ColumnNames:     []string{"Name"},
+		Checksum:        ChecksumSHA256,
+		ChecksumSidecar: true,
+		Signer:          Ed25519Signer(private),
+	}))
+
+	sidecar, err := ioutil.ReadFile(path + ".sha256")
+	require.NoError(t, err)
+	signature, err := ioutil.ReadFile(path + ".sha256.sig")
+	require.NoError(t, err)
+
+	assert.Equal(t, sha256Hex("a\n")+"  out.csv\n", string(sidecar))
+	assert.True(t, ed25519.Verify(public, sidecar, signature))
+
+	_, err = NewWriter(&bytes.Buffer{}, &WriterOptions{ColumnNames: []string{"Name"}, Signer: Ed25519Signer(private)})
+	assert.Error(t, err)
+}
@@ -0,0 +1,39 @@
+package csvee
+
+import (
+	"reflect"
+)
+
+// slabAllocator hands out pointers to new values of a type, allocating them in contiguous slabs
+// rather than one at a time to reduce the number of objects the garbage collector must track.
+type slabAllocator struct {
+	base reflect.Type
+	size int
+	slab reflect.Value
+	next int
+}
+
+func newSlabAllocator(base reflect.Type, size int) *slabAllocator {
+
+	return &slabAllocator{
+		base: base,
+		size: size,
+	}
+}
+
+// new returns a pointer to a zero value of the allocator's type.
+func (a *slabAllocator) new() reflect.Value {
+
+	if a.size <= 1 {
+		return reflect.New(a.base)
+	}
+
+	if !a.slab.IsValid() || a.next == a.size {
+		a.slab = reflect.MakeSlice(reflect.SliceOf(a.base), a.size, a.size)
+		a.next = 0
+	}
+
+	v := a.slab.Index(a.next).Addr()
+	a.next++
+	return v
+}
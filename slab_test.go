@@ -0,0 +1,29 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_ReadAllSlab verifies pointer destinations are allocated contiguously in slabs
+func TestReader_ReadAllSlab(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader("I\n1\n2\n3\n"), &ReaderOptions{ReadHeaders: true, SlabSize: 2})
+	require.NoError(t, err)
+
+	var actualData []*readTo
+	require.NoError(t, reader.ReadAll(&actualData))
+	require.Len(t, actualData, 3)
+
+	for i, row := range actualData {
+		assert.Equal(t, i+1, row.I)
+	}
+
+	first := uintptr(unsafe.Pointer(actualData[0]))
+	second := uintptr(unsafe.Pointer(actualData[1]))
+	assert.Equal(t, unsafe.Sizeof(readTo{}), second-first)
+}
@@ -0,0 +1,80 @@
+package csvee
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// defaultSliceDelimiter separates the elements of slice fields unless another is configured.
+const defaultSliceDelimiter = ","
+
+// splitSlice splits field into the elements of a slice, separated by delimiter. An element whose
+// first character other than spaces is a double quote is quoted: it ends at the next double quote
+// that is not doubled, and may contain the delimiter and doubled double quotes, which stand for one.
+// Spaces around quoted elements are dropped.
+func splitSlice(field, delimiter string) ([]string, error) {
+
+	var elements []string
+	rest := field
+	for {
+		trimmed := strings.TrimLeft(rest, " ")
+		if !strings.HasPrefix(trimmed, `"`) {
+			if i := strings.Index(rest, delimiter); i >= 0 {
+				elements = append(elements, rest[:i])
+				rest = rest[i+len(delimiter):]
+				continue
+			}
+			return append(elements, rest), nil
+		}
+
+		var element strings.Builder
+		rest = trimmed[1:]
+		for {
+			i := strings.IndexByte(rest, '"')
+			if i < 0 {
+				return nil, errors.New("slice element has an unterminated quote")
+			}
+			element.WriteString(rest[:i])
+			rest = rest[i+1:]
+			if !strings.HasPrefix(rest, `"`) {
+				break
+			}
+			element.WriteByte('"')
+			rest = rest[1:]
+		}
+		elements = append(elements, element.String())
+
+		if !strings.HasPrefix(rest, delimiter) {
+			rest = strings.TrimLeft(rest, " ")
+		}
+		if rest == "" {
+			return elements, nil
+		}
+		if !strings.HasPrefix(rest, delimiter) {
+			return nil, errors.New("slice element has text after its closing quote")
+		}
+		rest = rest[len(delimiter):]
+	}
+}
+
+// quoteSliceElement quotes element, as splitSlice expects, if it contains delimiter or would
+// otherwise be taken to be quoted.
+func quoteSliceElement(element, delimiter string) string {
+
+	if !strings.Contains(element, delimiter) && !strings.HasPrefix(strings.TrimLeft(element, " "), `"`) {
+		return element
+	}
+
+	return `"` + strings.ReplaceAll(element, `"`, `""`) + `"`
+}
+
+// validateSliceDelimiter checks that delimiter can separate slice elements.
+func validateSliceDelimiter(delimiter string) error {
+
+	if delimiter == "" || strings.Contains(delimiter, `"`) || strings.TrimLeft(delimiter, " ") == "" {
+		return errors.Errorf("invalid slice delimiter %q", delimiter)
+	}
+
+	return nil
+}
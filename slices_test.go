@@ -0,0 +1,164 @@
+package csvee
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sliceRow struct {
+	Tags   []string
+	Scores []*int
+	Levels []level
+}
+
+// TestSplitSlice verifies cells are split into slice elements, honoring quoted elements
+func TestSplitSlice(t *testing.T) {
+
+	var testCases = []struct {
+		name      string
+		field     string
+		delimiter string
+		exp       []string
+		expErr    string
+	}{
+		{name: "plain", field: "a,b,c", delimiter: ",", exp: []string{"a", "b", "c"}},
+		{name: "multi-character delimiter", field: "x, y; z", delimiter: "; ", exp: []string{"x, y", "z"}},
+		{name: "quoted delimiter", field: `"a|b"|c`, delimiter: "|", exp: []string{"a|b", "c"}},
+		{name: "doubled quotes", field: `"say ""hi"""`, delimiter: ",", exp: []string{`say "hi"`}},
+		{name: "spaces around quotes", field: ` "a,b" , c`, delimiter: ",", exp: []string{"a,b", " c"}},
+		{name: "inner quote", field: `a"b,c`, delimiter: ",", exp: []string{`a"b`, "c"}},
+		{name: "empty elements", field: ",", delimiter: ",", exp: []string{"", ""}},
+		{name: "unterminated", field: `"a,b`, delimiter: ",", expErr: "slice element has an unterminated quote"},
+		{name: "text after quote", field: `"a"b,c`, delimiter: ",", expErr: "slice element has text after its closing quote"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			actual, err := splitSlice(tt.field, tt.delimiter)
+			if tt.expErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, actual)
+		})
+	}
+}
+
+// TestReader_SliceDelimiters verifies slice fields are split on the configured delimiters and may
+// hold pointers and unmarshaler types
+func TestReader_SliceDelimiters(t *testing.T) {
+
+	one, two := 1, 2
+
+	var testCases = []struct {
+		name    string
+		input   string
+		options ReaderOptions
+		exp     []sliceRow
+		expErr  string
+	}{
+		{
+			name:  "default",
+			input: "Tags,Scores,Levels\n\"a,\"\"b,c\"\"\",\"1, ,2\",\"warn,info\"\n",
+			exp:   []sliceRow{{Tags: []string{"a", "b,c"}, Scores: []*int{&one, nil, &two}, Levels: []level{"WARN", "INFO"}}},
+		},
+		{
+			name:    "reader delimiter",
+			input:   "Tags,Scores,Levels\na|b|c,1|2,warn\n",
+			options: ReaderOptions{SliceDelimiter: "|"},
+			exp:     []sliceRow{{Tags: []string{"a", "b", "c"}, Scores: []*int{&one, &two}, Levels: []level{"WARN"}}},
+		},
+		{
+			name:    "column delimiter",
+			input:   "Tags,Scores,Levels\n\"x, y; z\",1|2,\n",
+			options: ReaderOptions{SliceDelimiter: "|", SliceDelimiters: map[string]string{"Tags": "; "}},
+			exp:     []sliceRow{{Tags: []string{"x, y", "z"}, Scores: []*int{&one, &two}, Levels: []level{}}},
+		},
+		{
+			name:   "unterminated quote",
+			input:  "Tags,Scores,Levels\n\"\"\"a,b\",,\n",
+			expErr: `column "Tags": slice element has an unterminated quote`,
+		},
+		{
+			name:    "invalid delimiter",
+			input:   "Tags,Scores,Levels\n",
+			options: ReaderOptions{SliceDelimiter: " "},
+			expErr:  `invalid slice delimiter " "`,
+		},
+		{
+			name:    "unknown column",
+			input:   "Tags,Scores,Levels\n",
+			options: ReaderOptions{SliceDelimiters: map[string]string{"Nope": "|"}},
+			expErr:  `slice delimiter provided for unknown column "Nope"`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			options := tt.options
+			options.ReadHeaders = true
+			reader, err := NewReader(strings.NewReader(tt.input), &options)
+			if err == nil {
+				var actual []sliceRow
+				if err = reader.ReadAll(&actual); err == nil {
+					assert.Equal(t, tt.exp, actual)
+				}
+			}
+
+			if tt.expErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+// TestWriter_SliceDelimiters verifies slice elements are joined with the configured delimiters,
+// quoted where needed, and read back unchanged
+func TestWriter_SliceDelimiters(t *testing.T) {
+
+	one := 1
+	rows := []sliceRow{
+		{Tags: []string{"a|b", `"c"`, "d"}, Scores: []*int{&one, nil}, Levels: []level{"WARN"}},
+		{Tags: []string{"x, y"}, Levels: []level{}},
+	}
+
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, &WriterOptions{
+		ColumnNames:     []string{"Tags", "Scores", "Levels"},
+		WriteHeaders:    true,
+		SliceDelimiter:  "|",
+		SliceDelimiters: map[string]string{"Levels": ";"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteAll(rows))
+	require.NoError(t, writer.Flush())
+
+	assert.Equal(t, "Tags,Scores,Levels\n\"\"\"a|b\"\"|\"\"\"\"\"\"c\"\"\"\"\"\"|d\",1|,WARN\n\"x, y\",,\n", buf.String())
+
+	reader, err := NewReader(&buf, &ReaderOptions{
+		ReadHeaders:     true,
+		SliceDelimiter:  "|",
+		SliceDelimiters: map[string]string{"Levels": ";"},
+	})
+	require.NoError(t, err)
+
+	var actual []sliceRow
+	require.NoError(t, reader.ReadAll(&actual))
+	require.Len(t, actual, 2)
+	assert.Equal(t, rows[0].Tags, actual[0].Tags)
+	assert.Equal(t, []*int{&one, nil}, actual[0].Scores)
+	assert.Equal(t, rows[0].Levels, actual[0].Levels)
+	assert.Equal(t, rows[1].Tags, actual[1].Tags)
+	assert.Equal(t, []*int{}, actual[1].Scores)
+}
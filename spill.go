@@ -0,0 +1,639 @@
+package csvee
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// defaultMemoryBudget is the number of bytes of records held in memory before they are spilled.
+const defaultMemoryBudget = 64 << 20
+
+// defaultMaxOpenFiles is the number of spill files merged at once unless SpillOptions.MaxOpenFiles
+// says otherwise.
+const defaultMaxOpenFiles = 64
+
+// SpillOptions bounds the memory that Sort, Dedupe, and Join hold records in, so that they can
+// process inputs larger than memory. Records are held until their cells exceed MemoryBudget, then
+// sorted and written to a temporary file, and the files are merged once every record has been read.
+type SpillOptions struct {
+	// MemoryBudget is the approximate number of bytes of records held in memory before they are
+	// spilled to disk. Defaults to 64 MiB.
+	MemoryBudget int
+
+	// TempDir is the directory spill files are created in. They are removed before the operator
+	// returns. Defaults to os.TempDir.
+	TempDir string
+
+	// MaxOpenFiles is the number of spill files merged at once. With more, groups of them are first
+	// merged into larger files, in as many passes as it takes, so that large inputs do not run into
+	// the limit on open files. Defaults to 64, and must be at least 2 if set.
+	MaxOpenFiles int
+}
+
+// Sort reads the remaining records of r and writes them to w ordered by the cells of the key
+// columns, compared as strings, keeping the order they were read in where keys are equal. w must
+// have r's columns. Records beyond options' memory budget are spilled to disk; options may be nil
+// for the defaults. Sort flushes w but does not close it.
+func Sort(r *Reader, key Columns, w *Writer, options *SpillOptions) error {
+
+	if len(key) == 0 {
+		return errors.New("at least one key column is required")
+	}
+
+	return sortRecords(r, key, w, options, nil)
+}
+
+// Dedupe reads the remaining records of r and writes the first record read with each key to w, in
+// order of their keys as Sort orders them, returning the number of records dropped as duplicates.
+// The key defaults to every column, which drops records that repeat another entirely. Unlike
+// ReaderOptions.DedupWindow, duplicates are found however far apart they are. w must have r's
+// columns. Records beyond options' memory budget are spilled to disk; options may be nil for the
+// defaults. Dedupe flushes w but does not close it.
+func Dedupe(r *Reader, key Columns, w *Writer, options *SpillOptions) (int, error) {
+
+	if len(key) == 0 {
+		key = r.Columns()
+	}
+
+	var (
+		dropped int
+		last    []string
+	)
+	err := sortRecords(r, key, w, options, func(record []string, keyIndexes []int) bool {
+		if last != nil && compareKeys(last, record, keyIndexes) == 0 {
+			dropped++
+			return false
+		}
+		last = record
+		return true
+	})
+
+	return dropped, err
+}
+
+// Join reads the remaining records of left and right and writes a record to w for each pair of
+// them whose key columns hold the same cells, in order of their keys as Sort orders them. Records
+// of left and right without a match are dropped, as in an SQL inner join. Each record written holds
+// the cells of the left record followed by those of the right record that are not in the key, so w
+// must have left's columns followed by right's other columns. Both inputs are sorted with records
+// beyond options' memory budget spilled to disk, but the right records that share a key are held in
+// memory while they are joined. options may be nil for the defaults. Join flushes w but does not
+// close it.
+func Join(left, right *Reader, key Columns, w *Writer, options *SpillOptions) error {
+
+	if len(key) == 0 {
+		return errors.New("at least one key column is required")
+	}
+
+	leftKey, err := columnIndexes(left.ColumnNames, key)
+	if err != nil {
+		return err
+	}
+	rightKey, err := columnIndexes(right.ColumnNames, key)
+	if err != nil {
+		return err
+	}
+
+	inKey := make(map[int]bool, len(rightKey))
+	for _, k := range rightKey {
+		inKey[k] = true
+	}
+	var rightOther []int
+	columns := append([]string(nil), left.ColumnNames...)
+	for i, name := range right.ColumnNames {
+		if !inKey[i] {
+			rightOther = append(rightOther, i)
+			columns = append(columns, name)
+		}
+	}
+	if !sameColumns(w.ColumnNames, columns) {
+		return errors.New("the writer's columns must be the left reader's columns followed by the right reader's other columns")
+	}
+
+	nextLeft, closeLeft, err := sortedRecords(left, leftKey, options)
+	if err != nil {
+		return err
+	}
+	defer closeLeft()
+
+	nextRight, closeRight, err := sortedRecords(right, rightKey, options)
+	if err != nil {
+		return err
+	}
+	defer closeRight()
+
+	l, err := nextRecord(nextLeft)
+	if err != nil {
+		return err
+	}
+	r, err := nextRecord(nextRight)
+	if err != nil {
+		return err
+	}
+
+	for l != nil && r != nil {
+
+		c := compareKeyCells(l, leftKey, r, rightKey)
+		if c < 0 {
+			if l, err = nextRecord(nextLeft); err != nil {
+				return err
+			}
+			continue
+		}
+		if c > 0 {
+			if r, err = nextRecord(nextRight); err != nil {
+				return err
+			}
+			continue
+		}
+
+		group := [][]string{r}
+		for {
+			if r, err = nextRecord(nextRight); err != nil {
+				return err
+			}
+			if r == nil || compareKeys(group[0], r, rightKey) != 0 {
+				break
+			}
+			group = append(group, r)
+		}
+
+		for l != nil && compareKeyCells(l, leftKey, group[0], rightKey) == 0 {
+			for _, match := range group {
+				record := append(make([]string, 0, len(columns)), l...)
+				for _, i := range rightOther {
+					record = append(record, match[i])
+				}
+				if err := w.WriteRecord(record); err != nil {
+					return err
+				}
+			}
+			if l, err = nextRecord(nextLeft); err != nil {
+				return err
+			}
+		}
+	}
+
+	return w.Flush()
+}
+
+// sortRecords writes the records of r to w sorted by key, passing each to keep, if it is set, to
+// decide whether it is written.
+func sortRecords(r *Reader, key Columns, w *Writer, options *SpillOptions, keep func(record []string, keyIndexes []int) bool) error {
+
+	if !sameColumns(w.ColumnNames, r.ColumnNames) {
+		return errors.New("the writer's columns must be the reader's columns")
+	}
+
+	keyIndexes, err := columnIndexes(r.ColumnNames, key)
+	if err != nil {
+		return err
+	}
+
+	next, closeSorted, err := sortedRecords(r, keyIndexes, options)
+	if err != nil {
+		return err
+	}
+	defer closeSorted()
+
+	for {
+		record, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if keep != nil && !keep(record, keyIndexes) {
+			continue
+		}
+		if err := w.WriteRecord(record); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// sortedRecords reads the remaining records of r through a spiller and returns a function that
+// returns them in order of the cells of the key columns, then io.EOF, and a function that removes
+// the spiller's files.
+func sortedRecords(r *Reader, key []int, options *SpillOptions) (func() ([]string, error), func(), error) {
+
+	s, err := newSpiller(options, func(a, b []string) bool {
+		return compareKeys(a, b, key) < 0
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for {
+		record, err := r.ReadRaw()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			s.close()
+			return nil, nil, err
+		}
+
+		if err := s.add(copyFields(record)); err != nil {
+			s.close()
+			return nil, nil, err
+		}
+	}
+
+	next, err := s.sorted()
+	if err != nil {
+		s.close()
+		return nil, nil, err
+	}
+
+	return next, s.close, nil
+}
+
+// nextRecord returns the next record from next, or nil at io.EOF.
+func nextRecord(next func() ([]string, error)) ([]string, error) {
+
+	record, err := next()
+	if err == io.EOF {
+		return nil, nil
+	}
+
+	return record, err
+}
+
+// compareKeys compares the key cells of a and b in turn as strings.
+func compareKeys(a, b []string, key []int) int {
+
+	return compareKeyCells(a, key, b, key)
+}
+
+// compareKeyCells compares the cells of a in the columns aKey with those of b in the columns bKey
+// in turn as strings, so that records with different columns can be compared by the same key.
+func compareKeyCells(a []string, aKey []int, b []string, bKey []int) int {
+
+	for i := range aKey {
+		var cellA, cellB string
+		if aKey[i] < len(a) {
+			cellA = a[aKey[i]]
+		}
+		if bKey[i] < len(b) {
+			cellB = b[bKey[i]]
+		}
+		if c := strings.Compare(cellA, cellB); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// spiller holds records in memory up to a budget and spills them to sorted run files beyond it,
+// so that they can be read back in order however many there are. Run files are closed once they
+// are written and only opened again, at most maxOpen at a time, to be merged.
+type spiller struct {
+	less    func(a, b []string) bool
+	budget  int
+	dir     string
+	maxOpen int
+
+	held [][]string
+	size int
+	runs []string
+
+	// open holds the run files of the merge in progress.
+	open []*os.File
+}
+
+func newSpiller(options *SpillOptions, less func(a, b []string) bool) (*spiller, error) {
+
+	s := &spiller{less: less, budget: defaultMemoryBudget, maxOpen: defaultMaxOpenFiles}
+	if options != nil {
+		if options.MemoryBudget < 0 {
+			return nil, errors.Errorf("memory budget must not be negative, got %d", options.MemoryBudget)
+		}
+		if options.MemoryBudget > 0 {
+			s.budget = options.MemoryBudget
+		}
+		if options.MaxOpenFiles < 0 || options.MaxOpenFiles == 1 {
+			return nil, errors.Errorf("max open files must be at least 2, got %d", options.MaxOpenFiles)
+		}
+		if options.MaxOpenFiles > 0 {
+			s.maxOpen = options.MaxOpenFiles
+		}
+		s.dir = options.TempDir
+	}
+
+	return s, nil
+}
+
+// add holds record, spilling the records held if they exceed the budget.
+func (s *spiller) add(record []string) error {
+
+	s.held = append(s.held, record)
+	s.size += recordSize(record)
+	if s.size > s.budget {
+		return s.spill()
+	}
+
+	return nil
+}
+
+// spill sorts the records held and writes them to a new run file.
+func (s *spiller) spill() error {
+
+	if len(s.held) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(s.held, func(i, j int) bool {
+		return s.less(s.held[i], s.held[j])
+	})
+
+	i := 0
+	err := s.writeRun(func() ([]string, error) {
+		if i == len(s.held) {
+			return nil, io.EOF
+		}
+		i++
+		return s.held[i-1], nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.held, s.size = nil, 0
+	return nil
+}
+
+// writeRun writes the records returned by next, until io.EOF, to a new run file.
+func (s *spiller) writeRun(next func() ([]string, error)) error {
+
+	file, err := ioutil.TempFile(s.dir, "csvee-spill-*")
+	if err != nil {
+		return errors.Wrap(err, "creating spill file")
+	}
+	s.runs = append(s.runs, file.Name())
+
+	out := bufio.NewWriter(file)
+	for {
+		record, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_ = file.Close()
+			return err
+		}
+		if err := writeSpilled(out, record); err != nil {
+			_ = file.Close()
+			return errors.Wrap(err, "writing spill file")
+		}
+	}
+	if err := out.Flush(); err != nil {
+		_ = file.Close()
+		return errors.Wrap(err, "writing spill file")
+	}
+
+	return errors.Wrap(file.Close(), "writing spill file")
+}
+
+// sorted returns a function that returns every record added, in order, then io.EOF. Records that
+// compare equal are returned in the order they were added. Runs beyond the number that may be open
+// at once are first merged into larger runs.
+func (s *spiller) sorted() (func() ([]string, error), error) {
+
+	if len(s.runs) == 0 {
+		sort.SliceStable(s.held, func(i, j int) bool {
+			return s.less(s.held[i], s.held[j])
+		})
+		i := 0
+		return func() ([]string, error) {
+			if i == len(s.held) {
+				return nil, io.EOF
+			}
+			i++
+			return s.held[i-1], nil
+		}, nil
+	}
+
+	if err := s.spill(); err != nil {
+		return nil, err
+	}
+
+	for len(s.runs) > s.maxOpen {
+		runs := s.runs
+		s.runs = nil
+		for start := 0; start < len(runs); start += s.maxOpen {
+			end := start + s.maxOpen
+			if end > len(runs) {
+				end = len(runs)
+			}
+			if err := s.mergeRuns(runs[start:end]); err != nil {
+				s.runs = append(s.runs, runs[start:]...)
+				return nil, err
+			}
+		}
+	}
+
+	return s.merge(s.runs)
+}
+
+// mergeRuns merges the run files named by runs, which are removed afterwards, into a new run file.
+// Consecutive groups of runs merged in order keep equal records in the order they were added.
+func (s *spiller) mergeRuns(runs []string) error {
+
+	next, err := s.merge(runs)
+	if err == nil {
+		err = s.writeRun(next)
+	}
+
+	s.closeOpen()
+	for _, name := range runs {
+		_ = os.Remove(name)
+	}
+
+	return err
+}
+
+// merge opens the run files named by runs and returns a function that returns their records in
+// order, then io.EOF.
+func (s *spiller) merge(runs []string) (func() ([]string, error), error) {
+
+	merged := &spillRuns{less: s.less}
+	for i, name := range runs {
+		file, err := os.Open(name)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading spill file")
+		}
+		s.open = append(s.open, file)
+
+		run := &spillRun{in: bufio.NewReader(file), order: i}
+		if err := merged.advance(run); err != nil {
+			return nil, err
+		}
+	}
+
+	return func() ([]string, error) {
+		if merged.Len() == 0 {
+			return nil, io.EOF
+		}
+		run := merged.runs[0]
+		record := run.record
+		if err := merged.advance(run); err != nil {
+			return nil, err
+		}
+		return record, nil
+	}, nil
+}
+
+// closeOpen closes the run files of the merge in progress.
+func (s *spiller) closeOpen() {
+
+	for _, file := range s.open {
+		_ = file.Close()
+	}
+	s.open = nil
+}
+
+// close closes and removes the run files.
+func (s *spiller) close() {
+
+	s.closeOpen()
+	for _, name := range s.runs {
+		_ = os.Remove(name)
+	}
+	s.runs = nil
+}
+
+// recordSize approximates the bytes of memory record takes.
+func recordSize(record []string) int {
+
+	size := 24
+	for _, field := range record {
+		size += 16 + len(field)
+	}
+
+	return size
+}
+
+// writeSpilled writes record to a run file as its number of fields followed by the length and
+// bytes of each.
+func writeSpilled(out *bufio.Writer, record []string) error {
+
+	var buf [binary.MaxVarintLen64]byte
+	if _, err := out.Write(buf[:binary.PutUvarint(buf[:], uint64(len(record)))]); err != nil {
+		return err
+	}
+
+	for _, field := range record {
+		if _, err := out.Write(buf[:binary.PutUvarint(buf[:], uint64(len(field)))]); err != nil {
+			return err
+		}
+		if _, err := out.WriteString(field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readSpilled reads a record written by writeSpilled, returning io.EOF at the end of the run.
+func readSpilled(in *bufio.Reader) ([]string, error) {
+
+	fields, err := binary.ReadUvarint(in)
+	if err != nil {
+		return nil, err
+	}
+
+	record := make([]string, fields)
+	for i := range record {
+		length, err := binary.ReadUvarint(in)
+		if err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+		field := make([]byte, length)
+		if _, err := io.ReadFull(in, field); err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+		record[i] = string(field)
+	}
+
+	return record, nil
+}
+
+// spillRun is a run file being merged, with the next record read from it.
+type spillRun struct {
+	in     *bufio.Reader
+	order  int
+	record []string
+}
+
+// spillRuns is a heap of runs ordered by their next records, and by the order the runs were
+// spilled in where those are equal, so that merging them keeps equal records in the order added.
+type spillRuns struct {
+	less func(a, b []string) bool
+	runs []*spillRun
+}
+
+func (h *spillRuns) Len() int { return len(h.runs) }
+
+func (h *spillRuns) Less(i, j int) bool {
+
+	a, b := h.runs[i], h.runs[j]
+	if h.less(a.record, b.record) {
+		return true
+	}
+	if h.less(b.record, a.record) {
+		return false
+	}
+
+	return a.order < b.order
+}
+
+func (h *spillRuns) Swap(i, j int) { h.runs[i], h.runs[j] = h.runs[j], h.runs[i] }
+
+func (h *spillRuns) Push(x interface{}) { h.runs = append(h.runs, x.(*spillRun)) }
+
+func (h *spillRuns) Pop() interface{} {
+
+	run := h.runs[len(h.runs)-1]
+	h.runs = h.runs[:len(h.runs)-1]
+	return run
+}
+
+// advance reads the next record of run, which is either on the heap at its top or not yet on it,
+// and restores the heap, removing run from it at the end of its file.
+func (h *spillRuns) advance(run *spillRun) error {
+
+	record, err := readSpilled(run.in)
+	onHeap := run.record != nil
+	if err == io.EOF {
+		if onHeap {
+			heap.Pop(h)
+		}
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "reading spill file")
+	}
+
+	run.record = record
+	if onHeap {
+		heap.Fix(h, 0)
+	} else {
+		heap.Push(h, run)
+	}
+
+	return nil
+}
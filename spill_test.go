@@ -0,0 +1,246 @@
+package csvee
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSort verifies records are sorted by key, stably, whether they are held in memory or spilled
+// to disk, and that spill files are removed
+func TestSort(t *testing.T) {
+
+	input := "id,name,note\n" +
+		"3,c,\"multi\nline\"\n" +
+		"1,a,first\n" +
+		"2,b,\n" +
+		"1,a,second\n" +
+		"10,j,x\n"
+	exp := "id,name,note\n" +
+		"1,a,first\n" +
+		"1,a,second\n" +
+		"10,j,x\n" +
+		"2,b,\n" +
+		"3,c,\"multi\nline\"\n"
+
+	var testCases = []struct {
+		name    string
+		budget  int
+		maxOpen int
+	}{
+		{name: "in memory", budget: 0},
+		{name: "spill every record", budget: 1},
+		{name: "spill some records", budget: 150},
+		{name: "merge in passes", budget: 1, maxOpen: 2},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			dir := t.TempDir()
+
+			reader, err := NewReader(strings.NewReader(input), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			writer, err := NewWriter(&buf, &WriterOptions{ColumnNames: reader.Columns(), WriteHeaders: true})
+			require.NoError(t, err)
+
+			options := &SpillOptions{MemoryBudget: tt.budget, TempDir: dir, MaxOpenFiles: tt.maxOpen}
+			require.NoError(t, Sort(reader, Columns{"id"}, writer, options))
+			assert.Equal(t, exp, buf.String())
+
+			files, err := ioutil.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Empty(t, files)
+		})
+	}
+}
+
+// TestDedupe verifies the first record read with each key is kept however far apart duplicates are
+func TestDedupe(t *testing.T) {
+
+	input := "id,name\n2,b\n1,a\n2,b\n3,c\n1,z\n2,b\n"
+
+	var testCases = []struct {
+		name       string
+		key        Columns
+		budget     int
+		exp        string
+		expDropped int
+	}{
+		{name: "whole records", exp: "id,name\n1,a\n1,z\n2,b\n3,c\n", expDropped: 2},
+		{name: "key", key: Columns{"id"}, exp: "id,name\n1,a\n2,b\n3,c\n", expDropped: 3},
+		{name: "key spilled", key: Columns{"id"}, budget: 1, exp: "id,name\n1,a\n2,b\n3,c\n", expDropped: 3},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader(input), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			writer, err := NewWriter(&buf, &WriterOptions{ColumnNames: reader.Columns(), WriteHeaders: true})
+			require.NoError(t, err)
+
+			dropped, err := Dedupe(reader, tt.key, writer, &SpillOptions{MemoryBudget: tt.budget, TempDir: t.TempDir()})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expDropped, dropped)
+			assert.Equal(t, tt.exp, buf.String())
+		})
+	}
+}
+
+// TestSort_Errors verifies invalid keys, writers, and options are rejected
+func TestSort_Errors(t *testing.T) {
+
+	var testCases = []struct {
+		name    string
+		key     Columns
+		columns []string
+		options *SpillOptions
+		expErr  string
+	}{
+		{name: "no key", columns: []string{"id"}, expErr: "at least one key column is required"},
+		{name: "missing key", key: Columns{"x"}, columns: []string{"id"}, expErr: `column "x" not found`},
+		{name: "writer columns", key: Columns{"id"}, columns: []string{"other"}, expErr: "the writer's columns must be the reader's columns"},
+		{name: "negative budget", key: Columns{"id"}, columns: []string{"id"}, options: &SpillOptions{MemoryBudget: -1}, expErr: "memory budget must not be negative, got -1"},
+		{name: "one open file", key: Columns{"id"}, columns: []string{"id"}, options: &SpillOptions{MaxOpenFiles: 1}, expErr: "max open files must be at least 2, got 1"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader("id\n1\n"), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+
+			writer, err := NewWriter(&bytes.Buffer{}, &WriterOptions{ColumnNames: tt.columns})
+			require.NoError(t, err)
+
+			assert.EqualError(t, Sort(reader, tt.key, writer, tt.options), tt.expErr)
+		})
+	}
+}
+
+// TestSpiller_MergePasses verifies runs are merged a limited number at a time, keeping records with
+// equal keys in the order they were added
+func TestSpiller_MergePasses(t *testing.T) {
+
+	s, err := newSpiller(&SpillOptions{MemoryBudget: 1, TempDir: t.TempDir(), MaxOpenFiles: 3}, func(a, b []string) bool {
+		return a[0] < b[0]
+	})
+	require.NoError(t, err)
+	defer s.close()
+
+	var exp [][]string
+	for i := 0; i < 20; i++ {
+		require.NoError(t, s.add([]string{string(rune('a' + i%4)), strconv.Itoa(i)}))
+	}
+	for k := 0; k < 4; k++ {
+		for i := k; i < 20; i += 4 {
+			exp = append(exp, []string{string(rune('a' + k)), strconv.Itoa(i)})
+		}
+	}
+	assert.Len(t, s.runs, 20)
+
+	next, err := s.sorted()
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(s.runs), 3)
+	assert.LessOrEqual(t, len(s.open), 3)
+
+	var actual [][]string
+	for {
+		record, err := next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		actual = append(actual, record)
+	}
+	assert.Equal(t, exp, actual)
+}
+
+// TestJoin verifies records of two inputs are paired by key whether they are held in memory or
+// spilled to disk, and that spill files are removed
+func TestJoin(t *testing.T) {
+
+	left := "id,name\n2,b\n1,a\n3,c\n1,a2\n"
+	right := "region,id\nx,1\ny,2\nz,1\nw,4\n"
+	exp := "id,name,region\n1,a,x\n1,a,z\n1,a2,x\n1,a2,z\n2,b,y\n"
+
+	var testCases = []struct {
+		name    string
+		budget  int
+		maxOpen int
+	}{
+		{name: "in memory", budget: 0},
+		{name: "spill every record", budget: 1},
+		{name: "merge in passes", budget: 1, maxOpen: 2},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			dir := t.TempDir()
+
+			leftReader, err := NewReader(strings.NewReader(left), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+			rightReader, err := NewReader(strings.NewReader(right), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			writer, err := NewWriter(&buf, &WriterOptions{ColumnNames: []string{"id", "name", "region"}, WriteHeaders: true})
+			require.NoError(t, err)
+
+			options := &SpillOptions{MemoryBudget: tt.budget, TempDir: dir, MaxOpenFiles: tt.maxOpen}
+			require.NoError(t, Join(leftReader, rightReader, Columns{"id"}, writer, options))
+			assert.Equal(t, exp, buf.String())
+
+			files, err := ioutil.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Empty(t, files)
+		})
+	}
+}
+
+// TestJoin_Errors verifies invalid keys and writers are rejected
+func TestJoin_Errors(t *testing.T) {
+
+	var testCases = []struct {
+		name    string
+		key     Columns
+		columns []string
+		expErr  string
+	}{
+		{name: "no key", columns: []string{"id", "name", "region"}, expErr: "at least one key column is required"},
+		{name: "missing key", key: Columns{"name"}, columns: []string{"id", "name", "region"}, expErr: `column "name" not found`},
+		{
+			name:    "writer columns",
+			key:     Columns{"id"},
+			columns: []string{"id", "region"},
+			expErr:  "the writer's columns must be the left reader's columns followed by the right reader's other columns",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			left, err := NewReader(strings.NewReader("id,name\n1,a\n"), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+			right, err := NewReader(strings.NewReader("region,id\nx,1\n"), &ReaderOptions{ReadHeaders: true})
+			require.NoError(t, err)
+
+			writer, err := NewWriter(&bytes.Buffer{}, &WriterOptions{ColumnNames: tt.columns})
+			require.NoError(t, err)
+
+			assert.EqualError(t, Join(left, right, tt.key, writer, nil), tt.expErr)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package csvee
+
+import (
+	"math"
+	"reflect"
+	"sort"
+)
+
+const (
+	// suggestMinConfidence is the lowest similarity SuggestMapping will propose a mapping for.
+	suggestMinConfidence = 0.5
+
+	// suggestAmbiguityMargin is how close the two best candidates for a column must be for the column
+	// to be reported as ambiguous rather than mapped.
+	suggestAmbiguityMargin = 0.05
+)
+
+// ColumnMapping proposes the field a column should be decoded into.
+type ColumnMapping struct {
+	// Column is the header as it appears in the file.
+	Column string
+
+	// Field is the name of the struct field the column maps to, or empty if it maps to none.
+	Field string
+
+	// Name is the field name, tag name, or alias the column matched. Renaming the column to Name
+	// binds it to Field.
+	Name string
+
+	// Confidence is the similarity, from 0 to 1, between Column and Name.
+	Confidence float64
+}
+
+// Mapping is a proposed column to field mapping in header order.
+type Mapping []ColumnMapping
+
+// ColumnNames returns the column names that bind each column to its proposed field, suitable for
+// Reader.SetColumns or ReaderOptions.ColumnNames. Unmapped columns keep their header.
+func (m Mapping) ColumnNames() []string {
+
+	columnNames := make([]string, len(m))
+	for i, cm := range m {
+		columnNames[i] = cm.Column
+		if cm.Field != "" {
+			columnNames[i] = cm.Name
+		}
+	}
+
+	return columnNames
+}
+
+// Ambiguity is a column that could reasonably map to more than one field.
+type Ambiguity struct {
+	Column     string
+	Candidates []ColumnMapping
+}
+
+// SuggestMapping proposes a mapping from headers to the fields of structType, with a confidence score
+// for each, so that tools can ask users to confirm it before importing. Columns whose best candidates
+// are too close to call are left unmapped and reported as ambiguities. Each field is proposed for at
+// most one column, preferring the column with the highest confidence. If structType is nil or is not
+// a struct or a pointer to one, every column is left unmapped.
+func SuggestMapping(headers []string, structType reflect.Type) (Mapping, []Ambiguity) {
+
+	mapping := make(Mapping, len(headers))
+	for i, header := range headers {
+		mapping[i] = ColumnMapping{Column: header}
+	}
+
+	if structType == nil {
+		return mapping, nil
+	}
+
+	structType = getBaseType(structType)
+	if structType.Kind() != reflect.Struct {
+		return mapping, nil
+	}
+
+	type proposal struct {
+		header    int
+		candidate fieldCandidate
+		score     float64
+	}
+
+	candidates := fieldCandidates(structType, nil)
+
+	var proposals []proposal
+	var ambiguities []Ambiguity
+	for i, header := range headers {
+
+		scored := make([]proposal, 0, len(candidates))
+		for _, candidate := range candidates {
+			scored = append(scored, proposal{header: i, candidate: candidate, score: nameSimilarity(header, candidate.name)})
+		}
+		sort.SliceStable(scored, func(a, b int) bool { return scored[a].score > scored[b].score })
+
+		if len(scored) == 0 || scored[0].score < suggestMinConfidence {
+			continue
+		}
+
+		// Candidates close to the best that map to a different field make the column ambiguous.
+		var rivals []ColumnMapping
+		for _, p := range scored[1:] {
+			if scored[0].score-p.score > suggestAmbiguityMargin {
+				break
+			}
+			if fieldIndexKey(p.candidate.field) != fieldIndexKey(scored[0].candidate.field) {
+				rivals = append(rivals, toColumnMapping(header, p.candidate, p.score))
+			}
+		}
+
+		if len(rivals) > 0 && scored[0].score < 1 {
+			ambiguities = append(ambiguities, Ambiguity{
+				Column:     header,
+				Candidates: append([]ColumnMapping{toColumnMapping(header, scored[0].candidate, scored[0].score)}, rivals...),
+			})
+			continue
+		}
+
+		proposals = append(proposals, scored[0])
+	}
+
+	sort.SliceStable(proposals, func(a, b int) bool { return proposals[a].score > proposals[b].score })
+
+	claimed := make(map[string]bool)
+	for _, p := range proposals {
+		key := fieldIndexKey(p.candidate.field)
+		if claimed[key] {
+			continue
+		}
+		claimed[key] = true
+		mapping[p.header] = toColumnMapping(headers[p.header], p.candidate, p.score)
+	}
+
+	return mapping, ambiguities
+}
+
+func toColumnMapping(header string, candidate fieldCandidate, score float64) ColumnMapping {
+
+	return ColumnMapping{
+		Column:     header,
+		Field:      candidate.field.Name,
+		Name:       candidate.name,
+		Confidence: math.Round(score*1000) / 1000,
+	}
+}
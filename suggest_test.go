@@ -0,0 +1,71 @@
+package csvee
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type suggestReadTo struct {
+	FirstName string
+	LastName  string
+	Email     string `csvee:"email,alias=e-mail"`
+	Phone     string
+}
+
+// TestSuggestMapping verifies mappings are proposed with confidence and ambiguous columns are reported
+func TestSuggestMapping(t *testing.T) {
+
+	headers := []string{"first name", "E-Mail", "Nmae", "phone", "zzz"}
+
+	mapping, ambiguities := SuggestMapping(headers, reflect.TypeOf(suggestReadTo{}))
+
+	assert.Equal(t, Mapping{
+		{Column: "first name", Field: "FirstName", Name: "FirstName", Confidence: 1},
+		{Column: "E-Mail", Field: "Email", Name: "email", Confidence: 1},
+		{Column: "Nmae"},
+		{Column: "phone", Field: "Phone", Name: "Phone", Confidence: 1},
+		{Column: "zzz"},
+	}, mapping)
+	assert.Equal(t, []string{"FirstName", "email", "Nmae", "Phone", "zzz"}, mapping.ColumnNames())
+
+	assert.Empty(t, ambiguities)
+
+	type codes struct {
+		Code1 string
+		Code2 string
+	}
+
+	mapping, ambiguities = SuggestMapping([]string{"Code"}, reflect.TypeOf(codes{}))
+	assert.Equal(t, Mapping{{Column: "Code"}}, mapping)
+	assert.Equal(t, []Ambiguity{{
+		Column: "Code",
+		Candidates: []ColumnMapping{
+			{Column: "Code", Field: "Code1", Name: "Code1", Confidence: 0.8},
+			{Column: "Code", Field: "Code2", Name: "Code2", Confidence: 0.8},
+		},
+	}}, ambiguities)
+}
+
+// TestSuggestMapping_NotStruct verifies every column is left unmapped when there is no struct type
+func TestSuggestMapping_NotStruct(t *testing.T) {
+
+	var testCases = []struct {
+		name   string
+		inType reflect.Type
+	}{
+		{name: "nil"},
+		{name: "int", inType: reflect.TypeOf(0)},
+		{name: "pointer to map", inType: reflect.TypeOf(&map[string]string{})},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			mapping, ambiguities := SuggestMapping([]string{"FirstName", "Email"}, tt.inType)
+			assert.Equal(t, Mapping{{Column: "FirstName"}, {Column: "Email"}}, mapping)
+			assert.Empty(t, ambiguities)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package csvee
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RowTimeoutError reports a row that took longer than ReaderOptions.RowTimeout to decode. It wraps
+// ErrRowTimeout.
+type RowTimeoutError struct {
+	Row     int
+	Timeout time.Duration
+}
+
+func (e *RowTimeoutError) Error() string {
+
+	return fmt.Sprintf("row %d: decoding took longer than %v", e.Row, e.Timeout)
+}
+
+// Unwrap returns ErrRowTimeout.
+func (e *RowTimeoutError) Unwrap() error {
+
+	return ErrRowTimeout
+}
+
+// maxAbandonedRows is the number of timed out rows a Reader lets keep decoding in the background
+// before failing further rows without decoding them, bounding the goroutines that decodings which
+// never finish can leak.
+const maxAbandonedRows = 8
+
+// The states of a decoding started by decodeWithin.
+const (
+	decodeRunning int32 = iota
+	decodeFinished
+	decodeAbandoned
+)
+
+// decodeWithin decodes row into v on another goroutine, giving up once timeout has passed. The
+// goroutine decodes into a copy of v that shares no pointers, slices, or maps with it, using a copy
+// of the reader with its own record, warnings, and traces, so that if it is abandoned it cannot
+// change either; v and the reader are only updated once it succeeds in time.
+func (r *Reader) decodeWithin(row row, v interface{}, timeout time.Duration) error {
+
+	if r.abandonedRows == nil {
+		r.abandonedRows = new(int32)
+	}
+	if atomic.LoadInt32(r.abandonedRows) >= maxAbandonedRows {
+		return errors.Wrapf(ErrTooManyAbandonedRows, "row %d", r.rowsRead)
+	}
+
+	// Find the struct or map that decoding sets, or the nil pointer it allocates, and copy it.
+	target := reflect.ValueOf(v).Elem()
+	for target.Kind() == reflect.Ptr && !target.IsNil() {
+		target = target.Elem()
+	}
+	clone := reflect.New(target.Type())
+	clone.Elem().Set(target)
+	unshare(clone.Elem(), make(map[sharedPointer]reflect.Value))
+
+	// The csv.Reader may reuse the record's storage once it is abandoned.
+	row.record = append([]string(nil), row.record...)
+
+	// Build the lazily computed settings before copying the reader, so the copy only reads them.
+	r.settings()
+	r.LastRecord()
+	worker := *r
+	worker.lastRecord = row.record
+	worker.warnings, worker.onWarning = nil, nil
+	worker.leadingZeroColumns = make(map[string]bool, len(r.leadingZeroColumns))
+	for column := range r.leadingZeroColumns {
+		worker.leadingZeroColumns[column] = true
+	}
+
+	var traces []RowTrace
+	worker.traceWriter = nil
+	if r.onTrace != nil || r.traceWriter != nil {
+		worker.onTrace = func(trace RowTrace) { traces = append(traces, trace) }
+	}
+
+	done := make(chan error, 1)
+	state := decodeRunning
+	go func() {
+		done <- worker.decodeRow(row, clone.Interface())
+		if !atomic.CompareAndSwapInt32(&state, decodeRunning, decodeFinished) {
+			atomic.AddInt32(worker.abandonedRows, -1)
+		}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-timer.C:
+		if atomic.CompareAndSwapInt32(&state, decodeRunning, decodeAbandoned) {
+			atomic.AddInt32(r.abandonedRows, 1)
+			return &RowTimeoutError{Row: r.rowsRead, Timeout: timeout}
+		}
+		// The decoding finished as the timer fired.
+		err = <-done
+	}
+
+	for _, trace := range traces {
+		r.emitTrace(trace)
+	}
+	if err != nil {
+		return err
+	}
+
+	target.Set(clone.Elem())
+	r.leadingZeroColumns = worker.leadingZeroColumns
+	for _, w := range worker.warnings {
+		r.warnings = append(r.warnings, w)
+		if r.onWarning != nil {
+			r.onWarning(w)
+		}
+	}
+
+	return nil
+}
+
+// sharedPointer identifies memory reached through a pointer, so that unshare copies it only once.
+type sharedPointer struct {
+	p uintptr
+	t reflect.Type
+}
+
+// unshare replaces the pointers, slices, and maps that v, a shallow copy of some value, reaches
+// through its exported fields with copies of what they refer to, so that setting fields through v
+// does not change the original. Unexported fields, which decoding does not set, are left shared.
+func unshare(v reflect.Value, copies map[sharedPointer]reflect.Value) {
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		key := sharedPointer{p: v.Pointer(), t: v.Type()}
+		if c, exists := copies[key]; exists {
+			v.Set(c)
+			return
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(v.Elem())
+		copies[key] = c
+		unshare(c.Elem(), copies)
+		v.Set(c)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			// Exported fields of embedded unexported structs are settable, so those are followed too.
+			if field := v.Type().Field(i); field.IsExported() || field.Anonymous {
+				unshare(v.Field(i), copies)
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			unshare(v.Index(i), copies)
+		}
+	case reflect.Slice:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Cap())
+		reflect.Copy(c, v)
+		for i := 0; i < c.Len(); i++ {
+			unshare(c.Index(i), copies)
+		}
+		v.Set(c)
+	case reflect.Map:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(v.Type().Elem()).Elem()
+			value.Set(iter.Value())
+			unshare(value, copies)
+			c.SetMapIndex(iter.Key(), value)
+		}
+		v.Set(c)
+	}
+}
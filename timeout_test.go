@@ -0,0 +1,180 @@
+package csvee
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type timeoutRow struct {
+	Name  string
+	Code  string
+	Count int
+}
+
+// slowConverter returns a converter that takes longer than any test's row timeout for "slow" cells.
+func slowConverter(release <-chan struct{}) Converter {
+
+	return func(s string) (interface{}, error) {
+		if s == "slow" {
+			<-release
+		}
+		return strings.ToUpper(s), nil
+	}
+}
+
+// TestReader_RowTimeout verifies rows that take too long to decode fail with their row number
+func TestReader_RowTimeout(t *testing.T) {
+
+	release := make(chan struct{})
+	defer close(release)
+
+	input := "Name,Code,Count\nann,a,1\nbob,slow,2\ncat,c,3\n"
+	reader, err := NewReader(strings.NewReader(input), &ReaderOptions{
+		ReadHeaders:      true,
+		RowTimeout:       20 * time.Millisecond,
+		ColumnConverters: map[string]Converter{"Code": slowConverter(release)},
+	})
+	require.NoError(t, err)
+
+	var rows []timeoutRow
+	err = reader.ReadAll(&rows)
+	require.Error(t, err)
+	assert.Equal(t, "row 2: decoding took longer than 20ms", err.Error())
+	assert.True(t, errors.Is(err, ErrRowTimeout))
+
+	var timeoutErr *RowTimeoutError
+	require.True(t, errors.As(err, &timeoutErr))
+	assert.Equal(t, 2, timeoutErr.Row)
+
+	// The abandoned row does not change the target.
+	var row timeoutRow
+	require.NoError(t, reader.Read(&row))
+	assert.Equal(t, timeoutRow{Name: "cat", Code: "C", Count: 3}, row)
+}
+
+// TestReader_RowTimeout_Decodes verifies rows decoded within the timeout are decoded as they are
+// without one
+func TestReader_RowTimeout_Decodes(t *testing.T) {
+
+	var warnings []Warning
+	input := "Name,Code,Count\nann,a,01\n"
+	reader, err := NewReader(strings.NewReader(input), &ReaderOptions{
+		ReadHeaders:        true,
+		RowTimeout:         time.Second,
+		Merge:              true,
+		DetectLeadingZeros: true,
+		OnWarning:          func(w Warning) { warnings = append(warnings, w) },
+	})
+	require.NoError(t, err)
+
+	row := &timeoutRow{Name: "old"}
+	require.NoError(t, reader.Read(&row))
+	assert.Equal(t, &timeoutRow{Name: "ann", Code: "a", Count: 1}, row)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, reader.Warnings(), warnings)
+	assert.Equal(t, "Count", warnings[0].Column)
+}
+
+// TestReader_RowTimeout_Maps verifies map targets are only updated once decoded in time
+func TestReader_RowTimeout_Maps(t *testing.T) {
+
+	release := make(chan struct{})
+	defer close(release)
+
+	input := "Name,Code\nann,a\nbob,slow\n"
+	reader, err := NewReader(strings.NewReader(input), &ReaderOptions{
+		ReadHeaders:      true,
+		RowTimeout:       20 * time.Millisecond,
+		ColumnConverters: map[string]Converter{"Code": slowConverter(release)},
+	})
+	require.NoError(t, err)
+
+	row := map[string]interface{}{"Other": 1}
+	require.NoError(t, reader.Read(&row))
+	assert.Equal(t, map[string]interface{}{"Other": 1, "Name": "ann", "Code": "A"}, row)
+
+	err = reader.Read(&row)
+	assert.True(t, errors.Is(err, ErrRowTimeout))
+	assert.Equal(t, map[string]interface{}{"Other": 1, "Name": "ann", "Code": "A"}, row)
+}
+
+type timeoutPointerRow struct {
+	Code  string
+	Count *int
+	Tags  []string
+	Done  string
+}
+
+// TestReader_RowTimeout_Abandoned verifies an abandoned row does not write through the target's
+// pointers or slices once it resumes
+func TestReader_RowTimeout_Abandoned(t *testing.T) {
+
+	release := make(chan struct{})
+	finished := make(chan struct{})
+
+	input := "Code,Count,Tags,Done\nslow,5,\"x,y\",done\n"
+	reader, err := NewReader(strings.NewReader(input), &ReaderOptions{
+		ReadHeaders: true,
+		RowTimeout:  20 * time.Millisecond,
+		Merge:       true,
+		ColumnConverters: map[string]Converter{
+			"Code": slowConverter(release),
+			"Done": func(s string) (interface{}, error) {
+				close(finished)
+				return s, nil
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	count := 7
+	tags := []string{"a", "b"}
+	row := timeoutPointerRow{Count: &count, Tags: tags}
+
+	err = reader.Read(&row)
+	assert.True(t, errors.Is(err, ErrRowTimeout))
+
+	close(release)
+	<-finished
+
+	assert.Equal(t, 7, count)
+	assert.Equal(t, []string{"a", "b"}, tags)
+	assert.Equal(t, timeoutPointerRow{Count: &count, Tags: tags}, row)
+}
+
+// TestReader_RowTimeout_AbandonedLimit verifies rows fail without being decoded while too many timed
+// out rows are still decoding, and are decoded again once those finish
+func TestReader_RowTimeout_AbandonedLimit(t *testing.T) {
+
+	release := make(chan struct{})
+
+	input := "Name,Code,Count\n" + strings.Repeat("bob,slow,2\n", maxAbandonedRows+1) + "cat,c,3\n"
+	reader, err := NewReader(strings.NewReader(input), &ReaderOptions{
+		ReadHeaders:      true,
+		RowTimeout:       5 * time.Millisecond,
+		ColumnConverters: map[string]Converter{"Code": slowConverter(release)},
+	})
+	require.NoError(t, err)
+
+	var row timeoutRow
+	for i := 0; i < maxAbandonedRows; i++ {
+		var timeoutErr *RowTimeoutError
+		require.True(t, errors.As(reader.Read(&row), &timeoutErr))
+	}
+
+	err = reader.Read(&row)
+	assert.True(t, errors.Is(err, ErrTooManyAbandonedRows))
+	assert.Equal(t, "row 9: "+ErrTooManyAbandonedRows.Error(), err.Error())
+
+	close(release)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(reader.abandonedRows) == 0 }, time.Second, time.Millisecond)
+
+	require.NoError(t, reader.Read(&row))
+	assert.Equal(t, timeoutRow{Name: "cat", Code: "C", Count: 3}, row)
+}
@@ -0,0 +1,182 @@
+package csvee
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// TraceDecision is what decoding a row did with one of its cells.
+type TraceDecision string
+
+const (
+	// TraceSet means the cell was parsed and its field set.
+	TraceSet TraceDecision = "set"
+
+	// TraceBlank means the cell was blank, which leaves fields other than strings and times
+	// untouched.
+	TraceBlank TraceDecision = "blank"
+
+	// TraceNull means the cell held a null value, which zeroes its field, or leaves it untouched
+	// when merging.
+	TraceNull TraceDecision = "null"
+
+	// TraceSkipped means the cell was skipped, leaving its field untouched, by the column's
+	// whitespace policy or because it was empty when merging.
+	TraceSkipped TraceDecision = "skipped"
+
+	// TraceNoElement means the cell was blank or null and bound to an element of a slice or array of
+	// structs that no other cell of the row created.
+	TraceNoElement TraceDecision = "no element"
+
+	// TraceCollected means the cell was collected, along with the other cells of its repeated
+	// column, into a slice or array field.
+	TraceCollected TraceDecision = "collected"
+
+	// TraceUnmapped means no field is bound to the cell's column.
+	TraceUnmapped TraceDecision = "unmapped"
+
+	// TraceFailed means the cell could not be decoded into its field.
+	TraceFailed TraceDecision = "failed"
+)
+
+// CellTrace describes how a cell was decoded.
+type CellTrace struct {
+	// Column and Value are the name and raw contents of the cell.
+	Column string
+	Value  string
+
+	// Field is the path of Go field names the cell was bound to, such as "Billing.Street", or empty
+	// if it is unmapped.
+	Field string
+
+	Decision TraceDecision
+
+	// Result is the value of the field after the cell was decoded, if it was set or nulled.
+	Result interface{}
+
+	// Err is the error the cell failed with.
+	Err error
+}
+
+func (c CellTrace) String() string {
+
+	if c.Decision == TraceUnmapped {
+		return fmt.Sprintf("%q = %q: %s", c.Column, c.Value, c.Decision)
+	}
+
+	switch c.Decision {
+	case TraceSet, TraceNull, TraceCollected:
+		return fmt.Sprintf("%q = %q -> %s: %s, now %#v", c.Column, c.Value, c.Field, c.Decision, c.Result)
+	case TraceFailed:
+		return fmt.Sprintf("%q = %q -> %s: %s: %v", c.Column, c.Value, c.Field, c.Decision, c.Err)
+	}
+
+	return fmt.Sprintf("%q = %q -> %s: %s", c.Column, c.Value, c.Field, c.Decision)
+}
+
+// RowTrace describes how a row was decoded into a struct, cell by cell, so that it can be seen why a
+// field ended up with the value it did.
+type RowTrace struct {
+	// Row is the 1-based number of the record, excluding headers.
+	Row int
+
+	// Target is the name of the struct type the row was decoded into.
+	Target string
+
+	// Cells holds a trace of each cell, in the order they were decoded, followed by any unmapped
+	// cells in column order. Decoding stops at the first cell that fails.
+	Cells []CellTrace
+}
+
+func (t RowTrace) String() string {
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "row %d into %s:", t.Row, t.Target)
+	for _, cell := range t.Cells {
+		b.WriteString("\n  ")
+		b.WriteString(cell.String())
+	}
+
+	return b.String()
+}
+
+// rowTracer records the decisions made decoding a row, for a RowTrace. Its methods do nothing on a
+// nil tracer, which decoding uses when it is not traced.
+type rowTracer struct {
+	trace RowTrace
+}
+
+// startTrace returns a tracer for the row being decoded into the struct pointed to by structPtr, or
+// nil if the reader does not trace rows.
+func (r *Reader) startTrace(structPtr reflect.Value) *rowTracer {
+
+	if r.onTrace == nil && r.traceWriter == nil {
+		return nil
+	}
+
+	return &rowTracer{trace: RowTrace{Row: r.rowsRead, Target: structPtr.Elem().Type().String()}}
+}
+
+// cell records the decision made for col's cell, which was decoded into the struct pointed to by
+// target.
+func (t *rowTracer) cell(col columnPlan, record []string, target reflect.Value, decision TraceDecision, err error) {
+
+	if t == nil {
+		return
+	}
+
+	cell := CellTrace{Column: col.name, Value: record[col.column], Field: col.field.Name, Decision: decision, Err: err}
+
+	if decision == TraceSet || decision == TraceNull || decision == TraceCollected {
+		if v, exists := fieldByIndex(target.Elem(), col.field.Index); exists {
+			cell.Result = v.Interface()
+		}
+	}
+
+	t.trace.Cells = append(t.trace.Cells, cell)
+}
+
+// finish adds the row's unmapped cells to the trace and passes it to the reader's trace callback and
+// writer.
+func (t *rowTracer) finish(r *Reader, plan *decodePlan, record []string) {
+
+	if t == nil {
+		return
+	}
+
+	mapped := make([]bool, len(record))
+	for _, col := range plan.columns {
+		mapped[col.column] = true
+		for _, j := range col.repeats {
+			mapped[j] = true
+		}
+	}
+	for j := range record {
+		if !mapped[j] && j < len(r.ColumnNames) {
+			t.trace.Cells = append(t.trace.Cells, CellTrace{Column: r.ColumnNames[j], Value: record[j], Decision: TraceUnmapped})
+		}
+	}
+
+	r.emitTrace(t.trace)
+}
+
+// emitTrace passes trace to the reader's trace callback and writer.
+func (r *Reader) emitTrace(trace RowTrace) {
+
+	if r.onTrace != nil {
+		r.onTrace(trace)
+	}
+	if r.traceWriter != nil {
+		_, _ = io.WriteString(r.traceWriter, trace.String()+"\n")
+	}
+}
+
+// blankLeavesField reports whether field is blank and decoding it leaves col's field untouched, as it
+// does for scalar fields other than strings and times.
+func blankLeavesField(col columnPlan, field string) bool {
+
+	return col.sliceType == nil && col.fieldType.Kind() != reflect.String && !isTimeType(col.fieldType) &&
+		strings.TrimSpace(field) == ""
+}
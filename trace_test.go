@@ -0,0 +1,80 @@
+package csvee
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tracedRow struct {
+	Name  string
+	Qty   int
+	Score *float64
+}
+
+// TestReader_Trace verifies each decoded row is traced cell by cell
+func TestReader_Trace(t *testing.T) {
+
+	const data = "Name,Qty,Score,Extra\na,1,2.5,x\nb,,,y\nc,z,1,\n"
+
+	var traces []RowTrace
+	var out bytes.Buffer
+	reader, err := NewReader(strings.NewReader(data), &ReaderOptions{
+		ReadHeaders: true,
+		OnTrace:     func(trace RowTrace) { traces = append(traces, trace) },
+		TraceWriter: &out,
+	})
+	require.NoError(t, err)
+
+	var rows []tracedRow
+	require.Error(t, reader.ReadAll(&rows))
+	require.Len(t, traces, 3)
+
+	score := 2.5
+	assert.Equal(t, RowTrace{Row: 1, Target: "csvee.tracedRow", Cells: []CellTrace{
+		{Column: "Name", Value: "a", Field: "Name", Decision: TraceSet, Result: "a"},
+		{Column: "Qty", Value: "1", Field: "Qty", Decision: TraceSet, Result: 1},
+		{Column: "Score", Value: "2.5", Field: "Score", Decision: TraceSet, Result: &score},
+		{Column: "Extra", Value: "x", Decision: TraceUnmapped},
+	}}, traces[0])
+
+	assert.Equal(t, []TraceDecision{TraceSet, TraceBlank, TraceBlank, TraceUnmapped}, traceDecisions(traces[1]))
+
+	assert.Equal(t, []TraceDecision{TraceSet, TraceFailed, TraceUnmapped}, traceDecisions(traces[2]))
+	assert.EqualError(t, traces[2].Cells[1].Err, `row 3, column "Qty": invalid value "z" for int`)
+
+	lines := strings.Split(out.String(), "\n")
+	assert.Equal(t, []string{
+		"row 2 into csvee.tracedRow:",
+		`  "Name" = "b" -> Name: set, now "b"`,
+		`  "Qty" = "" -> Qty: blank`,
+		`  "Score" = "" -> Score: blank`,
+		`  "Extra" = "y": unmapped`,
+	}, lines[5:10])
+
+	traces = nil
+	reader, err = NewReader(strings.NewReader("Name,Qty,Score\na,NULL,NULL\n"), &ReaderOptions{
+		ReadHeaders: true,
+		NullValues:  []string{"NULL"},
+		OnTrace:     func(trace RowTrace) { traces = append(traces, trace) },
+	})
+	require.NoError(t, err)
+	require.NoError(t, reader.ReadAll(&rows))
+
+	require.Len(t, traces, 1)
+	assert.Equal(t, []TraceDecision{TraceSet, TraceNull, TraceNull}, traceDecisions(traces[0]))
+	assert.Equal(t, `"Score" = "NULL" -> Score: null, now (*float64)(nil)`, traces[0].Cells[2].String())
+}
+
+func traceDecisions(trace RowTrace) []TraceDecision {
+
+	decisions := make([]TraceDecision, len(trace.Cells))
+	for i, cell := range trace.Cells {
+		decisions[i] = cell.Decision
+	}
+
+	return decisions
+}
@@ -0,0 +1,68 @@
+package csvee
+
+import (
+	"io"
+	"reflect"
+)
+
+// TypedReader reads records into values of type T, which must be a struct or a pointer to one, or a
+// map as Reader.Read accepts, so that callers neither pass pointers nor assert types. The column to field mapping for T is
+// computed when the reader is created.
+type TypedReader[T any] struct {
+	reader *Reader
+}
+
+// NewTypedReader returns a new TypedReader that reads from r. It accepts the same options as
+// NewReader and also fails if T is not a struct, a pointer to one, or a supported map, or has a
+// field of an unsupported type.
+func NewTypedReader[T any](r io.Reader, options ...*ReaderOptions) (*TypedReader[T], error) {
+
+	reader, err := NewReader(r, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	vType := getBaseType(reflect.TypeOf((*T)(nil)).Elem())
+	if isMapTarget(vType) {
+		return &TypedReader[T]{reader: reader}, nil
+	}
+	if vType.Kind() != reflect.Struct {
+		return nil, ErrUnsupportedTargetType
+	}
+
+	if _, err := reader.planFor(vType); err != nil {
+		return nil, err
+	}
+
+	return &TypedReader[T]{reader: reader}, nil
+}
+
+// Reader returns the underlying Reader, for its accessors and untyped methods.
+func (tr *TypedReader[T]) Reader() *Reader {
+
+	return tr.reader
+}
+
+// Read reads the next record into a new T. It returns io.EOF when there are no more records.
+func (tr *TypedReader[T]) Read() (T, error) {
+
+	var v T
+	if err := tr.reader.Read(&v); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return v, nil
+}
+
+// ReadAll reads the remaining records into a slice of T. If an error occurs, the records decoded
+// before it are returned along with it.
+func (tr *TypedReader[T]) ReadAll() ([]T, error) {
+
+	var values []T
+	if err := tr.reader.ReadAll(&values); err != nil {
+		return values, err
+	}
+
+	return values, nil
+}
@@ -0,0 +1,67 @@
+package csvee
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typedRow struct {
+	Name string
+	Qty  int
+}
+
+// TestTypedReader verifies records are read into values of the reader's type
+func TestTypedReader(t *testing.T) {
+
+	const data = "Name,Qty\na,1\nb,2\nc,3\n"
+
+	reader, err := NewTypedReader[typedRow](strings.NewReader(data), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	row, err := reader.Read()
+	require.NoError(t, err)
+	assert.Equal(t, typedRow{Name: "a", Qty: 1}, row)
+
+	rows, err := reader.ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, []typedRow{{Name: "b", Qty: 2}, {Name: "c", Qty: 3}}, rows)
+
+	_, err = reader.Read()
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, []string{"Name", "Qty"}, reader.Reader().Columns())
+
+	pointers, err := NewTypedReader[*typedRow](strings.NewReader(data), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	ptr, err := pointers.Read()
+	require.NoError(t, err)
+	assert.Equal(t, &typedRow{Name: "a", Qty: 1}, ptr)
+
+	ptrs, err := pointers.ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, []*typedRow{{Name: "b", Qty: 2}, {Name: "c", Qty: 3}}, ptrs)
+}
+
+// TestTypedReader_Errors verifies invalid types and rows are reported
+func TestTypedReader_Errors(t *testing.T) {
+
+	_, err := NewTypedReader[string](strings.NewReader("Name\na\n"), &ReaderOptions{ReadHeaders: true})
+	assert.Equal(t, ErrUnsupportedTargetType, err)
+
+	_, err = NewTypedReader[struct{ Name chan int }](strings.NewReader("Name\na\n"), &ReaderOptions{ReadHeaders: true})
+	assert.Equal(t, ErrInvalidFieldType, err)
+
+	_, err = NewTypedReader[typedRow](strings.NewReader("Name\na\n"))
+	assert.Equal(t, ErrReaderOptionsRequired, err)
+
+	reader, err := NewTypedReader[typedRow](strings.NewReader("Name,Qty\na,1\nb,x\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	rows, err := reader.ReadAll()
+	assert.Error(t, err)
+	assert.Equal(t, []typedRow{{Name: "a", Qty: 1}}, rows)
+}
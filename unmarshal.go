@@ -0,0 +1,66 @@
+package csvee
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// isUnmarshaler reports whether a pointer to t implements encoding.TextUnmarshaler or
+// json.Unmarshaler, in which case cells are decoded into t by calling them. Times are parsed in
+// their column's format instead.
+func isUnmarshaler(t reflect.Type) bool {
+
+	if isTimeType(t) || t.Kind() == reflect.Ptr {
+		return false
+	}
+
+	pt := reflect.PtrTo(t)
+	return pt.Implements(textUnmarshalerType) || pt.Implements(jsonUnmarshalerType)
+}
+
+// unmarshalCell decodes field into v, which must be addressable and of a type isUnmarshaler accepts.
+// TextUnmarshalers are given the cell as it is. json.Unmarshalers are given the cell if it is valid
+// JSON, and otherwise the cell as a JSON string.
+func unmarshalCell(v reflect.Value, field string) error {
+
+	ptr := v.Addr().Interface()
+
+	if u, isText := ptr.(encoding.TextUnmarshaler); isText {
+		return u.UnmarshalText([]byte(field))
+	}
+
+	data := []byte(field)
+	if !json.Valid(data) {
+		data = []byte(strconv.Quote(field))
+	}
+
+	return ptr.(json.Unmarshaler).UnmarshalJSON(data)
+}
+
+// setUnmarshaled decodes field into the column's field on the struct pointed to by structPtr by
+// calling its unmarshaler, or its elements' for slices. Blank cells leave fields other than slices
+// untouched.
+func (r *Reader) setUnmarshaled(structPtr reflect.Value, col columnPlan, field string) error {
+
+	if col.sliceType != nil {
+		return r.setField(structPtr, col, field)
+	}
+
+	if strings.TrimSpace(field) == "" {
+		return nil
+	}
+
+	if err := unmarshalCell(settableField(structPtr.Elem(), col.field.Index), field); err != nil {
+		return &FieldError{Row: r.rowsRead, Column: col.name, Value: field, Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package csvee
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type deviceID [4]byte
+
+func (id *deviceID) UnmarshalText(text []byte) error {
+
+	_, err := hex.Decode(id[:], text)
+	return err
+}
+
+type level string
+
+func (l *level) UnmarshalText(text []byte) error {
+
+	*l = level(strings.ToUpper(string(text)))
+	return nil
+}
+
+type attributes map[string]string
+
+func (a *attributes) UnmarshalJSON(data []byte) error {
+
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*a = attributes{"name": s}
+		return nil
+	}
+
+	return json.Unmarshal(data, (*map[string]string)(a))
+}
+
+type unmarshaledRow struct {
+	ID         deviceID
+	Address    *net.IP
+	Level      level
+	Attributes attributes
+}
+
+// TestReader_Unmarshalers verifies fields whose types implement encoding.TextUnmarshaler or
+// json.Unmarshaler are decoded by them
+func TestReader_Unmarshalers(t *testing.T) {
+
+	input := "ID,Address,Level,Attributes\n" +
+		"0a0b0c0d,10.0.0.1,warn,\"{\"\"os\"\":\"\"linux\"\"}\"\n" +
+		"01020304,,info,gateway\n" +
+		"zz,10.0.0.2,info,\n"
+
+	reader, err := NewReader(strings.NewReader(input), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	var row unmarshaledRow
+	require.NoError(t, reader.Read(&row))
+	address := net.ParseIP("10.0.0.1")
+	assert.Equal(t, unmarshaledRow{
+		ID:         deviceID{10, 11, 12, 13},
+		Address:    &address,
+		Level:      "WARN",
+		Attributes: attributes{"os": "linux"},
+	}, row)
+
+	row = unmarshaledRow{}
+	require.NoError(t, reader.Read(&row))
+	assert.Equal(t, unmarshaledRow{ID: deviceID{1, 2, 3, 4}, Level: "INFO", Attributes: attributes{"name": "gateway"}}, row)
+
+	err = reader.Read(&row)
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "ID", fieldErr.Column)
+	assert.Equal(t, "zz", fieldErr.Value)
+}
+
+// TestParseInto_Unmarshaler verifies ParseInto decodes types implementing encoding.TextUnmarshaler
+func TestParseInto_Unmarshaler(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		value    string
+		t        reflect.Type
+		expected interface{}
+		err      bool
+	}{
+		{name: "text", value: "debug", t: reflect.TypeOf(level("")), expected: level("DEBUG")},
+		{name: "pointer", value: "ff000001", t: reflect.TypeOf(&deviceID{}), expected: &deviceID{255, 0, 0, 1}},
+		{name: "blank pointer", value: "", t: reflect.TypeOf(&deviceID{}), expected: (*deviceID)(nil)},
+		{name: "invalid", value: "nothex", t: reflect.TypeOf(deviceID{}), err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := ParseInto(tt.value, tt.t, "")
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
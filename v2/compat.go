@@ -0,0 +1,49 @@
+package csvee
+
+// ReaderOptions holds the options of the version 1 package, github.com/deelawn/csvee, that carry
+// over to version 2, so that code can move to version 2 without rewriting its configuration first.
+// Read and ReadAll accept the same targets as in version 1.
+type ReaderOptions struct {
+	ReadHeaders   bool
+	ColumnNames   []string
+	ColumnFormats map[string]string
+
+	// Delimiter and Comment are the field delimiter and comment character. Zero values leave the
+	// defaults in place.
+	Delimiter rune
+	Comment   rune
+}
+
+// FromOptions returns an Option that applies version 1 options. As in version 1, ReadHeaders takes
+// precedence over ColumnNames. A nil o applies nothing.
+func FromOptions(o *ReaderOptions) Option {
+
+	return func(s *settings) error {
+		if o == nil {
+			return nil
+		}
+
+		options := []Option{}
+		if o.ReadHeaders {
+			options = append(options, WithHeaders())
+		} else if len(o.ColumnNames) > 0 {
+			options = append(options, WithColumns(o.ColumnNames...))
+		}
+		for column, format := range o.ColumnFormats {
+			options = append(options, WithFormat(column, Format(format)))
+		}
+		if o.Delimiter != 0 {
+			options = append(options, WithComma(o.Delimiter))
+		}
+		if o.Comment != 0 {
+			options = append(options, WithComment(o.Comment))
+		}
+
+		for _, option := range options {
+			if err := option(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
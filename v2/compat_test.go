@@ -0,0 +1,49 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFromOptions verifies version 1 options configure a reader as they did in version 1
+func TestFromOptions(t *testing.T) {
+
+	var testCases = []struct {
+		name      string
+		inData    string
+		inOptions *ReaderOptions
+		expRows   []row
+	}{
+		{
+			name:      "read headers",
+			inData:    "ID,created\n1,1613235342\n",
+			inOptions: &ReaderOptions{ReadHeaders: true, ColumnNames: []string{"ignored"}, ColumnFormats: map[string]string{"created": "unix"}},
+			expRows:   []row{{base: base{ID: 1}, Created: time.Unix(1613235342, 0).UTC()}},
+		},
+		{
+			name:      "column names",
+			inData:    "1|ann\n",
+			inOptions: &ReaderOptions{ColumnNames: []string{"ID", "name"}, Delimiter: '|'},
+			expRows:   []row{{base: base{ID: 1}, Name: "ann"}},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader(tt.inData), FromOptions(tt.inOptions))
+			require.NoError(t, err)
+
+			var actualRows []row
+			require.NoError(t, reader.ReadAll(&actualRows))
+			assert.Equal(t, tt.expRows, actualRows)
+		})
+	}
+
+	_, err := NewReader(strings.NewReader(""), FromOptions(nil))
+	assert.EqualError(t, err, "WithColumns: columns are required without WithHeaders")
+}
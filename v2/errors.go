@@ -0,0 +1,120 @@
+package csvee
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// OptionError reports an Option that cannot be used, returned by NewReader and NewWriter.
+type OptionError struct {
+	// Option names the option, such as "WithComma".
+	Option string
+
+	// Err describes what is wrong with it.
+	Err error
+}
+
+func (e *OptionError) Error() string {
+
+	return fmt.Sprintf("%s: %v", e.Option, e.Err)
+}
+
+// Unwrap returns the reason the option cannot be used.
+func (e *OptionError) Unwrap() error {
+
+	return e.Err
+}
+
+// TargetError reports a value passed to Read, ReadAll, Write, or WriteAll whose type cannot be
+// read into or written.
+type TargetError struct {
+	// Type is the type of the value, or nil if the value was nil.
+	Type reflect.Type
+
+	// Reason describes what is wrong with the type.
+	Reason string
+}
+
+func (e *TargetError) Error() string {
+
+	if e.Type == nil {
+		return "nil target: " + e.Reason
+	}
+
+	return fmt.Sprintf("target of type %s: %s", e.Type, e.Reason)
+}
+
+// ParseError reports a record that could not be read from the CSV, wrapping the *csv.ParseError or
+// I/O error that caused it.
+type ParseError struct {
+	// Row is the 1-based number of the record in the input, counting the header if there is one.
+	Row int
+
+	Err error
+}
+
+func (e *ParseError) Error() string {
+
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+// Unwrap returns the underlying parse or I/O error.
+func (e *ParseError) Unwrap() error {
+
+	return e.Err
+}
+
+// ColumnCountError reports a record whose number of fields differs from the number of columns.
+type ColumnCountError struct {
+	// Row is the 1-based number of the record in the input, counting the header if there is one.
+	Row int
+
+	Fields  int
+	Columns int
+}
+
+func (e *ColumnCountError) Error() string {
+
+	return fmt.Sprintf("row %d: %d fields for %d columns", e.Row, e.Fields, e.Columns)
+}
+
+// DecodeError reports a cell that could not be decoded into its field.
+type DecodeError struct {
+	// Row is the 1-based number of the record in the input, counting the header if there is one.
+	Row int
+
+	Column string
+	Value  string
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+
+	return fmt.Sprintf("row %d, column %q: invalid value %q: %v", e.Row, e.Column, e.Value, e.Err)
+}
+
+// Unwrap returns the error reported by the parser of the field's type.
+func (e *DecodeError) Unwrap() error {
+
+	return e.Err
+}
+
+// EncodeError reports a field that could not be written.
+type EncodeError struct {
+	// Row is the 1-based number of the record in the output, counting the header if there is one.
+	Row int
+
+	Column string
+	Err    error
+}
+
+func (e *EncodeError) Error() string {
+
+	return fmt.Sprintf("row %d, column %q: %v", e.Row, e.Column, e.Err)
+}
+
+// Unwrap returns the error reported by the field's marshaler.
+func (e *EncodeError) Unwrap() error {
+
+	return e.Err
+}
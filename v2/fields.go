@@ -0,0 +1,386 @@
+package csvee
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// field is a struct field, possibly promoted from an embedded struct, along with the column it
+// maps to and how its values are read and written.
+type field struct {
+	name  string
+	index []int
+	codec codec
+}
+
+// codec converts between cells and values of one type.
+type codec struct {
+	decode func(v reflect.Value, s string, format Format, loc *time.Location) error
+	encode func(v reflect.Value, format Format) (string, error)
+}
+
+// typeFields returns the fields of the struct type t that map to columns, in the order they are
+// declared. A field maps to the column named by its csvee tag, or else to its own name. Fields of
+// embedded structs are promoted as Go promotes them: a shallower field hides deeper ones of the same
+// column, and fields at the same depth hide each other unless exactly one of them is tagged.
+func typeFields(t reflect.Type) ([]field, error) {
+
+	type candidate struct {
+		field
+		typ    reflect.Type
+		depth  int
+		tagged bool
+	}
+
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var candidates []candidate
+	visited := make(map[reflect.Type]bool)
+	current := []embedded{{typ: t}}
+	for depth := 0; len(current) > 0; depth++ {
+
+		var next []embedded
+		for _, e := range current {
+
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i := 0; i < e.typ.NumField(); i++ {
+
+				sf := e.typ.Field(i)
+				tag := sf.Tag.Get(tagName)
+				if tag == "-" {
+					continue
+				}
+
+				index := append(append([]int(nil), e.index...), i)
+				if sf.Anonymous && tag == "" && sf.Type.Kind() == reflect.Struct && sf.Type != timeType {
+					next = append(next, embedded{typ: sf.Type, index: index})
+					continue
+				}
+				if !sf.IsExported() {
+					continue
+				}
+
+				name := tag
+				if name == "" {
+					name = sf.Name
+				}
+				candidates = append(candidates, candidate{
+					field:  field{name: name, index: index},
+					typ:    sf.Type,
+					depth:  depth,
+					tagged: tag != "",
+				})
+			}
+		}
+		current = next
+	}
+
+	byName := make(map[string][]candidate)
+	for _, c := range candidates {
+		byName[c.name] = append(byName[c.name], c)
+	}
+
+	var fields []field
+	for _, named := range byName {
+
+		// Candidates were collected in depth order, so the first ones are the shallowest.
+		shallowest := named[:1]
+		for _, c := range named[1:] {
+			if c.depth == named[0].depth {
+				shallowest = append(shallowest, c)
+			}
+		}
+
+		var dominant []candidate
+		for _, c := range shallowest {
+			if c.tagged {
+				dominant = append(dominant, c)
+			}
+		}
+		if len(dominant) == 0 {
+			dominant = shallowest
+		}
+		if len(dominant) > 1 {
+			continue
+		}
+
+		c, err := newCodec(dominant[0].typ)
+		if err != nil {
+			return nil, &TargetError{Type: t, Reason: fmt.Sprintf("field for column %q: %v", dominant[0].name, err)}
+		}
+		dominant[0].codec = c
+		fields = append(fields, dominant[0].field)
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return indexLess(fields[i].index, fields[j].index)
+	})
+
+	return fields, nil
+}
+
+// indexLess reports whether the field at index a is declared before the one at index b.
+func indexLess(a, b []int) bool {
+
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+
+	return len(a) < len(b)
+}
+
+// newCodec returns the codec for values of type t.
+func newCodec(t reflect.Type) (codec, error) {
+
+	if t == timeType {
+		return codec{decode: decodeTime, encode: encodeTime}, nil
+	}
+
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) && reflect.PtrTo(t).Implements(textMarshalerType) {
+		return codec{decode: decodeText, encode: encodeText}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return newPointerCodec(t)
+	case reflect.Slice:
+		return newSliceCodec(t)
+	case reflect.String:
+		return codec{decode: decodeString, encode: encodeString}, nil
+	case reflect.Bool:
+		return codec{decode: decodeBool, encode: encodeBool}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return codec{decode: decodeInt, encode: encodeInt}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return codec{decode: decodeUint, encode: encodeUint}, nil
+	case reflect.Float32, reflect.Float64:
+		return codec{decode: decodeFloat, encode: encodeFloat}, nil
+	}
+
+	return codec{}, fmt.Errorf("unsupported type %s", t)
+}
+
+func newPointerCodec(t reflect.Type) (codec, error) {
+
+	elem, err := newCodec(t.Elem())
+	if err != nil {
+		return codec{}, err
+	}
+
+	return codec{
+		decode: func(v reflect.Value, s string, format Format, loc *time.Location) error {
+			if strings.TrimSpace(s) == "" {
+				v.Set(reflect.Zero(t))
+				return nil
+			}
+			ptr := reflect.New(t.Elem())
+			if err := elem.decode(ptr.Elem(), s, format, loc); err != nil {
+				return err
+			}
+			v.Set(ptr)
+			return nil
+		},
+		encode: func(v reflect.Value, format Format) (string, error) {
+			if v.IsNil() {
+				return "", nil
+			}
+			return elem.encode(v.Elem(), format)
+		},
+	}, nil
+}
+
+// newSliceCodec returns the codec of a slice type, whose elements are separated by commas.
+func newSliceCodec(t reflect.Type) (codec, error) {
+
+	if t.Elem().Kind() == reflect.Slice {
+		return codec{}, fmt.Errorf("unsupported type %s", t)
+	}
+
+	elem, err := newCodec(t.Elem())
+	if err != nil {
+		return codec{}, err
+	}
+
+	return codec{
+		decode: func(v reflect.Value, s string, format Format, loc *time.Location) error {
+			if strings.TrimSpace(s) == "" {
+				v.Set(reflect.Zero(t))
+				return nil
+			}
+			parts := strings.Split(s, ",")
+			slice := reflect.MakeSlice(t, len(parts), len(parts))
+			for i, part := range parts {
+				if err := elem.decode(slice.Index(i), part, format, loc); err != nil {
+					return err
+				}
+			}
+			v.Set(slice)
+			return nil
+		},
+		encode: func(v reflect.Value, format Format) (string, error) {
+			parts := make([]string, v.Len())
+			for i := range parts {
+				part, err := elem.encode(v.Index(i), format)
+				if err != nil {
+					return "", err
+				}
+				parts[i] = part
+			}
+			return strings.Join(parts, ","), nil
+		},
+	}, nil
+}
+
+func decodeTime(v reflect.Value, s string, format Format, loc *time.Location) error {
+
+	s = strings.TrimSpace(s)
+	if s == "" {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	tm, err := format.parse(s, loc)
+	if err != nil {
+		return err
+	}
+	v.Set(reflect.ValueOf(tm))
+	return nil
+}
+
+func encodeTime(v reflect.Value, format Format) (string, error) {
+
+	tm := v.Interface().(time.Time)
+	if tm.IsZero() {
+		return "", nil
+	}
+
+	return format.format(tm), nil
+}
+
+func decodeText(v reflect.Value, s string, _ Format, _ *time.Location) error {
+
+	return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+}
+
+func encodeText(v reflect.Value, _ Format) (string, error) {
+
+	if !v.CanAddr() {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		v = ptr.Elem()
+	}
+
+	text, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+	return string(text), err
+}
+
+func decodeString(v reflect.Value, s string, _ Format, _ *time.Location) error {
+
+	v.SetString(s)
+	return nil
+}
+
+func encodeString(v reflect.Value, _ Format) (string, error) {
+
+	return v.String(), nil
+}
+
+func decodeBool(v reflect.Value, s string, _ Format, _ *time.Location) error {
+
+	if s = strings.TrimSpace(s); s == "" {
+		v.SetBool(false)
+		return nil
+	}
+
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	v.SetBool(b)
+	return nil
+}
+
+func encodeBool(v reflect.Value, _ Format) (string, error) {
+
+	return strconv.FormatBool(v.Bool()), nil
+}
+
+func decodeInt(v reflect.Value, s string, _ Format, _ *time.Location) error {
+
+	if s = strings.TrimSpace(s); s == "" {
+		v.SetInt(0)
+		return nil
+	}
+
+	n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+	if err != nil {
+		return err
+	}
+	v.SetInt(n)
+	return nil
+}
+
+func encodeInt(v reflect.Value, _ Format) (string, error) {
+
+	return strconv.FormatInt(v.Int(), 10), nil
+}
+
+func decodeUint(v reflect.Value, s string, _ Format, _ *time.Location) error {
+
+	if s = strings.TrimSpace(s); s == "" {
+		v.SetUint(0)
+		return nil
+	}
+
+	n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+	if err != nil {
+		return err
+	}
+	v.SetUint(n)
+	return nil
+}
+
+func encodeUint(v reflect.Value, _ Format) (string, error) {
+
+	return strconv.FormatUint(v.Uint(), 10), nil
+}
+
+func decodeFloat(v reflect.Value, s string, _ Format, _ *time.Location) error {
+
+	if s = strings.TrimSpace(s); s == "" {
+		v.SetFloat(0)
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(s, v.Type().Bits())
+	if err != nil {
+		return err
+	}
+	v.SetFloat(f)
+	return nil
+}
+
+func encodeFloat(v reflect.Value, _ Format) (string, error) {
+
+	return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+}
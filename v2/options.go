@@ -0,0 +1,148 @@
+package csvee
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+)
+
+// Option configures a Reader or a Writer.
+type Option func(s *settings) error
+
+// settings are the values options set, shared by readers and writers.
+type settings struct {
+	headers          bool
+	columns          []string
+	formats          map[string]Format
+	comma            rune
+	comment          rune
+	lazyQuotes       bool
+	trimLeadingSpace bool
+	location         *time.Location
+}
+
+// newSettings applies options over the defaults and checks that the result is consistent.
+func newSettings(options []Option) (settings, error) {
+
+	s := settings{
+		comma:    ',',
+		formats:  make(map[string]Format),
+		location: time.UTC,
+	}
+
+	for _, option := range options {
+		if option == nil {
+			continue
+		}
+		if err := option(&s); err != nil {
+			return settings{}, err
+		}
+	}
+
+	if !validDelimiter(s.comma) {
+		return settings{}, &OptionError{Option: "WithComma", Err: errors.New("invalid delimiter")}
+	}
+	if s.comment != 0 && (!validDelimiter(s.comment) || s.comment == s.comma) {
+		return settings{}, &OptionError{Option: "WithComment", Err: errors.New("invalid comment character")}
+	}
+
+	return s, nil
+}
+
+// validDelimiter reports whether r can separate fields, as encoding/csv requires.
+func validDelimiter(r rune) bool {
+
+	return r != 0 && r != '"' && r != '\r' && r != '\n' && utf8.ValidRune(r) && r != utf8.RuneError
+}
+
+// WithHeaders makes a Reader take its column names from the first record and a Writer write the
+// column names before the first record.
+func WithHeaders() Option {
+
+	return func(s *settings) error {
+		s.headers = true
+		return nil
+	}
+}
+
+// WithColumns sets the column names. A Reader without WithHeaders needs them to map fields; a
+// Writer writes these columns, in this order, instead of every field of the value written. Names
+// must be unique and non empty.
+func WithColumns(names ...string) Option {
+
+	return func(s *settings) error {
+		seen := make(map[string]bool, len(names))
+		for _, name := range names {
+			if name == "" {
+				return &OptionError{Option: "WithColumns", Err: errors.New("column names must not be empty")}
+			}
+			if seen[name] {
+				return &OptionError{Option: "WithColumns", Err: errors.New("duplicate column name " + name)}
+			}
+			seen[name] = true
+		}
+		s.columns = append([]string(nil), names...)
+		return nil
+	}
+}
+
+// WithFormat sets the format of the time values in the named column.
+func WithFormat(column string, format Format) Option {
+
+	return func(s *settings) error {
+		if column == "" {
+			return &OptionError{Option: "WithFormat", Err: errors.New("column name must not be empty")}
+		}
+		s.formats[column] = format
+		return nil
+	}
+}
+
+// WithComma sets the field delimiter. Defaults to ','.
+func WithComma(r rune) Option {
+
+	return func(s *settings) error {
+		s.comma = r
+		return nil
+	}
+}
+
+// WithComment makes a Reader skip lines starting with r.
+func WithComment(r rune) Option {
+
+	return func(s *settings) error {
+		s.comment = r
+		return nil
+	}
+}
+
+// WithLazyQuotes makes a Reader accept quotes in unquoted fields and unescaped quotes in quoted
+// fields, as csv.Reader.LazyQuotes does.
+func WithLazyQuotes() Option {
+
+	return func(s *settings) error {
+		s.lazyQuotes = true
+		return nil
+	}
+}
+
+// WithTrimLeadingSpace makes a Reader ignore leading white space in fields.
+func WithTrimLeadingSpace() Option {
+
+	return func(s *settings) error {
+		s.trimLeadingSpace = true
+		return nil
+	}
+}
+
+// WithLocation sets the location of times read without a zone. Defaults to UTC.
+func WithLocation(loc *time.Location) Option {
+
+	return func(s *settings) error {
+		if loc == nil {
+			return &OptionError{Option: "WithLocation", Err: errors.New("location must not be nil")}
+		}
+		s.location = loc
+		return nil
+	}
+}
@@ -0,0 +1,222 @@
+package csvee
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"reflect"
+)
+
+// Reader reads CSV records into structs. Each column is decoded into the field of the struct that
+// is tagged with its name, or else has its name.
+type Reader struct {
+	csv      *csv.Reader
+	settings settings
+
+	// columns are nil until they are taken from the options or the header record.
+	columns []string
+	row     int
+	err     error
+
+	plans map[reflect.Type][]*field
+}
+
+// NewReader returns a new Reader that reads from r. It does not read from r; with WithHeaders, the
+// header record is read by the first call to Columns, Read, or ReadAll.
+func NewReader(r io.Reader, options ...Option) (*Reader, error) {
+
+	if r == nil {
+		return nil, &OptionError{Option: "NewReader", Err: errors.New("reader must not be nil")}
+	}
+
+	s, err := newSettings(options)
+	if err != nil {
+		return nil, err
+	}
+	if !s.headers && len(s.columns) == 0 {
+		return nil, &OptionError{Option: "WithColumns", Err: errors.New("columns are required without WithHeaders")}
+	}
+
+	cr := csv.NewReader(r)
+	cr.Comma = s.comma
+	cr.Comment = s.comment
+	cr.LazyQuotes = s.lazyQuotes
+	cr.TrimLeadingSpace = s.trimLeadingSpace
+	cr.FieldsPerRecord = -1
+
+	reader := &Reader{
+		csv:      cr,
+		settings: s,
+		plans:    make(map[reflect.Type][]*field),
+	}
+	if !s.headers {
+		reader.columns = s.columns
+	}
+
+	return reader, nil
+}
+
+// Columns returns the names of the columns, reading the header record if it has not been read.
+func (r *Reader) Columns() ([]string, error) {
+
+	if err := r.readHeaders(); err != nil {
+		return nil, err
+	}
+
+	return append([]string(nil), r.columns...), nil
+}
+
+// readHeaders reads the header record the first time it is called if the reader takes its columns
+// from the input. A failure is returned by every later call.
+func (r *Reader) readHeaders() error {
+
+	if r.columns != nil || r.err != nil {
+		return r.err
+	}
+
+	record, err := r.readRecord()
+	if err == io.EOF {
+		r.err = &ParseError{Row: 1, Err: io.ErrUnexpectedEOF}
+		return r.err
+	}
+	if err != nil {
+		r.err = err
+		return r.err
+	}
+
+	r.columns = record
+	return nil
+}
+
+// readRecord reads the next record, wrapping any error other than io.EOF in a *ParseError.
+func (r *Reader) readRecord() ([]string, error) {
+
+	record, err := r.csv.Read()
+	if err == io.EOF {
+		return nil, err
+	}
+
+	r.row++
+	if err != nil {
+		return nil, &ParseError{Row: r.row, Err: err}
+	}
+
+	return record, nil
+}
+
+// Read reads the next record into v, which must point to a struct. It returns io.EOF when there
+// are no more records.
+func (r *Reader) Read(v interface{}) error {
+
+	value := reflect.ValueOf(v)
+	if v == nil || value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return &TargetError{Type: reflect.TypeOf(v), Reason: "Read requires a non nil pointer to a struct"}
+	}
+
+	plan, err := r.plan(value.Elem().Type())
+	if err != nil {
+		return err
+	}
+
+	return r.read(value.Elem(), plan)
+}
+
+// ReadAll reads the remaining records into v, which must point to a slice of structs or of pointers
+// to structs. Records read before an error are kept in the slice.
+func (r *Reader) ReadAll(v interface{}) error {
+
+	value := reflect.ValueOf(v)
+	if v == nil || value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Slice {
+		return &TargetError{Type: reflect.TypeOf(v), Reason: "ReadAll requires a non nil pointer to a slice of structs"}
+	}
+
+	slice := value.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	base := elemType
+	if isPtr {
+		base = elemType.Elem()
+	}
+	if base.Kind() != reflect.Struct {
+		return &TargetError{Type: reflect.TypeOf(v), Reason: "ReadAll requires a non nil pointer to a slice of structs"}
+	}
+
+	plan, err := r.plan(base)
+	if err != nil {
+		return err
+	}
+
+	for {
+
+		next := reflect.New(base)
+		err := r.read(next.Elem(), plan)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if isPtr {
+			slice.Set(reflect.Append(slice, next))
+		} else {
+			slice.Set(reflect.Append(slice, next.Elem()))
+		}
+	}
+}
+
+// read reads the next record into the struct value target according to plan.
+func (r *Reader) read(target reflect.Value, plan []*field) error {
+
+	record, err := r.readRecord()
+	if err != nil {
+		return err
+	}
+
+	if len(record) != len(r.columns) {
+		return &ColumnCountError{Row: r.row, Fields: len(record), Columns: len(r.columns)}
+	}
+
+	for i, f := range plan {
+		if f == nil {
+			continue
+		}
+		column := r.columns[i]
+		if err := f.codec.decode(target.FieldByIndex(f.index), record[i], r.settings.formats[column], r.settings.location); err != nil {
+			return &DecodeError{Row: r.row, Column: column, Value: record[i], Err: err}
+		}
+	}
+
+	return nil
+}
+
+// plan returns, for each column, the field of the struct type t it is decoded into, or nil if it
+// has none. Plans are built once per type and kept for the life of the reader.
+func (r *Reader) plan(t reflect.Type) ([]*field, error) {
+
+	if err := r.readHeaders(); err != nil {
+		return nil, err
+	}
+
+	if plan, exists := r.plans[t]; exists {
+		return plan, nil
+	}
+
+	fields, err := typeFields(t)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*field, len(fields))
+	for i := range fields {
+		byName[fields[i].name] = &fields[i]
+	}
+
+	plan := make([]*field, len(r.columns))
+	for i, column := range r.columns {
+		plan[i] = byName[column]
+	}
+
+	r.plans[t] = plan
+	return plan, nil
+}
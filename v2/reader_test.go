@@ -0,0 +1,268 @@
+package csvee
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type base struct {
+	ID   int
+	Note string `csvee:"note"`
+}
+
+type row struct {
+	base
+	Name    string `csvee:"name"`
+	Score   *float64
+	Tags    []string
+	Active  bool
+	Created time.Time `csvee:"created"`
+	Skipped string    `csvee:"-"`
+	hidden  string
+}
+
+// failingReader fails every read, so that tests can tell whether a constructor reads its input.
+type failingReader struct {
+	reads int
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+
+	r.reads++
+	return 0, errors.New("read")
+}
+
+// TestNewReader verifies constructing a reader validates its options without reading the input
+func TestNewReader(t *testing.T) {
+
+	var testCases = []struct {
+		name      string
+		inOptions []Option
+		expErr    string
+	}{
+		{
+			name:      "headers",
+			inOptions: []Option{WithHeaders()},
+		},
+		{
+			name:      "columns",
+			inOptions: []Option{WithColumns("ID", "name")},
+		},
+		{
+			name:   "no columns",
+			expErr: "WithColumns: columns are required without WithHeaders",
+		},
+		{
+			name:      "duplicate columns",
+			inOptions: []Option{WithColumns("ID", "ID")},
+			expErr:    "WithColumns: duplicate column name ID",
+		},
+		{
+			name:      "invalid comma",
+			inOptions: []Option{WithHeaders(), WithComma('"')},
+			expErr:    "WithComma: invalid delimiter",
+		},
+		{
+			name:      "comment equals comma",
+			inOptions: []Option{WithHeaders(), WithComment(',')},
+			expErr:    "WithComment: invalid comment character",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			input := &failingReader{}
+			reader, err := NewReader(input, tt.inOptions...)
+			assert.Zero(t, input.reads)
+			if tt.expErr != "" {
+				assert.Nil(t, reader)
+				assert.EqualError(t, err, tt.expErr)
+				var optionErr *OptionError
+				assert.True(t, errors.As(err, &optionErr))
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, reader)
+		})
+	}
+}
+
+// TestReader_ReadAll verifies records are decoded into the fields their columns map to
+func TestReader_ReadAll(t *testing.T) {
+
+	score := 1.5
+
+	var testCases = []struct {
+		name      string
+		inData    string
+		inOptions []Option
+		expRows   []row
+		expErr    string
+	}{
+		{
+			name:      "headers",
+			inData:    "ID,name,note,Score,Tags,Active,created,Skipped,hidden\n1,ann,n,1.5,\"a,b\",true,1613235342,x,y\n2,bob,,,,false,,,\n",
+			inOptions: []Option{WithHeaders(), WithFormat("created", FormatUnix)},
+			expRows: []row{
+				{
+					base:    base{ID: 1, Note: "n"},
+					Name:    "ann",
+					Score:   &score,
+					Tags:    []string{"a", "b"},
+					Active:  true,
+					Created: time.Unix(1613235342, 0).UTC(),
+				},
+				{base: base{ID: 2}, Name: "bob"},
+			},
+		},
+		{
+			name:      "columns and dialect",
+			inData:    "# comment\n1;ann;2021-02-13\n",
+			inOptions: []Option{WithColumns("ID", "name", "created"), WithComma(';'), WithComment('#'), WithFormat("created", "2006-01-02")},
+			expRows:   []row{{base: base{ID: 1}, Name: "ann", Created: time.Date(2021, 2, 13, 0, 0, 0, 0, time.UTC)}},
+		},
+		{
+			name:      "invalid value",
+			inData:    "ID,name\n1,ann\nx,bob\n",
+			inOptions: []Option{WithHeaders()},
+			expRows:   []row{{base: base{ID: 1}, Name: "ann"}},
+			expErr:    `row 3, column "ID": invalid value "x": strconv.ParseInt: parsing "x": invalid syntax`,
+		},
+		{
+			name:      "column count",
+			inData:    "ID,name\n1\n",
+			inOptions: []Option{WithHeaders()},
+			expErr:    "row 2: 1 fields for 2 columns",
+		},
+		{
+			name:      "missing header",
+			inData:    "",
+			inOptions: []Option{WithHeaders()},
+			expErr:    "row 1: unexpected EOF",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader(tt.inData), tt.inOptions...)
+			require.NoError(t, err)
+
+			var actualRows []row
+			err = reader.ReadAll(&actualRows)
+			if tt.expErr != "" {
+				assert.EqualError(t, err, tt.expErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expRows, actualRows)
+		})
+	}
+}
+
+// TestReader_Read verifies records are read one at a time until io.EOF
+func TestReader_Read(t *testing.T) {
+
+	failing := &failingReader{}
+	reader, err := NewReader(failing, WithHeaders())
+	require.NoError(t, err)
+	_, err = reader.Columns()
+	var parseErr *ParseError
+	assert.True(t, errors.As(err, &parseErr))
+	assert.Equal(t, 1, failing.reads)
+
+	reader, err = NewReader(strings.NewReader("ID,name\n1,ann\n"), WithHeaders())
+	require.NoError(t, err)
+
+	columns, err := reader.Columns()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ID", "name"}, columns)
+
+	var actual row
+	require.NoError(t, reader.Read(&actual))
+	assert.Equal(t, row{base: base{ID: 1}, Name: "ann"}, actual)
+	assert.Equal(t, io.EOF, reader.Read(&actual))
+}
+
+// TestReader_Targets verifies values that cannot be read into are rejected with a *TargetError
+func TestReader_Targets(t *testing.T) {
+
+	var testCases = []struct {
+		name   string
+		inRead func(r *Reader) error
+		expErr string
+	}{
+		{
+			name:   "nil",
+			inRead: func(r *Reader) error { return r.Read(nil) },
+			expErr: "nil target: Read requires a non nil pointer to a struct",
+		},
+		{
+			name:   "not a pointer",
+			inRead: func(r *Reader) error { return r.Read(row{}) },
+			expErr: "target of type csvee.row: Read requires a non nil pointer to a struct",
+		},
+		{
+			name:   "not a slice",
+			inRead: func(r *Reader) error { return r.ReadAll(&row{}) },
+			expErr: "target of type *csvee.row: ReadAll requires a non nil pointer to a slice of structs",
+		},
+		{
+			name: "unsupported field",
+			inRead: func(r *Reader) error {
+				var v []struct{ ID map[string]int }
+				return r.ReadAll(&v)
+			},
+			expErr: `target of type struct { ID map[string]int }: field for column "ID": unsupported type map[string]int`,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader("ID\n1\n"), WithHeaders())
+			require.NoError(t, err)
+
+			err = tt.inRead(reader)
+			assert.EqualError(t, err, tt.expErr)
+			var targetErr *TargetError
+			assert.True(t, errors.As(err, &targetErr))
+		})
+	}
+}
+
+// TestTypeFields verifies embedded fields are promoted unless a shallower or tagged field hides them
+func TestTypeFields(t *testing.T) {
+
+	type inner struct {
+		A string `csvee:"a"`
+		B string
+		C string
+	}
+	type other struct {
+		C string
+	}
+	type outer struct {
+		inner
+		other
+		X string `csvee:"a"`
+	}
+
+	fields, err := typeFields(reflect.TypeOf(outer{}))
+	require.NoError(t, err)
+
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.name
+	}
+	assert.Equal(t, []string{"B", "a"}, names)
+	assert.Equal(t, []int{2}, fields[1].index)
+}
@@ -0,0 +1,166 @@
+package csvee
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"reflect"
+)
+
+// Writer writes structs as CSV records, using the same field mapping as Reader.
+type Writer struct {
+	csv      *csv.Writer
+	settings settings
+
+	// columns are nil until they are taken from the options or the first value written.
+	columns []string
+	row     int
+
+	plans map[reflect.Type][]*field
+}
+
+// NewWriter returns a new Writer that writes to w. It does not write to w; with WithHeaders, the
+// header record is written before the first record.
+func NewWriter(w io.Writer, options ...Option) (*Writer, error) {
+
+	if w == nil {
+		return nil, &OptionError{Option: "NewWriter", Err: errors.New("writer must not be nil")}
+	}
+
+	s, err := newSettings(options)
+	if err != nil {
+		return nil, err
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = s.comma
+
+	return &Writer{
+		csv:      cw,
+		settings: s,
+		columns:  s.columns,
+		plans:    make(map[reflect.Type][]*field),
+	}, nil
+}
+
+// Write writes v, a struct or a pointer to one, as a record. Without WithColumns, the columns are
+// the fields of the first value written, in the order they are declared. Records are buffered until
+// Flush is called.
+func (w *Writer) Write(v interface{}) error {
+
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if v == nil || value.Kind() != reflect.Struct {
+		return &TargetError{Type: reflect.TypeOf(v), Reason: "Write requires a struct or a non nil pointer to one"}
+	}
+
+	plan, err := w.plan(value.Type())
+	if err != nil {
+		return err
+	}
+
+	return w.write(value, plan)
+}
+
+// WriteAll writes each element of v, a slice of structs or of pointers to structs, and flushes
+// the output.
+func (w *Writer) WriteAll(v interface{}) error {
+
+	value := reflect.ValueOf(v)
+	if v == nil || value.Kind() != reflect.Slice {
+		return &TargetError{Type: reflect.TypeOf(v), Reason: "WriteAll requires a slice of structs"}
+	}
+
+	base := value.Type().Elem()
+	if base.Kind() == reflect.Ptr {
+		base = base.Elem()
+	}
+	if base.Kind() != reflect.Struct {
+		return &TargetError{Type: reflect.TypeOf(v), Reason: "WriteAll requires a slice of structs"}
+	}
+
+	plan, err := w.plan(base)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < value.Len(); i++ {
+		elem := reflect.Indirect(value.Index(i))
+		if !elem.IsValid() {
+			return &TargetError{Type: reflect.TypeOf(v), Reason: "WriteAll cannot write nil elements"}
+		}
+		if err := w.write(elem, plan); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// Flush writes any buffered records to the underlying writer.
+func (w *Writer) Flush() error {
+
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// write writes the struct value v as a record according to plan.
+func (w *Writer) write(v reflect.Value, plan []*field) error {
+
+	w.row++
+
+	record := make([]string, len(plan))
+	for i, f := range plan {
+		if f == nil {
+			continue
+		}
+		column := w.columns[i]
+		cell, err := f.codec.encode(v.FieldByIndex(f.index), w.settings.formats[column])
+		if err != nil {
+			return &EncodeError{Row: w.row, Column: column, Err: err}
+		}
+		record[i] = cell
+	}
+
+	return w.csv.Write(record)
+}
+
+// plan returns, for each column, the field of the struct type t it is encoded from, or nil if it
+// has none, writing the header record first if it is due.
+func (w *Writer) plan(t reflect.Type) ([]*field, error) {
+
+	if plan, exists := w.plans[t]; exists {
+		return plan, nil
+	}
+
+	fields, err := typeFields(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.columns == nil {
+		w.columns = make([]string, len(fields))
+		for i, f := range fields {
+			w.columns[i] = f.name
+		}
+	}
+
+	if w.settings.headers && w.row == 0 {
+		w.row++
+		if err := w.csv.Write(w.columns); err != nil {
+			return nil, err
+		}
+	}
+
+	byName := make(map[string]*field, len(fields))
+	for i := range fields {
+		byName[fields[i].name] = &fields[i]
+	}
+
+	plan := make([]*field, len(w.columns))
+	for i, column := range w.columns {
+		plan[i] = byName[column]
+	}
+
+	w.plans[t] = plan
+	return plan, nil
+}
@@ -0,0 +1,110 @@
+package csvee
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriter_WriteAll verifies structs are written with the columns of their fields or the options
+func TestWriter_WriteAll(t *testing.T) {
+
+	score := 1.5
+	rows := []*row{
+		{
+			base:    base{ID: 1, Note: "n"},
+			Name:    "ann",
+			Score:   &score,
+			Tags:    []string{"a", "b"},
+			Active:  true,
+			Created: time.Unix(1613235342, 0).UTC(),
+		},
+		{base: base{ID: 2}, Name: "bob"},
+	}
+
+	var testCases = []struct {
+		name      string
+		inOptions []Option
+		inRows    interface{}
+		expOutput string
+	}{
+		{
+			name:      "headers",
+			inOptions: []Option{WithHeaders(), WithFormat("created", FormatUnix)},
+			inRows:    rows,
+			expOutput: "ID,note,name,Score,Tags,Active,created\n1,n,ann,1.5,\"a,b\",true,1613235342\n2,,bob,,,false,\n",
+		},
+		{
+			name:      "columns",
+			inOptions: []Option{WithColumns("name", "Missing", "ID"), WithComma(';')},
+			inRows:    rows,
+			expOutput: "ann;;1\nbob;;2\n",
+		},
+		{
+			name:      "no rows",
+			inOptions: []Option{WithHeaders(), WithColumns("name")},
+			inRows:    []row{},
+			expOutput: "name\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var buf bytes.Buffer
+			writer, err := NewWriter(&buf, tt.inOptions...)
+			require.NoError(t, err)
+			assert.Zero(t, buf.Len())
+
+			require.NoError(t, writer.WriteAll(tt.inRows))
+			assert.Equal(t, tt.expOutput, buf.String())
+		})
+	}
+}
+
+// TestWriter_RoundTrip verifies records written by a Writer read back into equal values
+func TestWriter_RoundTrip(t *testing.T) {
+
+	score := 2.25
+	expected := []row{
+		{base: base{ID: 1, Note: "x"}, Name: "ann", Score: &score, Tags: []string{"a"}, Created: time.Date(2021, 2, 13, 1, 2, 3, 0, time.UTC)},
+		{base: base{ID: 2}, Name: "b,o\"b", Active: true},
+	}
+
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, WithHeaders())
+	require.NoError(t, err)
+	for _, r := range expected {
+		require.NoError(t, writer.Write(r))
+	}
+	require.NoError(t, writer.Flush())
+
+	reader, err := NewReader(strings.NewReader(buf.String()), WithHeaders())
+	require.NoError(t, err)
+
+	var actual []row
+	require.NoError(t, reader.ReadAll(&actual))
+	assert.Equal(t, expected, actual)
+}
+
+// TestWriter_Targets verifies values that cannot be written are rejected with a *TargetError
+func TestWriter_Targets(t *testing.T) {
+
+	writer, err := NewWriter(&bytes.Buffer{})
+	require.NoError(t, err)
+
+	var targetErr *TargetError
+	assert.True(t, errors.As(writer.Write(nil), &targetErr))
+	assert.True(t, errors.As(writer.Write(1), &targetErr))
+	assert.True(t, errors.As(writer.WriteAll([]int{1}), &targetErr))
+	assert.True(t, errors.As(writer.WriteAll([]*row{nil}), &targetErr))
+
+	_, err = NewWriter(nil)
+	var optionErr *OptionError
+	assert.True(t, errors.As(err, &optionErr))
+}
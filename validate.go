@@ -0,0 +1,239 @@
+package csvee
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// ErrorPolicy controls what ReadAll does when a row cannot be read, decoded, or validated.
+type ErrorPolicy int
+
+const (
+	// ErrorFail stops at the first row that fails and returns its error.
+	ErrorFail ErrorPolicy = iota
+
+	// ErrorSkip leaves rows that fail out of the result and carries on.
+	ErrorSkip
+
+	// ErrorCollect leaves rows that fail out of the result and carries on, then returns a
+	// *MultiError describing each of them once every row has been read.
+	ErrorCollect
+)
+
+var (
+	validatorRegistryMu sync.RWMutex
+	validatorRegistry   = map[string]func(v interface{}, line int) error{}
+)
+
+// RegisterValidator registers validate under name so it can be used as ReaderOptions.ValidatorName.
+// Registering a name that already exists replaces its validator.
+func RegisterValidator(name string, validate func(v interface{}, line int) error) {
+
+	validatorRegistryMu.Lock()
+	defer validatorRegistryMu.Unlock()
+
+	validatorRegistry[name] = validate
+}
+
+// registeredValidator returns the validator registered under name.
+func registeredValidator(name string) (func(v interface{}, line int) error, bool) {
+
+	validatorRegistryMu.RLock()
+	defer validatorRegistryMu.RUnlock()
+
+	validate, registered := validatorRegistry[name]
+	return validate, registered
+}
+
+// ValidationError is returned when the Validate hook rejects a row.
+type ValidationError struct {
+	Row  int
+	Line int
+	Err  error
+}
+
+func (e *ValidationError) Error() string {
+
+	return fmt.Sprintf("row %d: invalid: %v", e.Row, e.Err)
+}
+
+// Unwrap returns the error returned by the Validate hook.
+func (e *ValidationError) Unwrap() error {
+
+	return e.Err
+}
+
+// MultiError lists the rows that failed while reading with ErrorCollect, in the order they were
+// read.
+type MultiError struct {
+	Errors []*ParseError
+}
+
+func (e *MultiError) Error() string {
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d rows failed", len(e.Errors))
+	for i, err := range e.Errors {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		b.WriteString(err.Error())
+	}
+
+	return b.String()
+}
+
+// Unwrap returns the errors of the rows that failed.
+func (e *MultiError) Unwrap() []error {
+
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// validateRow calls the Validate hook, if there is one, with v, the row just decoded.
+func (r *Reader) validateRow(v interface{}) error {
+
+	if r.validate == nil {
+		return nil
+	}
+
+	if err := r.validate(v, r.lastLine); err != nil {
+		return &ValidationError{Row: r.rowsRead, Line: r.lastLine, Err: err}
+	}
+
+	return nil
+}
+
+// ErrorBudgetError is returned when more rows fail than ReaderOptions.ErrorBudget allows. Err is
+// the error of the row that exceeded the budget, or with ErrorCollect, a *MultiError including it.
+type ErrorBudgetError struct {
+	Budget int
+	Err    error
+}
+
+func (e *ErrorBudgetError) Error() string {
+
+	return fmt.Sprintf("more than %d rows failed: %v", e.Budget, e.Err)
+}
+
+// Unwrap returns the error of the rows that failed.
+func (e *ErrorBudgetError) Unwrap() error {
+
+	return e.Err
+}
+
+// rowFailures applies the reader's ErrorPolicy and ErrorBudget to the errors of individual rows.
+type rowFailures struct {
+	reader *Reader
+	failed int
+	errs   []*ParseError
+}
+
+// skip reports whether the row that caused err should be skipped, recording it if the errors are
+// being collected. Errors that are not caused by a single row, such as those returned by hooks, are
+// never skipped, and neither is the row that exceeds the error budget.
+func (f *rowFailures) skip(err error) bool {
+
+	if f.reader.onError == ErrorFail || !isRowError(err) {
+		return false
+	}
+
+	f.failed++
+	if budget := f.reader.errorBudget; budget > 0 && f.failed > budget {
+		return false
+	}
+
+	if f.reader.onError == ErrorCollect {
+		var parseErr *ParseError
+		if !errors.As(f.reader.parseError(err), &parseErr) {
+			return false
+		}
+		f.errs = append(f.errs, parseErr)
+	}
+
+	f.reject(err)
+	return true
+}
+
+// reject records the row that caused err as rejected in the reader's manifest, if it has one.
+func (f *rowFailures) reject(err error) {
+
+	if f.reader.manifest == nil {
+		return
+	}
+
+	row := f.reader.rowsRead
+	var parseErr *ParseError
+	if errors.As(f.reader.parseError(err), &parseErr) {
+		row = parseErr.Row
+	}
+
+	f.reader.manifest.reject(row, err)
+}
+
+// stop returns the error to stop reading with once err was not skipped.
+func (f *rowFailures) stop(err error) error {
+
+	budget := f.reader.errorBudget
+	if budget == 0 || f.failed <= budget {
+		return err
+	}
+
+	f.reject(err)
+	if f.reader.onError == ErrorCollect {
+		var parseErr *ParseError
+		if errors.As(f.reader.parseError(err), &parseErr) {
+			f.errs = append(f.errs, parseErr)
+		}
+		err = f.err()
+	}
+
+	return &ErrorBudgetError{Budget: budget, Err: err}
+}
+
+// rejected reports whether err is made of rows that were already recorded as rejected in the
+// manifest, one at a time.
+func rejected(err error) bool {
+
+	var (
+		multiErr  *MultiError
+		budgetErr *ErrorBudgetError
+	)
+
+	return errors.As(err, &multiErr) || errors.As(err, &budgetErr)
+}
+
+// err returns the collected errors as a *MultiError, or nil if there are none.
+func (f *rowFailures) err() error {
+
+	if len(f.errs) == 0 {
+		return nil
+	}
+
+	return &MultiError{Errors: f.errs}
+}
+
+// isRowError reports whether err was caused by the contents of the row being read.
+func isRowError(err error) bool {
+
+	var (
+		csvErr        *csv.ParseError
+		fieldErr      *FieldError
+		ruleErr       *RuleError
+		timeoutErr    *RowTimeoutError
+		validationErr *ValidationError
+	)
+
+	return errors.Is(err, ErrColumnNamesMismatch) || errors.As(err, &csvErr) || errors.As(err, &fieldErr) ||
+		errors.As(err, &ruleErr) || errors.As(err, &timeoutErr) || errors.As(err, &validationErr)
+}
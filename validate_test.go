@@ -0,0 +1,150 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validatedRow struct {
+	Name string
+	Age  int
+}
+
+// validateAge rejects rows with negative ages.
+func validateAge(v interface{}, line int) error {
+
+	if v.(*validatedRow).Age < 0 {
+		return errors.Errorf("age must not be negative")
+	}
+	return nil
+}
+
+// TestReader_OnError verifies how ReadAll handles rows that fail under each policy
+func TestReader_OnError(t *testing.T) {
+
+	input := "Name,Age\nann,34\nbob,x\ncat,-1\n\"dan,5\nemu,7\n"
+
+	var testCases = []struct {
+		name     string
+		policy   ErrorPolicy
+		pipeline int
+		exp      []validatedRow
+		expErr   string
+		expRows  []int
+		expLines []int
+	}{
+		{
+			name:   "fail",
+			policy: ErrorFail,
+			exp:    []validatedRow{{Name: "ann", Age: 34}},
+			expErr: `row 2, column "Age": invalid value "x" for int`,
+		},
+		{
+			name:   "skip",
+			policy: ErrorSkip,
+			exp:    []validatedRow{{Name: "ann", Age: 34}},
+		},
+		{
+			name:     "collect",
+			policy:   ErrorCollect,
+			exp:      []validatedRow{{Name: "ann", Age: 34}},
+			expErr:   "3 rows failed: ",
+			expRows:  []int{2, 3, 4},
+			expLines: []int{3, 4, 5},
+		},
+		{
+			name:     "collect with pipeline",
+			policy:   ErrorCollect,
+			pipeline: 2,
+			exp:      []validatedRow{{Name: "ann", Age: 34}},
+			expErr:   "3 rows failed: ",
+			expRows:  []int{2, 3, 4},
+			expLines: []int{3, 4, 5},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reader, err := NewReader(strings.NewReader(input), &ReaderOptions{
+				ReadHeaders:   true,
+				Validate:      validateAge,
+				OnError:       tt.policy,
+				PipelineDepth: tt.pipeline,
+			})
+			require.NoError(t, err)
+
+			var actual []validatedRow
+			err = reader.ReadAll(&actual)
+			assert.Equal(t, tt.exp, actual)
+
+			if tt.expErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expErr)
+
+			if tt.expRows == nil {
+				return
+			}
+			var multiErr *MultiError
+			require.True(t, errors.As(err, &multiErr))
+			var rows, lines []int
+			for _, parseErr := range multiErr.Errors {
+				rows, lines = append(rows, parseErr.Row), append(lines, parseErr.Line)
+			}
+			assert.Equal(t, tt.expRows, rows)
+			assert.Equal(t, tt.expLines, lines)
+
+			var validationErr *ValidationError
+			require.True(t, errors.As(multiErr.Errors[1], &validationErr))
+			assert.Equal(t, 4, validationErr.Line)
+			assert.Equal(t, "age must not be negative", validationErr.Err.Error())
+		})
+	}
+}
+
+// TestReader_Validate verifies the Validate hook is called with each decoded row and its line
+func TestReader_Validate(t *testing.T) {
+
+	var lines []int
+	reader, err := NewReader(strings.NewReader("Name,Age\nann,34\n\"b\nob\",1\ncat,-1\n"), &ReaderOptions{
+		ReadHeaders: true,
+		Validate: func(v interface{}, line int) error {
+			lines = append(lines, line)
+			return validateAge(v, line)
+		},
+	})
+	require.NoError(t, err)
+
+	var row validatedRow
+	require.NoError(t, reader.Read(&row))
+	require.NoError(t, reader.Read(&row))
+	assert.Equal(t, validatedRow{Name: "b\nob", Age: 1}, row)
+
+	err = reader.Read(&row)
+	require.Error(t, err)
+	assert.Equal(t, "row 3: invalid: age must not be negative", err.Error())
+	assert.Equal(t, []int{2, 3, 5}, lines)
+}
+
+// TestReader_OnError_Hooks verifies errors returned by hooks stop ReadAll whatever the policy
+func TestReader_OnError_Hooks(t *testing.T) {
+
+	stop := errors.New("stop")
+	reader, err := NewReader(strings.NewReader("Name,Age\nann,x\nbob,2\n"), &ReaderOptions{
+		ReadHeaders: true,
+		OnError:     ErrorSkip,
+		AfterRow:    func(n int, v interface{}) error { return stop },
+	})
+	require.NoError(t, err)
+
+	var actual []validatedRow
+	assert.Equal(t, stop, reader.ReadAll(&actual))
+	assert.Empty(t, actual)
+}
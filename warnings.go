@@ -0,0 +1,75 @@
+package csvee
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Warning describes data that was decoded successfully but may not have been decoded as intended.
+type Warning struct {
+	Row     int
+	Column  string
+	Value   string
+	Message string
+}
+
+func (w Warning) String() string {
+
+	if w.Column == "" {
+		return fmt.Sprintf("row %d: %s", w.Row, w.Message)
+	}
+
+	return fmt.Sprintf("row %d, column %q: %s", w.Row, w.Column, w.Message)
+}
+
+// maxWarnings is the number of warnings a Reader keeps for Warnings, so that a file raising a
+// warning on every row, such as a ragged one, does not grow its memory with every row read.
+const maxWarnings = 1000
+
+// Warnings returns the warnings raised so far, up to the first 1000. ReaderOptions.OnWarning is
+// called with every warning, including those past the first 1000.
+func (r *Reader) Warnings() []Warning {
+
+	return append([]Warning(nil), r.warnings...)
+}
+
+func (r *Reader) warn(column, value, message string) {
+
+	w := Warning{Row: r.rowsRead, Column: column, Value: value, Message: message}
+	if len(r.warnings) < maxWarnings {
+		r.warnings = append(r.warnings, w)
+	}
+
+	if r.onWarning != nil {
+		r.onWarning(w)
+	}
+}
+
+// checkLeadingZeros warns, once per column, about numeric values written with leading zeros, such as
+// ZIP codes or account numbers, which lose their zeros when decoded into a numeric field.
+func (r *Reader) checkLeadingZeros(column, value string, t reflect.Type) {
+
+	if !r.detectLeadingZeros || r.leadingZeroColumns[column] {
+		return
+	}
+
+	if _, hasBase := r.integerBases[column]; hasBase || !isPrimitiveKind(t.Kind()) {
+		return
+	}
+	if k := t.Kind(); k == reflect.Bool || k == reflect.String {
+		return
+	}
+
+	digits := strings.TrimLeft(strings.TrimSpace(value), "+-")
+	if len(digits) < 2 || digits[0] != '0' || digits[1] < '0' || digits[1] > '9' {
+		return
+	}
+
+	if r.leadingZeroColumns == nil {
+		r.leadingZeroColumns = make(map[string]bool)
+	}
+	r.leadingZeroColumns[column] = true
+
+	r.warn(column, value, fmt.Sprintf("value %s has leading zeros that are lost when decoded into %s", value, t))
+}
@@ -0,0 +1,37 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_DetectLeadingZeros verifies a warning is raised once per numeric column with leading zeros
+func TestReader_DetectLeadingZeros(t *testing.T) {
+
+	var onWarning []Warning
+	reader, err := NewReader(
+		strings.NewReader("I,F,S,IA\n01234,0.5,007,1\n00042,00.5,007,\"1,02\"\n"),
+		&ReaderOptions{
+			ReadHeaders:        true,
+			DetectLeadingZeros: true,
+			OnWarning:          func(w Warning) { onWarning = append(onWarning, w) },
+		},
+	)
+	require.NoError(t, err)
+
+	var actualData []readTo
+	require.NoError(t, reader.ReadAll(&actualData))
+	assert.Equal(t, 1234, actualData[0].I)
+
+	expWarnings := []Warning{
+		{Row: 1, Column: "I", Value: "01234", Message: "value 01234 has leading zeros that are lost when decoded into int"},
+		{Row: 2, Column: "F", Value: "00.5", Message: "value 00.5 has leading zeros that are lost when decoded into float64"},
+		{Row: 2, Column: "IA", Value: "02", Message: "value 02 has leading zeros that are lost when decoded into int"},
+	}
+	assert.Equal(t, expWarnings, reader.Warnings())
+	assert.Equal(t, expWarnings, onWarning)
+	assert.Equal(t, `row 1, column "I": value 01234 has leading zeros that are lost when decoded into int`, expWarnings[0].String())
+}
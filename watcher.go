@@ -0,0 +1,282 @@
+package csvee
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const defaultWatcherPollInterval = time.Second
+
+// defaultDriftSampleRows is the number of records of each file whose types are inferred to detect
+// schema drift unless WatcherOptions.DriftSampleRows says otherwise.
+const defaultDriftSampleRows = 100
+
+// DriftPolicy controls what a Watcher does with a file whose schema differs from that of the first
+// file it processed.
+type DriftPolicy int
+
+const (
+	// DriftIgnore processes files without comparing their schemas.
+	DriftIgnore DriftPolicy = iota
+
+	// DriftWarn reports a *SchemaDriftError to OnError and processes the file anyway.
+	DriftWarn
+
+	// DriftFail reports a *SchemaDriftError to OnError and moves the file to FailedDir without
+	// processing it.
+	DriftFail
+)
+
+// SchemaDriftError reports a file whose columns were added, removed, retyped, or reordered relative
+// to those of the first file a Watcher processed.
+type SchemaDriftError struct {
+	Path string
+	Diff SchemaDiff
+}
+
+func (e *SchemaDriftError) Error() string {
+
+	return fmt.Sprintf("schema of %s differs from the first file: %s", e.Path, e.Diff)
+}
+
+// WatcherOptions configure a Watcher.
+type WatcherOptions struct {
+	// Dir is the directory that is watched for new files.
+	Dir string
+
+	// Pattern is the glob, relative to Dir, that files must match to be processed. Defaults to "*.csv".
+	Pattern string
+
+	// DoneDir and FailedDir are the directories files are moved to after they are processed
+	// successfully or unsuccessfully. A file is never moved over another; if one of the same name is
+	// already there, a numeric suffix is added to the name, as in "data-1.csv".
+	DoneDir   string
+	FailedDir string
+
+	// PollInterval is how often Dir is checked for new files. Defaults to one second.
+	PollInterval time.Duration
+
+	// ReaderOptions are used to construct the Reader for each file. When ReadHeaders is set, each
+	// file is read with its own headers, and per column options naming columns that a file does not
+	// have are ignored for that file, as IgnoreUnknownColumns does.
+	ReaderOptions *ReaderOptions
+
+	// Process is called with a Reader for each file that is found.
+	Process func(r *Reader, path string) error
+
+	// OnError, if set, is called when a file fails to be processed or moved. Files that cannot be
+	// moved are left in Dir but are not processed again by the Watcher.
+	OnError func(path string, err error)
+
+	// SchemaDrift controls whether each file's schema, as InferSchema infers it from the file's
+	// first DriftSampleRows records, is compared to that of the first file the Watcher processed, so
+	// that columns an upstream change adds, removes, or retypes are caught. DriftSampleRows
+	// defaults to 100.
+	SchemaDrift     DriftPolicy
+	DriftSampleRows int
+}
+
+// Watcher monitors a directory for CSV files and processes each one through a Reader, moving it
+// to a done or failed directory afterwards. Files should be moved into the watched directory
+// atomically so that partially written files are not picked up.
+type Watcher struct {
+	options WatcherOptions
+
+	// unmoved holds the paths of files that were processed but could not be moved out of Dir.
+	mu      sync.Mutex
+	unmoved map[string]bool
+
+	// baseline is the schema of the first file processed when SchemaDrift is set.
+	baseline *Schema
+}
+
+// NewWatcher returns a new Watcher configured by options.
+func NewWatcher(options *WatcherOptions) (*Watcher, error) {
+
+	if options == nil || options.Dir == "" || options.DoneDir == "" || options.FailedDir == "" {
+		return nil, ErrWatcherDirsRequired
+	}
+	if options.Process == nil {
+		return nil, ErrWatcherProcessNil
+	}
+
+	wOptions := *options
+	if wOptions.Pattern == "" {
+		wOptions.Pattern = "*.csv"
+	}
+	if wOptions.PollInterval <= 0 {
+		wOptions.PollInterval = defaultWatcherPollInterval
+	}
+	if wOptions.ReaderOptions == nil {
+		wOptions.ReaderOptions = &ReaderOptions{}
+	}
+	if wOptions.DriftSampleRows <= 0 {
+		wOptions.DriftSampleRows = defaultDriftSampleRows
+	}
+
+	for _, dir := range []string{wOptions.DoneDir, wOptions.FailedDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, errors.Wrapf(err, "Could not create directory %s", dir)
+		}
+	}
+
+	return &Watcher{options: wOptions, unmoved: make(map[string]bool)}, nil
+}
+
+// Run scans the directory every poll interval until ctx is done.
+func (w *Watcher) Run(ctx context.Context) error {
+
+	ticker := time.NewTicker(w.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+
+		if err := w.Scan(); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan processes every file currently in the directory that matches the pattern, in name order,
+// skipping files that were processed before but could not be moved.
+func (w *Watcher) Scan() error {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(w.options.Dir, w.options.Pattern))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+
+		if w.unmoved[path] {
+			continue
+		}
+
+		destDir := w.options.DoneDir
+		if err := w.processFile(path); err != nil {
+			destDir = w.options.FailedDir
+			w.reportError(path, err)
+		}
+
+		if err := moveFile(path, destDir); err != nil {
+			w.unmoved[path] = true
+			w.reportError(path, err)
+		}
+	}
+
+	return nil
+}
+
+func (w *Watcher) processFile(path string) error {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	options := *w.options.ReaderOptions
+	if options.ReadHeaders {
+		options.IgnoreUnknownColumns = true
+	}
+
+	if w.options.SchemaDrift != DriftIgnore {
+		if err := w.checkDrift(f, path, &options); err != nil {
+			return err
+		}
+	}
+
+	reader, err := NewReader(f, &options)
+	if err != nil {
+		return err
+	}
+
+	return w.options.Process(reader, path)
+}
+
+// checkDrift infers the schema of the file f at path and compares it to that of the first file
+// checked, reporting or returning a *SchemaDriftError as the SchemaDrift policy says, then rewinds f
+// so that it can be read from the start.
+func (w *Watcher) checkDrift(f *os.File, path string, options *ReaderOptions) error {
+
+	reader, err := NewReader(f, options)
+	if err != nil {
+		return err
+	}
+
+	schema, _, err := inferSchema(reader, w.options.DriftSampleRows)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if w.baseline == nil {
+		w.baseline = &schema
+		return nil
+	}
+
+	diff := CompareSchemas(*w.baseline, schema)
+	if diff.Empty() {
+		return nil
+	}
+
+	driftErr := &SchemaDriftError{Path: path, Diff: diff}
+	if w.options.SchemaDrift == DriftFail {
+		return driftErr
+	}
+
+	w.reportError(path, driftErr)
+	return nil
+}
+
+// moveFile moves the file at path into dir. If dir already holds a file of the same name, a
+// numeric suffix is added to the name rather than replacing it.
+func moveFile(path, dir string) error {
+
+	name := filepath.Base(path)
+	ext := filepath.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+
+	dest := filepath.Join(dir, name)
+	for i := 1; ; i++ {
+		_, err := os.Lstat(dest)
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		dest = filepath.Join(dir, fmt.Sprintf("%s-%d%s", stem, i, ext))
+	}
+
+	return os.Rename(path, dest)
+}
+
+func (w *Watcher) reportError(path string, err error) {
+
+	if w.options.OnError != nil {
+		w.options.OnError(path, err)
+	}
+}
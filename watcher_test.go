@@ -0,0 +1,201 @@
+package csvee
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWatcher_Scan verifies matching files are processed and moved to the done or failed directory
+func TestWatcher_Scan(t *testing.T) {
+
+	root := t.TempDir()
+	inDir := filepath.Join(root, "in")
+	require.NoError(t, os.Mkdir(inDir, 0755))
+
+	files := map[string]string{
+		"a.csv":    "I,S\n1,a\n",
+		"b.csv":    "I,S\nx,b\n",
+		"skip.txt": "I,S\n1,a\n",
+	}
+	for name, data := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(inDir, name), []byte(data), 0644))
+	}
+
+	var processed []string
+	var failed []string
+	watcher, err := NewWatcher(&WatcherOptions{
+		Dir:           inDir,
+		DoneDir:       filepath.Join(root, "done"),
+		FailedDir:     filepath.Join(root, "failed"),
+		ReaderOptions: &ReaderOptions{ReadHeaders: true},
+		Process: func(r *Reader, path string) error {
+			processed = append(processed, filepath.Base(path))
+			var rows []readTo
+			return r.ReadAll(&rows)
+		},
+		OnError: func(path string, err error) {
+			failed = append(failed, filepath.Base(path))
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, watcher.Scan())
+
+	assert.Equal(t, []string{"a.csv", "b.csv"}, processed)
+	assert.Equal(t, []string{"b.csv"}, failed)
+	assert.FileExists(t, filepath.Join(root, "done", "a.csv"))
+	assert.FileExists(t, filepath.Join(root, "failed", "b.csv"))
+	assert.FileExists(t, filepath.Join(inDir, "skip.txt"))
+
+	_, err = NewWatcher(&WatcherOptions{Dir: inDir})
+	assert.Equal(t, ErrWatcherDirsRequired, err)
+}
+
+// TestWatcher_ScanMoves verifies files never replace ones already moved and files that cannot be
+// moved are not processed again
+func TestWatcher_ScanMoves(t *testing.T) {
+
+	root := t.TempDir()
+	inDir := filepath.Join(root, "in")
+	doneDir := filepath.Join(root, "done")
+	require.NoError(t, os.Mkdir(inDir, 0755))
+
+	var processed []string
+	var failed []string
+	watcher, err := NewWatcher(&WatcherOptions{
+		Dir:       inDir,
+		DoneDir:   doneDir,
+		FailedDir: filepath.Join(root, "failed"),
+		ReaderOptions: &ReaderOptions{
+			ReadHeaders:   true,
+			ColumnFormats: map[string]string{"Tu": TimeFormatUnix},
+		},
+		Process: func(r *Reader, path string) error {
+			processed = append(processed, filepath.Base(path))
+			var rows []readTo
+			return r.ReadAll(&rows)
+		},
+		OnError: func(path string, err error) {
+			failed = append(failed, filepath.Base(path))
+		},
+	})
+	require.NoError(t, err)
+
+	// Files with different headers share the watcher's options.
+	require.NoError(t, ioutil.WriteFile(filepath.Join(doneDir, "a.csv"), []byte("earlier"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(inDir, "a.csv"), []byte("I,S\n1,a\n"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(inDir, "b.csv"), []byte("I,Tu\n1,1613235342\n"), 0644))
+	require.NoError(t, watcher.Scan())
+
+	assert.Equal(t, []string{"a.csv", "b.csv"}, processed)
+	assert.Empty(t, failed)
+	earlier, err := ioutil.ReadFile(filepath.Join(doneDir, "a.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, "earlier", string(earlier))
+	assert.FileExists(t, filepath.Join(doneDir, "a-1.csv"))
+	assert.FileExists(t, filepath.Join(doneDir, "b.csv"))
+
+	// Replacing the done directory with a file makes moving into it fail.
+	require.NoError(t, os.RemoveAll(doneDir))
+	require.NoError(t, ioutil.WriteFile(doneDir, nil, 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(inDir, "c.csv"), []byte("I,S\n1,c\n"), 0644))
+
+	processed = nil
+	require.NoError(t, watcher.Scan())
+	require.NoError(t, watcher.Scan())
+
+	assert.Equal(t, []string{"c.csv"}, processed)
+	assert.Equal(t, []string{"c.csv"}, failed)
+	assert.FileExists(t, filepath.Join(inDir, "c.csv"))
+}
+
+// TestWatcher_SchemaDrift verifies files whose schemas differ from the first file's are reported or
+// failed according to the SchemaDrift policy
+func TestWatcher_SchemaDrift(t *testing.T) {
+
+	var testCases = []struct {
+		name         string
+		inPolicy     DriftPolicy
+		expProcessed []string
+		expErrs      []string
+		expFailed    []string
+	}{
+		{
+			name:         "ignore",
+			inPolicy:     DriftIgnore,
+			expProcessed: []string{"a.csv", "b.csv", "c.csv", "d.csv"},
+		},
+		{
+			name:         "warn",
+			inPolicy:     DriftWarn,
+			expProcessed: []string{"a.csv", "b.csv", "c.csv", "d.csv"},
+			expErrs: []string{
+				`b.csv differs from the first file: column "I" changed from int64 to string`,
+				`c.csv differs from the first file: added column "E"`,
+			},
+		},
+		{
+			name:         "fail",
+			inPolicy:     DriftFail,
+			expProcessed: []string{"a.csv", "d.csv"},
+			expErrs: []string{
+				`b.csv differs from the first file: column "I" changed from int64 to string`,
+				`c.csv differs from the first file: added column "E"`,
+			},
+			expFailed: []string{"b.csv", "c.csv"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			root := t.TempDir()
+			inDir := filepath.Join(root, "in")
+			require.NoError(t, os.Mkdir(inDir, 0755))
+
+			files := map[string]string{
+				"a.csv": "I,S\n1,a\n",
+				"b.csv": "I,S\nx,b\n",
+				"c.csv": "I,S,E\n2,c,e\n",
+				"d.csv": "I,S\n3,d\n",
+			}
+			for name, data := range files {
+				require.NoError(t, ioutil.WriteFile(filepath.Join(inDir, name), []byte(data), 0644))
+			}
+
+			var processed []string
+			var errs []string
+			watcher, err := NewWatcher(&WatcherOptions{
+				Dir:           inDir,
+				DoneDir:       filepath.Join(root, "done"),
+				FailedDir:     filepath.Join(root, "failed"),
+				ReaderOptions: &ReaderOptions{ReadHeaders: true},
+				SchemaDrift:   tt.inPolicy,
+				Process: func(r *Reader, path string) error {
+					processed = append(processed, filepath.Base(path))
+					_, err := r.ReadRaw()
+					return err
+				},
+				OnError: func(path string, err error) {
+					var driftErr *SchemaDriftError
+					require.ErrorAs(t, err, &driftErr)
+					assert.Equal(t, path, driftErr.Path)
+					errs = append(errs, strings.TrimPrefix(err.Error(), "schema of "+filepath.Dir(path)+string(filepath.Separator)))
+				},
+			})
+			require.NoError(t, err)
+			require.NoError(t, watcher.Scan())
+
+			assert.Equal(t, tt.expProcessed, processed)
+			assert.Equal(t, tt.expErrs, errs)
+			for _, name := range tt.expFailed {
+				assert.FileExists(t, filepath.Join(root, "failed", name))
+			}
+		})
+	}
+}
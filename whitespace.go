@@ -0,0 +1,40 @@
+package csvee
+
+import (
+	"strings"
+)
+
+// WhitespacePolicy controls how cells that contain only whitespace are decoded.
+type WhitespacePolicy int
+
+const (
+	// WhitespaceLiteral decodes whitespace-only cells as they are. String fields keep the whitespace
+	// and other fields are left at their zero value.
+	WhitespaceLiteral WhitespacePolicy = iota
+
+	// WhitespaceEmpty decodes whitespace-only cells as empty strings.
+	WhitespaceEmpty
+
+	// WhitespaceNull skips whitespace-only cells entirely, leaving fields, including string and
+	// pointer fields, at their zero value.
+	WhitespaceNull
+)
+
+// applyWhitespacePolicy returns the field as it should be decoded under policy and whether the field
+// should be skipped.
+func applyWhitespacePolicy(policy WhitespacePolicy, field string) (string, bool) {
+
+	if policy == WhitespaceLiteral || field == "" || strings.TrimSpace(field) != "" {
+		return field, false
+	}
+
+	return "", policy == WhitespaceNull
+}
+
+// cell returns the field for col as it should be decoded and whether it should be skipped, leaving
+// the target field untouched. Empty cells are skipped when merging.
+func (r *Reader) cell(col columnPlan, record []string) (string, bool) {
+
+	field, skip := applyWhitespacePolicy(r.settings()[col.column].whitespace, record[col.column])
+	return field, skip || r.merge && field == ""
+}
@@ -0,0 +1,72 @@
+package csvee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader_WhitespacePolicy verifies whitespace-only cells are decoded according to the policy
+func TestReader_WhitespacePolicy(t *testing.T) {
+
+	type names struct {
+		S  string
+		SP *string
+	}
+
+	spaces := "  "
+	empty := ""
+
+	var testCases = []struct {
+		name       string
+		inPolicy   WhitespacePolicy
+		inPolicies map[string]WhitespacePolicy
+		expData    names
+	}{
+		{
+			name:     "literal",
+			inPolicy: WhitespaceLiteral,
+			expData:  names{S: "  ", SP: &spaces},
+		},
+		{
+			name:     "empty",
+			inPolicy: WhitespaceEmpty,
+			expData:  names{S: "", SP: &empty},
+		},
+		{
+			name:     "null",
+			inPolicy: WhitespaceNull,
+			expData:  names{},
+		},
+		{
+			name:       "per column",
+			inPolicy:   WhitespaceLiteral,
+			inPolicies: map[string]WhitespacePolicy{"SP": WhitespaceNull},
+			expData:    names{S: "  "},
+		},
+	}
+
+	for _, tt := range testCases {
+		for _, fastPath := range []bool{false, true} {
+			t.Run(tt.name, func(t *testing.T) {
+
+				reader, err := NewReader(
+					strings.NewReader(`"  ","  "`),
+					&ReaderOptions{
+						ColumnNames:        []string{"S", "SP"},
+						WhitespacePolicy:   tt.inPolicy,
+						WhitespacePolicies: tt.inPolicies,
+						UnsafeFastPath:     fastPath,
+					},
+				)
+				require.NoError(t, err)
+
+				var actualData names
+				require.NoError(t, reader.Read(&actualData))
+				assert.Equal(t, tt.expData, actualData)
+			})
+		}
+	}
+}
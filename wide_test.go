@@ -0,0 +1,122 @@
+package csvee
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wideStruct returns a struct type with an int field for every bound'th of n columns, named C0, C1,
+// and so on like the columns of wideData.
+func wideStruct(n, bound int) reflect.Type {
+
+	var fields []reflect.StructField
+	for i := 0; i < n; i += bound {
+		fields = append(fields, reflect.StructField{Name: "C" + strconv.Itoa(i), Type: reflect.TypeOf(0)})
+	}
+
+	return reflect.StructOf(fields)
+}
+
+// wideData returns a header and rows for n columns, where each cell holds its column number.
+func wideData(n, rows int) string {
+
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString("C" + strconv.Itoa(i))
+	}
+	b.WriteByte('\n')
+
+	for row := 0; row < rows; row++ {
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.Itoa(i))
+		}
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+// TestReader_Wide verifies files with thousands of columns bind and decode sparse structs
+func TestReader_Wide(t *testing.T) {
+
+	const columns, bound = 5000, 100
+
+	reader, err := NewReader(strings.NewReader(wideData(columns, 2)), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	vType := wideStruct(columns, bound)
+	rows := reflect.New(reflect.SliceOf(vType))
+	require.NoError(t, reader.ReadAll(rows.Interface()))
+	require.Equal(t, 2, rows.Elem().Len())
+
+	row := rows.Elem().Index(1)
+	for i := 0; i < row.NumField(); i++ {
+		assert.Equal(t, int64(i*bound), row.Field(i).Int())
+	}
+
+	matches, err := reader.ColumnMatches(reflect.New(vType).Interface())
+	require.NoError(t, err)
+	assert.Equal(t, "C4900", matches[4900].Field)
+	assert.Equal(t, MatchNone, matches[4901].Method)
+}
+
+// BenchmarkReader_WideBind measures binding wide headers to a struct with a field for every column.
+func BenchmarkReader_WideBind(b *testing.B) {
+
+	for _, columns := range []int{500, 5000} {
+		b.Run(fmt.Sprintf("columns=%d", columns), func(b *testing.B) {
+
+			vType := wideStruct(columns, 1)
+			columnNames := make([]string, columns)
+			for i := range columnNames {
+				columnNames[i] = "C" + strconv.Itoa(i)
+			}
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := buildDecodePlan(vType, columnNames, planConfig{}, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkReader_WideRead measures decoding rows of a 5000 column file into structs binding some or
+// all of the columns.
+func BenchmarkReader_WideRead(b *testing.B) {
+
+	const columns = 5000
+	inData := wideData(columns, 100)
+
+	for _, bound := range []int{1, 100} {
+		b.Run(fmt.Sprintf("fields=%d", columns/bound), func(b *testing.B) {
+
+			sliceType := reflect.SliceOf(wideStruct(columns, bound))
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				reader, err := NewReader(strings.NewReader(inData), &ReaderOptions{ReadHeaders: true})
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				if err := reader.ReadAll(reflect.New(sliceType).Interface()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package csvee
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// WriteFile writes v, a struct, a slice of structs, or a pointer to either, to the file at path.
+// The rows are written to a temporary file in the same directory that is renamed to path once it is
+// complete, so the file at path is never observed partially written. If options.Sync is set, the
+// data and the rename are synced to disk before WriteFile returns. The file is created with mode
+// 0644.
+func WriteFile(path string, v interface{}, options *WriterOptions) (err error) {
+
+	if err := options.validate(); err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			file.Close()
+			os.Remove(file.Name())
+		}
+	}()
+
+	writer, err := NewWriter(file, options)
+	if err != nil {
+		return err
+	}
+
+	if err := writer.WriteAll(v); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	if err := file.Chmod(0644); err != nil {
+		return err
+	}
+
+	if options.Sync {
+		if err := file.Sync(); err != nil {
+			return err
+		}
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		return err
+	}
+
+	if err := writer.writeSidecar(path); err != nil {
+		return err
+	}
+
+	if options.Sync {
+		return syncDir(filepath.Dir(path))
+	}
+
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file beside path and renames it into place.
+func writeFileAtomic(path string, data []byte, sync bool) error {
+
+	file, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.Write(data); err == nil {
+		err = file.Chmod(0644)
+	}
+	if err == nil && sync {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+
+	if err != nil {
+		os.Remove(file.Name())
+	}
+
+	return err
+}
+
+// syncDir syncs a directory so that a rename within it is durable.
+func syncDir(dir string) error {
+
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	syncErr := d.Sync()
+	if err := d.Close(); syncErr == nil {
+		syncErr = err
+	}
+
+	return syncErr
+}
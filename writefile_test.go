@@ -0,0 +1,73 @@
+package csvee
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriteFile verifies files are written whole or not at all
+func TestWriteFile(t *testing.T) {
+
+	var testCases = []struct {
+		name    string
+		in      interface{}
+		sync    bool
+		expData string
+		expErr  bool
+	}{
+		{
+			name:    "struct",
+			in:      writeFrom{Name: "a"},
+			expData: "Name\na\n",
+		},
+		{
+			name:    "slice",
+			in:      []writeFrom{{Name: "a"}, {Name: "b"}},
+			sync:    true,
+			expData: "Name\na\nb\n",
+		},
+		{
+			name:    "slice pointer",
+			in:      &[]*writeFrom{{Name: "a"}},
+			expData: "Name\na\n",
+		},
+		{
+			name:   "invalid row",
+			in:     []interface{}{writeFrom{Name: "a"}, 1},
+			expErr: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			dir, err := ioutil.TempDir("", "csvee-writefile")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "out.csv")
+			err = WriteFile(path, tt.in, &WriterOptions{ColumnNames: []string{"Name"}, WriteHeaders: true, Sync: tt.sync})
+			require.Equal(t, tt.expErr, err != nil, err)
+
+			entries, err := ioutil.ReadDir(dir)
+			require.NoError(t, err)
+
+			if tt.expErr {
+				assert.Empty(t, entries)
+				return
+			}
+
+			require.Len(t, entries, 1)
+			assert.Equal(t, os.FileMode(0644), entries[0].Mode())
+
+			data, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expData, string(data))
+		})
+	}
+}
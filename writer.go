@@ -0,0 +1,917 @@
+package csvee
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Writer writes structs as CSV rows. It is the inverse of Reader: a row written from a struct reads
+// back into an equal struct with the same column names and formats.
+type Writer struct {
+	ColumnNames []string
+
+	// ColumnFormats maps column names to the format their time fields are written in.
+	ColumnFormats map[string]string
+
+	out            *bufio.Writer
+	part           io.Closer
+	openPart       PartOpener
+	parts          int
+	partRows       int
+	partBytes      int
+	maxRows        int
+	maxBytes       int
+	record         bytes.Buffer
+	recordWriter   *csv.Writer
+	writeHeaders   bool
+	headerNames    []string
+	headerWritten  bool
+	rows           int
+	escapeFormulas bool
+	nullString     string
+
+	sliceDelimiter  string
+	sliceDelimiters map[string]string
+
+	checksum        ChecksumAlgorithm
+	checksumTrailer bool
+	checksumSidecar bool
+	hash            hash.Hash
+	trailerWritten  bool
+	sync            bool
+
+	compression      Compression
+	compressionLevel int
+	compressor       io.WriteCloser
+
+	encrypter Encrypter
+	encryptor io.WriteCloser
+	signer    Signer
+
+	checkpoint      func(rows int64) error
+	checkpointEvery int
+
+	// headerNamer names fields after columns, and namedFields memoizes the names it gives each struct
+	// type's fields.
+	headerNamer HeaderNamer
+	namedFields map[reflect.Type]map[string]reflect.StructField
+
+	// encoding is the output's encoding, and transcoded holds records transcoded to it.
+	encoding   Encoding
+	writeBOM   bool
+	transcoded []byte
+
+	locale        *Locale
+	columnLocales map[string]*Locale
+}
+
+// WriterOptions configures a Writer.
+type WriterOptions struct {
+	// ColumnNames are the columns written, in order. Each is filled from the struct field it maps
+	// to, following the same rules as the Reader; columns with no matching field are left empty.
+	ColumnNames []string
+
+	// WriteHeaders writes ColumnNames as the first row of every part.
+	WriteHeaders bool
+
+	// ColumnFormats maps column names to the format their time fields are written in, as
+	// ReaderOptions.ColumnFormats does for reading. Times in other columns are written as RFC 3339.
+	ColumnFormats map[string]string
+
+	// EscapeFormulas prefixes string cells, and every cell of records passed to WriteRecord, that
+	// begin with =, +, -, @, tab, or carriage return with a single quote so spreadsheet applications
+	// do not evaluate them as formulas. It covers the records that ToCSV, FromJSONLines, ApplyPatch,
+	// MakeDelta, Sort, Dedupe, and Join write.
+	EscapeFormulas bool
+
+	// NullString is written for nil pointers and for the zero values of fields tagged omitempty, so
+	// that a value that was not provided can be told apart from an explicit zero. Defaults to an
+	// empty cell.
+	NullString string
+
+	// SliceDelimiter separates the elements of slice fields within a cell, as
+	// ReaderOptions.SliceDelimiter does for reading. Elements that contain it, or begin with a double
+	// quote, are written in double quotes. SliceDelimiters overrides it for individual columns.
+	// Defaults to ",".
+	SliceDelimiter  string
+	SliceDelimiters map[string]string
+
+	// MaxRowsPerPart and MaxBytesPerPart, if positive, limit the size of each part written by a
+	// Writer from NewPartWriter. Once writing a row would exceed either limit, the current part is
+	// closed and the row is written to a new one. The header does not count toward MaxRowsPerPart.
+	// A part always holds at least one row, even if that row alone exceeds MaxBytesPerPart.
+	MaxRowsPerPart  int
+	MaxBytesPerPart int
+
+	// Sync makes WriteFile sync the file and its directory to disk before returning.
+	Sync bool
+
+	// Checksum, if set, computes a checksum of each part as it is written; see Writer.Checksum.
+	Checksum ChecksumAlgorithm
+
+	// ChecksumTrailer ends each part with a "#<algorithm>=<checksum>" line when it is closed. The
+	// checksum covers everything before the trailer. It requires Checksum.
+	ChecksumTrailer bool
+
+	// ChecksumSidecar writes the checksum of each file written by WriteFile or PartFiles to a file
+	// beside it named with the algorithm as an extension, e.g. "out.csv.sha256". It requires
+	// Checksum.
+	ChecksumSidecar bool
+
+	// Compression compresses the output, including each part, as a stream. CompressionGzip is built
+	// in; any other compression must first be registered with RegisterCompressor. File names are not
+	// changed; Compression.Extension returns the conventional extension. MaxBytesPerPart counts
+	// uncompressed bytes.
+	Compression Compression
+
+	// CompressionLevel is passed to the compressor; 0 selects its default.
+	CompressionLevel int
+
+	// Encrypter, if set, encrypts the output, including each part, as a stream.
+	Encrypter Encrypter
+
+	// Signer, if set, signs each checksum sidecar, writing the signature beside it with a ".sig"
+	// extension, e.g. "out.csv.sha256.sig". It requires ChecksumSidecar.
+	Signer Signer
+
+	// Checkpoint, if set, is called with the number of rows written once every CheckpointEvery
+	// rows, after the Writer has been flushed, so that a job that crashes can resume by skipping the
+	// rows it has already written. Returning an error stops the write that reached the checkpoint,
+	// and the error is returned to its caller. It requires CheckpointEvery.
+	Checkpoint      func(rows int64) error
+	CheckpointEvery int
+
+	// HeaderNamer, if set, fills columns that no field is tagged with or named after from the field
+	// whose Go name it turns into the column's name, such as SnakeCaseName for a "first_name" column
+	// and a FirstName field. ColumnNamesFor lists the columns it names.
+	HeaderNamer HeaderNamer
+
+	// Encoding is the character encoding the output is written in, as ReaderOptions.Encoding is for
+	// reading. Defaults to UTF-8. Writing a character the encoding cannot represent fails.
+	// MaxBytesPerPart counts encoded bytes.
+	Encoding Encoding
+
+	// WriteBOM starts the output, and every part, with the byte order mark of Encoding, which Excel
+	// needs to recognize UTF-8 files. Windows-1252 and ISO-8859-1 have none.
+	WriteBOM bool
+
+	// Locale, if set, formats numbers and times for people in a region to read, such as LocaleDE for
+	// "1.234,5" and "31.12.2021". ColumnLocales overrides it for individual columns; a nil locale
+	// writes the column as usual.
+	Locale        *Locale
+	ColumnLocales map[string]*Locale
+
+	// MaxGroupElements is the number of elements written for each slice or array of structs tagged
+	// with an indexed prefix, such as `csvee:"prefix=item{n}_"`. ColumnNames name their columns with
+	// the placeholder, and each run of adjacent names with the same text before it is repeated for
+	// each element, so that "item{n}_name" and "item{n}_qty" become "item1_name", "item1_qty",
+	// "item2_name", and so on. Columns of missing elements are left empty, and a slice with more
+	// elements fails to be written. ColumnFormats, SliceDelimiters, and ColumnLocales may name
+	// columns the same way.
+	MaxGroupElements int
+}
+
+// PartOpener opens the numbered part, starting from 1, that a Writer rolls over to.
+type PartOpener func(part int) (io.WriteCloser, error)
+
+// PartFiles returns a PartOpener that creates files named by formatting the part number with
+// pattern, e.g. "out/part-%04d.csv".
+func PartFiles(pattern string) PartOpener {
+
+	return func(part int) (io.WriteCloser, error) {
+		return os.Create(fmt.Sprintf(pattern, part))
+	}
+}
+
+// NewWriter returns a Writer that writes to w.
+func NewWriter(w io.Writer, options *WriterOptions) (*Writer, error) {
+
+	if w == nil {
+		return nil, ErrWriterNil
+	}
+
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	if options.MaxRowsPerPart > 0 || options.MaxBytesPerPart > 0 {
+		return nil, ErrPartLimitsRequireOpener
+	}
+
+	writer := newWriter(options)
+	if err := writer.setOutput(w); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// NewPartWriter returns a Writer that writes to parts opened by open, rolling over to a new part
+// whenever the limits in options would be exceeded. Close must be called to close the last part.
+func NewPartWriter(open PartOpener, options *WriterOptions) (*Writer, error) {
+
+	if open == nil {
+		return nil, ErrPartOpenerNil
+	}
+
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	writer := newWriter(options)
+	writer.openPart = open
+	if err := writer.nextPart(); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+func newWriter(options *WriterOptions) *Writer {
+
+	options = options.expanded()
+	writer := &Writer{
+		ColumnNames:    append([]string(nil), options.ColumnNames...),
+		ColumnFormats:  make(map[string]string, len(options.ColumnFormats)),
+		maxRows:        options.MaxRowsPerPart,
+		maxBytes:       options.MaxBytesPerPart,
+		writeHeaders:   options.WriteHeaders,
+		escapeFormulas: options.EscapeFormulas,
+		nullString:     options.NullString,
+
+		checksum:        options.Checksum,
+		checksumTrailer: options.ChecksumTrailer,
+		checksumSidecar: options.ChecksumSidecar,
+		sync:            options.Sync,
+
+		compression:      options.Compression,
+		compressionLevel: options.CompressionLevel,
+
+		encrypter: options.Encrypter,
+		signer:    options.Signer,
+
+		checkpoint:      options.Checkpoint,
+		checkpointEvery: options.CheckpointEvery,
+
+		headerNamer: options.HeaderNamer,
+
+		encoding: options.Encoding,
+		writeBOM: options.WriteBOM,
+
+		locale:        options.Locale,
+		columnLocales: make(map[string]*Locale, len(options.ColumnLocales)),
+	}
+	writer.recordWriter = csv.NewWriter(&writer.record)
+
+	for k, v := range options.ColumnFormats {
+		writer.ColumnFormats[k] = v
+	}
+
+	writer.sliceDelimiter = options.SliceDelimiter
+	if writer.sliceDelimiter == "" {
+		writer.sliceDelimiter = defaultSliceDelimiter
+	}
+	writer.sliceDelimiters = make(map[string]string, len(options.SliceDelimiters))
+	for k, v := range options.SliceDelimiters {
+		writer.sliceDelimiters[k] = v
+	}
+	for k, v := range options.ColumnLocales {
+		writer.columnLocales[k] = v
+	}
+
+	return writer
+}
+
+func (o *WriterOptions) validate() error {
+
+	if o == nil {
+		return ErrWriterOptionsRequired
+	}
+
+	if len(o.ColumnNames) == 0 {
+		return ErrWriterColumnNamesRequired
+	}
+
+	if o.MaxGroupElements < 0 {
+		return errors.Errorf("max group elements must not be negative, got %d", o.MaxGroupElements)
+	}
+	for _, name := range o.ColumnNames {
+		if strings.Contains(name, indexPlaceholder) && o.MaxGroupElements == 0 {
+			return errors.Errorf("column %q is repeated for each group element, which requires max group elements", name)
+		}
+	}
+	o = o.expanded()
+
+	if o.MaxRowsPerPart < 0 {
+		return errors.Errorf("max rows per part must not be negative, got %d", o.MaxRowsPerPart)
+	}
+
+	if o.MaxBytesPerPart < 0 {
+		return errors.Errorf("max bytes per part must not be negative, got %d", o.MaxBytesPerPart)
+	}
+
+	if o.Checksum != "" {
+		if _, valid := o.Checksum.new(); !valid {
+			return errors.Errorf("unknown checksum algorithm %q", o.Checksum)
+		}
+	} else if o.ChecksumTrailer || o.ChecksumSidecar {
+		return errors.New("a checksum algorithm is required for a checksum trailer or sidecar")
+	}
+
+	if o.Compression != "" {
+		if _, err := o.Compression.compressor(); err != nil {
+			return err
+		}
+		if o.ChecksumTrailer {
+			return errors.New("a checksum trailer cannot be combined with compression")
+		}
+	}
+
+	if o.Signer != nil && !o.ChecksumSidecar {
+		return errors.New("a signer requires a checksum sidecar")
+	}
+
+	if o.Encrypter != nil && o.ChecksumTrailer {
+		return errors.New("a checksum trailer cannot be combined with encryption")
+	}
+
+	if !o.Encoding.Valid() {
+		return errors.Errorf("unknown encoding %q", o.Encoding)
+	}
+	if o.WriteBOM && o.Encoding.bom() == nil {
+		return errors.Errorf("%s has no byte order mark", o.Encoding)
+	}
+	if o.ChecksumTrailer && (o.Encoding == EncodingUTF16LE || o.Encoding == EncodingUTF16BE) {
+		return errors.Errorf("a checksum trailer cannot be combined with %s output", o.Encoding)
+	}
+
+	if o.CheckpointEvery < 0 {
+		return errors.Errorf("checkpoint interval must not be negative, got %d", o.CheckpointEvery)
+	}
+	if (o.Checkpoint == nil) != (o.CheckpointEvery == 0) {
+		return errors.New("a checkpoint requires both a callback and an interval")
+	}
+
+	if err := validateColumnNames(o.ColumnNames, false); err != nil {
+		return err
+	}
+
+	if err := validateColumnFormats(o.ColumnFormats); err != nil {
+		return err
+	}
+
+	if o.SliceDelimiter != "" {
+		if err := validateSliceDelimiter(o.SliceDelimiter); err != nil {
+			return err
+		}
+	}
+	for _, column := range sortedKeys(o.SliceDelimiters) {
+		delimiter := o.SliceDelimiters[column]
+		if err := validateSliceDelimiter(delimiter); err != nil {
+			return errors.Wrapf(err, "column %q", column)
+		}
+	}
+	known := make(map[string]bool, len(o.ColumnNames))
+	for _, name := range o.ColumnNames {
+		known[name] = true
+	}
+	for _, column := range sortedKeys(o.SliceDelimiters) {
+		if !known[column] {
+			return errors.Errorf("slice delimiter provided for unknown column %q", column)
+		}
+	}
+
+	if o.Locale != nil {
+		if err := o.Locale.validate(); err != nil {
+			return err
+		}
+	}
+	for _, column := range sortedKeys(o.ColumnLocales) {
+		if !known[column] {
+			return errors.Errorf("locale provided for unknown column %q", column)
+		}
+		if locale := o.ColumnLocales[column]; locale != nil {
+			if err := locale.validate(); err != nil {
+				return errors.Wrapf(err, "column %q", column)
+			}
+		}
+	}
+
+	return validateFormatColumns(o.ColumnFormats, o.ColumnNames)
+}
+
+// Write writes v, a struct or a pointer to one, as a single row. Rows are buffered until Flush or
+// Close is called.
+func (w *Writer) Write(v interface{}) error {
+
+	record, err := w.structRecord(v, w.rows+1)
+	if err != nil {
+		return err
+	}
+
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	return w.writeRecord(record)
+}
+
+// structRecord formats the fields of v, a struct or a pointer to one, as the cells of each column.
+// row numbers the record in errors.
+func (w *Writer) structRecord(v interface{}, row int) ([]string, error) {
+
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, ErrWriteSourceNil
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil, ErrUnsupportedSourceType
+	}
+
+	var groups []*writtenGroup
+
+	record := make([]string, len(w.ColumnNames))
+	for i, column := range w.ColumnNames {
+
+		var fieldValue reflect.Value
+		field, exists := fieldForColumn(value.Type(), column)
+		if !exists && w.headerNamer != nil {
+			field, exists = w.namedField(value.Type(), column)
+		}
+		if exists {
+			fieldValue, exists = fieldByIndex(value, field.Index)
+		} else if field, exists = prefixedFieldForColumn(value.Type(), column, ""); exists {
+			fieldValue, exists = fieldByIndex(value, field.Index)
+		} else {
+			field, fieldValue, exists = groupElementField(value, column, &groups)
+		}
+		if !exists {
+			continue
+		}
+
+		if parseFieldTag(field).omitEmpty && isEmptyValue(fieldValue) {
+			record[i] = w.nullString
+			continue
+		}
+
+		cell, err := w.formatValue(fieldValue, Format(w.ColumnFormats[column]), w.columnSliceDelimiter(column), w.columnLocale(column))
+		if err != nil {
+			return nil, &FieldError{Row: row, Column: column, Err: err}
+		}
+		record[i] = cell
+	}
+
+	if err := checkWrittenGroups(groups, row); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// namedField returns the field of vType that the HeaderNamer names column.
+func (w *Writer) namedField(vType reflect.Type, column string) (reflect.StructField, bool) {
+
+	fields, exists := w.namedFields[vType]
+	if !exists {
+		if w.namedFields == nil {
+			w.namedFields = make(map[reflect.Type]map[string]reflect.StructField)
+		}
+		fields = namedFields(vType, w.headerNamer)
+		w.namedFields[vType] = fields
+	}
+
+	field, exists := fields[column]
+	return field, exists
+}
+
+// WriteRecord writes record, which must have a cell for each column, as a single row. With
+// EscapeFormulas, every cell that spreadsheet applications would evaluate as a formula is escaped.
+func (w *Writer) WriteRecord(record []string) error {
+
+	if len(record) != len(w.ColumnNames) {
+		return ErrColumnNamesMismatch
+	}
+
+	if w.escapeFormulas {
+		escaped := make([]string, len(record))
+		for i, cell := range record {
+			escaped[i] = escapeFormula(cell)
+		}
+		record = escaped
+	}
+
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	return w.writeRecord(record)
+}
+
+func (w *Writer) writeRecord(record []string) error {
+
+	encoded, err := w.encode(record)
+	if err != nil {
+		return err
+	}
+
+	if w.partFull(len(encoded)) {
+		// Writing the header reuses the encoding buffer.
+		encoded = append([]byte(nil), encoded...)
+		if err := w.nextPart(); err != nil {
+			return err
+		}
+		if err := w.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	if err := w.writeEncoded(encoded); err != nil {
+		return err
+	}
+
+	w.rows++
+	w.partRows++
+	return w.reachCheckpoint()
+}
+
+// WriteAll writes v, a slice of structs or of pointers to structs, as one row per element. It also
+// accepts a pointer to a slice, or a single struct. Rows are buffered until Flush or Close is
+// called.
+func (w *Writer) WriteAll(v interface{}) error {
+
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr && !value.IsNil() && value.Elem().Kind() == reflect.Slice {
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Slice {
+		return w.Write(v)
+	}
+
+	for i := 0; i < value.Len(); i++ {
+		if err := w.Write(value.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Flush writes any buffered rows to the underlying io.Writer, including the header if no rows
+// have been written.
+func (w *Writer) Flush() error {
+
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	if err := w.out.Flush(); err != nil {
+		return err
+	}
+
+	if f, canFlush := w.compressor.(flusher); canFlush {
+		if err := f.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if f, canFlush := w.encryptor.(flusher); canFlush {
+		return f.Flush()
+	}
+
+	return nil
+}
+
+// Close flushes the Writer, writes the checksum trailer if one was requested, and closes the
+// current part if it was opened by a PartOpener. It does not close the io.Writer passed to
+// NewWriter.
+func (w *Writer) Close() error {
+
+	err := w.Flush()
+	if err == nil {
+		err = w.writeTrailer()
+	}
+
+	if w.compressor != nil {
+		if closeErr := w.compressor.Close(); err == nil {
+			err = closeErr
+		}
+		w.compressor = nil
+	}
+
+	if w.encryptor != nil {
+		if closeErr := w.encryptor.Close(); err == nil {
+			err = closeErr
+		}
+		w.encryptor = nil
+	}
+
+	if w.part != nil {
+		if closeErr := w.part.Close(); err == nil {
+			err = closeErr
+		}
+		if file, isFile := w.part.(*os.File); isFile && err == nil {
+			err = w.writeSidecar(file.Name())
+		}
+		w.part = nil
+	}
+
+	return err
+}
+
+// Parts returns the number of parts opened so far.
+func (w *Writer) Parts() int {
+
+	return w.parts
+}
+
+// partFull reports whether a row of size bytes must go to a new part.
+func (w *Writer) partFull(size int) bool {
+
+	if w.openPart == nil || w.partRows == 0 {
+		return false
+	}
+
+	return (w.maxRows > 0 && w.partRows >= w.maxRows) ||
+		(w.maxBytes > 0 && w.partBytes+size > w.maxBytes)
+}
+
+// nextPart closes the current part, if any, and opens the next one.
+func (w *Writer) nextPart() error {
+
+	if w.part != nil {
+		if err := w.Close(); err != nil {
+			return err
+		}
+	}
+
+	part, err := w.openPart(w.parts + 1)
+	if err != nil {
+		return errors.Wrapf(err, "Could not open part %d", w.parts+1)
+	}
+
+	w.parts++
+	w.part = part
+	w.partRows = 0
+	w.partBytes = 0
+	w.headerWritten = false
+	return w.setOutput(part)
+}
+
+// setOutput directs the writer's output to a new destination. The checksum is computed over the
+// bytes reaching the destination, after compression and encryption.
+func (w *Writer) setOutput(dst io.Writer) error {
+
+	w.trailerWritten = false
+	if h, exists := w.checksum.new(); exists {
+		w.hash = h
+		dst = io.MultiWriter(dst, h)
+	}
+
+	if w.encrypter != nil {
+		var err error
+		if w.encryptor, err = w.encrypter.Encrypt(dst); err != nil {
+			return errors.Wrap(err, "Could not start encryption")
+		}
+		dst = w.encryptor
+	}
+
+	if w.compression != "" {
+		compress, err := w.compression.compressor()
+		if err != nil {
+			return err
+		}
+
+		if w.compressor, err = compress(dst, w.compressionLevel); err != nil {
+			return err
+		}
+		dst = w.compressor
+	}
+
+	w.out = bufio.NewWriter(dst)
+	if w.writeBOM {
+		return w.writeEncoded(w.encoding.bom())
+	}
+
+	return nil
+}
+
+// overrideHeader sets the header row written as mode directs, with original as the names
+// HeaderOriginal writes, until the returned function restores the writer's own.
+func (w *Writer) overrideHeader(mode HeaderMode, original []string) func() {
+
+	writeHeaders, headerNames := w.writeHeaders, w.headerNames
+	switch mode {
+	case HeaderRenamed:
+		w.writeHeaders = true
+	case HeaderOriginal:
+		w.writeHeaders, w.headerNames = true, original
+	case HeaderSuppress:
+		w.writeHeaders = false
+	}
+
+	return func() {
+		w.writeHeaders, w.headerNames = writeHeaders, headerNames
+	}
+}
+
+func (w *Writer) writeHeader() error {
+
+	if !w.writeHeaders || w.headerWritten {
+		return nil
+	}
+
+	names := w.ColumnNames
+	if w.headerNames != nil {
+		names = w.headerNames
+	}
+
+	encoded, err := w.encode(names)
+	if err != nil {
+		return err
+	}
+
+	w.headerWritten = true
+	return w.writeEncoded(encoded)
+}
+
+// encode encodes record as a CSV line. The returned bytes are only valid until the next call.
+func (w *Writer) encode(record []string) ([]byte, error) {
+
+	w.record.Reset()
+	if err := w.recordWriter.Write(record); err != nil {
+		return nil, err
+	}
+
+	w.recordWriter.Flush()
+	if err := w.recordWriter.Error(); err != nil {
+		return nil, err
+	}
+
+	if w.encoding == EncodingDetect || w.encoding == EncodingUTF8 {
+		return w.record.Bytes(), nil
+	}
+
+	var err error
+	w.transcoded, err = appendEncoded(w.transcoded[:0], w.record.Bytes(), w.encoding)
+	return w.transcoded, err
+}
+
+func (w *Writer) writeEncoded(encoded []byte) error {
+
+	n, err := w.out.Write(encoded)
+	w.partBytes += n
+	return err
+}
+
+// fieldByIndex returns the nested field of value at index. It reports false if the field is
+// promoted through a nil embedded struct pointer, in which case its column is left empty.
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, bool) {
+
+	for i, x := range index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return reflect.Value{}, false
+			}
+			value = value.Elem()
+		}
+		value = value.Field(x)
+	}
+
+	return value, true
+}
+
+// columnSliceDelimiter returns the delimiter that separates slice elements in the named column.
+func (w *Writer) columnSliceDelimiter(column string) string {
+
+	if delimiter, exists := w.sliceDelimiters[column]; exists {
+		return delimiter
+	}
+	if w.sliceDelimiter == "" {
+		return defaultSliceDelimiter
+	}
+
+	return w.sliceDelimiter
+}
+
+// formatValue formats a field value as a cell, writing times in format if one is given, and numbers
+// and times as locale formats them if it is not nil. Values that implement encoding.TextMarshaler
+// are written as they marshal. Slices are written with their
+// elements separated by delimiter and quoted where needed, as the Reader expects them.
+func (w *Writer) formatValue(value reflect.Value, format Format, delimiter string, locale *Locale) (string, error) {
+
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return w.nullString, nil
+		}
+		value = value.Elem()
+	}
+
+	if marshaler, isMarshaler := textMarshaler(value); isMarshaler {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+
+	if t, isTime := value.Interface().(time.Time); isTime {
+		if locale != nil {
+			return locale.formatTime(t, format)
+		}
+		if format == "" {
+			return t.Format(time.RFC3339Nano), nil
+		}
+		return format.Format(t)
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		if w.escapeFormulas {
+			return escapeFormula(value.String()), nil
+		}
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if locale != nil {
+			return locale.formatInt(strconv.FormatInt(value.Int(), 10)), nil
+		}
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if locale != nil {
+			return locale.formatInt(strconv.FormatUint(value.Uint(), 10)), nil
+		}
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		if locale != nil {
+			return locale.formatFloat(value.Float(), value.Type().Bits()), nil
+		}
+		return strconv.FormatFloat(value.Float(), 'g', -1, value.Type().Bits()), nil
+	case reflect.Slice:
+		cells := make([]string, value.Len())
+		for i := range cells {
+			cell, err := w.formatValue(value.Index(i), format, delimiter, locale)
+			if err != nil {
+				return "", err
+			}
+			cells[i] = quoteSliceElement(cell, delimiter)
+		}
+		return strings.Join(cells, delimiter), nil
+	}
+
+	return "", ErrInvalidFieldType
+}
+
+// textMarshaler returns value's encoding.TextMarshaler, including through a pointer receiver if
+// value is addressable. Times are formatted by formatValue instead.
+func textMarshaler(value reflect.Value) (encoding.TextMarshaler, bool) {
+
+	if isTimeType(value.Type()) {
+		return nil, false
+	}
+	if marshaler, isMarshaler := value.Interface().(encoding.TextMarshaler); isMarshaler {
+		return marshaler, true
+	}
+	if value.CanAddr() {
+		marshaler, isMarshaler := value.Addr().Interface().(encoding.TextMarshaler)
+		return marshaler, isMarshaler
+	}
+
+	return nil, false
+}
+
+// isEmptyValue reports whether a field tagged omitempty holds no value: its zero value, or an empty
+// slice or map.
+func isEmptyValue(value reflect.Value) bool {
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	}
+
+	return value.IsZero()
+}
+
+// escapeFormula neutralizes cells that spreadsheet applications would evaluate as formulas.
+func escapeFormula(cell string) string {
+
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+
+	return cell
+}
@@ -0,0 +1,399 @@
+package csvee
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type writeFrom struct {
+	Name   string
+	Email  string `csvee:"email"`
+	Count  int
+	Ratio  float64
+	Ok     bool
+	Tags   []string
+	When   time.Time
+	Parent *string
+}
+
+// TestWriter_Write verifies structs are written as rows in column order
+func TestWriter_Write(t *testing.T) {
+
+	when := time.Date(2021, time.February, 13, 16, 55, 42, 0, time.UTC)
+
+	var testCases = []struct {
+		name    string
+		options WriterOptions
+		in      []interface{}
+		expData string
+	}{
+		{
+			name:    "headers",
+			options: WriterOptions{ColumnNames: []string{"Name", "email", "Count", "Ratio", "Ok", "Tags", "When", "Parent", "Missing"}, WriteHeaders: true},
+			in:      []interface{}{writeFrom{Name: "a", Email: "a@x.io", Count: -2, Ratio: 0.5, Ok: true, Tags: []string{"x", "y"}, When: when}},
+			expData: "Name,email,Count,Ratio,Ok,Tags,When,Parent,Missing\na,a@x.io,-2,0.5,true,\"x,y\",2021-02-13T16:55:42Z,,\n",
+		},
+		{
+			name:    "no rows",
+			options: WriterOptions{ColumnNames: []string{"Name"}, WriteHeaders: true},
+			expData: "Name\n",
+		},
+		{
+			name:    "pointer",
+			options: WriterOptions{ColumnNames: []string{"Count", "Name"}},
+			in:      []interface{}{&writeFrom{Name: "b", Count: 3}, writeFrom{Name: "c"}},
+			expData: "3,b\n0,c\n",
+		},
+		{
+			name:    "formulas not escaped",
+			options: WriterOptions{ColumnNames: []string{"Name", "Count"}},
+			in:      []interface{}{writeFrom{Name: "=1+1", Count: -1}},
+			expData: "=1+1,-1\n",
+		},
+		{
+			name:    "formulas escaped",
+			options: WriterOptions{ColumnNames: []string{"Name", "Count", "Tags"}, EscapeFormulas: true},
+			in: []interface{}{
+				writeFrom{Name: "=HYPERLINK(\"http://x\")", Count: -1, Tags: []string{"@SUM(A1)", "ok"}},
+				writeFrom{Name: "+1"},
+				writeFrom{Name: "-1"},
+				writeFrom{Name: "\tx"},
+				writeFrom{Name: "a=b"},
+			},
+			expData: "\"'=HYPERLINK(\"\"http://x\"\")\",-1,\"'@SUM(A1),ok\"\n'+1,0,\n'-1,0,\n'\tx,0,\na=b,0,\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var buf bytes.Buffer
+			writer, err := NewWriter(&buf, &tt.options)
+			require.NoError(t, err)
+
+			for _, v := range tt.in {
+				require.NoError(t, writer.Write(v))
+			}
+			require.NoError(t, writer.Flush())
+
+			assert.Equal(t, tt.expData, buf.String())
+		})
+	}
+}
+
+// TestWriter_OmitEmpty verifies zero values of fields tagged omitempty are written as the null
+// string, while explicit values and untagged zeros are written as usual
+func TestWriter_OmitEmpty(t *testing.T) {
+
+	type row struct {
+		Name   string
+		Count  int       `csvee:"Count,omitempty"`
+		Ok     bool      `csvee:",omitempty"`
+		Tags   []string  `csvee:"Tags,omitempty"`
+		When   time.Time `csvee:"When,omitempty"`
+		Score  *int      `csvee:"Score,omitempty"`
+		Amount float64
+	}
+
+	zero := 0
+	columns := []string{"Name", "Count", "Ok", "Tags", "When", "Score", "Amount"}
+
+	var testCases = []struct {
+		name    string
+		options WriterOptions
+		in      row
+		expData string
+	}{
+		{
+			name:    "empty cells",
+			options: WriterOptions{ColumnNames: columns},
+			in:      row{Name: "a", Tags: []string{}},
+			expData: "a,,,,,,0\n",
+		},
+		{
+			name:    "null string",
+			options: WriterOptions{ColumnNames: columns, NullString: "NULL"},
+			in:      row{Name: "a"},
+			expData: "a,NULL,NULL,NULL,NULL,NULL,0\n",
+		},
+		{
+			name:    "explicit zero through a pointer",
+			options: WriterOptions{ColumnNames: columns, NullString: "NULL"},
+			in:      row{Name: "a", Count: 2, Ok: true, Tags: []string{"x"}, Score: &zero},
+			expData: "a,2,true,x,NULL,0,0\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var buf bytes.Buffer
+			writer, err := NewWriter(&buf, &tt.options)
+			require.NoError(t, err)
+
+			require.NoError(t, writer.Write(tt.in))
+			require.NoError(t, writer.Flush())
+			assert.Equal(t, tt.expData, buf.String())
+		})
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+
+		var buf bytes.Buffer
+		writer, err := NewWriter(&buf, &WriterOptions{ColumnNames: columns, WriteHeaders: true, NullString: "NULL"})
+		require.NoError(t, err)
+
+		in := []row{{Name: "a", Score: &zero}, {Name: "b", Count: 3}}
+		for _, v := range in {
+			require.NoError(t, writer.Write(v))
+		}
+		require.NoError(t, writer.Flush())
+
+		reader, err := NewReader(&buf, &ReaderOptions{ReadHeaders: true, NullValues: []string{"NULL"}})
+		require.NoError(t, err)
+
+		for _, exp := range in {
+			var actual row
+			require.NoError(t, reader.Read(&actual))
+			assert.Equal(t, exp, actual)
+		}
+	})
+}
+
+// TestWriter_Errors verifies invalid writers and sources are rejected
+func TestWriter_Errors(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	_, err := NewWriter(nil, &WriterOptions{ColumnNames: []string{"Name"}})
+	assert.Equal(t, ErrWriterNil, err)
+
+	_, err = NewWriter(&buf, nil)
+	assert.Equal(t, ErrWriterOptionsRequired, err)
+
+	_, err = NewWriter(&buf, &WriterOptions{})
+	assert.Equal(t, ErrWriterColumnNamesRequired, err)
+
+	writer, err := NewWriter(&buf, &WriterOptions{ColumnNames: []string{"Name", "Bad"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, ErrWriteSourceNil, writer.Write((*writeFrom)(nil)))
+	assert.Equal(t, ErrUnsupportedSourceType, writer.Write("x"))
+	assert.Equal(t, ErrColumnNamesMismatch, writer.WriteRecord([]string{"a"}))
+
+	err = writer.Write(struct {
+		Name string
+		Bad  map[string]int
+	}{})
+	assert.EqualError(t, err, `row 1, column "Bad": `+ErrInvalidFieldType.Error())
+}
+
+type partBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *partBuffer) Close() error {
+
+	b.closed = true
+	return nil
+}
+
+// TestWriter_Parts verifies the writer rolls over to a new part when a part limit would be exceeded
+func TestWriter_Parts(t *testing.T) {
+
+	var testCases = []struct {
+		name     string
+		options  WriterOptions
+		rows     int
+		expParts []string
+	}{
+		{
+			name:     "no limits",
+			options:  WriterOptions{ColumnNames: []string{"Count"}, WriteHeaders: true},
+			rows:     3,
+			expParts: []string{"Count\n0\n1\n2\n"},
+		},
+		{
+			name:     "max rows",
+			options:  WriterOptions{ColumnNames: []string{"Count"}, WriteHeaders: true, MaxRowsPerPart: 2},
+			rows:     5,
+			expParts: []string{"Count\n0\n1\n", "Count\n2\n3\n", "Count\n4\n"},
+		},
+		{
+			name:     "max bytes",
+			options:  WriterOptions{ColumnNames: []string{"Count"}, MaxBytesPerPart: 5},
+			rows:     12,
+			expParts: []string{"0\n1\n", "2\n3\n", "4\n5\n", "6\n7\n", "8\n9\n", "10\n", "11\n"},
+		},
+		{
+			name:     "byte order marks",
+			options:  WriterOptions{ColumnNames: []string{"Count"}, WriteHeaders: true, MaxRowsPerPart: 1, WriteBOM: true},
+			rows:     2,
+			expParts: []string{"\ufeffCount\n0\n", "\ufeffCount\n1\n"},
+		},
+		{
+			name:     "row larger than max bytes",
+			options:  WriterOptions{ColumnNames: []string{"Count"}, WriteHeaders: true, MaxBytesPerPart: 1},
+			rows:     2,
+			expParts: []string{"Count\n0\n", "Count\n1\n"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var parts []*partBuffer
+			writer, err := NewPartWriter(func(part int) (io.WriteCloser, error) {
+				require.Equal(t, len(parts)+1, part)
+				parts = append(parts, &partBuffer{})
+				return parts[len(parts)-1], nil
+			}, &tt.options)
+			require.NoError(t, err)
+
+			for i := 0; i < tt.rows; i++ {
+				require.NoError(t, writer.Write(writeFrom{Count: i}))
+			}
+			require.NoError(t, writer.Close())
+
+			actualParts := make([]string, len(parts))
+			for i, part := range parts {
+				assert.True(t, part.closed)
+				actualParts[i] = part.String()
+			}
+			assert.Equal(t, tt.expParts, actualParts)
+			assert.Equal(t, len(tt.expParts), writer.Parts())
+		})
+	}
+}
+
+// TestPartFiles verifies parts are written to numbered files
+func TestPartFiles(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "csvee-parts")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writer, err := NewPartWriter(PartFiles(filepath.Join(dir, "part-%04d.csv")), &WriterOptions{ColumnNames: []string{"Name"}, MaxRowsPerPart: 1})
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(writeFrom{Name: "a"}))
+	require.NoError(t, writer.Write(writeFrom{Name: "b"}))
+	require.NoError(t, writer.Close())
+
+	for name, expData := range map[string]string{"part-0001.csv": "a\n", "part-0002.csv": "b\n"} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, expData, string(data))
+	}
+
+	_, err = NewWriter(&bytes.Buffer{}, &WriterOptions{ColumnNames: []string{"Name"}, MaxRowsPerPart: 1})
+	assert.Equal(t, ErrPartLimitsRequireOpener, err)
+
+	_, err = NewPartWriter(nil, &WriterOptions{ColumnNames: []string{"Name"}})
+	assert.Equal(t, ErrPartOpenerNil, err)
+}
+
+// TestWriter_WriteAll verifies slices written with column formats read back unchanged
+func TestWriter_WriteAll(t *testing.T) {
+
+	nine := 9
+	when := time.Unix(1613235342, 0)
+
+	inData := []readTo{
+		{nestedReadTo: nestedReadTo{NS: "n"}, F: 1.5, I: 2, B: true, S: "a \"quoted\", value", IP: &nine, IA: []int{1, 2}, SA: []string{"x", "y"}, Tu: when, T: when.UTC()},
+		{S: "b", IA: []int{}, SA: []string{""}, Tu: when, T: when.UTC()},
+	}
+
+	columns := []string{"NS", "F", "I", "B", "S", "IP", "IA", "SA", "Tu", "T"}
+	formats := map[string]string{"Tu": TimeFormatUnix, "T": "2006-01-02 15:04:05"}
+
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, &WriterOptions{ColumnNames: columns, ColumnFormats: formats, WriteHeaders: true})
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteAll(&inData))
+	require.NoError(t, writer.Flush())
+
+	assert.Equal(t,
+		"NS,F,I,B,S,IP,IA,SA,Tu,T\n"+
+			"n,1.5,2,true,\"a \"\"quoted\"\", value\",9,\"1,2\",\"x,y\",1613235342,2021-02-13 16:55:42\n"+
+			",0,0,false,b,,,,1613235342,2021-02-13 16:55:42\n",
+		buf.String())
+
+	reader, err := NewReader(&buf, &ReaderOptions{ReadHeaders: true, ColumnFormats: formats})
+	require.NoError(t, err)
+
+	var actualData []readTo
+	require.NoError(t, reader.ReadAll(&actualData))
+	assert.Equal(t, inData, actualData)
+}
+
+// TestWriter_ColumnFormats verifies column formats are validated
+func TestWriter_ColumnFormats(t *testing.T) {
+
+	_, err := NewWriter(&bytes.Buffer{}, &WriterOptions{ColumnNames: []string{"When"}, ColumnFormats: map[string]string{"When": "nope"}})
+	assert.Error(t, err)
+
+	_, err = NewWriter(&bytes.Buffer{}, &WriterOptions{ColumnNames: []string{"When"}, ColumnFormats: map[string]string{"Other": TimeFormatUnix}})
+	assert.Error(t, err)
+
+	RegisterFormat("parse-only", func(field string) (time.Time, error) { return time.Time{}, nil })
+	writer, err := NewWriter(&bytes.Buffer{}, &WriterOptions{ColumnNames: []string{"When"}, ColumnFormats: map[string]string{"When": "parse-only"}})
+	require.NoError(t, err)
+	assert.EqualError(t, writer.Write(writeFrom{}), `row 1, column "When": format "parse-only" has no registered formatter`)
+}
+
+// TestWriter_GroupColumns verifies slices of structs are written to numbered groups of columns
+func TestWriter_GroupColumns(t *testing.T) {
+
+	var buf bytes.Buffer
+	options := &WriterOptions{
+		ColumnNames:      []string{"ID", "item{n}_name", "item{n}_qty", "billing_city"},
+		WriteHeaders:     true,
+		MaxGroupElements: 3,
+	}
+
+	writer, err := NewWriter(&buf, options)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ID", "item1_name", "item1_qty", "item2_name", "item2_qty", "item3_name", "item3_qty", "billing_city"}, writer.ColumnNames)
+
+	orders := []prefixedGroupedOrder{
+		{ID: 1, Items: []groupedItem{{Name: "apple", Qty: 3}, {Name: "pear", Qty: 1}}, Billing: nestedAddress{City: "Springfield"}},
+		{ID: 2},
+	}
+	require.NoError(t, writer.WriteAll(orders))
+	require.NoError(t, writer.Flush())
+	assert.Equal(t, "ID,item1_name,item1_qty,item2_name,item2_qty,item3_name,item3_qty,billing_city\n"+
+		"1,apple,3,pear,1,,,Springfield\n"+
+		"2,,,,,,,\n", buf.String())
+
+	reader, err := NewReader(strings.NewReader(buf.String()), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	var read []prefixedGroupedOrder
+	require.NoError(t, reader.ReadAll(&read))
+	assert.Equal(t, orders[0], read[0])
+
+	err = writer.Write(prefixedGroupedOrder{ID: 3, Items: make([]groupedItem, 4)})
+	assert.EqualError(t, err, `row 3, column "item1_name": 4 elements do not fit in the columns of 3`)
+
+	_, err = NewWriter(&buf, &WriterOptions{ColumnNames: []string{"item{n}_name"}})
+	assert.EqualError(t, err, `column "item{n}_name" is repeated for each group element, which requires max group elements`)
+
+	_, err = NewWriter(&buf, &WriterOptions{ColumnNames: []string{"ID"}, MaxGroupElements: -1})
+	assert.EqualError(t, err, "max group elements must not be negative, got -1")
+}
+
+type prefixedGroupedOrder struct {
+	ID      int
+	Items   []groupedItem `csvee:"prefix=item{n}_"`
+	Billing nestedAddress `csvee:"prefix=billing_"`
+}
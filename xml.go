@@ -0,0 +1,275 @@
+package csvee
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// XMLOptions configures an XMLEncoder.
+type XMLOptions struct {
+	// ColumnNames are the columns written, in order, as elements of each row. Each is filled from
+	// the struct field it maps to, following the same rules as the Writer.
+	ColumnNames []string
+
+	// ColumnFormats maps column names to the format their time fields are written in.
+	ColumnFormats map[string]string
+
+	// ElementNames maps column names to the names of their elements. Other columns' elements are
+	// named after the column, with characters that are not allowed in XML names replaced by
+	// underscores.
+	ElementNames map[string]string
+
+	// RootElement and RowElement name the element enclosing the rows and the element of each row.
+	// They default to "rows" and "row".
+	RootElement string
+	RowElement  string
+
+	// Indent, if not empty, puts each element on its own line, indented by this string per level.
+	Indent string
+}
+
+// XMLEncoder writes structs or records as XML, for systems that cannot consume CSV:
+//
+//	<rows><row><Name>Ann</Name><Age>34</Age></row></rows>
+//
+// Close must be called to end the root element.
+type XMLEncoder struct {
+	encoder  *xml.Encoder
+	cells    *Writer
+	elements []xml.Name
+	root     xml.StartElement
+	row      xml.StartElement
+	started  bool
+	rows     int
+}
+
+// NewXMLEncoder returns an XMLEncoder that writes to w.
+func NewXMLEncoder(w io.Writer, options *XMLOptions) (*XMLEncoder, error) {
+
+	if w == nil {
+		return nil, ErrWriterNil
+	}
+
+	if options == nil {
+		return nil, ErrWriterOptionsRequired
+	}
+
+	if len(options.ColumnNames) == 0 {
+		return nil, ErrWriterColumnNamesRequired
+	}
+
+	if err := validateColumnNames(options.ColumnNames, false); err != nil {
+		return nil, err
+	}
+
+	if err := validateColumnFormats(options.ColumnFormats); err != nil {
+		return nil, err
+	}
+
+	if err := validateFormatColumns(options.ColumnFormats, options.ColumnNames); err != nil {
+		return nil, err
+	}
+
+	root, row := options.RootElement, options.RowElement
+	if root == "" {
+		root = "rows"
+	}
+	if row == "" {
+		row = "row"
+	}
+	for _, name := range []string{root, row} {
+		if !validXMLName(name) {
+			return nil, errors.Errorf("invalid element name %q", name)
+		}
+	}
+
+	elements, err := xmlElementNames(options.ColumnNames, options.ElementNames)
+	if err != nil {
+		return nil, err
+	}
+
+	encoder := xml.NewEncoder(w)
+	if options.Indent != "" {
+		encoder.Indent("", options.Indent)
+	}
+
+	return &XMLEncoder{
+		encoder:  encoder,
+		cells:    newWriter(&WriterOptions{ColumnNames: options.ColumnNames, ColumnFormats: options.ColumnFormats}),
+		elements: elements,
+		root:     xml.StartElement{Name: xml.Name{Local: root}},
+		row:      xml.StartElement{Name: xml.Name{Local: row}},
+	}, nil
+}
+
+// Write writes v, a struct or a pointer to one, as a row element.
+func (e *XMLEncoder) Write(v interface{}) error {
+
+	record, err := e.cells.structRecord(v, e.rows+1)
+	if err != nil {
+		return err
+	}
+
+	return e.writeRecord(record)
+}
+
+// WriteRecord writes record, which must have a cell for each column, as a row element.
+func (e *XMLEncoder) WriteRecord(record []string) error {
+
+	if len(record) != len(e.elements) {
+		return ErrColumnNamesMismatch
+	}
+
+	return e.writeRecord(record)
+}
+
+func (e *XMLEncoder) writeRecord(record []string) error {
+
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	if err := e.encoder.EncodeToken(e.row); err != nil {
+		return err
+	}
+
+	for i, cell := range record {
+		if err := e.encoder.EncodeElement(cell, xml.StartElement{Name: e.elements[i]}); err != nil {
+			return err
+		}
+	}
+
+	if err := e.encoder.EncodeToken(e.row.End()); err != nil {
+		return err
+	}
+
+	e.rows++
+	return nil
+}
+
+// Close ends the root element and flushes the output. It does not close the underlying writer.
+func (e *XMLEncoder) Close() error {
+
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	if err := e.encoder.EncodeToken(e.root.End()); err != nil {
+		return err
+	}
+
+	return e.encoder.Flush()
+}
+
+// start opens the root element if it has not been opened.
+func (e *XMLEncoder) start() error {
+
+	if e.started {
+		return nil
+	}
+
+	e.started = true
+	return e.encoder.EncodeToken(e.root)
+}
+
+// ToXML streams the reader's remaining records to w as XML. The reader's column names are used as
+// the columns, overriding any in options, which may be nil.
+func ToXML(reader *Reader, w io.Writer, options *XMLOptions) error {
+
+	var opts XMLOptions
+	if options != nil {
+		opts = *options
+	}
+	opts.ColumnNames = reader.ColumnNames
+	opts.ColumnFormats = nil
+
+	encoder, err := NewXMLEncoder(w, &opts)
+	if err != nil {
+		return err
+	}
+
+	err = reader.recordRun(func() (int, error) {
+		reader.startPipeline()
+		defer reader.stopPipeline()
+
+		for {
+			record, err := reader.readRecord()
+			if err == io.EOF {
+				return encoder.rows, nil
+			}
+			if err != nil {
+				return encoder.rows, err
+			}
+
+			if err := encoder.WriteRecord(record); err != nil {
+				return encoder.rows, err
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// xmlElementNames returns the element name of each column, checking that they are distinct.
+func xmlElementNames(columnNames []string, names map[string]string) ([]xml.Name, error) {
+
+	for column, name := range names {
+		if !validXMLName(name) {
+			return nil, errors.Errorf("invalid element name %q for column %q", name, column)
+		}
+	}
+
+	elements := make([]xml.Name, len(columnNames))
+	columns := make(map[string]string, len(columnNames))
+	for i, column := range columnNames {
+
+		name, exists := names[column]
+		if !exists {
+			name = xmlName(column)
+		}
+
+		if other, exists := columns[name]; exists {
+			return nil, errors.Errorf("columns %q and %q have the same element name %q", other, column, name)
+		}
+		columns[name] = column
+		elements[i] = xml.Name{Local: name}
+	}
+
+	return elements, nil
+}
+
+// xmlName converts s to a valid XML name by replacing characters that are not allowed with
+// underscores, and prefixing an underscore if it does not start with a letter or underscore.
+func xmlName(s string) string {
+
+	name := strings.Map(func(r rune) rune {
+		if isXMLNameRune(r) {
+			return r
+		}
+		return '_'
+	}, s)
+
+	if first := []rune(name)[0]; !unicode.IsLetter(first) && first != '_' {
+		name = "_" + name
+	}
+
+	return name
+}
+
+// validXMLName reports whether s is a valid XML name without a namespace prefix.
+func validXMLName(s string) bool {
+
+	return s != "" && xmlName(s) == s
+}
+
+func isXMLNameRune(r rune) bool {
+
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
@@ -0,0 +1,88 @@
+package csvee
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type xmlRow struct {
+	Name      string
+	UnitPrice float64   `csvee:"Unit Price"`
+	Added     time.Time `csvee:"added"`
+	Note      *string
+}
+
+// TestXMLEncoder verifies structs and records are written as row elements named after columns
+func TestXMLEncoder(t *testing.T) {
+
+	note := "fish & chips <hot>"
+	added := time.Date(2022, 5, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		options  XMLOptions
+		expected string
+		err      string
+	}{
+		{
+			name:     "defaults",
+			options:  XMLOptions{ColumnNames: []string{"Name", "Unit Price", "added", "Note"}, ColumnFormats: map[string]string{"added": "2006-01-02"}},
+			expected: `<rows><row><Name>Cod</Name><Unit_Price>4.5</Unit_Price><added>2022-05-06</added><Note>fish &amp; chips &lt;hot&gt;</Note></row><row><Name>a</Name><Unit_Price>b</Unit_Price><added>c</added><Note>d</Note></row></rows>`,
+		},
+		{
+			name: "names",
+			options: XMLOptions{
+				ColumnNames:  []string{"Name", "Unit Price"},
+				ElementNames: map[string]string{"Unit Price": "price"},
+				RootElement:  "items",
+				RowElement:   "item",
+				Indent:       " ",
+			},
+			expected: "<items>\n <item>\n  <Name>Cod</Name>\n  <price>4.5</price>\n </item>\n <item>\n  <Name>a</Name>\n  <price>b</price>\n </item>\n</items>",
+		},
+		{
+			name:    "invalid element name",
+			options: XMLOptions{ColumnNames: []string{"Name"}, ElementNames: map[string]string{"Name": "1st"}},
+			err:     `invalid element name "1st" for column "Name"`,
+		},
+		{
+			name:    "duplicate element name",
+			options: XMLOptions{ColumnNames: []string{"a b", "a_b"}},
+			err:     `columns "a b" and "a_b" have the same element name "a_b"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			encoder, err := NewXMLEncoder(&buf, &tt.options)
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.NoError(t, encoder.Write(xmlRow{Name: "Cod", UnitPrice: 4.5, Added: added, Note: &note}))
+			require.NoError(t, encoder.WriteRecord([]string{"a", "b", "c", "d"}[:len(tt.options.ColumnNames)]))
+			require.NoError(t, encoder.Close())
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+// TestToXML verifies a reader's records are streamed as XML
+func TestToXML(t *testing.T) {
+
+	reader, err := NewReader(strings.NewReader("id,2nd name\n1,x\n2,y\n"), &ReaderOptions{ReadHeaders: true})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, ToXML(reader, &buf, nil))
+	assert.Equal(t, `<rows><row><id>1</id><_2nd_name>x</_2nd_name></row><row><id>2</id><_2nd_name>y</_2nd_name></row></rows>`, buf.String())
+}
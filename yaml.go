@@ -0,0 +1,155 @@
+package csvee
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// YAMLOptions configures ToYAML.
+type YAMLOptions struct {
+	// ColumnKinds maps column names to the kind of YAML scalar their cells are written as, as
+	// JSONOptions.ColumnKinds does for JSON. Times are written as YAML timestamps.
+	ColumnKinds map[string]ColumnKind
+
+	// InferKinds writes cells of columns not in ColumnKinds that parse as numbers, or are true or
+	// false, as YAML numbers or bools.
+	InferKinds bool
+}
+
+// ToYAML streams the reader's remaining records to w as a YAML sequence with a mapping per record,
+// whose keys are the column names in order, such as "- Name: Ann" followed by "  Age: 34".
+// Cells are written as strings, quoted where they would otherwise be read as another type, unless
+// opts, which may be nil, gives them another kind. Cells the reader reads as null are written as
+// null. If there are no records, the sequence is written as [].
+func ToYAML(reader *Reader, w io.Writer, opts *YAMLOptions) error {
+
+	if opts == nil {
+		opts = &YAMLOptions{}
+	}
+
+	kinds, err := reader.cellKinds(opts.ColumnKinds, opts.InferKinds)
+	if err != nil {
+		return err
+	}
+
+	keys := make([][]byte, len(reader.ColumnNames))
+	for j, name := range reader.ColumnNames {
+		keys[j] = append(appendYAMLString(nil, name), ':', ' ')
+	}
+
+	return reader.recordRun(func() (int, error) {
+		reader.startPipeline()
+		defer reader.stopPipeline()
+
+		bw := bufio.NewWriter(w)
+		var buf []byte
+
+		var rowsWritten int
+		for {
+			record, err := reader.readRecord()
+			if err == io.EOF {
+				break
+			}
+			if err == nil && len(record) != len(reader.ColumnNames) {
+				err = ErrColumnNamesMismatch
+			}
+			if err != nil {
+				return rowsWritten, err
+			}
+
+			for j, cell := range record {
+				if j == 0 {
+					buf = append(buf, "- "...)
+				} else {
+					buf = append(buf, "  "...)
+				}
+				buf = append(buf, keys[j]...)
+
+				value, err := reader.typedCell(j, cell, kinds)
+				if err != nil {
+					return rowsWritten, &FieldError{Row: reader.rowsRead, Column: reader.ColumnNames[j], Value: cell, Err: err}
+				}
+				buf = append(appendYAMLValue(buf, value), '\n')
+			}
+			rowsWritten++
+
+			if _, err := bw.Write(buf); err != nil {
+				return rowsWritten, err
+			}
+			buf = buf[:0]
+		}
+
+		if rowsWritten == 0 {
+			if _, err := bw.WriteString("[]\n"); err != nil {
+				return rowsWritten, err
+			}
+		}
+
+		return rowsWritten, bw.Flush()
+	})
+}
+
+// appendYAMLValue appends value, as returned by Reader.typedCell, to buf as a YAML scalar.
+func appendYAMLValue(buf []byte, value interface{}) []byte {
+
+	switch value := value.(type) {
+	case nil:
+		return append(buf, "null"...)
+	case int64:
+		return strconv.AppendInt(buf, value, 10)
+	case float64:
+		switch {
+		case math.IsNaN(value):
+			return append(buf, ".nan"...)
+		case math.IsInf(value, 1):
+			return append(buf, ".inf"...)
+		case math.IsInf(value, -1):
+			return append(buf, "-.inf"...)
+		}
+		return strconv.AppendFloat(buf, value, 'g', -1, 64)
+	case bool:
+		return strconv.AppendBool(buf, value)
+	case time.Time:
+		return value.AppendFormat(buf, time.RFC3339Nano)
+	}
+
+	return appendYAMLString(buf, value.(string))
+}
+
+// appendYAMLString appends s to buf as a YAML string, plain if it is made of letters, digits,
+// spaces, and a few punctuation characters that cannot be mistaken for YAML syntax or another type,
+// and otherwise double quoted.
+func appendYAMLString(buf []byte, s string) []byte {
+
+	if yamlPlain(s) {
+		return append(buf, s...)
+	}
+
+	// JSON strings are valid double quoted YAML scalars.
+	return appendJSONString(buf, s)
+}
+
+func yamlPlain(s string) bool {
+
+	if s == "" || s != strings.TrimSpace(s) || !unicode.IsLetter([]rune(s)[0]) {
+		return false
+	}
+
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "y", "n", "null":
+		return false
+	}
+
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(" _./()", r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,89 @@
+package csvee
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestToYAML verifies records are streamed as a YAML sequence of mappings with typed values
+func TestToYAML(t *testing.T) {
+
+	input := "Name,Port,Ratio,Enabled,Updated,Note\n" +
+		"web,8080,0.5,true,2021-03-04,\"a: b\"\n" +
+		"db,,1e3,no,,N/A\n"
+
+	tests := []struct {
+		name     string
+		input    string
+		options  *ReaderOptions
+		yaml     *YAMLOptions
+		expected string
+		err      string
+	}{
+		{
+			name:    "strings",
+			input:   input,
+			options: &ReaderOptions{ReadHeaders: true},
+			expected: "- Name: web\n  Port: \"8080\"\n  Ratio: \"0.5\"\n  Enabled: \"true\"\n  Updated: \"2021-03-04\"\n  Note: \"a: b\"\n" +
+				"- Name: db\n  Port: \"\"\n  Ratio: \"1e3\"\n  Enabled: \"no\"\n  Updated: \"\"\n  Note: N/A\n",
+		},
+		{
+			name:    "kinds",
+			input:   input,
+			options: &ReaderOptions{ReadHeaders: true, BoolParsing: BoolLenient, ColumnFormats: map[string]string{"Updated": "2006-01-02"}, NullValues: []string{"N/A"}},
+			yaml: &YAMLOptions{ColumnKinds: map[string]ColumnKind{
+				"Port": KindInt, "Ratio": KindFloat, "Enabled": KindBool, "Updated": KindTime,
+			}},
+			expected: "- Name: web\n  Port: 8080\n  Ratio: 0.5\n  Enabled: true\n  Updated: 2021-03-04T00:00:00Z\n  Note: \"a: b\"\n" +
+				"- Name: db\n  Port: null\n  Ratio: 1000\n  Enabled: false\n  Updated: null\n  Note: null\n",
+		},
+		{
+			name:    "inferred",
+			input:   input,
+			options: &ReaderOptions{ReadHeaders: true},
+			yaml:    &YAMLOptions{InferKinds: true},
+			expected: "- Name: web\n  Port: 8080\n  Ratio: 0.5\n  Enabled: true\n  Updated: \"2021-03-04\"\n  Note: \"a: b\"\n" +
+				"- Name: db\n  Port: \"\"\n  Ratio: 1000\n  Enabled: \"no\"\n  Updated: \"\"\n  Note: N/A\n",
+		},
+		{
+			name:     "quoted keys",
+			input:    "first name,#\nAnn,\"line\nbreak\"\n",
+			options:  &ReaderOptions{ReadHeaders: true},
+			expected: "- first name: Ann\n  \"#\": \"line\\nbreak\"\n",
+		},
+		{
+			name:     "empty",
+			input:    "Name\n",
+			options:  &ReaderOptions{ReadHeaders: true},
+			expected: "[]\n",
+		},
+		{
+			name:    "invalid int",
+			input:   input,
+			options: &ReaderOptions{ReadHeaders: true},
+			yaml:    &YAMLOptions{ColumnKinds: map[string]ColumnKind{"Ratio": KindInt}},
+			err:     `row 1, column "Ratio"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewReader(strings.NewReader(tt.input), tt.options)
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			err = ToYAML(reader, &buf, tt.yaml)
+			if tt.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package csvee
+
+import (
+	"strings"
+	"time"
+)
+
+// commonZoneOffsets are the UTC offsets, in hours, of the zone abbreviations in
+// CommonZoneAbbreviations.
+var commonZoneOffsets = map[string]int{
+	"UTC": 0, "GMT": 0,
+	"EST": -5, "EDT": -4, "CST": -6, "CDT": -5, "MST": -7, "MDT": -6, "PST": -8, "PDT": -7,
+	"AKST": -9, "AKDT": -8, "HST": -10,
+	"WET": 0, "WEST": 1, "BST": 1, "CET": 1, "CEST": 2, "EET": 2, "EEST": 3,
+}
+
+// CommonZoneAbbreviations returns a table, for ReaderOptions.ZoneAbbreviations, of the abbreviations
+// of common US and European time zones, each mapped to a fixed zone with its offset. Ambiguous
+// abbreviations resolve to the US zone, such as "CST" to Central Standard Time, and "BST" to British
+// Summer Time. The table is a new map each time, so it can be extended.
+func CommonZoneAbbreviations() map[string]*time.Location {
+
+	zones := make(map[string]*time.Location, len(commonZoneOffsets))
+	for name, hours := range commonZoneOffsets {
+		zones[name] = time.FixedZone(name, hours*60*60)
+	}
+
+	return zones
+}
+
+// parseZoned parses value in f, a layout with a zone abbreviation, as being in the location zones
+// maps the abbreviation to, and reports whether zones has one for it. The value is parsed in UTC,
+// which records the abbreviation as written, where time.Parse would look it up in the local zone.
+func (f Format) parseZoned(value string, zones map[string]*time.Location) (time.Time, bool) {
+
+	t, err := f.ParseInLocation(value, time.UTC)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	name, _ := t.Zone()
+	loc, exists := zones[name]
+	if !exists {
+		return time.Time{}, false
+	}
+
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc), true
+}
+
+// hasZoneAbbreviation reports whether f is a layout holding the zone abbreviation element, "MST",
+// and no numeric zone offset, such as "-0700" or "Z07:00", which Parse would take the offset from.
+func (f Format) hasZoneAbbreviation() bool {
+
+	layout, _ := f.epochUnit()
+	if _, registered := layout.parser(); registered {
+		return false
+	}
+
+	return strings.Contains(string(layout), "MST") && !strings.Contains(string(layout), "-07") &&
+		!strings.Contains(string(layout), "Z07")
+}